@@ -9,30 +9,70 @@ import (
 	"net/http"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/apitokens"
+	"github.com/start-codex/tookly/internal/attachments"
+	"github.com/start-codex/tookly/internal/audit"
 	"github.com/start-codex/tookly/internal/auth"
+	"github.com/start-codex/tookly/internal/boardevents"
 	"github.com/start-codex/tookly/internal/boards"
+	"github.com/start-codex/tookly/internal/comments"
+	"github.com/start-codex/tookly/internal/customfields"
+	"github.com/start-codex/tookly/internal/dashboards"
+	"github.com/start-codex/tookly/internal/digests"
+	"github.com/start-codex/tookly/internal/exports"
+	"github.com/start-codex/tookly/internal/imports"
 	"github.com/start-codex/tookly/internal/instance"
 	"github.com/start-codex/tookly/internal/invitations"
+	"github.com/start-codex/tookly/internal/issuelinks"
 	"github.com/start-codex/tookly/internal/issues"
 	"github.com/start-codex/tookly/internal/issuetypes"
+	"github.com/start-codex/tookly/internal/labels"
+	"github.com/start-codex/tookly/internal/mywork"
+	"github.com/start-codex/tookly/internal/notifications"
 	"github.com/start-codex/tookly/internal/oidc"
 	"github.com/start-codex/tookly/internal/projects"
+	"github.com/start-codex/tookly/internal/savedfilters"
+	"github.com/start-codex/tookly/internal/search"
+	"github.com/start-codex/tookly/internal/sprints"
 	"github.com/start-codex/tookly/internal/statuses"
+	"github.com/start-codex/tookly/internal/webhooks"
+	"github.com/start-codex/tookly/internal/worklogs"
 	"github.com/start-codex/tookly/internal/workspaces"
 )
 
 // newAPIHandler builds the API sub-mux with auth middleware and all domain routes.
-func newAPIHandler(db *sqlx.DB) http.Handler {
+// hub feeds board event streams and store holds attachment contents; nil
+// disables them. maxAttachmentSize of zero uses the attachments default.
+func newAPIHandler(db *sqlx.DB, hub *boardevents.Hub, store attachments.Storage, maxAttachmentSize int64) http.Handler {
 	api := http.NewServeMux()
 	instance.RegisterRoutes(api, db)
 	auth.RegisterRoutes(api, db)
+	apitokens.RegisterRoutes(api, db)
 	oidc.RegisterRoutes(api, db)
 	workspaces.RegisterRoutes(api, db)
 	invitations.RegisterRoutes(api, db)
 	projects.RegisterRoutes(api, db)
 	statuses.RegisterRoutes(api, db)
 	issuetypes.RegisterRoutes(api, db)
+	labels.RegisterRoutes(api, db)
+	customfields.RegisterRoutes(api, db)
 	boards.RegisterRoutes(api, db)
+	boardevents.RegisterRoutes(api, db, hub)
 	issues.RegisterRoutes(api, db)
+	comments.RegisterRoutes(api, db)
+	attachments.RegisterRoutes(api, db, store, maxAttachmentSize)
+	worklogs.RegisterRoutes(api, db)
+	imports.RegisterRoutes(api, db)
+	exports.RegisterRoutes(api, db, store)
+	notifications.RegisterRoutes(api, db)
+	digests.RegisterRoutes(api, db)
+	issuelinks.RegisterRoutes(api, db)
+	sprints.RegisterRoutes(api, db)
+	search.RegisterRoutes(api, db)
+	mywork.RegisterRoutes(api, db)
+	savedfilters.RegisterRoutes(api, db)
+	dashboards.RegisterRoutes(api, db)
+	webhooks.RegisterRoutes(api, db)
+	audit.RegisterRoutes(api, db)
 	return withAuth(api, db)
 }
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/start-codex/tookly/internal/apitokens"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func doBearerRequest(t *testing.T, srv string, method, path, token string) int {
+	t.Helper()
+	req, err := http.NewRequest(method, srv+path, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// TestAPITokenAuth verifies bearer tokens authenticate as their owner and
+// that read-scoped tokens cannot issue writes.
+func TestAPITokenAuth(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	srv := setupTestServer(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	seedMember(t, db, wsID, userID, "admin")
+
+	read, err := apitokens.Create(ctx, db, apitokens.CreateParams{UserID: userID, Name: "read", Scope: apitokens.ScopeRead})
+	if err != nil {
+		t.Fatalf("create read token: %v", err)
+	}
+	write, err := apitokens.Create(ctx, db, apitokens.CreateParams{UserID: userID, Name: "write", Scope: apitokens.ScopeWrite})
+	if err != nil {
+		t.Fatalf("create write token: %v", err)
+	}
+
+	if status := doBearerRequest(t, srv.URL, "GET", "/workspaces/"+wsID, read.RawToken); status != 200 {
+		t.Fatalf("read token GET: status = %d, want 200", status)
+	}
+	if status := doBearerRequest(t, srv.URL, "DELETE", "/auth/tokens/"+write.Token.ID, read.RawToken); status != 403 {
+		t.Fatalf("read token DELETE: status = %d, want 403", status)
+	}
+	if status := doBearerRequest(t, srv.URL, "POST", "/auth/tokens", write.RawToken); status != 403 {
+		t.Fatalf("token creating a token: status = %d, want 403", status)
+	}
+	if status := doBearerRequest(t, srv.URL, "DELETE", "/auth/tokens/"+read.Token.ID, write.RawToken); status != 204 {
+		t.Fatalf("write token DELETE: status = %d, want 204", status)
+	}
+	if status := doBearerRequest(t, srv.URL, "GET", "/workspaces/"+wsID, read.RawToken); status != 401 {
+		t.Fatalf("revoked token GET: status = %d, want 401", status)
+	}
+	if status := doBearerRequest(t, srv.URL, "GET", "/workspaces/"+wsID, "tky_bogus"); status != 401 {
+		t.Fatalf("unknown token GET: status = %d, want 401", status)
+	}
+}
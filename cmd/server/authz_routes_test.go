@@ -23,7 +23,7 @@ import (
 // setupTestServer creates a test HTTP server with the full API handler stack.
 func setupTestServer(t *testing.T, db *sqlx.DB) *httptest.Server {
 	t.Helper()
-	handler := newAPIHandler(db)
+	handler := newAPIHandler(db, nil, nil, 0)
 	srv := httptest.NewServer(handler)
 	t.Cleanup(srv.Close)
 	return srv
@@ -984,5 +984,364 @@ func TestWorkflowAdmin_IssueTypes(t *testing.T) {
 	}
 }
 
+// TestCommentAuthz_EditDelete verifies only the comment author or a workspace
+// admin may edit or delete a comment, while any member may read and reply.
+func TestCommentAuthz_EditDelete(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	srv := setupTestServer(t, db)
+
+	author := testpg.SeedUser(t, db)
+	other := testpg.SeedUser(t, db)
+	admin := testpg.SeedUser(t, db)
+	outsider := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	seedMember(t, db, wsID, author, "member")
+	seedMember(t, db, wsID, other, "member")
+	seedMember(t, db, wsID, admin, "admin")
+	projID := testpg.SeedProject(t, db, wsID, "CMTZ")
+
+	authorToken := loginCookie(t, db, author)
+	otherToken := loginCookie(t, db, other)
+	adminToken := loginCookie(t, db, admin)
+	outsiderToken := loginCookie(t, db, outsider)
+
+	env := doRequestWithBody(t, srv, "POST", "/projects/"+projID+"/issues", authorToken, map[string]any{
+		"issue_type_id": seedIssueType(t, db, projID),
+		"status_id":     seedStatus(t, db, projID),
+		"title":         "Discuss",
+	})
+	if env.Status != 201 {
+		t.Fatalf("create issue: status = %d, want 201 (error: %s)", env.Status, env.Error)
+	}
+	var issue struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(env.Data, &issue); err != nil {
+		t.Fatalf("unmarshal issue: %v", err)
+	}
+	base := "/projects/" + projID + "/issues/" + issue.ID + "/comments"
+
+	env = doRequestWithBody(t, srv, "POST", base, outsiderToken, map[string]any{"body": "hi"})
+	if env.Status != 403 {
+		t.Fatalf("outsider POST comment: %d, want 403", env.Status)
+	}
+	env = doRequestWithBody(t, srv, "POST", base, authorToken, map[string]any{"body": "first"})
+	if env.Status != 201 {
+		t.Fatalf("author POST comment: %d, want 201 (error: %s)", env.Status, env.Error)
+	}
+	var comment struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(env.Data, &comment); err != nil {
+		t.Fatalf("unmarshal comment: %v", err)
+	}
+	env = doRequestWithBody(t, srv, "POST", base, otherToken, map[string]any{"body": "reply", "parent_comment_id": comment.ID})
+	if env.Status != 201 {
+		t.Fatalf("member reply: %d, want 201 (error: %s)", env.Status, env.Error)
+	}
+
+	env = doRequestWithBody(t, srv, "PUT", base+"/"+comment.ID, otherToken, map[string]any{"body": "hijack"})
+	if env.Status != 403 {
+		t.Fatalf("other member PUT comment: %d, want 403", env.Status)
+	}
+	env = doRequestWithBody(t, srv, "PUT", base+"/"+comment.ID, authorToken, map[string]any{"body": "edited"})
+	if env.Status != 200 {
+		t.Fatalf("author PUT comment: %d, want 200 (error: %s)", env.Status, env.Error)
+	}
+	env = doRequestWithBody(t, srv, "PUT", base+"/"+comment.ID, adminToken, map[string]any{"body": "moderated"})
+	if env.Status != 200 {
+		t.Fatalf("admin PUT comment: %d, want 200 (error: %s)", env.Status, env.Error)
+	}
+
+	denv := doRequest(t, srv, "GET", base+"/"+comment.ID+"/revisions", otherToken)
+	if denv.Status != 200 {
+		t.Fatalf("member GET revisions: %d, want 200", denv.Status)
+	}
+
+	denv = doRequest(t, srv, "DELETE", base+"/"+comment.ID, otherToken)
+	if denv.Status != 403 {
+		t.Fatalf("other member DELETE comment: %d, want 403", denv.Status)
+	}
+	denv = doRequest(t, srv, "DELETE", base+"/"+comment.ID, adminToken)
+	if denv.Status != 204 {
+		t.Fatalf("admin DELETE comment: %d, want 204 (error: %s)", denv.Status, denv.Error)
+	}
+}
+
+func seedProjectMember(t *testing.T, db *sqlx.DB, projectID, userID, role string) {
+	t.Helper()
+	_, err := db.ExecContext(context.Background(),
+		`INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, $3)`,
+		projectID, userID, role,
+	)
+	if err != nil {
+		t.Fatalf("seed project member: %v", err)
+	}
+}
+
+// TestProjectRoles_Matrix verifies every project-scoped route against each
+// effective project role: reads need viewer, issue writes need member, and
+// workflow configuration (statuses, issue types, labels, boards) needs admin.
+// Workspace owners and admins inherit project admin; workspace members with
+// no project_members row act as project members.
+func TestProjectRoles_Matrix(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	srv := setupTestServer(t, db)
+
+	owner := testpg.SeedUser(t, db)
+	wsAdmin := testpg.SeedUser(t, db)
+	projAdmin := testpg.SeedUser(t, db)
+	projMember := testpg.SeedUser(t, db)
+	implicitMember := testpg.SeedUser(t, db)
+	viewer := testpg.SeedUser(t, db)
+	outsider := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	seedMember(t, db, wsID, owner, "owner")
+	seedMember(t, db, wsID, wsAdmin, "admin")
+	seedMember(t, db, wsID, projAdmin, "member")
+	seedMember(t, db, wsID, projMember, "member")
+	seedMember(t, db, wsID, implicitMember, "member")
+	seedMember(t, db, wsID, viewer, "member")
+	projID := testpg.SeedProject(t, db, wsID, "ROLES")
+	seedProjectMember(t, db, projID, projAdmin, authz.RoleAdmin)
+	seedProjectMember(t, db, projID, projMember, authz.RoleMember)
+	seedProjectMember(t, db, projID, viewer, authz.RoleViewer)
+
+	statusID := seedStatus(t, db, projID)
+	issueTypeID := seedIssueType(t, db, projID)
+	boardID := seedBoard(t, db, projID)
+
+	issueNumber := 1000
+	newIssue := func() string {
+		t.Helper()
+		issueNumber++
+		var id string
+		if err := db.QueryRowContext(context.Background(),
+			`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+			 VALUES ($1, $2, $3, $4, 'Matrix', '', 'medium', $5, $2)
+			 RETURNING id`,
+			projID, issueNumber, issueTypeID, statusID, owner,
+		).Scan(&id); err != nil {
+			t.Fatalf("seed issue: %v", err)
+		}
+		return id
+	}
+	newColumnWithStatus := func() string {
+		t.Helper()
+		colID := seedColumn(t, db, seedBoard(t, db, projID))
+		if _, err := db.ExecContext(context.Background(),
+			`INSERT INTO board_column_statuses (board_column_id, status_id) VALUES ($1, $2)`, colID, statusID,
+		); err != nil {
+			t.Fatalf("seed column status: %v", err)
+		}
+		return colID
+	}
+	unique := func(prefix string) string { return prefix + " " + testpg.UniqueSuffix(t, db) }
+	newLabel := func() string {
+		t.Helper()
+		var id string
+		if err := db.QueryRowContext(context.Background(),
+			`INSERT INTO labels (workspace_id, project_id, name, color) VALUES ($1, $2, $3, '#1f6feb') RETURNING id`,
+			wsID, projID, unique("L"),
+		).Scan(&id); err != nil {
+			t.Fatalf("seed label: %v", err)
+		}
+		return id
+	}
+
+	type route struct {
+		name    string
+		minRole string
+		wantOK  int
+		build   func() (method, path string, body any)
+	}
+	routes := []route{
+		// Reads
+		{"list issues", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/issues", nil
+		}},
+		{"get issue", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/issues/" + newIssue(), nil
+		}},
+		{"list attachments", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/issues/" + newIssue() + "/attachments", nil
+		}},
+		{"list worklogs", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/issues/" + newIssue() + "/worklogs", nil
+		}},
+		{"get timesheet", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/timesheet?format=csv", nil
+		}},
+		{"list watchers", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/issues/" + newIssue() + "/watchers", nil
+		}},
+		{"watch issue", authz.RoleViewer, 204, func() (string, string, any) {
+			return "PUT", "/projects/" + projID + "/issues/" + newIssue() + "/watch", nil
+		}},
+		{"list statuses", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/statuses", nil
+		}},
+		{"list issue types", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/issue-types", nil
+		}},
+		{"list labels", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/labels", nil
+		}},
+		{"list boards", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/boards", nil
+		}},
+		{"get board", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/boards/" + boardID, nil
+		}},
+		{"list board columns", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/boards/" + boardID + "/columns", nil
+		}},
+		{"list board issues", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/boards/" + boardID + "/issues", nil
+		}},
+		// Issue writes
+		{"create issue", authz.RoleMember, 201, func() (string, string, any) {
+			return "POST", "/projects/" + projID + "/issues", map[string]any{
+				"issue_type_id": issueTypeID, "status_id": statusID, "title": "New",
+			}
+		}},
+		{"update issue", authz.RoleMember, 200, func() (string, string, any) {
+			return "PUT", "/projects/" + projID + "/issues/" + newIssue(), map[string]any{
+				"title": "Updated", "priority": "high",
+			}
+		}},
+		{"patch issue", authz.RoleMember, 200, func() (string, string, any) {
+			return "PATCH", "/projects/" + projID + "/issues/" + newIssue(), map[string]any{"priority": "low"}
+		}},
+		{"bulk update issues", authz.RoleMember, 200, func() (string, string, any) {
+			return "POST", "/projects/" + projID + "/issues/bulk", map[string]any{
+				"issue_ids": []string{newIssue()}, "operation": "set_priority", "priority": "high",
+			}
+		}},
+		{"move issue", authz.RoleMember, 204, func() (string, string, any) {
+			return "POST", "/projects/" + projID + "/issues/" + newIssue() + "/move", map[string]any{
+				"target_status_id": statusID, "target_position": 0,
+			}
+		}},
+		{"log work", authz.RoleMember, 201, func() (string, string, any) {
+			return "POST", "/projects/" + projID + "/issues/" + newIssue() + "/worklogs", map[string]any{"minutes": 30}
+		}},
+		{"archive issue", authz.RoleMember, 204, func() (string, string, any) {
+			return "DELETE", "/projects/" + projID + "/issues/" + newIssue(), nil
+		}},
+		// Workflow configuration
+		{"create status", authz.RoleAdmin, 201, func() (string, string, any) {
+			return "POST", "/projects/" + projID + "/statuses", map[string]any{"name": unique("S"), "category": "todo"}
+		}},
+		{"update status", authz.RoleAdmin, 200, func() (string, string, any) {
+			return "PUT", "/projects/" + projID + "/statuses/" + seedStatus(t, db, projID), map[string]any{
+				"name": unique("S"), "category": "doing",
+			}
+		}},
+		{"archive status", authz.RoleAdmin, 204, func() (string, string, any) {
+			return "DELETE", "/projects/" + projID + "/statuses/" + seedStatus(t, db, projID), nil
+		}},
+		{"create issue type", authz.RoleAdmin, 201, func() (string, string, any) {
+			return "POST", "/projects/" + projID + "/issue-types", map[string]any{"name": unique("T"), "icon": "bug", "level": 0}
+		}},
+		{"archive issue type", authz.RoleAdmin, 204, func() (string, string, any) {
+			return "DELETE", "/projects/" + projID + "/issue-types/" + seedIssueType(t, db, projID), nil
+		}},
+		{"create label", authz.RoleAdmin, 201, func() (string, string, any) {
+			return "POST", "/projects/" + projID + "/labels", map[string]any{"name": unique("L"), "color": "#d73a4a"}
+		}},
+		{"update label", authz.RoleAdmin, 200, func() (string, string, any) {
+			return "PUT", "/projects/" + projID + "/labels/" + newLabel(), map[string]any{"name": unique("L"), "color": "#0e8a16"}
+		}},
+		{"delete label", authz.RoleAdmin, 204, func() (string, string, any) {
+			return "DELETE", "/projects/" + projID + "/labels/" + newLabel(), nil
+		}},
+		{"create board", authz.RoleAdmin, 201, func() (string, string, any) {
+			return "POST", "/projects/" + projID + "/boards", map[string]any{"name": unique("B"), "type": "kanban"}
+		}},
+		{"archive board", authz.RoleAdmin, 204, func() (string, string, any) {
+			return "DELETE", "/boards/" + seedBoard(t, db, projID), nil
+		}},
+		{"add column", authz.RoleAdmin, 201, func() (string, string, any) {
+			return "POST", "/boards/" + seedBoard(t, db, projID) + "/columns", map[string]any{"name": unique("C")}
+		}},
+		{"archive column", authz.RoleAdmin, 204, func() (string, string, any) {
+			return "DELETE", "/columns/" + seedColumn(t, db, seedBoard(t, db, projID)), nil
+		}},
+		{"assign column status", authz.RoleAdmin, 204, func() (string, string, any) {
+			return "POST", "/columns/" + seedColumn(t, db, seedBoard(t, db, projID)) + "/statuses", map[string]any{"status_id": statusID}
+		}},
+		{"unassign column status", authz.RoleAdmin, 204, func() (string, string, any) {
+			return "DELETE", "/columns/" + newColumnWithStatus() + "/statuses/" + statusID, nil
+		}},
+		{"list imports", authz.RoleAdmin, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/imports", nil
+		}},
+		{"export project", authz.RoleAdmin, 200, func() (string, string, any) {
+			return "GET", "/projects/" + projID + "/export", nil
+		}},
+		{"list saved filters", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/workspaces/" + wsID + "/filters", nil
+		}},
+		{"list dashboards", authz.RoleViewer, 200, func() (string, string, any) {
+			return "GET", "/workspaces/" + wsID + "/dashboards", nil
+		}},
+	}
+
+	rank := map[string]int{authz.RoleViewer: 1, authz.RoleMember: 2, authz.RoleAdmin: 3}
+	actors := []struct {
+		name   string
+		userID string
+		role   string // effective project role; "" for non-members
+	}{
+		{"workspace owner", owner, authz.RoleAdmin},
+		{"workspace admin", wsAdmin, authz.RoleAdmin},
+		{"project admin", projAdmin, authz.RoleAdmin},
+		{"project member", projMember, authz.RoleMember},
+		{"workspace member without project role", implicitMember, authz.RoleMember},
+		{"project viewer", viewer, authz.RoleViewer},
+		{"non-member", outsider, ""},
+	}
+
+	for _, actor := range actors {
+		token := loginCookie(t, db, actor.userID)
+		for _, rt := range routes {
+			t.Run(actor.name+"/"+rt.name, func(t *testing.T) {
+				want := http.StatusForbidden
+				if actor.role != "" && rank[actor.role] >= rank[rt.minRole] {
+					want = rt.wantOK
+				}
+				method, path, body := rt.build()
+				env := doRequestWithBody(t, srv, method, path, token, body)
+				if env.Status != want {
+					t.Fatalf("%s %s: status = %d, want %d (error: %s)", method, path, env.Status, want, env.Error)
+				}
+			})
+		}
+	}
+}
+
+// TestAuthzWiring_MyIssues verifies GET /me/issues needs a session but no
+// membership: a user without workspaces gets an empty list.
+func TestAuthzWiring_MyIssues(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	srv := setupTestServer(t, db)
+
+	outsider := testpg.SeedUser(t, db)
+
+	env := doRequest(t, srv, "GET", "/me/issues", "")
+	if env.Status != 401 {
+		t.Fatalf("anonymous GET /me/issues: status = %d, want 401", env.Status)
+	}
+
+	env = doRequest(t, srv, "GET", "/me/issues?status_category=todo", loginCookie(t, db, outsider))
+	if env.Status != 200 {
+		t.Fatalf("GET /me/issues: status = %d, want 200 (error: %s)", env.Status, env.Error)
+	}
+}
+
 // Ensure authz import is used (it's needed for the test to compile with the right module).
 var _ = authz.ErrForbidden
@@ -36,7 +36,7 @@ func setupFreshInstanceServer(t *testing.T) (*httptest.Server, *sqlx.DB) {
 	resetInstance(t, db)
 	t.Cleanup(func() { resetInstance(t, db) })
 
-	handler := newAPIHandler(db)
+	handler := newAPIHandler(db, nil, nil, 0)
 	srv := httptest.NewServer(handler)
 	t.Cleanup(srv.Close)
 	return srv, db
@@ -16,6 +16,10 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/boardevents"
+	"github.com/start-codex/tookly/internal/digests"
+	"github.com/start-codex/tookly/internal/imports"
+	"github.com/start-codex/tookly/internal/webhooks"
 	"github.com/start-codex/tookly/migrations"
 )
 
@@ -30,6 +34,12 @@ func main() {
 		port = "8080"
 	}
 
+	store, maxAttachmentSize, err := attachmentStorageFromEnv()
+	if err != nil {
+		slog.Error("failed to configure attachment storage", "error", err)
+		os.Exit(1)
+	}
+
 	db, err := sqlx.Open("postgres", dsn)
 	if err != nil {
 		slog.Error("failed to open database", "error", err)
@@ -50,8 +60,20 @@ func main() {
 	}
 	slog.Info("migrations applied")
 
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	go (&webhooks.Worker{DB: db}).Run(workerCtx)
+	baseURL := os.Getenv("BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:" + port
+	}
+	go (&digests.Worker{DB: db, BaseURL: baseURL}).Run(workerCtx)
+	go (&imports.Worker{DB: db}).Run(workerCtx)
+	hub := boardevents.NewHub(db, dsn)
+	go hub.Run(workerCtx)
+
 	mux := http.NewServeMux()
-	mux.Handle("/api/", http.StripPrefix("/api", newAPIHandler(db)))
+	mux.Handle("/api/", http.StripPrefix("/api", newAPIHandler(db, hub, store, maxAttachmentSize)))
 	registerUI(mux)
 
 	srv := &http.Server{
@@ -75,6 +97,7 @@ func main() {
 
 	<-stop
 	slog.Info("shutting down gracefully")
+	stopWorker()
 
 	shutCtx, shutCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutCancel()
@@ -10,11 +10,14 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"log/slog"
+	"net"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/apitokens"
+	"github.com/start-codex/tookly/internal/audit"
 	"github.com/start-codex/tookly/internal/authz"
 	"github.com/start-codex/tookly/internal/instance"
 	"github.com/start-codex/tookly/internal/respond"
@@ -31,15 +34,33 @@ func (sw *statusWriter) WriteHeader(status int) {
 	sw.ResponseWriter.WriteHeader(status)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, which
+// streaming handlers need for Flush and SetWriteDeadline.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
 func withRequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		b := make([]byte, 16)
 		_, _ = rand.Read(b)
-		w.Header().Set("X-Request-ID", hex.EncodeToString(b))
-		next.ServeHTTP(w, r)
+		id := hex.EncodeToString(b)
+		w.Header().Set("X-Request-ID", id)
+		ctx := audit.WithRequest(r.Context(), clientIP(r), id)
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// clientIP returns the host part of the connection's remote address.
+// Forwarding headers are not trusted since they are client-controlled.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func withLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
@@ -70,6 +91,8 @@ var staticPublicRoutes = []struct{ method, path string }{
 	{"POST", "/invitations/accept"},
 	{"POST", "/auth/verify-email"},
 	{"GET", "/auth/oidc/providers"},
+	{"GET", "/notifications/unsubscribe"},
+	{"POST", "/notifications/unsubscribe"},
 }
 
 // isPublicRoute returns:
@@ -117,6 +140,25 @@ func withAuth(next http.Handler, db *sqlx.DB) http.Handler {
 			return
 		}
 
+		if raw := apitokens.BearerToken(r); raw != "" {
+			token, err := apitokens.Validate(r.Context(), db, raw)
+			if err != nil {
+				if apitokens.IsAuthError(err) {
+					respond.Error(w, http.StatusUnauthorized, "authentication required")
+					return
+				}
+				respond.Error(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			if !token.Allows(r.Method) {
+				respond.Error(w, http.StatusForbidden, "api token scope is read-only")
+				return
+			}
+			ctx := authz.WithUserID(r.Context(), token.UserID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		cookie, err := r.Cookie("session_id")
 		if err != nil || cookie.Value == "" {
 			respond.Error(w, http.StatusUnauthorized, "authentication required")
@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/start-codex/tookly/internal/attachments"
+)
+
+// attachmentStorageFromEnv builds the attachment backend selected by
+// ATTACHMENTS_STORAGE ("local", the default, or "s3") and returns it with
+// the per-file limit from ATTACHMENTS_MAX_BYTES (zero means the default).
+func attachmentStorageFromEnv() (attachments.Storage, int64, error) {
+	var maxSize int64
+	if v := os.Getenv("ATTACHMENTS_MAX_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, 0, errors.New("ATTACHMENTS_MAX_BYTES must be a positive integer")
+		}
+		maxSize = n
+	}
+
+	switch backend := os.Getenv("ATTACHMENTS_STORAGE"); backend {
+	case "", "local":
+		dir := os.Getenv("ATTACHMENTS_DIR")
+		if dir == "" {
+			dir = "data/attachments"
+		}
+		store, err := attachments.NewLocalStorage(dir)
+		if err != nil {
+			return nil, 0, err
+		}
+		return store, maxSize, nil
+	case "s3":
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		pathStyle, _ := strconv.ParseBool(os.Getenv("S3_PATH_STYLE"))
+		store, err := attachments.NewS3Storage(attachments.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          region,
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       pathStyle,
+		}, nil)
+		if err != nil {
+			return nil, 0, err
+		}
+		return store, maxSize, nil
+	default:
+		return nil, 0, fmt.Errorf("unknown ATTACHMENTS_STORAGE %q", backend)
+	}
+}
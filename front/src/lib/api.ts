@@ -8,6 +8,14 @@ interface ApiResponse<T> {
 	data?: T;
 	error?: string;
 	message?: string;
+	next_cursor?: string;
+	has_more?: boolean;
+}
+
+interface Page<T> {
+	data: T;
+	next_cursor?: string;
+	has_more: boolean;
 }
 
 export class ApiError extends Error {
@@ -19,13 +27,17 @@ export class ApiError extends Error {
 }
 
 async function request<T>(method: string, path: string, body?: unknown): Promise<T> {
+	return (await requestEnvelope<T>(method, path, body)).data as T;
+}
+
+async function requestEnvelope<T>(method: string, path: string, body?: unknown): Promise<ApiResponse<T>> {
 	const res = await fetch(`${BASE}${path}`, {
 		method,
 		headers: body ? { 'Content-Type': 'application/json' } : {},
 		body: body ? JSON.stringify(body) : undefined
 	});
 
-	if (res.status === 204) return undefined as T;
+	if (res.status === 204) return { status: res.status };
 
 	const json: ApiResponse<T> = await res.json().catch(() => ({ status: res.status, error: res.statusText }));
 
@@ -33,7 +45,24 @@ async function request<T>(method: string, path: string, body?: unknown): Promise
 		throw new ApiError(res.status, json.error ?? res.statusText);
 	}
 
-	return json.data as T;
+	return json;
+}
+
+async function getPage<T>(path: string): Promise<Page<T>> {
+	const json = await requestEnvelope<T>('GET', path);
+	return { data: json.data as T, next_cursor: json.next_cursor, has_more: json.has_more ?? false };
+}
+
+// getAll follows next_cursor until a paginated list has no more items.
+async function getAll<T>(path: string, params: URLSearchParams): Promise<T[]> {
+	const items: T[] = [];
+	for (;;) {
+		const qs = params.toString();
+		const page = await getPage<T[]>(`${path}${qs ? `?${qs}` : ''}`);
+		items.push(...(page.data ?? []));
+		if (!page.has_more || !page.next_cursor) return items;
+		params.set('cursor', page.next_cursor);
+	}
 }
 
 const get = <T>(path: string) => request<T>('GET', path);
@@ -169,11 +198,13 @@ export const boards = {
 export const issues = {
 	create: (projectID: string, body: CreateIssueBody) =>
 		post<Issue>(`/projects/${projectID}/issues`, body),
+	// list loads every matching issue, one page of 200 at a time.
 	list: (projectID: string, params?: { status_id?: string; assignee_id?: string }) => {
 		const qs = new URLSearchParams(
 			Object.entries(params ?? {}).filter(([, v]) => v) as [string, string][]
-		).toString();
-		return get<Issue[]>(`/projects/${projectID}/issues${qs ? `?${qs}` : ''}`);
+		);
+		qs.set('limit', '200');
+		return getAll<Issue>(`/projects/${projectID}/issues`, qs);
 	},
 	get: (projectID: string, issueID: string) =>
 		get<Issue>(`/projects/${projectID}/issues/${issueID}`),
@@ -0,0 +1,179 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package apitokens manages personal API tokens. Tokens authenticate as
+// their owner through an "Authorization: Bearer" header and are stored
+// hashed with sessions.HashToken, like session tokens.
+package apitokens
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/sessions"
+)
+
+var (
+	ErrNotFound     = errors.New("api token not found")
+	ErrExpired      = errors.New("api token expired")
+	ErrUserArchived = errors.New("user account is archived")
+	ErrInvalidScope = errors.New("scope must be read or write")
+)
+
+// Token scopes. A read token may only issue safe requests (GET, HEAD,
+// OPTIONS); a write token may issue any request its owner may.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// TokenPrefix starts every raw token so leaked tokens are easy to recognise.
+const TokenPrefix = "tky_"
+
+// Token is a personal API token. The hash is never exposed.
+type Token struct {
+	ID         string     `db:"id"           json:"id"`
+	UserID     string     `db:"user_id"      json:"user_id"`
+	Name       string     `db:"name"         json:"name"`
+	Scope      string     `db:"scope"        json:"scope"`
+	ExpiresAt  *time.Time `db:"expires_at"   json:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  time.Time  `db:"created_at"   json:"created_at"`
+}
+
+// Allows reports whether the token's scope permits a request with method.
+func (t Token) Allows(method string) bool {
+	if t.Scope == ScopeWrite {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+type CreateParams struct {
+	UserID    string
+	Name      string
+	Scope     string
+	ExpiresAt *time.Time
+}
+
+func (params CreateParams) Validate() error {
+	if params.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > 100 {
+		return errors.New("name must be at most 100 characters")
+	}
+	if params.Scope != ScopeRead && params.Scope != ScopeWrite {
+		return ErrInvalidScope
+	}
+	if params.ExpiresAt != nil && !params.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+// CreateResult holds the persisted token and the raw bearer token, which is
+// returned to the client exactly once.
+type CreateResult struct {
+	Token    Token
+	RawToken string
+}
+
+// Create issues a new token for params.UserID.
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (CreateResult, error) {
+	if db == nil {
+		return CreateResult{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return CreateResult{}, err
+	}
+	params.Name = strings.TrimSpace(params.Name)
+	return createToken(ctx, db, params)
+}
+
+// List returns the user's unrevoked tokens, newest first. Expired tokens are
+// included so they can be cleaned up.
+func List(ctx context.Context, db *sqlx.DB, userID string) ([]Token, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	return listTokens(ctx, db, userID)
+}
+
+// Revoke revokes one of the user's tokens. Returns ErrNotFound if the token
+// does not exist, belongs to another user or is already revoked.
+func Revoke(ctx context.Context, db *sqlx.DB, userID, tokenID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if userID == "" {
+		return errors.New("user_id is required")
+	}
+	if tokenID == "" {
+		return errors.New("token_id is required")
+	}
+	return revokeToken(ctx, db, userID, tokenID)
+}
+
+// RevokeAllTx revokes every token of a user inside an existing transaction.
+// Used when the user is archived.
+func RevokeAllTx(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	if tx == nil {
+		return errors.New("tx is required")
+	}
+	if userID == "" {
+		return errors.New("user_id is required")
+	}
+	return revokeAllTx(ctx, tx, userID)
+}
+
+// Validate looks up a raw bearer token and records its use. Returns
+// ErrNotFound for unknown or revoked tokens, ErrExpired past expires_at and
+// ErrUserArchived when the owner has been archived.
+func Validate(ctx context.Context, db *sqlx.DB, rawToken string) (Token, error) {
+	if db == nil {
+		return Token{}, errors.New("db is required")
+	}
+	if rawToken == "" {
+		return Token{}, errors.New("token is required")
+	}
+	if !strings.HasPrefix(rawToken, TokenPrefix) {
+		return Token{}, ErrNotFound
+	}
+	return validateToken(ctx, db, sessions.HashToken(rawToken))
+}
+
+// IsAuthError reports whether err means the token should be treated as
+// unauthenticated rather than as an internal server failure.
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrExpired) ||
+		errors.Is(err, ErrUserArchived)
+}
+
+// BearerToken extracts the token of an "Authorization: Bearer" header, or
+// returns "" when there is none.
+func BearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
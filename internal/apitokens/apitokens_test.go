@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package apitokens
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateParams_Validate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name    string
+		params  CreateParams
+		wantErr bool
+	}{
+		{name: "valid read", params: CreateParams{UserID: "u", Name: "ci", Scope: ScopeRead}},
+		{name: "valid write with expiry", params: CreateParams{UserID: "u", Name: "ci", Scope: ScopeWrite, ExpiresAt: &future}},
+		{name: "missing user_id", params: CreateParams{Name: "ci", Scope: ScopeRead}, wantErr: true},
+		{name: "blank name", params: CreateParams{UserID: "u", Name: "  ", Scope: ScopeRead}, wantErr: true},
+		{name: "long name", params: CreateParams{UserID: "u", Name: strings.Repeat("x", 101), Scope: ScopeRead}, wantErr: true},
+		{name: "unknown scope", params: CreateParams{UserID: "u", Name: "ci", Scope: "admin"}, wantErr: true},
+		{name: "expiry in the past", params: CreateParams{UserID: "u", Name: "ci", Scope: ScopeRead, ExpiresAt: &past}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToken_Allows(t *testing.T) {
+	read := Token{Scope: ScopeRead}
+	write := Token{Scope: ScopeWrite}
+	for _, m := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+		if !read.Allows(m) {
+			t.Errorf("read token should allow %s", m)
+		}
+	}
+	for _, m := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		if read.Allows(m) {
+			t.Errorf("read token should not allow %s", m)
+		}
+		if !write.Allows(m) {
+			t.Errorf("write token should allow %s", m)
+		}
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"Bearer tky_abc", "tky_abc"},
+		{"bearer tky_abc", "tky_abc"},
+		{"Basic dXNlcjpwYXNz", ""},
+		{"Bearer", ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		if got := BearerToken(r); got != tt.want {
+			t.Errorf("BearerToken(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	for _, err := range []error{ErrNotFound, ErrExpired, ErrUserArchived} {
+		if !IsAuthError(err) {
+			t.Errorf("IsAuthError(%v) = false", err)
+		}
+	}
+	if IsAuthError(errors.New("connection refused")) {
+		t.Error("IsAuthError(other) = true")
+	}
+}
+
+func TestNilDB(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Create(ctx, nil, CreateParams{}); err == nil {
+		t.Fatal("Create with nil db should fail")
+	}
+	if _, err := List(ctx, nil, "u"); err == nil {
+		t.Fatal("List with nil db should fail")
+	}
+	if err := Revoke(ctx, nil, "u", "t"); err == nil {
+		t.Fatal("Revoke with nil db should fail")
+	}
+	if _, err := Validate(ctx, nil, "tky_x"); err == nil {
+		t.Fatal("Validate with nil db should fail")
+	}
+	if err := RevokeAllTx(ctx, nil, "u"); err == nil {
+		t.Fatal("RevokeAllTx with nil tx should fail")
+	}
+}
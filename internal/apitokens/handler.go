@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package apitokens
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("POST /auth/tokens", handleCreate(db))
+	mux.HandleFunc("GET /auth/tokens", handleList(db))
+	mux.HandleFunc("DELETE /auth/tokens/{tokenID}", handleRevoke(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, ErrNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	default:
+		slog.Error("apitokens handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func handleCreate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		// A token must not be able to extend its own lifetime by minting
+		// new ones; creation needs an interactive session.
+		if BearerToken(r) != "" {
+			respond.Error(w, http.StatusForbidden, "api tokens cannot create api tokens")
+			return
+		}
+		var body struct {
+			Name      string     `json:"name"`
+			Scope     string     `json:"scope"`
+			ExpiresAt *time.Time `json:"expires_at"`
+		}
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if body.Scope == "" {
+			body.Scope = ScopeRead
+		}
+		params := CreateParams{UserID: userID, Name: body.Name, Scope: body.Scope, ExpiresAt: body.ExpiresAt}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		result, err := Create(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusCreated, struct {
+			Token
+			RawToken string `json:"token"`
+		}{result.Token, result.RawToken})
+	}
+}
+
+func handleList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		tokens, err := List(r.Context(), db, userID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, tokens)
+	}
+}
+
+func handleRevoke(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if err := Revoke(r.Context(), db, userID, r.PathValue("tokenID")); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package apitokens
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/sessions"
+)
+
+const tokenCols = `t.id, t.user_id, t.name, t.scope, t.expires_at, t.last_used_at, t.created_at`
+
+// lastUsedResolution bounds how often validation writes last_used_at, so a
+// busy script does not update the row on every request.
+const lastUsedResolution = time.Minute
+
+func createToken(ctx context.Context, db *sqlx.DB, params CreateParams) (CreateResult, error) {
+	raw, err := sessions.GenerateToken()
+	if err != nil {
+		return CreateResult{}, fmt.Errorf("generate token: %w", err)
+	}
+	rawToken := TokenPrefix + raw
+
+	var token Token
+	err = db.QueryRowxContext(ctx,
+		`INSERT INTO api_tokens AS t (user_id, name, token_hash, scope, expires_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+tokenCols,
+		params.UserID, params.Name, sessions.HashToken(rawToken), params.Scope, params.ExpiresAt,
+	).StructScan(&token)
+	if err != nil {
+		return CreateResult{}, fmt.Errorf("insert api token: %w", err)
+	}
+	return CreateResult{Token: token, RawToken: rawToken}, nil
+}
+
+func listTokens(ctx context.Context, db *sqlx.DB, userID string) ([]Token, error) {
+	tokens := []Token{}
+	if err := db.SelectContext(ctx, &tokens,
+		`SELECT `+tokenCols+`
+		 FROM api_tokens t
+		 WHERE t.user_id = $1 AND t.revoked_at IS NULL
+		 ORDER BY t.created_at DESC, t.id DESC`,
+		userID,
+	); err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func revokeToken(ctx context.Context, db *sqlx.DB, userID, tokenID string) error {
+	res, err := db.ExecContext(ctx,
+		`UPDATE api_tokens
+		 SET revoked_at = NOW()
+		 WHERE id::text = $1 AND user_id = $2 AND revoked_at IS NULL`,
+		tokenID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("revoke api token: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("revoke api token rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func revokeAllTx(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE api_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID,
+	); err != nil {
+		return fmt.Errorf("revoke api tokens by user: %w", err)
+	}
+	return nil
+}
+
+func validateToken(ctx context.Context, db *sqlx.DB, tokenHash string) (Token, error) {
+	var row struct {
+		Token
+		UserArchived bool `db:"user_archived"`
+	}
+	err := db.GetContext(ctx, &row,
+		`SELECT `+tokenCols+`, (u.archived_at IS NOT NULL) AS user_archived
+		 FROM api_tokens t
+		 JOIN app_users u ON u.id = t.user_id
+		 WHERE t.token_hash = $1 AND t.revoked_at IS NULL`,
+		tokenHash,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Token{}, ErrNotFound
+		}
+		return Token{}, fmt.Errorf("get api token: %w", err)
+	}
+	if row.UserArchived {
+		return Token{}, ErrUserArchived
+	}
+	if row.ExpiresAt != nil && time.Now().After(*row.ExpiresAt) {
+		return Token{}, ErrExpired
+	}
+
+	if row.LastUsedAt == nil || time.Since(*row.LastUsedAt) >= lastUsedResolution {
+		if _, err := db.ExecContext(ctx,
+			`UPDATE api_tokens SET last_used_at = NOW() WHERE id = $1`,
+			row.ID,
+		); err != nil {
+			return Token{}, fmt.Errorf("touch api token: %w", err)
+		}
+		now := time.Now()
+		row.LastUsedAt = &now
+	}
+	return row.Token, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package apitokens
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/sessions"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestTokenLifecycle(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	otherID := testpg.SeedUser(t, db)
+
+	created, err := Create(ctx, db, CreateParams{UserID: userID, Name: " ci ", Scope: ScopeRead})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(created.RawToken, TokenPrefix) || created.Token.Name != "ci" {
+		t.Fatalf("Create = %+v", created)
+	}
+	var stored string
+	if err := db.GetContext(ctx, &stored, `SELECT token_hash FROM api_tokens WHERE id = $1`, created.Token.ID); err != nil {
+		t.Fatalf("load hash: %v", err)
+	}
+	if stored != sessions.HashToken(created.RawToken) {
+		t.Fatal("token is not stored as sessions.HashToken of the raw token")
+	}
+
+	got, err := Validate(ctx, db, created.RawToken)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if got.UserID != userID || got.Scope != ScopeRead || got.LastUsedAt == nil {
+		t.Fatalf("Validate = %+v", got)
+	}
+	if _, err := Validate(ctx, db, TokenPrefix+"unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Validate unknown = %v, want ErrNotFound", err)
+	}
+
+	tokens, err := List(ctx, db, userID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].LastUsedAt == nil {
+		t.Fatalf("List = %+v, want one used token", tokens)
+	}
+
+	if err := Revoke(ctx, db, otherID, created.Token.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Revoke by other user = %v, want ErrNotFound", err)
+	}
+	if err := Revoke(ctx, db, userID, created.Token.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := Validate(ctx, db, created.RawToken); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Validate revoked = %v, want ErrNotFound", err)
+	}
+	if tokens, _ := List(ctx, db, userID); len(tokens) != 0 {
+		t.Fatalf("List after revoke = %+v, want none", tokens)
+	}
+}
+
+func TestTokenExpired(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	expires := time.Now().Add(time.Hour)
+	created, err := Create(ctx, db, CreateParams{UserID: userID, Name: "short", Scope: ScopeWrite, ExpiresAt: &expires})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE api_tokens SET expires_at = NOW() - INTERVAL '1 second' WHERE id = $1`, created.Token.ID); err != nil {
+		t.Fatalf("expire token: %v", err)
+	}
+	if _, err := Validate(ctx, db, created.RawToken); !errors.Is(err, ErrExpired) {
+		t.Fatalf("Validate expired = %v, want ErrExpired", err)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package attachments stores files attached to issues. Metadata lives in
+// Postgres; the bytes live in a Storage backend, either a local directory
+// or an S3-compatible bucket. Attachments of archived issues are hidden.
+package attachments
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultMaxSize is the per-file limit used when none is configured.
+const DefaultMaxSize = 25 << 20
+
+const maxFilenameLen = 255
+
+var (
+	ErrNotFound      = errors.New("attachment not found")
+	ErrIssueNotFound = errors.New("issue not found")
+	ErrTooLarge      = errors.New("file exceeds the attachment size limit")
+	ErrEmpty         = errors.New("file is empty")
+)
+
+type Attachment struct {
+	ID          string    `db:"id"           json:"id"`
+	IssueID     string    `db:"issue_id"     json:"issue_id"`
+	UploaderID  string    `db:"uploader_id"  json:"uploader_id"`
+	Filename    string    `db:"filename"     json:"filename"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Size        int64     `db:"size_bytes"   json:"size"`
+	StorageKey  string    `db:"storage_key"  json:"-"`
+	CreatedAt   time.Time `db:"created_at"   json:"created_at"`
+}
+
+// CreateParams uploads Content as a new attachment. At most MaxSize bytes
+// are accepted; zero means DefaultMaxSize. The content type is sniffed from
+// the bytes, never taken from the client.
+type CreateParams struct {
+	ProjectID  string
+	IssueID    string
+	UploaderID string
+	Filename   string
+	Content    io.Reader
+	MaxSize    int64
+}
+
+func (params CreateParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.IssueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if params.UploaderID == "" {
+		return errors.New("uploader_id is required")
+	}
+	if params.Content == nil {
+		return errors.New("file is required")
+	}
+	if params.MaxSize < 0 {
+		return errors.New("max_size must be >= 0")
+	}
+	return nil
+}
+
+// Create spools the upload to a temporary file to measure and sniff it,
+// writes it to store and records its metadata. The stored object is
+// removed again if the metadata cannot be written.
+func Create(ctx context.Context, db *sqlx.DB, store Storage, params CreateParams) (Attachment, error) {
+	if db == nil {
+		return Attachment{}, errors.New("db is required")
+	}
+	if store == nil {
+		return Attachment{}, errors.New("storage is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Attachment{}, err
+	}
+	if params.MaxSize == 0 {
+		params.MaxSize = DefaultMaxSize
+	}
+	if err := checkIssue(ctx, db, params.ProjectID, params.IssueID); err != nil {
+		return Attachment{}, err
+	}
+
+	tmp, err := os.CreateTemp("", "tookly-upload-*")
+	if err != nil {
+		return Attachment{}, fmt.Errorf("create upload spool: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+	size, err := io.Copy(tmp, io.LimitReader(params.Content, params.MaxSize+1))
+	if err != nil {
+		return Attachment{}, fmt.Errorf("spool upload: %w", err)
+	}
+	if size > params.MaxSize {
+		return Attachment{}, ErrTooLarge
+	}
+	if size == 0 {
+		return Attachment{}, ErrEmpty
+	}
+	head := make([]byte, 512)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Attachment{}, fmt.Errorf("read upload head: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return Attachment{}, fmt.Errorf("rewind upload: %w", err)
+	}
+
+	key, err := NewKey(params.ProjectID, params.IssueID)
+	if err != nil {
+		return Attachment{}, err
+	}
+	att := Attachment{
+		IssueID:     params.IssueID,
+		UploaderID:  params.UploaderID,
+		Filename:    CleanFilename(params.Filename),
+		ContentType: http.DetectContentType(head[:n]),
+		Size:        size,
+		StorageKey:  key,
+	}
+	if err := store.Put(ctx, key, tmp, size, att.ContentType); err != nil {
+		return Attachment{}, fmt.Errorf("store attachment: %w", err)
+	}
+	created, err := insertAttachment(ctx, db, params.ProjectID, att)
+	if err != nil {
+		if delErr := store.Delete(context.WithoutCancel(ctx), key); delErr != nil {
+			slog.Error("remove orphaned attachment", "key", key, "error", delErr)
+		}
+		return Attachment{}, err
+	}
+	return created, nil
+}
+
+// List returns the attachments of an active issue, oldest first.
+func List(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Attachment, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" || issueID == "" {
+		return nil, errors.New("project_id and issue_id are required")
+	}
+	return listAttachments(ctx, db, projectID, issueID)
+}
+
+func Get(ctx context.Context, db *sqlx.DB, projectID, issueID, attachmentID string) (Attachment, error) {
+	if db == nil {
+		return Attachment{}, errors.New("db is required")
+	}
+	if projectID == "" || issueID == "" || attachmentID == "" {
+		return Attachment{}, errors.New("project_id, issue_id and attachment_id are required")
+	}
+	return getAttachment(ctx, db, projectID, issueID, attachmentID)
+}
+
+// Open returns the metadata and contents of an attachment. The caller
+// closes the reader.
+func Open(ctx context.Context, db *sqlx.DB, store Storage, projectID, issueID, attachmentID string) (Attachment, io.ReadCloser, error) {
+	if store == nil {
+		return Attachment{}, nil, errors.New("storage is required")
+	}
+	att, err := Get(ctx, db, projectID, issueID, attachmentID)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+	rc, err := store.Get(ctx, att.StorageKey)
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return Attachment{}, nil, ErrNotFound
+		}
+		return Attachment{}, nil, fmt.Errorf("open attachment: %w", err)
+	}
+	return att, rc, nil
+}
+
+// Delete removes the metadata of an attachment, then its contents. A
+// failure to remove the contents is logged, not returned: the attachment
+// is already gone for users.
+func Delete(ctx context.Context, db *sqlx.DB, store Storage, projectID, issueID, attachmentID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if store == nil {
+		return errors.New("storage is required")
+	}
+	if projectID == "" || issueID == "" || attachmentID == "" {
+		return errors.New("project_id, issue_id and attachment_id are required")
+	}
+	key, err := deleteAttachment(ctx, db, projectID, issueID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(context.WithoutCancel(ctx), key); err != nil {
+		slog.Error("remove attachment contents", "key", key, "error", err)
+	}
+	return nil
+}
+
+// NewKey returns a fresh storage key grouped by project and issue.
+func NewKey(projectID, issueID string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate attachment key: %w", err)
+	}
+	return path.Join(projectID, issueID, hex.EncodeToString(b)), nil
+}
+
+// CleanFilename keeps the base name of a client-supplied filename and
+// drops control characters, so it is safe to echo in headers.
+func CleanFilename(name string) string {
+	name = name[strings.LastIndexAny(name, `/\`)+1:]
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." {
+		return "attachment"
+	}
+	if len(name) > maxFilenameLen {
+		name = strings.ToValidUTF8(name[:maxFilenameLen], "")
+	}
+	return name
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package attachments
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+const (
+	// multipartOverhead is the room left for multipart headers and other
+	// form fields on top of the file size limit.
+	multipartOverhead = 1 << 20
+	// transferTimeout replaces the server's read or write deadline while a
+	// file is uploaded or downloaded.
+	transferTimeout = 10 * time.Minute
+)
+
+// inlineTypes are shown in the browser; everything else is downloaded.
+var inlineTypes = map[string]bool{
+	"image/png": true, "image/jpeg": true, "image/gif": true, "image/webp": true,
+}
+
+// RegisterRoutes registers the attachment routes. Without a store, uploads,
+// downloads and deletes answer 503. maxSize is the per-file limit; zero
+// means DefaultMaxSize.
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB, store Storage, maxSize int64) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	mux.HandleFunc("POST /projects/{projectID}/issues/{issueID}/attachments", handleCreate(db, store, maxSize))
+	mux.HandleFunc("GET /projects/{projectID}/issues/{issueID}/attachments", handleList(db))
+	mux.HandleFunc("GET /projects/{projectID}/issues/{issueID}/attachments/{attachmentID}", handleDownload(db, store))
+	mux.HandleFunc("DELETE /projects/{projectID}/issues/{issueID}/attachments/{attachmentID}", handleDelete(db, store))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	var maxBytes *http.MaxBytesError
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound),
+		errors.Is(err, authz.ErrProjectNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound),
+		errors.Is(err, ErrIssueNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrTooLarge),
+		errors.As(err, &maxBytes):
+		respond.Error(w, http.StatusRequestEntityTooLarge, ErrTooLarge.Error())
+	case errors.Is(err, ErrEmpty):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		slog.Error("attachments handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func unavailable(w http.ResponseWriter) {
+	respond.Error(w, http.StatusServiceUnavailable, "attachment storage unavailable")
+}
+
+// handleCreate accepts a multipart/form-data body whose "file" part is the
+// attachment. The part is streamed to storage rather than parsed into memory.
+func handleCreate(db *sqlx.DB, store Storage, maxSize int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleMember); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if store == nil {
+			unavailable(w)
+			return
+		}
+		_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(transferTimeout))
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+		mr, err := r.MultipartReader()
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, "expected a multipart/form-data body")
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				respond.Error(w, http.StatusUnprocessableEntity, "file is required")
+				return
+			}
+			if err != nil {
+				var maxBytes *http.MaxBytesError
+				if errors.As(err, &maxBytes) {
+					fail(w, err)
+					return
+				}
+				respond.Error(w, http.StatusBadRequest, "invalid multipart body")
+				return
+			}
+			if part.FormName() != "file" {
+				part.Close()
+				continue
+			}
+			att, err := Create(r.Context(), db, store, CreateParams{
+				ProjectID:  r.PathValue("projectID"),
+				IssueID:    r.PathValue("issueID"),
+				UploaderID: authedUserID,
+				Filename:   part.FileName(),
+				Content:    part,
+				MaxSize:    maxSize,
+			})
+			if err != nil {
+				fail(w, err)
+				return
+			}
+			respond.JSON(w, http.StatusCreated, att)
+			return
+		}
+	}
+}
+
+func handleList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := List(r.Context(), db, r.PathValue("projectID"), r.PathValue("issueID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+// handleDownload streams the attachment with its sniffed content type.
+// Images are shown inline; everything else is served as a download, and
+// nosniff plus a sandbox CSP keep browsers from running uploaded content.
+func handleDownload(db *sqlx.DB, store Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+			fail(w, err)
+			return
+		}
+		if store == nil {
+			unavailable(w)
+			return
+		}
+		att, rc, err := Open(r.Context(), db, store, r.PathValue("projectID"), r.PathValue("issueID"), r.PathValue("attachmentID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		defer rc.Close()
+
+		disposition := "attachment"
+		if inlineTypes[att.ContentType] {
+			disposition = "inline"
+		}
+		h := w.Header()
+		h.Set("Content-Type", att.ContentType)
+		h.Set("Content-Length", strconv.FormatInt(att.Size, 10))
+		h.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": att.Filename}))
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Content-Security-Policy", "sandbox")
+		h.Set("Cache-Control", "private, no-cache")
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(transferTimeout))
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, rc); err != nil {
+			slog.Warn("stream attachment", "attachment_id", att.ID, "error", err)
+		}
+	}
+}
+
+// handleDelete lets the uploader or a project admin remove an attachment.
+func handleDelete(db *sqlx.DB, store Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projID := r.PathValue("projectID")
+		_, role, err := authz.ProjectRole(r.Context(), db, projID)
+		if err == nil && !authz.HasRole(role, authz.RoleMember) {
+			err = authz.ErrForbidden
+		}
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if store == nil {
+			unavailable(w)
+			return
+		}
+		att, err := Get(r.Context(), db, projID, r.PathValue("issueID"), r.PathValue("attachmentID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if att.UploaderID != authedUserID && role != authz.RoleAdmin {
+			fail(w, authz.ErrForbidden)
+			return
+		}
+		if err := Delete(r.Context(), db, store, projID, att.IssueID, att.ID); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package attachments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+const attachmentCols = `a.id, a.issue_id, a.uploader_id, a.filename, a.content_type, a.size_bytes, a.storage_key, a.created_at`
+
+// activeIssue joins attachments (alias a) to their issue when it is active
+// and belongs to the project in $1.
+const activeIssue = `JOIN issues i ON i.id = a.issue_id AND i.project_id = $1 AND i.archived_at IS NULL`
+
+func checkIssue(ctx context.Context, db *sqlx.DB, projectID, issueID string) error {
+	var exists bool
+	if err := db.GetContext(ctx, &exists,
+		`SELECT EXISTS(SELECT 1 FROM issues WHERE id = $1 AND project_id = $2 AND archived_at IS NULL)`,
+		issueID, projectID,
+	); err != nil {
+		return fmt.Errorf("check issue: %w", err)
+	}
+	if !exists {
+		return ErrIssueNotFound
+	}
+	return nil
+}
+
+func insertAttachment(ctx context.Context, db *sqlx.DB, projectID string, att Attachment) (Attachment, error) {
+	var created Attachment
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create attachment", func(tx *sqlx.Tx) error {
+		// The upload may have taken a while; the issue must still be active.
+		var issueID string
+		if err := tx.GetContext(ctx, &issueID,
+			`SELECT id
+			 FROM issues
+			 WHERE id = $1
+			   AND project_id = $2
+			   AND archived_at IS NULL
+			 FOR SHARE`,
+			att.IssueID, projectID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrIssueNotFound
+			}
+			return fmt.Errorf("lock issue: %w", err)
+		}
+		if err := tx.QueryRowxContext(ctx,
+			`INSERT INTO attachments (issue_id, uploader_id, filename, content_type, size_bytes, storage_key)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 RETURNING id, issue_id, uploader_id, filename, content_type, size_bytes, storage_key, created_at`,
+			att.IssueID, att.UploaderID, att.Filename, att.ContentType, att.Size, att.StorageKey,
+		).StructScan(&created); err != nil {
+			return fmt.Errorf("insert attachment: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return Attachment{}, err
+	}
+	return created, nil
+}
+
+func listAttachments(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Attachment, error) {
+	if err := checkIssue(ctx, db, projectID, issueID); err != nil {
+		return nil, err
+	}
+	list := []Attachment{}
+	if err := db.SelectContext(ctx, &list,
+		`SELECT `+attachmentCols+`
+		 FROM attachments a
+		 `+activeIssue+`
+		 WHERE a.issue_id = $2
+		 ORDER BY a.created_at, a.id`,
+		projectID, issueID,
+	); err != nil {
+		return nil, fmt.Errorf("list attachments: %w", err)
+	}
+	return list, nil
+}
+
+func getAttachment(ctx context.Context, db *sqlx.DB, projectID, issueID, attachmentID string) (Attachment, error) {
+	var att Attachment
+	if err := db.GetContext(ctx, &att,
+		`SELECT `+attachmentCols+`
+		 FROM attachments a
+		 `+activeIssue+`
+		 WHERE a.issue_id = $2
+		   AND a.id = $3`,
+		projectID, issueID, attachmentID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Attachment{}, ErrNotFound
+		}
+		return Attachment{}, fmt.Errorf("get attachment: %w", err)
+	}
+	return att, nil
+}
+
+// deleteAttachment removes the row and returns the storage key of the
+// removed attachment.
+func deleteAttachment(ctx context.Context, db *sqlx.DB, projectID, issueID, attachmentID string) (string, error) {
+	var key string
+	if err := db.GetContext(ctx, &key,
+		`DELETE FROM attachments a
+		 USING issues i
+		 WHERE i.id = a.issue_id
+		   AND i.project_id = $1
+		   AND i.archived_at IS NULL
+		   AND a.issue_id = $2
+		   AND a.id = $3
+		 RETURNING a.storage_key`,
+		projectID, issueID, attachmentID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("delete attachment: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestAttachments(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	projectID := testpg.SeedProject(t, db, wsID, "ATT")
+	otherProjectID := testpg.SeedProject(t, db, wsID, "ATO")
+
+	var typeID, statusID, issueID string
+	if err := db.GetContext(ctx, &typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert issue type: %v", err)
+	}
+	if err := db.GetContext(ctx, &statusID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	if err := db.GetContext(ctx, &issueID,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, 1, $2, $3, 'Issue', '', 'medium', $4, 0) RETURNING id`,
+		projectID, typeID, statusID, userID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+	params := CreateParams{ProjectID: projectID, IssueID: issueID, UploaderID: userID, Filename: "../shot.png", Content: bytes.NewReader(png)}
+
+	att, err := Create(ctx, db, store, params)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if att.Filename != "shot.png" || att.ContentType != "image/png" || att.Size != int64(len(png)) || att.UploaderID != userID {
+		t.Fatalf("created attachment = %+v", att)
+	}
+
+	tooLarge := params
+	tooLarge.Content = strings.NewReader("0123456789")
+	tooLarge.MaxSize = 9
+	if _, err := Create(ctx, db, store, tooLarge); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Create over the limit: error = %v, want ErrTooLarge", err)
+	}
+	empty := params
+	empty.Content = strings.NewReader("")
+	if _, err := Create(ctx, db, store, empty); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("Create empty: error = %v, want ErrEmpty", err)
+	}
+	wrongProject := params
+	wrongProject.ProjectID = otherProjectID
+	wrongProject.Content = strings.NewReader("x")
+	if _, err := Create(ctx, db, store, wrongProject); !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("Create in another project: error = %v, want ErrIssueNotFound", err)
+	}
+
+	list, err := List(ctx, db, projectID, issueID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != att.ID {
+		t.Fatalf("List = %+v, want the one attachment", list)
+	}
+	if _, err := Get(ctx, db, otherProjectID, issueID, att.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get through another project: error = %v, want ErrNotFound", err)
+	}
+
+	got, rc, err := Open(ctx, db, store, projectID, issueID, att.ID)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	content, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || !bytes.Equal(content, png) || got.ID != att.ID {
+		t.Fatalf("Open = %+v, %d bytes, %v", got, len(content), err)
+	}
+
+	// Archiving the issue hides its attachments.
+	if _, err := db.ExecContext(ctx, `UPDATE issues SET archived_at = NOW() WHERE id = $1`, issueID); err != nil {
+		t.Fatalf("archive issue: %v", err)
+	}
+	if _, err := List(ctx, db, projectID, issueID); !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("List archived: error = %v, want ErrIssueNotFound", err)
+	}
+	if _, _, err := Open(ctx, db, store, projectID, issueID, att.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Open archived: error = %v, want ErrNotFound", err)
+	}
+	if err := Delete(ctx, db, store, projectID, issueID, att.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete archived: error = %v, want ErrNotFound", err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE issues SET archived_at = NULL WHERE id = $1`, issueID); err != nil {
+		t.Fatalf("restore issue: %v", err)
+	}
+
+	if err := Delete(ctx, db, store, projectID, issueID, att.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, att.StorageKey); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("stored object after Delete: error = %v, want ErrObjectNotFound", err)
+	}
+	if err := Delete(ctx, db, store, projectID, issueID, att.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete twice: error = %v, want ErrNotFound", err)
+	}
+}
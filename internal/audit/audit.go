@@ -0,0 +1,154 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package audit records administrative actions in the append-only audit_log
+// table. Producers call Record inside the transaction that makes the change.
+// The actor is the authenticated user of the context; the client IP and
+// request ID come from WithRequest, which the server middleware sets.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+)
+
+// Actions.
+const (
+	ActionSMTPUpdated                = "instance.smtp_updated"
+	ActionOIDCProviderCreated        = "oidc_provider.created"
+	ActionOIDCProviderUpdated        = "oidc_provider.updated"
+	ActionOIDCProviderDeleted        = "oidc_provider.deleted"
+	ActionWorkspaceMemberRoleChanged = "workspace_member.role_changed"
+	ActionProjectMemberRoleChanged   = "project_member.role_changed"
+	ActionInvitationRevoked          = "invitation.revoked"
+	ActionProjectArchived            = "project.archived"
+)
+
+// Target types.
+const (
+	TargetInstanceConfig  = "instance_config"
+	TargetOIDCProvider    = "oidc_provider"
+	TargetWorkspaceMember = "workspace_member"
+	TargetProjectMember   = "project_member"
+	TargetInvitation      = "invitation"
+	TargetProject         = "project"
+)
+
+// Entry is one row of the audit log. WorkspaceID is nil for instance-level
+// actions; ActorID is nil for actions without an authenticated user.
+type Entry struct {
+	ID          string          `db:"id"           json:"id"`
+	WorkspaceID *string         `db:"workspace_id" json:"workspace_id"`
+	ActorID     *string         `db:"actor_id"     json:"actor_id"`
+	Action      string          `db:"action"       json:"action"`
+	TargetType  string          `db:"target_type"  json:"target_type"`
+	TargetID    string          `db:"target_id"    json:"target_id"`
+	Before      json.RawMessage `db:"before"       json:"before"`
+	After       json.RawMessage `db:"after"        json:"after"`
+	IP          string          `db:"ip"           json:"ip"`
+	RequestID   string          `db:"request_id"   json:"request_id"`
+	CreatedAt   time.Time       `db:"created_at"   json:"created_at"`
+}
+
+// Change describes an action to record. Before and After are marshalled to
+// JSON; leave either nil when there is no prior or resulting state. Callers
+// must strip secrets from both.
+type Change struct {
+	WorkspaceID string
+	Action      string
+	TargetType  string
+	TargetID    string
+	Before      any
+	After       any
+}
+
+type requestKey struct{}
+
+type requestInfo struct {
+	ip        string
+	requestID string
+}
+
+// WithRequest stores the client IP and request ID recorded by Record.
+func WithRequest(ctx context.Context, ip, requestID string) context.Context {
+	return context.WithValue(ctx, requestKey{}, requestInfo{ip: ip, requestID: requestID})
+}
+
+// Record appends c to the audit log inside the caller's transaction.
+func Record(ctx context.Context, tx *sqlx.Tx, c Change) error {
+	if tx == nil {
+		return errors.New("tx is required")
+	}
+	if c.Action == "" {
+		return errors.New("action is required")
+	}
+	if c.TargetType == "" || c.TargetID == "" {
+		return errors.New("target is required")
+	}
+	before, err := marshal(c.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshal(c.After)
+	if err != nil {
+		return err
+	}
+	info, _ := ctx.Value(requestKey{}).(requestInfo)
+	actorID, _ := authz.UserIDFromContext(ctx)
+	return insertEntry(ctx, tx, c, actorID, before, after, info)
+}
+
+func marshal(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// ListParams filters the audit log. Every set field narrows the result;
+// Since is inclusive and Until exclusive.
+type ListParams struct {
+	WorkspaceID string
+	ActorID     string
+	Action      string
+	TargetType  string
+	TargetID    string
+	Since       *time.Time
+	Until       *time.Time
+	Limit       int
+	Offset      int
+}
+
+func (params ListParams) Validate() error {
+	if params.Limit < 0 || params.Limit > 200 {
+		return errors.New("limit must be between 0 and 200")
+	}
+	if params.Offset < 0 {
+		return errors.New("offset must be >= 0")
+	}
+	if params.Since != nil && params.Until != nil && !params.Since.Before(*params.Until) {
+		return errors.New("since must be before until")
+	}
+	return nil
+}
+
+// List returns matching entries, newest first.
+func List(ctx context.Context, db *sqlx.DB, params ListParams) ([]Entry, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.Limit == 0 {
+		params.Limit = 50
+	}
+	return listEntries(ctx, db, params)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package audit
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestListParams_Validate(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	tests := []struct {
+		name    string
+		params  ListParams
+		wantErr bool
+	}{
+		{name: "empty", params: ListParams{}},
+		{name: "range", params: ListParams{Since: &earlier, Until: &now, Limit: 200}},
+		{name: "limit too large", params: ListParams{Limit: 201}, wantErr: true},
+		{name: "negative offset", params: ListParams{Offset: -1}, wantErr: true},
+		{name: "inverted range", params: ListParams{Since: &now, Until: &earlier}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseListParams(t *testing.T) {
+	q := url.Values{
+		"actor_id":    {"u1"},
+		"action":      {ActionProjectArchived},
+		"target_type": {TargetProject},
+		"target_id":   {"p1"},
+		"since":       {"2025-01-01T00:00:00Z"},
+		"limit":       {"10"},
+	}
+	params, err := parseListParams(q)
+	if err != nil {
+		t.Fatalf("parseListParams: %v", err)
+	}
+	if params.ActorID != "u1" || params.Action != ActionProjectArchived || params.TargetType != TargetProject ||
+		params.TargetID != "p1" || params.Limit != 10 || params.Until != nil {
+		t.Fatalf("params = %+v", params)
+	}
+	if params.Since == nil || !params.Since.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("since = %v", params.Since)
+	}
+
+	for _, bad := range []url.Values{{"since": {"yesterday"}}, {"until": {"2025-01-01"}}, {"limit": {"ten"}}} {
+		if _, err := parseListParams(bad); err == nil {
+			t.Errorf("parseListParams(%v) should fail", bad)
+		}
+	}
+}
+
+func TestRecord_Validation(t *testing.T) {
+	ctx := context.Background()
+	if err := Record(ctx, nil, Change{Action: ActionProjectArchived, TargetType: TargetProject, TargetID: "p"}); err == nil {
+		t.Fatal("Record with nil tx should fail")
+	}
+}
+
+func TestList_NilDB(t *testing.T) {
+	if _, err := List(context.Background(), nil, ListParams{}); err == nil {
+		t.Fatal("List with nil db should fail")
+	}
+}
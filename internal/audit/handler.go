@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package audit
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("GET /instance/audit-log", handleInstanceList(db))
+	mux.HandleFunc("GET /workspaces/{workspaceID}/audit-log", handleWorkspaceList(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	default:
+		slog.Error("audit handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// handleInstanceList serves the whole log, instance-level actions included,
+// to instance admins. workspace_id narrows it to one workspace.
+func handleInstanceList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := authz.RequireInstanceAdmin(r.Context(), db); err != nil {
+			fail(w, err)
+			return
+		}
+		params, err := parseListParams(r.URL.Query())
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		params.WorkspaceID = r.URL.Query().Get("workspace_id")
+		list(w, r, db, params)
+	}
+}
+
+func handleWorkspaceList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID := r.PathValue("workspaceID")
+		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+			fail(w, err)
+			return
+		}
+		params, err := parseListParams(r.URL.Query())
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		params.WorkspaceID = wsID
+		list(w, r, db, params)
+	}
+}
+
+func list(w http.ResponseWriter, r *http.Request, db *sqlx.DB, params ListParams) {
+	if err := params.Validate(); err != nil {
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	entries, err := List(r.Context(), db, params)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	respond.JSON(w, http.StatusOK, entries)
+}
+
+func parseListParams(q url.Values) (ListParams, error) {
+	params := ListParams{
+		ActorID:    q.Get("actor_id"),
+		Action:     q.Get("action"),
+		TargetType: q.Get("target_type"),
+		TargetID:   q.Get("target_id"),
+	}
+	for name, dst := range map[string]**time.Time{"since": &params.Since, "until": &params.Until} {
+		if s := q.Get(name); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return ListParams{}, errors.New(name + " must be an RFC 3339 timestamp")
+			}
+			*dst = &t
+		}
+	}
+	for name, dst := range map[string]*int{"limit": &params.Limit, "offset": &params.Offset} {
+		if s := q.Get(name); s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return ListParams{}, errors.New(name + " must be an integer")
+			}
+			*dst = v
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const entryCols = `id, workspace_id, actor_id, action, target_type, target_id, before, after, ip, request_id, created_at`
+
+func insertEntry(ctx context.Context, tx *sqlx.Tx, c Change, actorID string, before, after []byte, info requestInfo) error {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (workspace_id, actor_id, action, target_type, target_id, before, after, ip, request_id)
+		 VALUES (NULLIF($1, '')::uuid, NULLIF($2, '')::uuid, $3, $4, $5, $6, $7, $8, $9)`,
+		c.WorkspaceID, actorID, c.Action, c.TargetType, c.TargetID, before, after, info.ip, info.requestID,
+	); err != nil {
+		return fmt.Errorf("insert audit log entry: %w", err)
+	}
+	return nil
+}
+
+func listEntries(ctx context.Context, db *sqlx.DB, params ListParams) ([]Entry, error) {
+	query := `SELECT ` + entryCols + ` FROM audit_log WHERE TRUE`
+	var args []any
+	for _, f := range []struct{ col, value string }{
+		{"workspace_id::text", params.WorkspaceID},
+		{"actor_id::text", params.ActorID},
+		{"action", params.Action},
+		{"target_type", params.TargetType},
+		{"target_id", params.TargetID},
+	} {
+		if f.value != "" {
+			args = append(args, f.value)
+			query += fmt.Sprintf(" AND %s = $%d", f.col, len(args))
+		}
+	}
+	if params.Since != nil {
+		args = append(args, *params.Since)
+		query += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if params.Until != nil {
+		args = append(args, *params.Until)
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+	args = append(args, params.Limit, params.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	entries := []Entry{}
+	if err := db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("list audit log: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func record(t *testing.T, ctx context.Context, db *sqlx.DB, c Change) {
+	t.Helper()
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit audit", func(tx *sqlx.Tx) error {
+		return Record(ctx, tx, c)
+	})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+
+	actorID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	otherWS := testpg.SeedWorkspace(t, db)
+	ctx := WithRequest(authz.WithUserID(context.Background(), actorID), "198.51.100.1", "req-42")
+
+	record(t, ctx, db, Change{WorkspaceID: wsID, Action: ActionProjectArchived, TargetType: TargetProject, TargetID: "p1",
+		Before: map[string]any{"archived_at": nil}, After: map[string]any{"archived_at": "now"}})
+	record(t, ctx, db, Change{WorkspaceID: otherWS, Action: ActionInvitationRevoked, TargetType: TargetInvitation, TargetID: "i1"})
+	record(t, context.Background(), db, Change{Action: ActionSMTPUpdated, TargetType: TargetInstanceConfig, TargetID: "smtp"})
+
+	entries, err := List(context.Background(), db, ListParams{WorkspaceID: wsID})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("workspace entries = %d, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Action != ActionProjectArchived || e.ActorID == nil || *e.ActorID != actorID ||
+		e.IP != "198.51.100.1" || e.RequestID != "req-42" || e.Before == nil || e.After == nil {
+		t.Fatalf("entry = %+v", e)
+	}
+
+	byActor, err := List(context.Background(), db, ListParams{ActorID: actorID})
+	if err != nil {
+		t.Fatalf("List by actor: %v", err)
+	}
+	if len(byActor) != 2 || byActor[0].Action != ActionInvitationRevoked {
+		t.Fatalf("actor entries = %+v, want 2 newest first", byActor)
+	}
+
+	instance, err := List(context.Background(), db, ListParams{Action: ActionSMTPUpdated, TargetID: "smtp", Since: &e.CreatedAt})
+	if err != nil {
+		t.Fatalf("List instance: %v", err)
+	}
+	if len(instance) == 0 || instance[0].WorkspaceID != nil || instance[0].ActorID != nil || instance[0].Before != nil {
+		t.Fatalf("instance entries = %+v", instance)
+	}
+
+	if _, err := db.ExecContext(context.Background(), `UPDATE audit_log SET action = 'x' WHERE id = $1`, e.ID); err == nil {
+		t.Fatal("UPDATE on audit_log should be rejected")
+	}
+	if _, err := db.ExecContext(context.Background(), `DELETE FROM audit_log WHERE id = $1`, e.ID); err == nil {
+		t.Fatal("DELETE on audit_log should be rejected")
+	}
+}
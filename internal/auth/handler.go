@@ -15,6 +15,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/start-codex/tookly/internal/authz"
 	"github.com/start-codex/tookly/internal/email"
+	"github.com/start-codex/tookly/internal/notifications"
 	"github.com/start-codex/tookly/internal/respond"
 	"github.com/start-codex/tookly/internal/sessions"
 )
@@ -157,10 +158,17 @@ func handleMe(db *sqlx.DB) http.HandlerFunc {
 			return
 		}
 		verificationRequired, _ := IsVerificationRequired(r.Context(), db)
+		unread, err := notifications.UnreadCount(r.Context(), db, user.ID)
+		if err != nil {
+			slog.Error("count unread notifications", "error", err)
+			respond.Error(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
 		respond.JSON(w, http.StatusOK, map[string]any{
 			"authenticated":               true,
 			"user":                        user,
 			"email_verification_required": verificationRequired,
+			"unread_notifications":        unread,
 		})
 	}
 }
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/apitokens"
 	"github.com/start-codex/tookly/internal/email"
 	"github.com/start-codex/tookly/internal/pgutil"
 )
@@ -254,24 +255,28 @@ func updatePasswordTx(ctx context.Context, tx *sqlx.Tx, userID, newHash string)
 	return nil
 }
 
+// archiveUser archives the user and revokes their API tokens in one
+// transaction, so no token outlives the account.
 func archiveUser(ctx context.Context, db *sqlx.DB, id string) error {
-	res, err := db.ExecContext(ctx,
-		`UPDATE app_users
-		 SET archived_at = NOW()
-		 WHERE id = $1 AND archived_at IS NULL`,
-		id,
-	)
-	if err != nil {
-		return fmt.Errorf("archive user: %w", err)
-	}
-	n, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("archive user rows affected: %w", err)
-	}
-	if n == 0 {
-		return ErrNotFound
-	}
-	return nil
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit archive user", func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE app_users
+			 SET archived_at = NOW()
+			 WHERE id = $1 AND archived_at IS NULL`,
+			id,
+		)
+		if err != nil {
+			return fmt.Errorf("archive user: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("archive user rows affected: %w", err)
+		}
+		if n == 0 {
+			return ErrNotFound
+		}
+		return apitokens.RevokeAllTx(ctx, tx, id)
+	})
 }
 
 // --- verify token store ---
@@ -13,6 +13,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/apitokens"
 	"github.com/start-codex/tookly/internal/sessions"
 	"github.com/start-codex/tookly/internal/testpg"
 )
@@ -314,3 +315,21 @@ func seedUser(t *testing.T, db *sqlx.DB) User {
 	})
 	return u
 }
+
+func TestArchiveUser_RevokesAPITokens(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	u := seedUser(t, db)
+	created, err := apitokens.Create(ctx, db, apitokens.CreateParams{UserID: u.ID, Name: "ci", Scope: apitokens.ScopeWrite})
+	if err != nil {
+		t.Fatalf("create api token: %v", err)
+	}
+	if err := Archive(ctx, db, u.ID); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if _, err := apitokens.Validate(ctx, db, created.RawToken); !errors.Is(err, apitokens.ErrNotFound) {
+		t.Fatalf("Validate after archive = %v, want ErrNotFound", err)
+	}
+}
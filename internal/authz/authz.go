@@ -22,6 +22,22 @@ var (
 	ErrColumnNotFound    = errors.New("column not found")
 )
 
+// Project roles, in increasing order of privilege.
+const (
+	RoleViewer = "viewer"
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
+var roleRank = map[string]int{RoleViewer: 1, RoleMember: 2, RoleAdmin: 3}
+
+// HasRole reports whether role grants at least minRole. Unknown roles grant
+// nothing.
+func HasRole(role, minRole string) bool {
+	min, ok := roleRank[minRole]
+	return ok && roleRank[role] >= min
+}
+
 type ctxKey struct{}
 
 // WithUserID stores the authenticated user ID in the context.
@@ -181,3 +197,103 @@ func RequireColumnAccess(ctx context.Context, db *sqlx.DB, columnID string) (str
 	}
 	return wsID, projID, bID, nil
 }
+
+// ProjectRole resolves the authenticated user's effective role in a project.
+// Workspace owners and admins are always project admins. Other workspace
+// members get the role of their project_members row, or member if they have
+// none. Returns the resolved workspaceID and role.
+func ProjectRole(ctx context.Context, db *sqlx.DB, projectID string) (string, string, error) {
+	if db == nil {
+		return "", "", errors.New("db is required")
+	}
+	if projectID == "" {
+		return "", "", errors.New("projectID is required")
+	}
+	userID, err := UserIDFromContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	wsID, err := projectWorkspaceID(ctx, db, projectID)
+	if err != nil {
+		return "", "", err
+	}
+	exists, err := workspaceExists(ctx, db, wsID)
+	if err != nil {
+		return "", "", fmt.Errorf("resolve project role: %w", err)
+	}
+	if !exists {
+		return "", "", ErrWorkspaceNotFound
+	}
+	wsRole, err := memberRole(ctx, db, wsID, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if wsRole == "owner" || wsRole == "admin" {
+		return wsID, RoleAdmin, nil
+	}
+	role, err := projectMemberRole(ctx, db, projectID, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if role == "" {
+		role = RoleMember
+	}
+	return wsID, role, nil
+}
+
+// RequireProjectRole verifies that the authenticated user's effective project
+// role (see ProjectRole) is at least minRole. Returns the resolved workspaceID.
+func RequireProjectRole(ctx context.Context, db *sqlx.DB, projectID, minRole string) (string, error) {
+	min, ok := roleRank[minRole]
+	if !ok {
+		return "", fmt.Errorf("unknown project role %q", minRole)
+	}
+	wsID, role, err := ProjectRole(ctx, db, projectID)
+	if err != nil {
+		return "", err
+	}
+	if roleRank[role] < min {
+		return "", ErrForbidden
+	}
+	return wsID, nil
+}
+
+// RequireBoardRole is RequireProjectRole for the project that owns the board.
+// Returns workspaceID and projectID.
+func RequireBoardRole(ctx context.Context, db *sqlx.DB, boardID, minRole string) (string, string, error) {
+	if db == nil {
+		return "", "", errors.New("db is required")
+	}
+	if boardID == "" {
+		return "", "", errors.New("boardID is required")
+	}
+	projID, err := boardProjectID(ctx, db, boardID)
+	if err != nil {
+		return "", "", err
+	}
+	wsID, err := RequireProjectRole(ctx, db, projID, minRole)
+	if err != nil {
+		return "", "", err
+	}
+	return wsID, projID, nil
+}
+
+// RequireColumnRole is RequireProjectRole for the project that owns the
+// column's board. Returns workspaceID, projectID, and boardID.
+func RequireColumnRole(ctx context.Context, db *sqlx.DB, columnID, minRole string) (string, string, string, error) {
+	if db == nil {
+		return "", "", "", errors.New("db is required")
+	}
+	if columnID == "" {
+		return "", "", "", errors.New("columnID is required")
+	}
+	bID, err := columnBoardID(ctx, db, columnID)
+	if err != nil {
+		return "", "", "", err
+	}
+	wsID, projID, err := RequireBoardRole(ctx, db, bID, minRole)
+	if err != nil {
+		return "", "", "", err
+	}
+	return wsID, projID, bID, nil
+}
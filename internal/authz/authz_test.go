@@ -185,3 +185,94 @@ func TestRequireColumnAccess_Guards(t *testing.T) {
 		})
 	}
 }
+
+func TestRequireProjectRole_Guards(t *testing.T) {
+	ctx := WithUserID(context.Background(), "user-1")
+	tests := []struct {
+		name      string
+		db        *sqlx.DB
+		projectID string
+		minRole   string
+		wantErr   string
+	}{
+		{name: "nil db", db: nil, projectID: "p-1", minRole: RoleMember, wantErr: "db is required"},
+		{name: "empty projectID", db: fakeDB(t), projectID: "", minRole: RoleMember, wantErr: "projectID is required"},
+		{name: "unknown role", db: fakeDB(t), projectID: "p-1", minRole: "owner", wantErr: `unknown project role "owner"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := RequireProjectRole(ctx, tc.db, tc.projectID, tc.minRole)
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequireProjectRole_NoContext(t *testing.T) {
+	_, err := RequireProjectRole(context.Background(), fakeDB(t), "p-1", RoleViewer)
+	if !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("error = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestRequireBoardRole_Guards(t *testing.T) {
+	ctx := WithUserID(context.Background(), "user-1")
+	tests := []struct {
+		name    string
+		db      *sqlx.DB
+		boardID string
+		wantErr string
+	}{
+		{name: "nil db", db: nil, boardID: "b-1", wantErr: "db is required"},
+		{name: "empty boardID", db: fakeDB(t), boardID: "", wantErr: "boardID is required"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := RequireBoardRole(ctx, tc.db, tc.boardID, RoleAdmin)
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequireColumnRole_Guards(t *testing.T) {
+	ctx := WithUserID(context.Background(), "user-1")
+	tests := []struct {
+		name     string
+		db       *sqlx.DB
+		columnID string
+		wantErr  string
+	}{
+		{name: "nil db", db: nil, columnID: "c-1", wantErr: "db is required"},
+		{name: "empty columnID", db: fakeDB(t), columnID: "", wantErr: "columnID is required"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := RequireColumnRole(ctx, tc.db, tc.columnID, RoleAdmin)
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	tests := []struct {
+		role, min string
+		want      bool
+	}{
+		{RoleAdmin, RoleMember, true},
+		{RoleMember, RoleMember, true},
+		{RoleViewer, RoleMember, false},
+		{RoleMember, RoleAdmin, false},
+		{"", RoleViewer, false},
+		{RoleAdmin, "owner", false},
+	}
+	for _, tt := range tests {
+		if got := HasRole(tt.role, tt.min); got != tt.want {
+			t.Errorf("HasRole(%q, %q) = %v, want %v", tt.role, tt.min, got, tt.want)
+		}
+	}
+}
@@ -52,6 +52,23 @@ func memberRole(ctx context.Context, db *sqlx.DB, workspaceID, userID string) (s
 	return role, nil
 }
 
+// projectMemberRole returns the user's project_members role, or "" if the user
+// has no active row for the project.
+func projectMemberRole(ctx context.Context, db *sqlx.DB, projectID, userID string) (string, error) {
+	var role string
+	err := db.GetContext(ctx, &role,
+		`SELECT role FROM project_members WHERE project_id = $1 AND user_id = $2 AND archived_at IS NULL`,
+		projectID, userID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("get project member role: %w", err)
+	}
+	return role, nil
+}
+
 func isInstanceAdmin(ctx context.Context, db *sqlx.DB, userID string) (bool, error) {
 	var isAdmin bool
 	err := db.GetContext(ctx, &isAdmin,
@@ -258,6 +258,79 @@ func TestRequireProjectMembership_Integration(t *testing.T) {
 	}
 }
 
+func seedProjectMember(t *testing.T, db *sqlx.DB, projectID, userID, role string) {
+	t.Helper()
+	_, err := db.ExecContext(context.Background(),
+		`INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, $3)`,
+		projectID, userID, role,
+	)
+	if err != nil {
+		t.Fatalf("seed project member: %v", err)
+	}
+}
+
+func TestProjectRole_Integration(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+
+	owner := testpg.SeedUser(t, db)
+	wsAdmin := testpg.SeedUser(t, db)
+	wsAdminViewer := testpg.SeedUser(t, db)
+	plain := testpg.SeedUser(t, db)
+	projAdmin := testpg.SeedUser(t, db)
+	viewer := testpg.SeedUser(t, db)
+	nonMember := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	seedMember(t, db, wsID, owner, "owner")
+	seedMember(t, db, wsID, wsAdmin, "admin")
+	seedMember(t, db, wsID, wsAdminViewer, "admin")
+	seedMember(t, db, wsID, plain, "member")
+	seedMember(t, db, wsID, projAdmin, "member")
+	seedMember(t, db, wsID, viewer, "member")
+	projID := testpg.SeedProject(t, db, wsID, "ROLE")
+	seedProjectMember(t, db, projID, wsAdminViewer, RoleViewer)
+	seedProjectMember(t, db, projID, projAdmin, RoleAdmin)
+	seedProjectMember(t, db, projID, viewer, RoleViewer)
+
+	tests := []struct {
+		name     string
+		userID   string
+		wantRole string
+		wantErr  error
+	}{
+		{name: "workspace owner is project admin", userID: owner, wantRole: RoleAdmin},
+		{name: "workspace admin is project admin", userID: wsAdmin, wantRole: RoleAdmin},
+		{name: "workspace admin ignores project row", userID: wsAdminViewer, wantRole: RoleAdmin},
+		{name: "member without project row", userID: plain, wantRole: RoleMember},
+		{name: "project admin row", userID: projAdmin, wantRole: RoleAdmin},
+		{name: "project viewer row", userID: viewer, wantRole: RoleViewer},
+		{name: "non-member forbidden", userID: nonMember, wantErr: ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithUserID(context.Background(), tt.userID)
+			gotWS, role, err := ProjectRole(ctx, db, projID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if gotWS != wsID || role != tt.wantRole {
+				t.Fatalf("ProjectRole() = (%q, %q), want (%q, %q)", gotWS, role, wsID, tt.wantRole)
+			}
+		})
+	}
+
+	ctx := WithUserID(context.Background(), viewer)
+	if _, err := RequireProjectRole(ctx, db, projID, RoleViewer); err != nil {
+		t.Fatalf("viewer RequireProjectRole(viewer) error = %v", err)
+	}
+	if _, err := RequireProjectRole(ctx, db, projID, RoleMember); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("viewer RequireProjectRole(member) error = %v, want ErrForbidden", err)
+	}
+}
+
 func TestRequireBoardAccess_Integration(t *testing.T) {
 	db := testpg.Open(t)
 	testpg.EnsureMigrated(t, db)
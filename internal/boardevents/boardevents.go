@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package boardevents streams issue changes to open boards over Server-Sent
+// Events. Producers call Publish inside their own transaction, which appends
+// a row to board_events; a trigger NOTIFYs the board_events channel on
+// commit, and the Hub of every server replica LISTENs and fans the event out
+// to the streams of the issue's project. Event IDs are board_events ids, so
+// a reconnecting client resumes with Last-Event-ID.
+package boardevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Channel is the Postgres NOTIFY channel fed by the board_events trigger.
+const Channel = "board_events"
+
+// Retention is how long events stay available for resuming.
+const Retention = 24 * time.Hour
+
+// Event is one stored board event. Data is the JSON sent as the SSE data.
+type Event struct {
+	ID        int64           `db:"id"`
+	ProjectID string          `db:"project_id"`
+	IssueID   string          `db:"issue_id"`
+	Type      string          `db:"event_type"`
+	Data      json.RawMessage `db:"payload"`
+	CreatedAt time.Time       `db:"created_at"`
+}
+
+// Publish appends an event for the project's boards inside the caller's
+// transaction; subscribers receive it once the transaction commits.
+func Publish(ctx context.Context, tx *sqlx.Tx, projectID, issueID, eventType string, data any) error {
+	if tx == nil {
+		return errors.New("tx is required")
+	}
+	if projectID == "" || issueID == "" {
+		return errors.New("project_id and issue_id are required")
+	}
+	if eventType == "" {
+		return errors.New("event_type is required")
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return insertEvent(ctx, tx, projectID, issueID, eventType, payload)
+}
+
+// parseNotification splits a "project_id:event_id" NOTIFY payload.
+func parseNotification(extra string) (string, int64, error) {
+	projectID, idStr, ok := strings.Cut(extra, ":")
+	if !ok || projectID == "" {
+		return "", 0, errors.New("malformed board event notification")
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return "", 0, errors.New("malformed board event notification")
+	}
+	return projectID, id, nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package boardevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublish_NilTx(t *testing.T) {
+	if err := Publish(context.Background(), nil, "p", "i", "issue.moved", nil); err == nil {
+		t.Fatal("expected error for nil tx")
+	}
+}
+
+func TestParseNotification(t *testing.T) {
+	projectID, id, err := parseNotification("b7c1:42")
+	if err != nil || projectID != "b7c1" || id != 42 {
+		t.Fatalf("parseNotification = %q, %d, %v", projectID, id, err)
+	}
+	for _, bad := range []string{"", "b7c1", ":42", "b7c1:", "b7c1:x"} {
+		if _, _, err := parseNotification(bad); err == nil {
+			t.Fatalf("parseNotification(%q): expected error", bad)
+		}
+	}
+}
+
+func TestLastEventID(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		query   string
+		want    int64
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "header", header: "12", want: 12},
+		{name: "query", query: "7", want: 7},
+		{name: "header wins", header: "12", query: "7", want: 12},
+		{name: "not a number", header: "abc", wantErr: true},
+		{name: "negative", query: "-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/boards/b/events"
+			if tt.query != "" {
+				target += "?last_event_id=" + tt.query
+			}
+			r := httptest.NewRequest("GET", target, nil)
+			if tt.header != "" {
+				r.Header.Set("Last-Event-ID", tt.header)
+			}
+			got, err := lastEventID(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lastEventID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("lastEventID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteEvent(t *testing.T) {
+	var buf bytes.Buffer
+	writeEvent(&buf, Event{ID: 9, Type: "issue.moved", Data: json.RawMessage(`{"key":"P-1"}`)})
+	if want := "id: 9\nevent: issue.moved\ndata: {\"key\":\"P-1\"}\n\n"; buf.String() != want {
+		t.Fatalf("writeEvent = %q, want %q", buf.String(), want)
+	}
+	buf.Reset()
+	writeReset(&buf, 15)
+	if want := "id: 15\nevent: reset\ndata: {}\n\n"; buf.String() != want {
+		t.Fatalf("writeReset = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestHub_Dispatch(t *testing.T) {
+	h := NewHub(nil, "")
+	a := h.subscribe("p1")
+	b := h.subscribe("p2")
+	if !h.hasSubscribers("p1") || h.hasSubscribers("p3") {
+		t.Fatal("hasSubscribers does not reflect subscriptions")
+	}
+
+	h.dispatch(Event{ID: 1, ProjectID: "p1"})
+	if ev := <-a.ch; ev.ID != 1 {
+		t.Fatalf("subscriber got event %d, want 1", ev.ID)
+	}
+	if len(b.ch) != 0 {
+		t.Fatal("event leaked to another project's subscriber")
+	}
+
+	h.unsubscribe("p1", a)
+	if _, ok := <-a.ch; ok {
+		t.Fatal("unsubscribe did not close the channel")
+	}
+	h.unsubscribe("p1", a) // a second unsubscribe must not panic
+	if h.hasSubscribers("p1") {
+		t.Fatal("unsubscribed project still has subscribers")
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	h := NewHub(nil, "")
+	s := h.subscribe("p")
+	for i := range subscriberBuffer + 1 {
+		h.dispatch(Event{ID: int64(i + 1), ProjectID: "p"})
+	}
+	n := 0
+	for range s.ch {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Fatalf("slow subscriber received %d events before being dropped, want %d", n, subscriberBuffer)
+	}
+	if h.hasSubscribers("p") {
+		t.Fatal("slow subscriber was not removed")
+	}
+	h.unsubscribe("p", s)
+}
+
+func TestHub_Close(t *testing.T) {
+	h := NewHub(nil, "")
+	s := h.subscribe("p")
+	h.close()
+	if _, ok := <-s.ch; ok {
+		t.Fatal("close did not end open subscriptions")
+	}
+	if h.subscribe("p") != nil {
+		t.Fatal("subscribe after close should return nil")
+	}
+}
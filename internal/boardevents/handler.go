@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package boardevents
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+const (
+	heartbeatInterval = 25 * time.Second
+	retryMillis       = 3000
+	// replayLimit bounds a resume; beyond it the client is told to reload.
+	replayLimit = 500
+)
+
+// RegisterRoutes registers the board event stream. Without a running hub
+// the stream answers 503.
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB, hub *Hub) {
+	mux.HandleFunc("GET /boards/{boardID}/events", handleStream(db, hub))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrBoardNotFound),
+		errors.Is(err, authz.ErrProjectNotFound),
+		errors.Is(err, authz.ErrWorkspaceNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	default:
+		slog.Error("boardevents handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// handleStream serves issue.created, issue.updated, issue.moved and
+// issue.archived events for the board's project. Clients resume with the
+// Last-Event-ID header, or the last_event_id query parameter on first
+// connect; a "reset" event means the gap was too large to replay and the
+// board must be reloaded.
+func handleStream(db *sqlx.DB, hub *Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, projectID, err := authz.RequireBoardAccess(r.Context(), db, r.PathValue("boardID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		lastID, err := lastEventID(r)
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Subscribe before replaying so nothing committed in between is lost.
+		var sub *subscriber
+		if hub != nil {
+			sub = hub.subscribe(projectID)
+		}
+		if sub == nil {
+			respond.Error(w, http.StatusServiceUnavailable, "real-time events unavailable")
+			return
+		}
+		defer hub.unsubscribe(projectID, sub)
+
+		// Streams outlive the server's write timeout.
+		rc := http.NewResponseController(w)
+		_ = rc.SetWriteDeadline(time.Time{})
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", retryMillis)
+
+		replayed := map[int64]bool{}
+		if lastID > 0 {
+			events, err := listAfter(r.Context(), db, projectID, lastID, replayLimit+1)
+			if err != nil {
+				slog.Error("board events replay failed", "error", err)
+				return
+			}
+			if len(events) > replayLimit {
+				latest, err := maxEventID(r.Context(), db)
+				if err != nil {
+					slog.Error("board events replay failed", "error", err)
+					return
+				}
+				writeReset(w, latest)
+				events = nil
+			}
+			for _, ev := range events {
+				writeEvent(w, ev)
+				replayed[ev.ID] = true
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case ev, ok := <-sub.ch:
+				if !ok {
+					return
+				}
+				if replayed[ev.ID] {
+					continue
+				}
+				writeEvent(w, ev)
+			case <-heartbeat.C:
+				io.WriteString(w, ": ping\n\n")
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func lastEventID(r *http.Request) (int64, error) {
+	s := r.Header.Get("Last-Event-ID")
+	if s == "" {
+		s = r.URL.Query().Get("last_event_id")
+	}
+	if s == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("last event id must be a non-negative integer")
+	}
+	return id, nil
+}
+
+func writeEvent(w io.Writer, ev Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, ev.Data)
+}
+
+// writeReset tells the client to reload the board, moving its resume
+// point to latest.
+func writeReset(w io.Writer, latest int64) {
+	fmt.Fprintf(w, "id: %d\nevent: reset\ndata: {}\n\n", latest)
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package boardevents
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	subscriberBuffer = 64
+	catchUpLimit     = 1000
+	pingInterval     = 90 * time.Second
+	pruneInterval    = time.Hour
+)
+
+// Hub fans board events out to the streams open on this server. It holds
+// one LISTEN connection, opened from dsn, for its whole lifetime.
+type Hub struct {
+	db  *sqlx.DB
+	dsn string
+
+	mu     sync.Mutex
+	subs   map[string]map[*subscriber]struct{}
+	closed bool
+}
+
+// subscriber is one open stream. ch is closed when the subscriber falls
+// behind; the client then reconnects and resumes from the database.
+type subscriber struct {
+	ch chan Event
+}
+
+func NewHub(db *sqlx.DB, dsn string) *Hub {
+	return &Hub{db: db, dsn: dsn, subs: map[string]map[*subscriber]struct{}{}}
+}
+
+// Run listens for notifications until ctx is done, then ends every open
+// stream so server shutdown is not held up by them.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.close()
+	listener := pq.NewListener(h.dsn, time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("board events listener", "error", err)
+		}
+	})
+	defer listener.Close()
+	if err := listener.Listen(Channel); err != nil {
+		slog.Error("board events listen failed", "error", err)
+		return
+	}
+	lastID, err := maxEventID(ctx, h.db)
+	if err != nil {
+		slog.Error("board events start failed", "error", err)
+		return
+	}
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	prune := time.NewTicker(pruneInterval)
+	defer prune.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-listener.Notify:
+			if n == nil {
+				// The connection was re-established; notifications sent in
+				// between are lost, so replay from the table.
+				lastID = h.catchUp(ctx, lastID)
+				continue
+			}
+			projectID, id, err := parseNotification(n.Extra)
+			if err != nil {
+				slog.Warn("board events notification", "error", err, "payload", n.Extra)
+				continue
+			}
+			lastID = max(lastID, id)
+			if !h.hasSubscribers(projectID) {
+				continue
+			}
+			ev, err := getEvent(ctx, h.db, id)
+			if err != nil {
+				slog.Error("board events load failed", "error", err)
+				continue
+			}
+			h.dispatch(ev)
+		case <-ping.C:
+			if err := listener.Ping(); err != nil {
+				slog.Warn("board events listener ping", "error", err)
+			}
+		case <-prune.C:
+			if err := pruneEvents(ctx, h.db, Retention); err != nil {
+				slog.Error("board events prune failed", "error", err)
+			}
+		}
+	}
+}
+
+func (h *Hub) catchUp(ctx context.Context, lastID int64) int64 {
+	events, err := listAfter(ctx, h.db, "", lastID, catchUpLimit)
+	if err != nil {
+		slog.Error("board events catch-up failed", "error", err)
+		return lastID
+	}
+	for _, ev := range events {
+		h.dispatch(ev)
+		lastID = ev.ID
+	}
+	return lastID
+}
+
+// subscribe returns nil once the hub has stopped.
+func (h *Hub) subscribe(projectID string) *subscriber {
+	s := &subscriber{ch: make(chan Event, subscriberBuffer)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	if h.subs[projectID] == nil {
+		h.subs[projectID] = map[*subscriber]struct{}{}
+	}
+	h.subs[projectID][s] = struct{}{}
+	return s
+}
+
+func (h *Hub) unsubscribe(projectID string, s *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[projectID][s]; !ok {
+		return
+	}
+	delete(h.subs[projectID], s)
+	if len(h.subs[projectID]) == 0 {
+		delete(h.subs, projectID)
+	}
+	close(s.ch)
+}
+
+func (h *Hub) hasSubscribers(projectID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs[projectID]) > 0
+}
+
+// dispatch hands ev to every subscriber of its project without blocking.
+// Subscribers whose buffer is full are dropped.
+func (h *Hub) dispatch(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subs[ev.ProjectID] {
+		select {
+		case s.ch <- ev:
+		default:
+			delete(h.subs[ev.ProjectID], s)
+			close(s.ch)
+		}
+	}
+	if len(h.subs[ev.ProjectID]) == 0 {
+		delete(h.subs, ev.ProjectID)
+	}
+}
+
+func (h *Hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for projectID, subs := range h.subs {
+		for s := range subs {
+			close(s.ch)
+		}
+		delete(h.subs, projectID)
+	}
+}
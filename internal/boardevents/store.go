@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package boardevents
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const eventCols = `id, project_id, issue_id, event_type, payload, created_at`
+
+func insertEvent(ctx context.Context, tx *sqlx.Tx, projectID, issueID, eventType string, payload []byte) error {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO board_events (project_id, issue_id, event_type, payload)
+		 VALUES ($1, $2, $3, $4)`,
+		projectID, issueID, eventType, payload,
+	); err != nil {
+		return fmt.Errorf("insert board event: %w", err)
+	}
+	return nil
+}
+
+func getEvent(ctx context.Context, db *sqlx.DB, id int64) (Event, error) {
+	var ev Event
+	if err := db.GetContext(ctx, &ev,
+		`SELECT `+eventCols+` FROM board_events WHERE id = $1`,
+		id,
+	); err != nil {
+		return Event{}, fmt.Errorf("get board event: %w", err)
+	}
+	return ev, nil
+}
+
+// listAfter returns up to limit events with an id above afterID, oldest
+// first. An empty projectID matches every project.
+func listAfter(ctx context.Context, db *sqlx.DB, projectID string, afterID int64, limit int) ([]Event, error) {
+	events := []Event{}
+	if err := db.SelectContext(ctx, &events,
+		`SELECT `+eventCols+`
+		 FROM board_events
+		 WHERE id > $1
+		   AND ($2 = '' OR project_id::text = $2)
+		 ORDER BY id
+		 LIMIT $3`,
+		afterID, projectID, limit,
+	); err != nil {
+		return nil, fmt.Errorf("list board events: %w", err)
+	}
+	return events, nil
+}
+
+func maxEventID(ctx context.Context, db *sqlx.DB) (int64, error) {
+	var id int64
+	if err := db.GetContext(ctx, &id, `SELECT COALESCE(MAX(id), 0) FROM board_events`); err != nil {
+		return 0, fmt.Errorf("max board event id: %w", err)
+	}
+	return id, nil
+}
+
+func pruneEvents(ctx context.Context, db *sqlx.DB, olderThan time.Duration) error {
+	if _, err := db.ExecContext(ctx,
+		`DELETE FROM board_events WHERE created_at < NOW() - make_interval(secs => $1)`,
+		olderThan.Seconds(),
+	); err != nil {
+		return fmt.Errorf("prune board events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package boardevents
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+type seed struct {
+	projectID string
+	issueID   string
+}
+
+func seedIssue(t *testing.T, db *sqlx.DB) seed {
+	t.Helper()
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	projectID := testpg.SeedProject(t, db, wsID, "EVT")
+	var s seed
+	s.projectID = projectID
+	var statusID, typeID string
+	if err := db.Get(&statusID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'Todo', 'todo', 0) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	if err := db.Get(&typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert issue type: %v", err)
+	}
+	if err := db.Get(&s.issueID,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, 1, $2, $3, 'Event', '', 'medium', $4, 0) RETURNING id`,
+		projectID, typeID, statusID, userID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+	return s
+}
+
+func publish(t *testing.T, db *sqlx.DB, s seed, eventType string) {
+	t.Helper()
+	err := pgutil.WithTx(context.Background(), db, nil, "begin tx", "commit publish", func(tx *sqlx.Tx) error {
+		return Publish(context.Background(), tx, s.projectID, s.issueID, eventType, map[string]string{"issue_id": s.issueID})
+	})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+func TestPublishAndReplay(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	s := seedIssue(t, db)
+
+	before, err := maxEventID(ctx, db)
+	if err != nil {
+		t.Fatalf("maxEventID: %v", err)
+	}
+	publish(t, db, s, "issue.created")
+	publish(t, db, s, "issue.moved")
+
+	events, err := listAfter(ctx, db, s.projectID, before, 10)
+	if err != nil {
+		t.Fatalf("listAfter: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != "issue.created" || events[1].Type != "issue.moved" {
+		t.Fatalf("listAfter = %+v, want created then moved", events)
+	}
+	resumed, err := listAfter(ctx, db, s.projectID, events[0].ID, 10)
+	if err != nil {
+		t.Fatalf("listAfter resume: %v", err)
+	}
+	if len(resumed) != 1 || resumed[0].ID != events[1].ID {
+		t.Fatalf("resume from %d = %+v, want only %d", events[0].ID, resumed, events[1].ID)
+	}
+	other, err := listAfter(ctx, db, "00000000-0000-0000-0000-000000000000", before, 10)
+	if err != nil {
+		t.Fatalf("listAfter other project: %v", err)
+	}
+	if len(other) != 0 {
+		t.Fatalf("other project got %d events, want 0", len(other))
+	}
+}
+
+func TestHub_Run(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	s := seedIssue(t, db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := NewHub(db, os.Getenv("MINI_JIRA_TEST_DSN"))
+	done := make(chan struct{})
+	go func() {
+		hub.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	sub := hub.subscribe(s.projectID)
+	if sub == nil {
+		t.Fatal("subscribe returned nil on a running hub")
+	}
+	// The listener connects asynchronously; publish until the first event
+	// arrives.
+	deadline := time.After(10 * time.Second)
+	tick := time.NewTicker(200 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		publish(t, db, s, "issue.updated")
+		select {
+		case ev := <-sub.ch:
+			if ev.ProjectID != s.projectID || ev.IssueID != s.issueID || ev.Type != "issue.updated" {
+				t.Fatalf("received %+v", ev)
+			}
+			return
+		case <-tick.C:
+		case <-deadline:
+			t.Fatal("no event received from the hub")
+		}
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/filterquery"
+	"github.com/start-codex/tookly/internal/issues"
 )
 
 var (
@@ -43,6 +45,12 @@ type Column struct {
 	ArchivedAt *time.Time `db:"archived_at" json:"archived_at,omitempty"`
 }
 
+// ColumnIssues is a board column with the issues whose status is mapped to it.
+type ColumnIssues struct {
+	Column Column         `json:"column"`
+	Issues []issues.Issue `json:"issues"`
+}
+
 type CreateParams struct {
 	ProjectID   string
 	Name        string
@@ -60,7 +68,7 @@ func (params CreateParams) Validate() error {
 	if !validBoardTypes[params.Type] {
 		return errors.New("type must be 'kanban' or 'scrum'")
 	}
-	return nil
+	return filterquery.Validate(params.FilterQuery)
 }
 
 type AddColumnParams struct {
@@ -173,3 +181,17 @@ func UnassignStatus(ctx context.Context, db *sqlx.DB, boardColumnID, statusID st
 	}
 	return unassignStatus(ctx, db, boardColumnID, statusID)
 }
+
+// ListIssues returns the board's columns in order, each with the active issues
+// whose status is mapped to it and that match the board's filter query.
+// viewerID resolves "me" in the filter. A stored filter query that no longer
+// parses is logged and ignored.
+func ListIssues(ctx context.Context, db *sqlx.DB, boardID, viewerID string) ([]ColumnIssues, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if boardID == "" {
+		return nil, errors.New("board_id is required")
+	}
+	return listBoardIssues(ctx, db, boardID, viewerID)
+}
@@ -46,6 +46,16 @@ func TestCreateBoardParams_Validate(t *testing.T) {
 			params:  CreateParams{ProjectID: "proj-1", Name: "Main Board", Type: ""},
 			wantErr: true,
 		},
+		{
+			name:    "valid filter query",
+			params:  CreateParams{ProjectID: "proj-1", Name: "Mine", Type: "kanban", FilterQuery: "assignee:me priority:high,critical"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid filter query",
+			params:  CreateParams{ProjectID: "proj-1", Name: "Mine", Type: "kanban", FilterQuery: "assignee:me AND ("},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -153,3 +163,10 @@ func TestUnassignStatus_NilDB(t *testing.T) {
 		t.Fatalf("UnassignStatus() error = %v, want %q", err, "db is required")
 	}
 }
+
+func TestListBoardIssues_NilDB(t *testing.T) {
+	_, err := ListIssues(context.Background(), nil, "board-1", "user-1")
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("ListIssues() error = %v, want %q", err, "db is required")
+	}
+}
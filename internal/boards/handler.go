@@ -11,6 +11,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/filterquery"
 	"github.com/start-codex/tookly/internal/respond"
 )
 
@@ -21,6 +22,7 @@ func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
 	mux.HandleFunc("DELETE /boards/{boardID}", handleArchive(db))
 	mux.HandleFunc("POST /boards/{boardID}/columns", handleAddColumn(db))
 	mux.HandleFunc("GET /boards/{boardID}/columns", handleListColumns(db))
+	mux.HandleFunc("GET /boards/{boardID}/issues", handleListIssues(db))
 	mux.HandleFunc("DELETE /columns/{columnID}", handleArchiveColumn(db))
 	mux.HandleFunc("POST /columns/{columnID}/statuses", handleAssignStatus(db))
 	mux.HandleFunc("DELETE /columns/{columnID}/statuses/{statusID}", handleUnassignStatus(db))
@@ -41,6 +43,8 @@ func fail(w http.ResponseWriter, err error) {
 		respond.Error(w, http.StatusNotFound, err.Error())
 	case errors.Is(err, ErrDuplicateName), errors.Is(err, ErrDuplicateColumnName):
 		respond.Error(w, http.StatusConflict, err.Error())
+	case errors.As(err, new(*filterquery.SyntaxError)):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
 	default:
 		respond.Error(w, http.StatusInternalServerError, "internal server error")
 	}
@@ -49,12 +53,7 @@ func fail(w http.ResponseWriter, err error) {
 func handleCreate(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		projID := r.PathValue("projectID")
-		wsID, err := authz.RequireProjectMembership(r.Context(), db, projID)
-		if err != nil {
-			fail(w, err)
-			return
-		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -121,12 +120,7 @@ func handleGet(db *sqlx.DB) http.HandlerFunc {
 func handleArchive(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		boardID := r.PathValue("boardID")
-		wsID, _, err := authz.RequireBoardAccess(r.Context(), db, boardID)
-		if err != nil {
-			fail(w, err)
-			return
-		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		if _, _, err := authz.RequireBoardRole(r.Context(), db, boardID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -141,12 +135,7 @@ func handleArchive(db *sqlx.DB) http.HandlerFunc {
 func handleAddColumn(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		boardID := r.PathValue("boardID")
-		wsID, _, err := authz.RequireBoardAccess(r.Context(), db, boardID)
-		if err != nil {
-			fail(w, err)
-			return
-		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		if _, _, err := authz.RequireBoardRole(r.Context(), db, boardID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -187,15 +176,31 @@ func handleListColumns(db *sqlx.DB) http.HandlerFunc {
 	}
 }
 
-func handleArchiveColumn(db *sqlx.DB) http.HandlerFunc {
+func handleListIssues(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		colID := r.PathValue("columnID")
-		wsID, _, _, err := authz.RequireColumnAccess(r.Context(), db, colID)
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardAccess(r.Context(), db, boardID); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
 		if err != nil {
 			fail(w, err)
 			return
 		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		list, err := ListIssues(r.Context(), db, boardID, authedUserID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+func handleArchiveColumn(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		colID := r.PathValue("columnID")
+		if _, _, _, err := authz.RequireColumnRole(r.Context(), db, colID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -210,12 +215,7 @@ func handleArchiveColumn(db *sqlx.DB) http.HandlerFunc {
 func handleAssignStatus(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		colID := r.PathValue("columnID")
-		wsID, _, _, err := authz.RequireColumnAccess(r.Context(), db, colID)
-		if err != nil {
-			fail(w, err)
-			return
-		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		if _, _, _, err := authz.RequireColumnRole(r.Context(), db, colID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -237,12 +237,7 @@ func handleAssignStatus(db *sqlx.DB) http.HandlerFunc {
 func handleUnassignStatus(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		colID := r.PathValue("columnID")
-		wsID, _, _, err := authz.RequireColumnAccess(r.Context(), db, colID)
-		if err != nil {
-			fail(w, err)
-			return
-		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		if _, _, _, err := authz.RequireColumnRole(r.Context(), db, colID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -10,35 +10,61 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/filterquery"
+	"github.com/start-codex/tookly/internal/issues"
 	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/webhooks"
 )
 
 const boardCols = `id, project_id, name, type, filter_query, created_at, updated_at, archived_at`
 const columnCols = `id, board_id, name, position, created_at, updated_at, archived_at`
 
+const boardIssueCols = `i.id, i.project_id, i.number, i.issue_type_id, i.status_id, i.parent_issue_id, i.sprint_id,
+	i.title, i.description, i.priority, i.assignee_id, i.reporter_id, i.due_date,
+	i.original_estimate_minutes, i.remaining_estimate_minutes, i.external_ref,
+	i.status_position, i.version, i.created_at, i.updated_at, i.archived_at`
+
 func createBoard(ctx context.Context, db *sqlx.DB, params CreateParams) (Board, error) {
 	var board Board
-	err := db.QueryRowxContext(
-		ctx,
-		`INSERT INTO boards (project_id, name, type, filter_query)
-		 VALUES ($1, $2, $3, $4)
-		 RETURNING `+boardCols,
-		params.ProjectID,
-		params.Name,
-		params.Type,
-		params.FilterQuery,
-	).StructScan(&board)
-	if err != nil {
-		if pgutil.IsUniqueViolation(err) {
-			return Board{}, ErrDuplicateName
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create board", func(tx *sqlx.Tx) error {
+		if err := tx.QueryRowxContext(
+			ctx,
+			`INSERT INTO boards (project_id, name, type, filter_query)
+			 VALUES ($1, $2, $3, $4)
+			 RETURNING `+boardCols,
+			params.ProjectID,
+			params.Name,
+			params.Type,
+			params.FilterQuery,
+		).StructScan(&board); err != nil {
+			if pgutil.IsUniqueViolation(err) {
+				return ErrDuplicateName
+			}
+			return fmt.Errorf("insert board: %w", err)
 		}
-		return Board{}, fmt.Errorf("insert board: %w", err)
+		return enqueueWebhook(ctx, tx, webhooks.EventBoardCreated, board)
+	})
+	if err != nil {
+		return Board{}, err
 	}
 	return board, nil
 }
 
+// enqueueWebhook queues a board.* webhook with the board as its data.
+func enqueueWebhook(ctx context.Context, tx *sqlx.Tx, eventType string, board Board) error {
+	var workspaceID string
+	if err := tx.GetContext(ctx, &workspaceID,
+		`SELECT workspace_id FROM projects WHERE id = $1`,
+		board.ProjectID,
+	); err != nil {
+		return fmt.Errorf("load board workspace: %w", err)
+	}
+	return webhooks.Enqueue(ctx, tx, webhooks.Event{WorkspaceID: workspaceID, Type: eventType, Data: board})
+}
+
 func getBoard(ctx context.Context, db *sqlx.DB, id string) (Board, error) {
 	var board Board
 	err := db.GetContext(
@@ -77,25 +103,24 @@ func listBoards(ctx context.Context, db *sqlx.DB, projectID string) ([]Board, er
 }
 
 func archiveBoard(ctx context.Context, db *sqlx.DB, id string) error {
-	res, err := db.ExecContext(
-		ctx,
-		`UPDATE boards
-		 SET archived_at = NOW()
-		 WHERE id = $1
-		   AND archived_at IS NULL`,
-		id,
-	)
-	if err != nil {
-		return fmt.Errorf("archive board: %w", err)
-	}
-	n, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("archive board rows affected: %w", err)
-	}
-	if n == 0 {
-		return ErrNotFound
-	}
-	return nil
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit archive board", func(tx *sqlx.Tx) error {
+		var board Board
+		if err := tx.QueryRowxContext(
+			ctx,
+			`UPDATE boards
+			 SET archived_at = NOW()
+			 WHERE id = $1
+			   AND archived_at IS NULL
+			 RETURNING `+boardCols,
+			id,
+		).StructScan(&board); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("archive board: %w", err)
+		}
+		return enqueueWebhook(ctx, tx, webhooks.EventBoardArchived, board)
+	})
 }
 
 func addColumn(ctx context.Context, db *sqlx.DB, params AddColumnParams) (Column, error) {
@@ -191,3 +216,69 @@ func unassignStatus(ctx context.Context, db *sqlx.DB, boardColumnID, statusID st
 	}
 	return nil
 }
+
+type boardIssueRow struct {
+	BoardColumnID string `db:"board_column_id"`
+	issues.Issue
+}
+
+func listBoardIssues(ctx context.Context, db *sqlx.DB, boardID, viewerID string) ([]ColumnIssues, error) {
+	board, err := getBoard(ctx, db, boardID)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := listColumns(ctx, db, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Boards saved before filter queries were validated may hold free text
+	// that no longer parses; treat those as unfiltered rather than failing.
+	node, err := filterquery.Parse(board.FilterQuery)
+	if err != nil {
+		slog.Warn("ignoring invalid board filter query", "board_id", boardID, "error", err)
+		node = nil
+	}
+	filter, args, err := filterquery.Compile(node, filterquery.Env{Alias: "i", UserID: viewerID}, []any{boardID, board.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []boardIssueRow{}
+	if err := db.SelectContext(ctx, &rows,
+		`SELECT bc.id AS board_column_id, `+boardIssueCols+`
+		 FROM board_columns bc
+		 JOIN board_column_statuses bcs ON bcs.board_column_id = bc.id
+		 JOIN statuses s ON s.id = bcs.status_id
+		 JOIN issues i ON i.status_id = bcs.status_id
+		 WHERE bc.board_id = $1
+		   AND bc.archived_at IS NULL
+		   AND i.project_id = $2
+		   AND i.archived_at IS NULL
+		   AND (`+filter+`)
+		 ORDER BY bc.position ASC, s.position ASC, i.status_position ASC`,
+		args...,
+	); err != nil {
+		return nil, fmt.Errorf("list board issues: %w", err)
+	}
+
+	out := make([]ColumnIssues, len(columns))
+	index := make(map[string]int, len(columns))
+	for i, col := range columns {
+		out[i] = ColumnIssues{Column: col, Issues: []issues.Issue{}}
+		index[col.ID] = i
+	}
+	list := make([]issues.Issue, len(rows))
+	for i, row := range rows {
+		list[i] = row.Issue
+	}
+	if err := issues.LoadDetails(ctx, db, list); err != nil {
+		return nil, err
+	}
+	for n, row := range rows {
+		if i, ok := index[row.BoardColumnID]; ok {
+			out[i].Issues = append(out[i].Issues, list[n])
+		}
+	}
+	return out, nil
+}
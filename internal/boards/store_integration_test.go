@@ -12,6 +12,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/filterquery"
 	"github.com/start-codex/tookly/internal/testpg"
 )
 
@@ -36,7 +37,7 @@ func TestCreateBoard(t *testing.T) {
 			name: "creates scrum board",
 			arrange: func(t *testing.T, db *sqlx.DB) (CreateParams, func(*testing.T)) {
 				proj := seedProject(t, db)
-				params := CreateParams{ProjectID: proj, Name: "Sprint Board", Type: "scrum", FilterQuery: "type=story"}
+				params := CreateParams{ProjectID: proj, Name: "Sprint Board", Type: "scrum", FilterQuery: "type:story"}
 				return params, func(t *testing.T) {}
 			},
 		},
@@ -389,6 +390,133 @@ func TestArchiveColumn(t *testing.T) {
 	}
 }
 
+func TestListBoardIssues(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	proj, todoID := seedProjectWithStatus(t, db)
+	var doneID, typeID string
+	if err := db.GetContext(ctx, &doneID,
+		`INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'Done', 'done', 1) RETURNING id`, proj,
+	); err != nil {
+		t.Fatalf("seed done status: %v", err)
+	}
+	if err := db.GetContext(ctx, &typeID,
+		`INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Bug', 1) RETURNING id`, proj,
+	); err != nil {
+		t.Fatalf("seed issue type: %v", err)
+	}
+
+	insert := func(number int, statusID, priority string, assignee *string) {
+		t.Helper()
+		if _, err := db.ExecContext(ctx,
+			`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, assignee_id, reporter_id, status_position)
+			 VALUES ($1, $2, $3, $4, 'Issue', '', $5, $6, $7, $2)`,
+			proj, number, typeID, statusID, priority, assignee, userID,
+		); err != nil {
+			t.Fatalf("insert issue %d: %v", number, err)
+		}
+	}
+	insert(1, todoID, "high", &userID)
+	insert(2, todoID, "low", &userID)
+	insert(3, doneID, "high", nil)
+	insert(4, doneID, "critical", &userID)
+
+	board, err := Create(ctx, db, CreateParams{ProjectID: proj, Name: "Mine", Type: "kanban", FilterQuery: "assignee:me priority:high,critical"})
+	if err != nil {
+		t.Fatalf("create board: %v", err)
+	}
+	todoCol, err := AddColumn(ctx, db, AddColumnParams{BoardID: board.ID, Name: "To Do"})
+	if err != nil {
+		t.Fatalf("add todo column: %v", err)
+	}
+	doneCol, err := AddColumn(ctx, db, AddColumnParams{BoardID: board.ID, Name: "Done"})
+	if err != nil {
+		t.Fatalf("add done column: %v", err)
+	}
+	if err := AssignStatus(ctx, db, todoCol.ID, todoID); err != nil {
+		t.Fatalf("assign todo: %v", err)
+	}
+	if err := AssignStatus(ctx, db, doneCol.ID, doneID); err != nil {
+		t.Fatalf("assign done: %v", err)
+	}
+
+	got, err := ListIssues(ctx, db, board.ID, userID)
+	if err != nil {
+		t.Fatalf("ListIssues() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("columns: got %d, want 2", len(got))
+	}
+	if got[0].Column.ID != todoCol.ID || len(got[0].Issues) != 1 || got[0].Issues[0].Number != 1 {
+		t.Fatalf("todo column = %+v", got[0])
+	}
+	if got[1].Column.ID != doneCol.ID || len(got[1].Issues) != 1 || got[1].Issues[0].Number != 4 {
+		t.Fatalf("done column = %+v", got[1])
+	}
+}
+
+func TestListBoardIssues_InvalidStoredFilterQuery(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	proj, todoID := seedProjectWithStatus(t, db)
+	var typeID string
+	if err := db.GetContext(ctx, &typeID,
+		`INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Bug', 1) RETURNING id`, proj,
+	); err != nil {
+		t.Fatalf("seed issue type: %v", err)
+	}
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, 1, $2, $3, 'Issue', '', 'low', $4, 1)`,
+		proj, typeID, todoID, userID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+
+	board, err := Create(ctx, db, CreateParams{ProjectID: proj, Name: "Legacy", Type: "kanban"})
+	if err != nil {
+		t.Fatalf("create board: %v", err)
+	}
+	col, err := AddColumn(ctx, db, AddColumnParams{BoardID: board.ID, Name: "To Do"})
+	if err != nil {
+		t.Fatalf("add column: %v", err)
+	}
+	if err := AssignStatus(ctx, db, col.ID, todoID); err != nil {
+		t.Fatalf("assign status: %v", err)
+	}
+	// Saved before filter queries were validated.
+	db.MustExec(`UPDATE boards SET filter_query = 'only the urgent stuff (' WHERE id = $1`, board.ID)
+
+	got, err := ListIssues(ctx, db, board.ID, userID)
+	if err != nil {
+		t.Fatalf("ListIssues() error = %v, want the unfiltered board", err)
+	}
+	if len(got) != 1 || len(got[0].Issues) != 1 {
+		t.Fatalf("ListIssues() = %+v, want one column with one issue", got)
+	}
+}
+
+func TestCreateBoard_InvalidFilterQuery(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+
+	proj := seedProject(t, db)
+	_, err := Create(context.Background(), db, CreateParams{ProjectID: proj, Name: "Bad", Type: "kanban", FilterQuery: "priority:urgent"})
+	var syntaxErr *filterquery.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Create() error = %v, want *filterquery.SyntaxError", err)
+	}
+	if syntaxErr.Pos != 10 {
+		t.Fatalf("error position = %d, want 10", syntaxErr.Pos)
+	}
+}
+
 // --- helpers ---
 
 func seedProject(t *testing.T, db *sqlx.DB) string {
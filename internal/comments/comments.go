@@ -0,0 +1,212 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package comments
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrNotFound      = errors.New("comment not found")
+	ErrIssueNotFound = errors.New("issue not found")
+	ErrInvalidParent = errors.New("replies must target a top-level comment on the same issue")
+)
+
+// Comment is a single comment on an issue. Top-level comments carry their
+// replies inline; replies never have replies of their own.
+type Comment struct {
+	ID              string     `db:"id"                json:"id"`
+	IssueID         string     `db:"issue_id"          json:"issue_id"`
+	ParentCommentID *string    `db:"parent_comment_id" json:"parent_comment_id,omitempty"`
+	AuthorID        string     `db:"author_id"         json:"author_id"`
+	AuthorName      string     `db:"author_name"       json:"author_name"`
+	Body            string     `db:"body"              json:"body"`
+	CreatedAt       time.Time  `db:"created_at"        json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"        json:"updated_at"`
+	EditedAt        *time.Time `db:"edited_at"         json:"edited_at,omitempty"`
+	DeletedAt       *time.Time `db:"deleted_at"        json:"deleted_at,omitempty"`
+	Replies         []Comment  `db:"-"                 json:"replies,omitempty"`
+}
+
+// Revision is a previous body of a comment, recorded when the comment is edited.
+type Revision struct {
+	ID        string    `db:"id"         json:"id"`
+	CommentID string    `db:"comment_id" json:"comment_id"`
+	Body      string    `db:"body"       json:"body"`
+	EditedBy  string    `db:"edited_by"  json:"edited_by"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type CreateParams struct {
+	ProjectID       string
+	IssueID         string
+	ParentCommentID string
+	AuthorID        string
+	Body            string
+}
+
+func (params CreateParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.IssueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if params.AuthorID == "" {
+		return errors.New("author_id is required")
+	}
+	if strings.TrimSpace(params.Body) == "" {
+		return errors.New("body is required")
+	}
+	return nil
+}
+
+type UpdateParams struct {
+	ProjectID string
+	IssueID   string
+	CommentID string
+	EditorID  string
+	Body      string
+}
+
+func (params UpdateParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.IssueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if params.CommentID == "" {
+		return errors.New("comment_id is required")
+	}
+	if params.EditorID == "" {
+		return errors.New("editor_id is required")
+	}
+	if strings.TrimSpace(params.Body) == "" {
+		return errors.New("body is required")
+	}
+	return nil
+}
+
+// Create adds a comment to an active issue and records a "commented" issue event.
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Comment, error) {
+	if db == nil {
+		return Comment{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Comment{}, err
+	}
+	return createComment(ctx, db, params)
+}
+
+func Get(ctx context.Context, db *sqlx.DB, projectID, issueID, commentID string) (Comment, error) {
+	if db == nil {
+		return Comment{}, errors.New("db is required")
+	}
+	if projectID == "" {
+		return Comment{}, errors.New("project_id is required")
+	}
+	if issueID == "" {
+		return Comment{}, errors.New("issue_id is required")
+	}
+	if commentID == "" {
+		return Comment{}, errors.New("comment_id is required")
+	}
+	return getComment(ctx, db, projectID, issueID, commentID)
+}
+
+// List returns the comment threads of an issue, oldest first. Deleted comments
+// are omitted unless they still have visible replies, in which case they are
+// returned with an empty body so the thread stays intact.
+func List(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Comment, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" {
+		return nil, errors.New("project_id is required")
+	}
+	if issueID == "" {
+		return nil, errors.New("issue_id is required")
+	}
+	flat, err := listComments(ctx, db, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	return threadComments(flat), nil
+}
+
+// Update replaces the body of a comment, keeping the previous body as a revision.
+// Comments of archived issues are refused with ErrIssueNotFound.
+func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Comment, error) {
+	if db == nil {
+		return Comment{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Comment{}, err
+	}
+	return updateComment(ctx, db, params)
+}
+
+// Delete soft-deletes a comment. Its revisions and replies are kept.
+// Comments of archived issues are refused with ErrIssueNotFound.
+func Delete(ctx context.Context, db *sqlx.DB, projectID, issueID, commentID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if projectID == "" {
+		return errors.New("project_id is required")
+	}
+	if issueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if commentID == "" {
+		return errors.New("comment_id is required")
+	}
+	return deleteComment(ctx, db, projectID, issueID, commentID)
+}
+
+// ListRevisions returns the previous bodies of a comment, newest first.
+func ListRevisions(ctx context.Context, db *sqlx.DB, projectID, issueID, commentID string) ([]Revision, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" {
+		return nil, errors.New("project_id is required")
+	}
+	if issueID == "" {
+		return nil, errors.New("issue_id is required")
+	}
+	if commentID == "" {
+		return nil, errors.New("comment_id is required")
+	}
+	return listRevisions(ctx, db, projectID, issueID, commentID)
+}
+
+// threadComments nests replies under their parent. Input must be ordered oldest first.
+func threadComments(flat []Comment) []Comment {
+	index := map[string]int{}
+	threads := []Comment{}
+	for _, c := range flat {
+		if c.ParentCommentID == nil {
+			index[c.ID] = len(threads)
+			threads = append(threads, c)
+		}
+	}
+	for _, c := range flat {
+		if c.ParentCommentID == nil {
+			continue
+		}
+		if i, ok := index[*c.ParentCommentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, c)
+		}
+	}
+	return threads
+}
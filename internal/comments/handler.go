@@ -0,0 +1,202 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package comments
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("POST /projects/{projectID}/issues/{issueID}/comments", handleCreate(db))
+	mux.HandleFunc("GET /projects/{projectID}/issues/{issueID}/comments", handleList(db))
+	mux.HandleFunc("PUT /projects/{projectID}/issues/{issueID}/comments/{commentID}", handleUpdate(db))
+	mux.HandleFunc("DELETE /projects/{projectID}/issues/{issueID}/comments/{commentID}", handleDelete(db))
+	mux.HandleFunc("GET /projects/{projectID}/issues/{issueID}/comments/{commentID}/revisions", handleListRevisions(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound),
+		errors.Is(err, authz.ErrProjectNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound),
+		errors.Is(err, ErrIssueNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInvalidParent):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		slog.Error("comments handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// requireAuthorOrAdmin allows the comment author, or any workspace admin/owner.
+func requireAuthorOrAdmin(ctx context.Context, db *sqlx.DB, workspaceID, userID string, comment Comment) error {
+	if comment.AuthorID == userID {
+		return nil
+	}
+	return authz.RequireWorkspaceAdmin(ctx, db, workspaceID)
+}
+
+func handleCreate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body struct {
+			ParentCommentID string `json:"parent_comment_id"`
+			Body            string `json:"body"`
+		}
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := CreateParams{
+			ProjectID:       r.PathValue("projectID"),
+			IssueID:         r.PathValue("issueID"),
+			ParentCommentID: body.ParentCommentID,
+			AuthorID:        authedUserID,
+			Body:            body.Body,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		comment, err := Create(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusCreated, comment)
+	}
+}
+
+func handleList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := List(r.Context(), db, r.PathValue("projectID"), r.PathValue("issueID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+func handleUpdate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projID := r.PathValue("projectID")
+		wsID, err := authz.RequireProjectMembership(r.Context(), db, projID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body struct {
+			Body string `json:"body"`
+		}
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := UpdateParams{
+			ProjectID: projID,
+			IssueID:   r.PathValue("issueID"),
+			CommentID: r.PathValue("commentID"),
+			EditorID:  authedUserID,
+			Body:      body.Body,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		existing, err := Get(r.Context(), db, params.ProjectID, params.IssueID, params.CommentID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if err := requireAuthorOrAdmin(r.Context(), db, wsID, authedUserID, existing); err != nil {
+			fail(w, err)
+			return
+		}
+		comment, err := Update(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, comment)
+	}
+}
+
+func handleDelete(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projID := r.PathValue("projectID")
+		wsID, err := authz.RequireProjectMembership(r.Context(), db, projID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		issueID, commentID := r.PathValue("issueID"), r.PathValue("commentID")
+		existing, err := Get(r.Context(), db, projID, issueID, commentID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if err := requireAuthorOrAdmin(r.Context(), db, wsID, authedUserID, existing); err != nil {
+			fail(w, err)
+			return
+		}
+		if err := Delete(r.Context(), db, projID, issueID, commentID); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func handleListRevisions(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := ListRevisions(r.Context(), db, r.PathValue("projectID"), r.PathValue("issueID"), r.PathValue("commentID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package comments
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+// commentCols selects a comment joined with its author (alias u) and blanks
+// the body of deleted comments.
+const commentCols = `c.id, c.issue_id, c.parent_comment_id, c.author_id, u.name AS author_name,
+	CASE WHEN c.deleted_at IS NULL THEN c.body ELSE '' END AS body,
+	c.created_at, c.updated_at, c.edited_at, c.deleted_at`
+
+func createComment(ctx context.Context, db *sqlx.DB, params CreateParams) (Comment, error) {
+	var comment Comment
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create comment", func(tx *sqlx.Tx) error {
+		var issueID string
+		if err := tx.GetContext(ctx, &issueID,
+			`SELECT id
+			 FROM issues
+			 WHERE id = $1
+			   AND project_id = $2
+			   AND archived_at IS NULL
+			 FOR SHARE`,
+			params.IssueID, params.ProjectID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrIssueNotFound
+			}
+			return fmt.Errorf("lock issue: %w", err)
+		}
+
+		var parentCommentID *string
+		if params.ParentCommentID != "" {
+			var grandparentID *string
+			if err := tx.GetContext(ctx, &grandparentID,
+				`SELECT parent_comment_id
+				 FROM comments
+				 WHERE id = $1
+				   AND issue_id = $2
+				   AND deleted_at IS NULL`,
+				params.ParentCommentID, params.IssueID,
+			); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return ErrInvalidParent
+				}
+				return fmt.Errorf("load parent comment: %w", err)
+			}
+			if grandparentID != nil {
+				return ErrInvalidParent
+			}
+			parentCommentID = &params.ParentCommentID
+		}
+
+		var id string
+		if err := tx.GetContext(ctx, &id,
+			`INSERT INTO comments (issue_id, parent_comment_id, author_id, body)
+			 VALUES ($1, $2, $3, $4)
+			 RETURNING id`,
+			params.IssueID, parentCommentID, params.AuthorID, params.Body,
+		); err != nil {
+			return fmt.Errorf("insert comment: %w", err)
+		}
+
+		payload, err := json.Marshal(map[string]any{
+			"comment_id":        id,
+			"parent_comment_id": parentCommentID,
+		})
+		if err != nil {
+			return fmt.Errorf("marshal comment event payload: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO issue_events (issue_id, actor_id, event_type, payload_json)
+			 VALUES ($1, $2, 'commented', $3)`,
+			params.IssueID, params.AuthorID, payload,
+		); err != nil {
+			return fmt.Errorf("insert comment event: %w", err)
+		}
+
+		if err := tx.GetContext(ctx, &comment,
+			`SELECT `+commentCols+`
+			 FROM comments c
+			 JOIN app_users u ON u.id = c.author_id
+			 WHERE c.id = $1`,
+			id,
+		); err != nil {
+			return fmt.Errorf("load created comment: %w", err)
+		}
+		return issues.PublishEvent(ctx, tx, params.IssueID, params.AuthorID, issues.EventCommented, issues.WebhookData{Comment: comment})
+	}); err != nil {
+		return Comment{}, err
+	}
+	return comment, nil
+}
+
+func getComment(ctx context.Context, db *sqlx.DB, projectID, issueID, commentID string) (Comment, error) {
+	var comment Comment
+	err := db.GetContext(ctx, &comment,
+		`SELECT `+commentCols+`
+		 FROM comments c
+		 JOIN issues i ON i.id = c.issue_id
+		 JOIN app_users u ON u.id = c.author_id
+		 WHERE c.id = $1
+		   AND c.issue_id = $2
+		   AND i.project_id = $3
+		   AND c.deleted_at IS NULL`,
+		commentID, issueID, projectID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Comment{}, ErrNotFound
+		}
+		return Comment{}, fmt.Errorf("get comment: %w", err)
+	}
+	return comment, nil
+}
+
+func listComments(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Comment, error) {
+	var exists bool
+	if err := db.GetContext(ctx, &exists,
+		`SELECT EXISTS(SELECT 1 FROM issues WHERE id = $1 AND project_id = $2)`,
+		issueID, projectID,
+	); err != nil {
+		return nil, fmt.Errorf("check issue: %w", err)
+	}
+	if !exists {
+		return nil, ErrIssueNotFound
+	}
+
+	comments := []Comment{}
+	if err := db.SelectContext(ctx, &comments,
+		`SELECT `+commentCols+`
+		 FROM comments c
+		 JOIN app_users u ON u.id = c.author_id
+		 WHERE c.issue_id = $1
+		   AND (
+		     c.deleted_at IS NULL
+		     OR (c.parent_comment_id IS NULL AND EXISTS (
+		       SELECT 1 FROM comments r
+		       WHERE r.parent_comment_id = c.id
+		         AND r.deleted_at IS NULL
+		     ))
+		   )
+		 ORDER BY c.created_at ASC, c.id ASC`,
+		issueID,
+	); err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+	return comments, nil
+}
+
+func updateComment(ctx context.Context, db *sqlx.DB, params UpdateParams) (Comment, error) {
+	var comment Comment
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update comment", func(tx *sqlx.Tx) error {
+		previous, err := lockComment(ctx, tx, params.ProjectID, params.IssueID, params.CommentID)
+		if err != nil {
+			return err
+		}
+
+		if previous != params.Body {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO comment_revisions (comment_id, body, edited_by)
+				 VALUES ($1, $2, $3)`,
+				params.CommentID, previous, params.EditorID,
+			); err != nil {
+				return fmt.Errorf("insert comment revision: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE comments
+				 SET body = $1, edited_at = NOW()
+				 WHERE id = $2`,
+				params.Body, params.CommentID,
+			); err != nil {
+				return fmt.Errorf("update comment: %w", err)
+			}
+		}
+
+		if err := tx.GetContext(ctx, &comment,
+			`SELECT `+commentCols+`
+			 FROM comments c
+			 JOIN app_users u ON u.id = c.author_id
+			 WHERE c.id = $1`,
+			params.CommentID,
+		); err != nil {
+			return fmt.Errorf("load updated comment: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return Comment{}, err
+	}
+	return comment, nil
+}
+
+func deleteComment(ctx context.Context, db *sqlx.DB, projectID, issueID, commentID string) error {
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit delete comment", func(tx *sqlx.Tx) error {
+		if _, err := lockComment(ctx, tx, projectID, issueID, commentID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE comments SET deleted_at = NOW() WHERE id = $1`,
+			commentID,
+		); err != nil {
+			return fmt.Errorf("delete comment: %w", err)
+		}
+		return nil
+	})
+}
+
+// lockComment locks a live comment for a change and returns its body. Like
+// createComment, it refuses comments of archived issues with
+// ErrIssueNotFound; the issue row is share-locked so it cannot be archived
+// meanwhile.
+func lockComment(ctx context.Context, tx *sqlx.Tx, projectID, issueID, commentID string) (string, error) {
+	var row struct {
+		Body          string `db:"body"`
+		IssueArchived bool   `db:"issue_archived"`
+	}
+	if err := tx.GetContext(ctx, &row,
+		`SELECT c.body, i.archived_at IS NOT NULL AS issue_archived
+		 FROM comments c
+		 JOIN issues i ON i.id = c.issue_id
+		 WHERE c.id = $1
+		   AND c.issue_id = $2
+		   AND i.project_id = $3
+		   AND c.deleted_at IS NULL
+		 FOR UPDATE OF c
+		 FOR SHARE OF i`,
+		commentID, issueID, projectID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("lock comment: %w", err)
+	}
+	if row.IssueArchived {
+		return "", ErrIssueNotFound
+	}
+	return row.Body, nil
+}
+
+func listRevisions(ctx context.Context, db *sqlx.DB, projectID, issueID, commentID string) ([]Revision, error) {
+	if _, err := getComment(ctx, db, projectID, issueID, commentID); err != nil {
+		return nil, err
+	}
+	revisions := []Revision{}
+	if err := db.SelectContext(ctx, &revisions,
+		`SELECT id, comment_id, body, edited_by, created_at
+		 FROM comment_revisions
+		 WHERE comment_id = $1
+		 ORDER BY created_at DESC, id DESC`,
+		commentID,
+	); err != nil {
+		return nil, fmt.Errorf("list comment revisions: %w", err)
+	}
+	return revisions, nil
+}
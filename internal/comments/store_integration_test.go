@@ -0,0 +1,181 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package comments
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+type issueFixture struct {
+	projectID string
+	issueID   string
+	userID    string
+}
+
+func seedIssue(t *testing.T, db *sqlx.DB) issueFixture {
+	t.Helper()
+	ctx := context.Background()
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	projID := testpg.SeedProject(t, db, wsID, "CMT")
+
+	var typeID, statusID, issueID string
+	if err := db.GetContext(ctx, &typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projID); err != nil {
+		t.Fatalf("insert issue_type: %v", err)
+	}
+	if err := db.GetContext(ctx, &statusID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, projID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	if err := db.GetContext(ctx, &issueID,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, 1, $2, $3, 'Issue', '', 'medium', $4, 0) RETURNING id`,
+		projID, typeID, statusID, userID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+	return issueFixture{projectID: projID, issueID: issueID, userID: userID}
+}
+
+func TestCommentThreads(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	fx := seedIssue(t, db)
+
+	root, err := Create(ctx, db, CreateParams{ProjectID: fx.projectID, IssueID: fx.issueID, AuthorID: fx.userID, Body: "root"})
+	if err != nil {
+		t.Fatalf("create root: %v", err)
+	}
+	reply, err := Create(ctx, db, CreateParams{ProjectID: fx.projectID, IssueID: fx.issueID, ParentCommentID: root.ID, AuthorID: fx.userID, Body: "reply"})
+	if err != nil {
+		t.Fatalf("create reply: %v", err)
+	}
+
+	_, err = Create(ctx, db, CreateParams{ProjectID: fx.projectID, IssueID: fx.issueID, ParentCommentID: reply.ID, AuthorID: fx.userID, Body: "nested"})
+	if !errors.Is(err, ErrInvalidParent) {
+		t.Fatalf("reply to reply: error = %v, want ErrInvalidParent", err)
+	}
+
+	threads, err := List(ctx, db, fx.projectID, fx.issueID)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(threads) != 1 || len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != reply.ID {
+		t.Fatalf("threads = %+v", threads)
+	}
+
+	var commented int
+	if err := db.GetContext(ctx, &commented,
+		`SELECT COUNT(*) FROM issue_events WHERE issue_id = $1 AND event_type = 'commented'`, fx.issueID,
+	); err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	if commented != 2 {
+		t.Fatalf("commented events: got %d, want 2", commented)
+	}
+
+	if err := Delete(ctx, db, fx.projectID, fx.issueID, root.ID); err != nil {
+		t.Fatalf("delete root: %v", err)
+	}
+	threads, err = List(ctx, db, fx.projectID, fx.issueID)
+	if err != nil {
+		t.Fatalf("List() after delete error = %v", err)
+	}
+	if len(threads) != 1 || threads[0].DeletedAt == nil || threads[0].Body != "" || len(threads[0].Replies) != 1 {
+		t.Fatalf("tombstone thread = %+v", threads)
+	}
+
+	if err := Delete(ctx, db, fx.projectID, fx.issueID, reply.ID); err != nil {
+		t.Fatalf("delete reply: %v", err)
+	}
+	threads, err = List(ctx, db, fx.projectID, fx.issueID)
+	if err != nil {
+		t.Fatalf("List() after deleting all error = %v", err)
+	}
+	if len(threads) != 0 {
+		t.Fatalf("threads after deleting all = %+v", threads)
+	}
+}
+
+func TestUpdateComment_Revisions(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	fx := seedIssue(t, db)
+
+	c, err := Create(ctx, db, CreateParams{ProjectID: fx.projectID, IssueID: fx.issueID, AuthorID: fx.userID, Body: "v1"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for _, body := range []string{"v2", "v2", "v3"} {
+		if _, err := Update(ctx, db, UpdateParams{
+			ProjectID: fx.projectID, IssueID: fx.issueID, CommentID: c.ID, EditorID: fx.userID, Body: body,
+		}); err != nil {
+			t.Fatalf("update to %q: %v", body, err)
+		}
+	}
+
+	got, err := Get(ctx, db, fx.projectID, fx.issueID, c.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Body != "v3" || got.EditedAt == nil {
+		t.Fatalf("comment = %+v", got)
+	}
+
+	revisions, err := ListRevisions(ctx, db, fx.projectID, fx.issueID, c.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions() error = %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Body != "v2" || revisions[1].Body != "v1" {
+		t.Fatalf("revisions = %+v", revisions)
+	}
+
+	_, err = Update(ctx, db, UpdateParams{
+		ProjectID: fx.projectID, IssueID: fx.issueID, CommentID: "00000000-0000-0000-0000-000000000000", EditorID: fx.userID, Body: "x",
+	})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("update missing: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestComments_ArchivedIssue(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	fx := seedIssue(t, db)
+
+	c, err := Create(ctx, db, CreateParams{ProjectID: fx.projectID, IssueID: fx.issueID, AuthorID: fx.userID, Body: "v1"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	db.MustExec(`UPDATE issues SET archived_at = now() WHERE id = $1`, fx.issueID)
+
+	if _, err := Create(ctx, db, CreateParams{ProjectID: fx.projectID, IssueID: fx.issueID, AuthorID: fx.userID, Body: "new"}); !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("create on archived issue: error = %v, want ErrIssueNotFound", err)
+	}
+	if _, err := Update(ctx, db, UpdateParams{
+		ProjectID: fx.projectID, IssueID: fx.issueID, CommentID: c.ID, EditorID: fx.userID, Body: "v2",
+	}); !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("update on archived issue: error = %v, want ErrIssueNotFound", err)
+	}
+	if err := Delete(ctx, db, fx.projectID, fx.issueID, c.ID); !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("delete on archived issue: error = %v, want ErrIssueNotFound", err)
+	}
+	got, err := Get(ctx, db, fx.projectID, fx.issueID, c.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Body != "v1" || got.EditedAt != nil {
+		t.Fatalf("comment = %+v, want it unchanged", got)
+	}
+}
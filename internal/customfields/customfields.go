@@ -0,0 +1,245 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package customfields manages project-defined issue fields and their values.
+// A field applies to every issue type of its project unless it is scoped to
+// specific issue types.
+package customfields
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNotFound         = errors.New("custom field not found")
+	ErrDuplicate        = errors.New("custom field key already exists in project")
+	ErrInvalidKey       = errors.New("key must start with a lowercase letter and contain only lowercase letters, digits and underscores (max 63)")
+	ErrInvalidType      = errors.New("type must be one of text, number, date, single_select, multi_select, user, url")
+	ErrInvalidOptions   = errors.New("options must be non-empty, unique and non-blank for select fields, and empty otherwise")
+	ErrInvalidIssueType = errors.New("issue_type_ids must reference active issue types of the project")
+)
+
+const (
+	TypeText         = "text"
+	TypeNumber       = "number"
+	TypeDate         = "date"
+	TypeSingleSelect = "single_select"
+	TypeMultiSelect  = "multi_select"
+	TypeUser         = "user"
+	TypeURL          = "url"
+)
+
+var validTypes = map[string]bool{
+	TypeText: true, TypeNumber: true, TypeDate: true, TypeSingleSelect: true,
+	TypeMultiSelect: true, TypeUser: true, TypeURL: true,
+}
+
+var keyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// ValidKey reports whether key is a well-formed custom field key.
+func ValidKey(key string) bool {
+	return keyRe.MatchString(key)
+}
+
+// Field is a custom field definition. IssueTypeIDs is empty when the field
+// applies to every issue type of the project.
+type Field struct {
+	ID           string         `db:"id"             json:"id"`
+	ProjectID    string         `db:"project_id"     json:"project_id"`
+	Key          string         `db:"key"            json:"key"`
+	Name         string         `db:"name"           json:"name"`
+	Type         string         `db:"field_type"     json:"type"`
+	OptionsJSON  []byte         `db:"options"        json:"-"`
+	Options      []string       `db:"-"              json:"options"`
+	Required     bool           `db:"required"       json:"required"`
+	Position     int            `db:"position"       json:"position"`
+	IssueTypeIDs pq.StringArray `db:"issue_type_ids" json:"issue_type_ids"`
+	CreatedAt    time.Time      `db:"created_at"     json:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"     json:"updated_at"`
+	ArchivedAt   *time.Time     `db:"archived_at"    json:"archived_at,omitempty"`
+}
+
+// Values maps custom field keys to their JSON values on an issue.
+type Values map[string]json.RawMessage
+
+// Change records the value of a custom field before and after an issue
+// mutation. A nil side means the field was unset.
+type Change struct {
+	From any
+	To   any
+}
+
+type CreateParams struct {
+	ProjectID    string
+	Key          string
+	Name         string
+	Type         string
+	Options      []string
+	Required     bool
+	IssueTypeIDs []string
+}
+
+func (params CreateParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if !ValidKey(params.Key) {
+		return ErrInvalidKey
+	}
+	if strings.TrimSpace(params.Name) == "" {
+		return errors.New("name is required")
+	}
+	if !validTypes[params.Type] {
+		return ErrInvalidType
+	}
+	return checkOptions(params.Type, params.Options)
+}
+
+// UpdateParams replaces the mutable parts of a field. Key and type cannot
+// change once values exist, so they are not updatable.
+type UpdateParams struct {
+	ProjectID    string
+	FieldID      string
+	Name         string
+	Options      []string
+	Required     bool
+	IssueTypeIDs []string
+}
+
+func (params UpdateParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.FieldID == "" {
+		return errors.New("field_id is required")
+	}
+	if strings.TrimSpace(params.Name) == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// SetParams carries the custom field input of an issue create or update.
+// Keys mapped to JSON null are cleared. With Creating set, every required
+// field that applies to the issue type must be given a value.
+type SetParams struct {
+	ProjectID   string
+	IssueID     string
+	IssueTypeID string
+	Values      map[string]json.RawMessage
+	Creating    bool
+}
+
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Field, error) {
+	if db == nil {
+		return Field{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Field{}, err
+	}
+	return createField(ctx, db, params)
+}
+
+func Get(ctx context.Context, db *sqlx.DB, projectID, fieldID string) (Field, error) {
+	if db == nil {
+		return Field{}, errors.New("db is required")
+	}
+	if projectID == "" {
+		return Field{}, errors.New("project_id is required")
+	}
+	if fieldID == "" {
+		return Field{}, errors.New("field_id is required")
+	}
+	return getField(ctx, db, projectID, fieldID)
+}
+
+// List returns the active fields of a project in position order.
+func List(ctx context.Context, db *sqlx.DB, projectID string) ([]Field, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" {
+		return nil, errors.New("project_id is required")
+	}
+	return listFields(ctx, db, projectID)
+}
+
+func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Field, error) {
+	if db == nil {
+		return Field{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Field{}, err
+	}
+	return updateField(ctx, db, params)
+}
+
+// Archive hides a field. Its values stay stored but are no longer returned.
+func Archive(ctx context.Context, db *sqlx.DB, projectID, fieldID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if projectID == "" {
+		return errors.New("project_id is required")
+	}
+	if fieldID == "" {
+		return errors.New("field_id is required")
+	}
+	return archiveField(ctx, db, projectID, fieldID)
+}
+
+// SetValues validates and writes custom field values for an issue inside the
+// caller's transaction. It returns the fields whose value changed, keyed by
+// field key.
+func SetValues(ctx context.Context, tx *sqlx.Tx, params SetParams) (map[string]Change, error) {
+	if tx == nil {
+		return nil, errors.New("tx is required")
+	}
+	if params.ProjectID == "" || params.IssueID == "" || params.IssueTypeID == "" {
+		return nil, errors.New("project_id, issue_id and issue_type_id are required")
+	}
+	return setValues(ctx, tx, params)
+}
+
+// Load returns the values of active fields for the given issues, keyed by
+// issue ID. Issues without values are absent from the map.
+func Load(ctx context.Context, q sqlx.QueryerContext, issueIDs []string) (map[string]Values, error) {
+	if q == nil {
+		return nil, errors.New("db is required")
+	}
+	if len(issueIDs) == 0 {
+		return map[string]Values{}, nil
+	}
+	return loadValues(ctx, q, issueIDs)
+}
+
+func checkOptions(fieldType string, options []string) error {
+	isSelect := fieldType == TypeSingleSelect || fieldType == TypeMultiSelect
+	if !isSelect {
+		if len(options) != 0 {
+			return ErrInvalidOptions
+		}
+		return nil
+	}
+	if len(options) == 0 {
+		return ErrInvalidOptions
+	}
+	seen := map[string]bool{}
+	for _, o := range options {
+		if strings.TrimSpace(o) == "" || seen[o] {
+			return ErrInvalidOptions
+		}
+		seen[o] = true
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package customfields
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCreateParams_Validate(t *testing.T) {
+	valid := CreateParams{ProjectID: "p", Key: "story_points", Name: "Story points", Type: TypeNumber}
+	sel := CreateParams{ProjectID: "p", Key: "team", Name: "Team", Type: TypeSingleSelect, Options: []string{"web", "api"}}
+
+	tests := []struct {
+		name    string
+		params  CreateParams
+		wantErr error
+	}{
+		{name: "valid", params: valid},
+		{name: "valid select", params: sel},
+		{name: "missing project_id", params: func() CreateParams { c := valid; c.ProjectID = ""; return c }(), wantErr: errAny},
+		{name: "uppercase key", params: func() CreateParams { c := valid; c.Key = "Points"; return c }(), wantErr: ErrInvalidKey},
+		{name: "key starting with digit", params: func() CreateParams { c := valid; c.Key = "1pts"; return c }(), wantErr: ErrInvalidKey},
+		{name: "blank name", params: func() CreateParams { c := valid; c.Name = " "; return c }(), wantErr: errAny},
+		{name: "unknown type", params: func() CreateParams { c := valid; c.Type = "checkbox"; return c }(), wantErr: ErrInvalidType},
+		{name: "options on number", params: func() CreateParams { c := valid; c.Options = []string{"1"}; return c }(), wantErr: ErrInvalidOptions},
+		{name: "select without options", params: func() CreateParams { c := sel; c.Options = nil; return c }(), wantErr: ErrInvalidOptions},
+		{name: "duplicate options", params: func() CreateParams { c := sel; c.Options = []string{"web", "web"}; return c }(), wantErr: ErrInvalidOptions},
+		{name: "blank option", params: func() CreateParams { c := sel; c.Options = []string{"web", ""}; return c }(), wantErr: ErrInvalidOptions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("Validate() error = %v, want nil", err)
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("Validate() error = nil, want error")
+			case tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// errAny marks cases that expect some error without a sentinel.
+var errAny = errors.New("any error")
+
+func TestUpdateParams_Validate(t *testing.T) {
+	valid := UpdateParams{ProjectID: "p", FieldID: "f", Name: "Points"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for _, p := range []UpdateParams{
+		{FieldID: "f", Name: "Points"},
+		{ProjectID: "p", Name: "Points"},
+		{ProjectID: "p", FieldID: "f"},
+	} {
+		if err := p.Validate(); err == nil {
+			t.Fatalf("Validate(%+v) error = nil, want error", p)
+		}
+	}
+}
+
+func TestField_Normalize(t *testing.T) {
+	options := []string{"web", "api", "ops"}
+	tests := []struct {
+		name    string
+		typ     string
+		raw     string
+		want    string // empty means cleared
+		wantErr bool
+	}{
+		{name: "null clears", typ: TypeText, raw: `null`},
+		{name: "text", typ: TypeText, raw: `"hello"`, want: `"hello"`},
+		{name: "empty text clears", typ: TypeText, raw: `""`},
+		{name: "text not string", typ: TypeText, raw: `12`, wantErr: true},
+		{name: "number", typ: TypeNumber, raw: `3.50`, want: `3.50`},
+		{name: "number as string", typ: TypeNumber, raw: `"3"`, wantErr: true},
+		{name: "date", typ: TypeDate, raw: `"2025-02-28"`, want: `"2025-02-28"`},
+		{name: "bad date", typ: TypeDate, raw: `"2025-02-30"`, wantErr: true},
+		{name: "single select", typ: TypeSingleSelect, raw: `"api"`, want: `"api"`},
+		{name: "unknown option", typ: TypeSingleSelect, raw: `"qa"`, wantErr: true},
+		{name: "multi select in option order", typ: TypeMultiSelect, raw: `["ops","web"]`, want: `["web","ops"]`},
+		{name: "empty multi select clears", typ: TypeMultiSelect, raw: `[]`},
+		{name: "multi select unknown", typ: TypeMultiSelect, raw: `["web","qa"]`, wantErr: true},
+		{name: "multi select not array", typ: TypeMultiSelect, raw: `"web"`, wantErr: true},
+		{name: "user", typ: TypeUser, raw: `"0b0e5c7e-8d1f-4a51-9a55-2f1d3c0c7a10"`, want: `"0b0e5c7e-8d1f-4a51-9a55-2f1d3c0c7a10"`},
+		{name: "user not uuid", typ: TypeUser, raw: `"alice"`, wantErr: true},
+		{name: "url", typ: TypeURL, raw: `"https://example.com/spec"`, want: `"https://example.com/spec"`},
+		{name: "url without scheme", typ: TypeURL, raw: `"example.com"`, wantErr: true},
+		{name: "ftp url", typ: TypeURL, raw: `"ftp://example.com"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Field{Key: "f", Type: tt.typ, Options: options}
+			got, err := f.normalize(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				var ve *ValueError
+				if !errors.As(err, &ve) || ve.Key != "f" {
+					t.Fatalf("normalize() error = %v, want *ValueError for f", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalize() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("normalize() = %s, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreate_NilDB(t *testing.T) {
+	_, err := Create(context.Background(), nil, CreateParams{ProjectID: "p", Key: "k", Name: "K", Type: TypeText})
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("Create() error = %v, want %q", err, "db is required")
+	}
+}
+
+func TestSetValues_NilTx(t *testing.T) {
+	_, err := SetValues(context.Background(), nil, SetParams{ProjectID: "p", IssueID: "i", IssueTypeID: "t"})
+	if err == nil || err.Error() != "tx is required" {
+		t.Fatalf("SetValues() error = %v, want %q", err, "tx is required")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package customfields
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("POST /projects/{projectID}/custom-fields", handleCreate(db))
+	mux.HandleFunc("GET /projects/{projectID}/custom-fields", handleList(db))
+	mux.HandleFunc("PUT /projects/{projectID}/custom-fields/{fieldID}", handleUpdate(db))
+	mux.HandleFunc("DELETE /projects/{projectID}/custom-fields/{fieldID}", handleArchive(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound),
+		errors.Is(err, authz.ErrProjectNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrDuplicate):
+		respond.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidOptions),
+		errors.Is(err, ErrInvalidIssueType):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		slog.Error("customfields handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func handleCreate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projID := r.PathValue("projectID")
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		var body struct {
+			Key          string   `json:"key"`
+			Name         string   `json:"name"`
+			Type         string   `json:"type"`
+			Options      []string `json:"options"`
+			Required     bool     `json:"required"`
+			IssueTypeIDs []string `json:"issue_type_ids"`
+		}
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := CreateParams{
+			ProjectID:    projID,
+			Key:          body.Key,
+			Name:         body.Name,
+			Type:         body.Type,
+			Options:      body.Options,
+			Required:     body.Required,
+			IssueTypeIDs: body.IssueTypeIDs,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		field, err := Create(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusCreated, field)
+	}
+}
+
+func handleList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projID := r.PathValue("projectID")
+		if _, err := authz.RequireProjectMembership(r.Context(), db, projID); err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := List(r.Context(), db, projID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+func handleUpdate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projID := r.PathValue("projectID")
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		var body struct {
+			Name         string   `json:"name"`
+			Options      []string `json:"options"`
+			Required     bool     `json:"required"`
+			IssueTypeIDs []string `json:"issue_type_ids"`
+		}
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := UpdateParams{
+			ProjectID:    projID,
+			FieldID:      r.PathValue("fieldID"),
+			Name:         body.Name,
+			Options:      body.Options,
+			Required:     body.Required,
+			IssueTypeIDs: body.IssueTypeIDs,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		field, err := Update(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, field)
+	}
+}
+
+func handleArchive(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projID := r.PathValue("projectID")
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		if err := Archive(r.Context(), db, projID, r.PathValue("fieldID")); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package customfields
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+// fieldCols selects a field (alias f) with its issue type scope.
+const fieldCols = `f.id, f.project_id, f.key, f.name, f.field_type, f.options, f.required, f.position,
+	ARRAY(SELECT s.issue_type_id::text FROM custom_field_issue_types s
+	      WHERE s.custom_field_id = f.id ORDER BY s.issue_type_id) AS issue_type_ids,
+	f.created_at, f.updated_at, f.archived_at`
+
+func decodeOptions(f *Field) error {
+	f.Options = []string{}
+	if err := json.Unmarshal(f.OptionsJSON, &f.Options); err != nil {
+		return fmt.Errorf("decode custom field options: %w", err)
+	}
+	return nil
+}
+
+func createField(ctx context.Context, db *sqlx.DB, params CreateParams) (Field, error) {
+	var id string
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create custom field", func(tx *sqlx.Tx) error {
+		options, err := json.Marshal(nonNil(params.Options))
+		if err != nil {
+			return fmt.Errorf("marshal options: %w", err)
+		}
+		if err := tx.GetContext(ctx, &id,
+			`INSERT INTO custom_fields (project_id, key, name, field_type, options, required, position)
+			 VALUES ($1, $2, $3, $4, $5, $6,
+			         (SELECT COALESCE(MAX(position), -1) + 1 FROM custom_fields
+			          WHERE project_id = $1 AND archived_at IS NULL))
+			 RETURNING id`,
+			params.ProjectID, params.Key, params.Name, params.Type, options, params.Required,
+		); err != nil {
+			if pgutil.IsUniqueViolation(err) {
+				return ErrDuplicate
+			}
+			return fmt.Errorf("insert custom field: %w", err)
+		}
+		return replaceScope(ctx, tx, params.ProjectID, id, params.IssueTypeIDs)
+	}); err != nil {
+		return Field{}, err
+	}
+	return getField(ctx, db, params.ProjectID, id)
+}
+
+func getField(ctx context.Context, db *sqlx.DB, projectID, fieldID string) (Field, error) {
+	var f Field
+	err := db.GetContext(ctx, &f,
+		`SELECT `+fieldCols+`
+		 FROM custom_fields f
+		 WHERE f.id = $1
+		   AND f.project_id = $2
+		   AND f.archived_at IS NULL`,
+		fieldID, projectID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Field{}, ErrNotFound
+		}
+		return Field{}, fmt.Errorf("get custom field: %w", err)
+	}
+	if err := decodeOptions(&f); err != nil {
+		return Field{}, err
+	}
+	return f, nil
+}
+
+func listFields(ctx context.Context, db *sqlx.DB, projectID string) ([]Field, error) {
+	fields := []Field{}
+	if err := db.SelectContext(ctx, &fields,
+		`SELECT `+fieldCols+`
+		 FROM custom_fields f
+		 WHERE f.project_id = $1
+		   AND f.archived_at IS NULL
+		 ORDER BY f.position ASC, f.key ASC`,
+		projectID,
+	); err != nil {
+		return nil, fmt.Errorf("list custom fields: %w", err)
+	}
+	for i := range fields {
+		if err := decodeOptions(&fields[i]); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+func updateField(ctx context.Context, db *sqlx.DB, params UpdateParams) (Field, error) {
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update custom field", func(tx *sqlx.Tx) error {
+		var fieldType string
+		if err := tx.GetContext(ctx, &fieldType,
+			`SELECT field_type
+			 FROM custom_fields
+			 WHERE id = $1
+			   AND project_id = $2
+			   AND archived_at IS NULL
+			 FOR UPDATE`,
+			params.FieldID, params.ProjectID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("lock custom field: %w", err)
+		}
+		if err := checkOptions(fieldType, params.Options); err != nil {
+			return err
+		}
+		options, err := json.Marshal(nonNil(params.Options))
+		if err != nil {
+			return fmt.Errorf("marshal options: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE custom_fields
+			 SET name = $1, options = $2, required = $3
+			 WHERE id = $4`,
+			params.Name, options, params.Required, params.FieldID,
+		); err != nil {
+			return fmt.Errorf("update custom field: %w", err)
+		}
+		return replaceScope(ctx, tx, params.ProjectID, params.FieldID, params.IssueTypeIDs)
+	}); err != nil {
+		return Field{}, err
+	}
+	return getField(ctx, db, params.ProjectID, params.FieldID)
+}
+
+func archiveField(ctx context.Context, db *sqlx.DB, projectID, fieldID string) error {
+	res, err := db.ExecContext(ctx,
+		`UPDATE custom_fields
+		 SET archived_at = NOW()
+		 WHERE id = $1
+		   AND project_id = $2
+		   AND archived_at IS NULL`,
+		fieldID, projectID,
+	)
+	if err != nil {
+		return fmt.Errorf("archive custom field: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("archive custom field rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// replaceScope sets the issue types a field applies to after checking they
+// are active issue types of the project.
+func replaceScope(ctx context.Context, tx *sqlx.Tx, projectID, fieldID string, issueTypeIDs []string) error {
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM custom_field_issue_types WHERE custom_field_id = $1`, fieldID,
+	); err != nil {
+		return fmt.Errorf("clear custom field scope: %w", err)
+	}
+	if len(issueTypeIDs) == 0 {
+		return nil
+	}
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO custom_field_issue_types (custom_field_id, issue_type_id)
+		 SELECT $1, t.id
+		 FROM issue_types t
+		 WHERE t.project_id = $2
+		   AND t.archived_at IS NULL
+		   AND t.id::text = ANY($3)`,
+		fieldID, projectID, pq.Array(issueTypeIDs),
+	)
+	if err != nil {
+		return fmt.Errorf("insert custom field scope: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("insert custom field scope rows affected: %w", err)
+	}
+	if int(n) != len(dedupe(issueTypeIDs)) {
+		return ErrInvalidIssueType
+	}
+	return nil
+}
+
+type storedValue struct {
+	FieldID string          `db:"custom_field_id"`
+	Value   json.RawMessage `db:"value"`
+}
+
+func setValues(ctx context.Context, tx *sqlx.Tx, params SetParams) (map[string]Change, error) {
+	applicable := []Field{}
+	if err := tx.SelectContext(ctx, &applicable,
+		`SELECT `+fieldCols+`
+		 FROM custom_fields f
+		 WHERE f.project_id = $1
+		   AND f.archived_at IS NULL
+		   AND (NOT EXISTS (SELECT 1 FROM custom_field_issue_types s WHERE s.custom_field_id = f.id)
+		        OR EXISTS (SELECT 1 FROM custom_field_issue_types s
+		                   WHERE s.custom_field_id = f.id AND s.issue_type_id = $2))
+		 ORDER BY f.position`,
+		params.ProjectID, params.IssueTypeID,
+	); err != nil {
+		return nil, fmt.Errorf("load applicable custom fields: %w", err)
+	}
+	byKey := make(map[string]Field, len(applicable))
+	for i := range applicable {
+		if err := decodeOptions(&applicable[i]); err != nil {
+			return nil, err
+		}
+		byKey[applicable[i].Key] = applicable[i]
+	}
+
+	next := map[string]json.RawMessage{}
+	for key, raw := range params.Values {
+		f, ok := byKey[key]
+		if !ok {
+			return nil, &ValueError{Key: key, Msg: "is not a field of this issue type"}
+		}
+		v, err := f.normalize(raw)
+		if err != nil {
+			return nil, err
+		}
+		if f.Type == TypeUser && v != nil {
+			if err := checkWorkspaceUser(ctx, tx, params.ProjectID, f.Key, v); err != nil {
+				return nil, err
+			}
+		}
+		next[key] = v
+	}
+
+	stored := []storedValue{}
+	if err := tx.SelectContext(ctx, &stored,
+		`SELECT custom_field_id, value FROM issue_custom_values WHERE issue_id = $1`,
+		params.IssueID,
+	); err != nil {
+		return nil, fmt.Errorf("load custom values: %w", err)
+	}
+	current := map[string]json.RawMessage{}
+	for _, s := range stored {
+		current[s.FieldID] = s.Value
+	}
+
+	for _, f := range applicable {
+		if !f.Required {
+			continue
+		}
+		v, given := next[f.Key]
+		if !given && !params.Creating {
+			v = current[f.ID]
+		}
+		if v == nil {
+			return nil, &ValueError{Key: f.Key, Msg: "is required"}
+		}
+	}
+
+	changes := map[string]Change{}
+	for key, v := range next {
+		f := byKey[key]
+		before := current[f.ID]
+		if jsonEqual(before, v) {
+			continue
+		}
+		if v == nil {
+			if _, err := tx.ExecContext(ctx,
+				`DELETE FROM issue_custom_values WHERE issue_id = $1 AND custom_field_id = $2`,
+				params.IssueID, f.ID,
+			); err != nil {
+				return nil, fmt.Errorf("clear custom value: %w", err)
+			}
+		} else if _, err := tx.ExecContext(ctx,
+			`INSERT INTO issue_custom_values (issue_id, custom_field_id, value)
+			 VALUES ($1, $2, $3)
+			 ON CONFLICT (issue_id, custom_field_id) DO UPDATE SET value = EXCLUDED.value`,
+			params.IssueID, f.ID, []byte(v),
+		); err != nil {
+			return nil, fmt.Errorf("upsert custom value: %w", err)
+		}
+		changes[key] = Change{From: decoded(before), To: decoded(v)}
+	}
+	return changes, nil
+}
+
+// checkWorkspaceUser verifies that a user field points at a member of the
+// project's workspace.
+func checkWorkspaceUser(ctx context.Context, tx *sqlx.Tx, projectID, key string, v json.RawMessage) error {
+	var userID string
+	if err := json.Unmarshal(v, &userID); err != nil {
+		return fmt.Errorf("decode user value: %w", err)
+	}
+	var ok bool
+	if err := tx.GetContext(ctx, &ok,
+		`SELECT EXISTS(
+			SELECT 1
+			FROM workspace_members wm
+			JOIN projects p ON p.workspace_id = wm.workspace_id
+			WHERE p.id = $1
+			  AND wm.user_id = $2
+			  AND wm.archived_at IS NULL
+		)`,
+		projectID, userID,
+	); err != nil {
+		return fmt.Errorf("check workspace user: %w", err)
+	}
+	if !ok {
+		return &ValueError{Key: key, Msg: "must be a member of the workspace"}
+	}
+	return nil
+}
+
+type loadedValue struct {
+	IssueID string          `db:"issue_id"`
+	Key     string          `db:"key"`
+	Value   json.RawMessage `db:"value"`
+}
+
+func loadValues(ctx context.Context, q sqlx.QueryerContext, issueIDs []string) (map[string]Values, error) {
+	rows := []loadedValue{}
+	if err := sqlx.SelectContext(ctx, q, &rows,
+		`SELECT v.issue_id, f.key, v.value
+		 FROM issue_custom_values v
+		 JOIN custom_fields f ON f.id = v.custom_field_id
+		 WHERE v.issue_id::text = ANY($1)
+		   AND f.archived_at IS NULL`,
+		pq.Array(issueIDs),
+	); err != nil {
+		return nil, fmt.Errorf("load custom values: %w", err)
+	}
+	out := map[string]Values{}
+	for _, r := range rows {
+		if out[r.IssueID] == nil {
+			out[r.IssueID] = Values{}
+		}
+		out[r.IssueID][r.Key] = r.Value
+	}
+	return out, nil
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func dedupe(s []string) []string {
+	seen := map[string]bool{}
+	out := s[:0:0]
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
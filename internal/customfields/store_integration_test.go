@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package customfields
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestCustomFields(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	projectID := testpg.SeedProject(t, db, wsID, "CFA")
+	db.MustExec(`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'member')`, wsID, userID)
+
+	var taskID, bugID, statusID, issueID string
+	if err := db.GetContext(ctx, &taskID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert task type: %v", err)
+	}
+	if err := db.GetContext(ctx, &bugID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Bug', 1) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert bug type: %v", err)
+	}
+	if err := db.GetContext(ctx, &statusID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	if err := db.GetContext(ctx, &issueID,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, 1, $2, $3, 'Issue', '', 'medium', $4, 0) RETURNING id`,
+		projectID, taskID, statusID, userID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+
+	owner, err := Create(ctx, db, CreateParams{ProjectID: projectID, Key: "owner", Name: "Owner", Type: TypeUser})
+	if err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	if _, err := Create(ctx, db, CreateParams{ProjectID: projectID, Key: "owner", Name: "Owner 2", Type: TypeText}); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("duplicate key: error = %v, want ErrDuplicate", err)
+	}
+	severity, err := Create(ctx, db, CreateParams{
+		ProjectID: projectID, Key: "severity", Name: "Severity", Type: TypeSingleSelect,
+		Options: []string{"low", "high"}, Required: true, IssueTypeIDs: []string{bugID},
+	})
+	if err != nil {
+		t.Fatalf("create severity: %v", err)
+	}
+	if len(severity.IssueTypeIDs) != 1 || severity.Options[1] != "high" || severity.Position != owner.Position+1 {
+		t.Fatalf("created severity = %+v", severity)
+	}
+	if _, err := Create(ctx, db, CreateParams{ProjectID: projectID, Key: "x", Name: "X", Type: TypeText, IssueTypeIDs: []string{projectID}}); !errors.Is(err, ErrInvalidIssueType) {
+		t.Fatalf("foreign issue type: error = %v, want ErrInvalidIssueType", err)
+	}
+
+	set := func(values string, creating bool) (map[string]Change, error) {
+		var in map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(values), &in); err != nil {
+			t.Fatalf("decode values: %v", err)
+		}
+		var changes map[string]Change
+		err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit set values", func(tx *sqlx.Tx) error {
+			var err error
+			changes, err = SetValues(ctx, tx, SetParams{ProjectID: projectID, IssueID: issueID, IssueTypeID: taskID, Values: in, Creating: creating})
+			return err
+		})
+		return changes, err
+	}
+
+	// severity only applies to bugs, so it is neither required nor accepted on a task.
+	var ve *ValueError
+	if _, err := set(`{"severity": "low"}`, true); !errors.As(err, &ve) || ve.Key != "severity" {
+		t.Fatalf("set out-of-scope field: error = %v, want ValueError for severity", err)
+	}
+	if _, err := set(`{"owner": "00000000-0000-0000-0000-000000000000"}`, true); !errors.As(err, &ve) || ve.Key != "owner" {
+		t.Fatalf("set non-member owner: error = %v, want ValueError for owner", err)
+	}
+	changes, err := set(`{"owner": "`+userID+`"}`, true)
+	if err != nil {
+		t.Fatalf("set owner: %v", err)
+	}
+	if c := changes["owner"]; c.From != nil || c.To != userID {
+		t.Fatalf("owner change = %+v", c)
+	}
+
+	loaded, err := Load(ctx, db, []string{issueID})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if string(loaded[issueID]["owner"]) != `"`+userID+`"` {
+		t.Fatalf("loaded values = %v", loaded[issueID])
+	}
+
+	if err := Archive(ctx, db, projectID, owner.ID); err != nil {
+		t.Fatalf("archive owner: %v", err)
+	}
+	loaded, err = Load(ctx, db, []string{issueID})
+	if err != nil {
+		t.Fatalf("load after archive: %v", err)
+	}
+	if _, ok := loaded[issueID]; ok {
+		t.Fatalf("archived field still loaded: %v", loaded[issueID])
+	}
+	list, err := List(ctx, db, projectID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != severity.ID {
+		t.Fatalf("list = %+v, want only severity", list)
+	}
+}
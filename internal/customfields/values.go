@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package customfields
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const maxTextLen = 10000
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ValueError reports an invalid custom field value on an issue.
+type ValueError struct {
+	Key string
+	Msg string
+}
+
+func (e *ValueError) Error() string {
+	return "custom_fields." + e.Key + ": " + e.Msg
+}
+
+// isNull reports whether raw is absent or the JSON literal null.
+func isNull(raw json.RawMessage) bool {
+	return len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// normalize checks raw against the field type and returns the canonical
+// JSON to store. A nil result means the value is cleared; empty strings
+// and empty multi-selects clear the field too.
+func (f Field) normalize(raw json.RawMessage) (json.RawMessage, error) {
+	if isNull(raw) {
+		return nil, nil
+	}
+	bad := func(msg string) error { return &ValueError{Key: f.Key, Msg: msg} }
+
+	if f.Type == TypeNumber {
+		// json.Number also accepts quoted numbers, so reject strings first.
+		var n json.Number
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte(`"`)) || json.Unmarshal(raw, &n) != nil {
+			return nil, bad("must be a number")
+		}
+		if _, err := strconv.ParseFloat(n.String(), 64); err != nil {
+			return nil, bad("must be a number")
+		}
+		return json.RawMessage(n.String()), nil
+	}
+
+	if f.Type == TypeMultiSelect {
+		var picked []string
+		if err := json.Unmarshal(raw, &picked); err != nil {
+			return nil, bad("must be an array of options")
+		}
+		var out []string
+		for _, o := range f.Options {
+			if slices.Contains(picked, o) {
+				out = append(out, o)
+			}
+		}
+		for _, p := range picked {
+			if !slices.Contains(f.Options, p) {
+				return nil, bad("unknown option " + strconv.Quote(p))
+			}
+		}
+		if len(out) == 0 {
+			return nil, nil
+		}
+		return json.Marshal(out)
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, bad("must be a string")
+	}
+	if s == "" {
+		return nil, nil
+	}
+	switch f.Type {
+	case TypeText:
+		if len(s) > maxTextLen {
+			return nil, bad("must be at most 10000 characters")
+		}
+	case TypeDate:
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, bad("must be a YYYY-MM-DD date")
+		}
+	case TypeSingleSelect:
+		if !slices.Contains(f.Options, s) {
+			return nil, bad("unknown option " + strconv.Quote(s))
+		}
+	case TypeUser:
+		if !uuidRe.MatchString(s) {
+			return nil, bad("must be a user ID")
+		}
+	case TypeURL:
+		u, err := url.ParseRequestURI(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, bad("must be an http or https URL")
+		}
+	}
+	return json.Marshal(s)
+}
+
+// decoded returns a stored value as a plain Go value for event payloads.
+func decoded(raw json.RawMessage) any {
+	if raw == nil {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+	return v
+}
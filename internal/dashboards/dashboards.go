@@ -0,0 +1,302 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package dashboards keeps personal dashboards: named lists of widgets that
+// each show a saved filter as an issue list or as counts. Render runs every
+// widget of a dashboard in one call.
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/savedfilters"
+)
+
+var (
+	ErrNotFound       = errors.New("dashboard not found")
+	ErrFilterNotFound = errors.New("saved filter not found in workspace")
+)
+
+// Widget types.
+const (
+	// WidgetFilterResults lists the issues matching the filter.
+	WidgetFilterResults = "filter_results"
+	// WidgetStatusCategoryCounts counts them per status category.
+	WidgetStatusCategoryCounts = "status_category_counts"
+	// WidgetAssigneeCounts counts them per assignee.
+	WidgetAssigneeCounts = "assignee_counts"
+	// WidgetDueThisWeek lists those due in the current week, Monday to
+	// Sunday in UTC, soonest first.
+	WidgetDueThisWeek = "due_this_week"
+)
+
+var validTypes = map[string]bool{
+	WidgetFilterResults: true, WidgetStatusCategoryCounts: true,
+	WidgetAssigneeCounts: true, WidgetDueThisWeek: true,
+}
+
+const (
+	defaultWidgetLimit = 10
+	maxWidgetLimit     = 50
+	maxWidgets         = 20
+	maxNameLen         = 200
+)
+
+// Dashboard belongs to one user. Result is only set on the widgets of a
+// rendered dashboard.
+type Dashboard struct {
+	ID          string    `db:"id"           json:"id"`
+	WorkspaceID string    `db:"workspace_id" json:"workspace_id"`
+	OwnerID     string    `db:"owner_id"     json:"owner_id"`
+	Name        string    `db:"name"         json:"name"`
+	CreatedAt   time.Time `db:"created_at"   json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"   json:"updated_at"`
+	Widgets     []Widget  `db:"-"            json:"widgets"`
+}
+
+// Widget shows a saved filter. Limit caps the issues of list widgets.
+type Widget struct {
+	ID          string  `db:"id"           json:"id"`
+	DashboardID string  `db:"dashboard_id" json:"-"`
+	Position    int     `db:"position"     json:"position"`
+	Type        string  `db:"type"         json:"type"`
+	Title       string  `db:"title"        json:"title"`
+	FilterID    string  `db:"filter_id"    json:"filter_id"`
+	Limit       int     `db:"issue_limit"  json:"limit"`
+	Result      *Result `db:"-"            json:"result,omitempty"`
+}
+
+// Result is what a widget shows: Issues for list widgets, Counts for count
+// widgets. Error explains a widget that could not run, such as one whose
+// filter is no longer shared with the dashboard's owner; the other widgets
+// still render.
+type Result struct {
+	Issues  []issues.Issue `json:"issues,omitempty"`
+	HasMore bool           `json:"has_more,omitempty"`
+	Counts  []issues.Count `json:"counts,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// WidgetParams describes a widget; Limit defaults to 10.
+type WidgetParams struct {
+	Type     string
+	Title    string
+	FilterID string
+	Limit    int
+}
+
+type CreateParams struct {
+	WorkspaceID string
+	OwnerID     string
+	Name        string
+	Widgets     []WidgetParams
+}
+
+func (params CreateParams) Validate() error {
+	if params.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if params.OwnerID == "" {
+		return errors.New("owner_id is required")
+	}
+	return validate(params.Name, params.Widgets)
+}
+
+// UpdateParams renames a dashboard and replaces its widgets, in order.
+type UpdateParams struct {
+	DashboardID string
+	Name        string
+	Widgets     []WidgetParams
+}
+
+func (params UpdateParams) Validate() error {
+	if params.DashboardID == "" {
+		return errors.New("dashboard_id is required")
+	}
+	return validate(params.Name, params.Widgets)
+}
+
+func validate(name string, widgets []WidgetParams) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > maxNameLen {
+		return errors.New("name must be at most 200 characters")
+	}
+	if len(widgets) > maxWidgets {
+		return errors.New("a dashboard holds at most 20 widgets")
+	}
+	for i, w := range widgets {
+		if !validTypes[w.Type] {
+			return fmt.Errorf("widgets[%d]: type must be one of filter_results, status_category_counts, assignee_counts, due_this_week", i)
+		}
+		if w.FilterID == "" {
+			return fmt.Errorf("widgets[%d]: filter_id is required", i)
+		}
+		if len(w.Title) > maxNameLen {
+			return fmt.Errorf("widgets[%d]: title must be at most 200 characters", i)
+		}
+		if w.Limit < 0 || w.Limit > maxWidgetLimit {
+			return fmt.Errorf("widgets[%d]: limit must be between 0 and 50", i)
+		}
+	}
+	return nil
+}
+
+// normalize trims the names and fills in default limits.
+func normalize(name string, widgets []WidgetParams) (string, []WidgetParams) {
+	out := make([]WidgetParams, len(widgets))
+	for i, w := range widgets {
+		w.Title = strings.TrimSpace(w.Title)
+		if w.Limit == 0 {
+			w.Limit = defaultWidgetLimit
+		}
+		out[i] = w
+	}
+	return strings.TrimSpace(name), out
+}
+
+// Create saves a dashboard. Every widget filter must be visible to the
+// owner in the dashboard's workspace.
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Dashboard, error) {
+	if db == nil {
+		return Dashboard{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Dashboard{}, err
+	}
+	params.Name, params.Widgets = normalize(params.Name, params.Widgets)
+	return createDashboard(ctx, db, params)
+}
+
+// Get returns a dashboard of ownerID with its widgets. Dashboards of other
+// users are not found.
+func Get(ctx context.Context, db *sqlx.DB, dashboardID, ownerID string) (Dashboard, error) {
+	if db == nil {
+		return Dashboard{}, errors.New("db is required")
+	}
+	if dashboardID == "" {
+		return Dashboard{}, errors.New("dashboard_id is required")
+	}
+	if ownerID == "" {
+		return Dashboard{}, errors.New("owner_id is required")
+	}
+	return getDashboard(ctx, db, dashboardID, ownerID)
+}
+
+// List returns the dashboards of ownerID in a workspace, by name.
+func List(ctx context.Context, db *sqlx.DB, workspaceID, ownerID string) ([]Dashboard, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if workspaceID == "" {
+		return nil, errors.New("workspace_id is required")
+	}
+	if ownerID == "" {
+		return nil, errors.New("owner_id is required")
+	}
+	return listDashboards(ctx, db, workspaceID, ownerID)
+}
+
+// Update replaces a dashboard's name and widgets. Callers are responsible
+// for checking that the user owns it.
+func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Dashboard, error) {
+	if db == nil {
+		return Dashboard{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Dashboard{}, err
+	}
+	params.Name, params.Widgets = normalize(params.Name, params.Widgets)
+	return updateDashboard(ctx, db, params)
+}
+
+func Delete(ctx context.Context, db *sqlx.DB, dashboardID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if dashboardID == "" {
+		return errors.New("dashboard_id is required")
+	}
+	return deleteDashboard(ctx, db, dashboardID)
+}
+
+// Render runs the widgets of d for its owner and sets their Result. Each
+// filter is resolved with savedfilters.Scope, so check decides which
+// projects the widgets see. now picks the week of due-this-week widgets.
+func Render(ctx context.Context, db *sqlx.DB, d Dashboard, now time.Time, check savedfilters.ProjectCheck) (Dashboard, error) {
+	if db == nil {
+		return Dashboard{}, errors.New("db is required")
+	}
+	widgets := make([]Widget, len(d.Widgets))
+	for i, w := range d.Widgets {
+		result, err := renderWidget(ctx, db, d, w, now, check)
+		if err != nil {
+			return Dashboard{}, fmt.Errorf("render widget %s: %w", w.ID, err)
+		}
+		w.Result = &result
+		widgets[i] = w
+	}
+	d.Widgets = widgets
+	return d, nil
+}
+
+func renderWidget(ctx context.Context, db *sqlx.DB, d Dashboard, w Widget, now time.Time, check savedfilters.ProjectCheck) (Result, error) {
+	f, err := savedfilters.Get(ctx, db, w.FilterID, d.OwnerID)
+	if errors.Is(err, savedfilters.ErrNotFound) || (err == nil && f.WorkspaceID != d.WorkspaceID) {
+		return Result{Error: ErrFilterNotFound.Error()}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	params, ok, err := savedfilters.Scope(ctx, db, f, d.OwnerID, check)
+	if errors.Is(err, savedfilters.ErrInvalidQuery) {
+		return Result{Error: err.Error()}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	if !ok {
+		return Result{}, nil
+	}
+
+	switch w.Type {
+	case WidgetStatusCategoryCounts, WidgetAssigneeCounts:
+		group := issues.CountByStatusCategory
+		if w.Type == WidgetAssigneeCounts {
+			group = issues.CountByAssignee
+		}
+		counts, err := issues.CountBy(ctx, db, params, group)
+		if err != nil {
+			return Result{}, err
+		}
+		return Result{Counts: counts}, nil
+	case WidgetDueThisWeek:
+		after, before := week(now)
+		params.DueAfter, params.DueBefore = &after, &before
+		params.Sort = "due_date"
+	}
+	params.Limit = w.Limit
+	page, err := issues.List(ctx, db, params)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Issues: page.Issues, HasMore: page.HasMore}, nil
+}
+
+// week returns exclusive due date bounds selecting the Monday to Sunday
+// week, in UTC, that holds now.
+func week(now time.Time) (after, before time.Time) {
+	now = now.UTC()
+	monday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).
+		AddDate(0, 0, -(int(now.Weekday())+6)%7)
+	return monday.AddDate(0, 0, -1), monday.AddDate(0, 0, 7)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package dashboards
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateDashboardParams_Validate(t *testing.T) {
+	valid := CreateParams{
+		WorkspaceID: "w",
+		OwnerID:     "u",
+		Name:        "My week",
+		Widgets: []WidgetParams{
+			{Type: WidgetFilterResults, FilterID: "f"},
+			{Type: WidgetStatusCategoryCounts, FilterID: "f", Title: "By status"},
+			{Type: WidgetAssigneeCounts, FilterID: "f"},
+			{Type: WidgetDueThisWeek, FilterID: "f", Limit: 50},
+		},
+	}
+	widget := func(w WidgetParams) CreateParams { c := valid; c.Widgets = []WidgetParams{w}; return c }
+
+	tests := []struct {
+		name    string
+		params  CreateParams
+		wantErr bool
+	}{
+		{name: "valid", params: valid, wantErr: false},
+		{name: "no widgets", params: func() CreateParams { c := valid; c.Widgets = nil; return c }(), wantErr: false},
+		{name: "missing workspace_id", params: func() CreateParams { c := valid; c.WorkspaceID = ""; return c }(), wantErr: true},
+		{name: "missing owner_id", params: func() CreateParams { c := valid; c.OwnerID = ""; return c }(), wantErr: true},
+		{name: "blank name", params: func() CreateParams { c := valid; c.Name = " "; return c }(), wantErr: true},
+		{name: "long name", params: func() CreateParams { c := valid; c.Name = strings.Repeat("n", 201); return c }(), wantErr: true},
+		{name: "too many widgets", params: func() CreateParams {
+			c := valid
+			c.Widgets = make([]WidgetParams, 21)
+			for i := range c.Widgets {
+				c.Widgets[i] = WidgetParams{Type: WidgetFilterResults, FilterID: "f"}
+			}
+			return c
+		}(), wantErr: true},
+		{name: "unknown type", params: widget(WidgetParams{Type: "burndown", FilterID: "f"}), wantErr: true},
+		{name: "missing filter_id", params: widget(WidgetParams{Type: WidgetFilterResults}), wantErr: true},
+		{name: "long title", params: widget(WidgetParams{Type: WidgetFilterResults, FilterID: "f", Title: strings.Repeat("t", 201)}), wantErr: true},
+		{name: "negative limit", params: widget(WidgetParams{Type: WidgetFilterResults, FilterID: "f", Limit: -1}), wantErr: true},
+		{name: "limit too high", params: widget(WidgetParams{Type: WidgetFilterResults, FilterID: "f", Limit: 51}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateDashboardParams_Validate(t *testing.T) {
+	valid := UpdateParams{DashboardID: "d", Name: "Mine"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	missing := valid
+	missing.DashboardID = ""
+	if err := missing.Validate(); err == nil {
+		t.Fatal("Validate() without dashboard_id: expected error")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	name, widgets := normalize("  Mine ", []WidgetParams{
+		{Type: WidgetFilterResults, Title: " Open ", FilterID: "f"},
+		{Type: WidgetDueThisWeek, FilterID: "f", Limit: 3},
+	})
+	if name != "Mine" {
+		t.Fatalf("name = %q, want %q", name, "Mine")
+	}
+	if widgets[0].Title != "Open" || widgets[0].Limit != defaultWidgetLimit || widgets[1].Limit != 3 {
+		t.Fatalf("widgets = %+v", widgets)
+	}
+}
+
+func TestWeek(t *testing.T) {
+	monday := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	for _, now := range []time.Time{
+		monday,
+		time.Date(2025, 6, 4, 15, 0, 0, 0, time.UTC),
+		time.Date(2025, 6, 8, 23, 59, 0, 0, time.UTC),
+		// Monday morning in Auckland is still Sunday in UTC.
+		time.Date(2025, 6, 9, 9, 0, 0, 0, time.FixedZone("NZST", 12*60*60)),
+	} {
+		after, before := week(now)
+		if !after.Equal(monday.AddDate(0, 0, -1)) || !before.Equal(monday.AddDate(0, 0, 7)) {
+			t.Errorf("week(%s) = %s, %s", now, after, before)
+		}
+	}
+}
+
+func TestCreateDashboard_NilDB(t *testing.T) {
+	_, err := Create(context.Background(), nil, CreateParams{WorkspaceID: "w", OwnerID: "u", Name: "n"})
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("Create() error = %v, want %q", err, "db is required")
+	}
+}
+
+func TestRender_NilDB(t *testing.T) {
+	_, err := Render(context.Background(), nil, Dashboard{}, time.Now(), nil)
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("Render() error = %v, want %q", err, "db is required")
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package dashboards
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+	"github.com/start-codex/tookly/internal/savedfilters"
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("POST /workspaces/{workspaceID}/dashboards", handleCreate(db))
+	mux.HandleFunc("GET /workspaces/{workspaceID}/dashboards", handleList(db))
+	mux.HandleFunc("GET /dashboards/{dashboardID}", handleGet(db))
+	mux.HandleFunc("PUT /dashboards/{dashboardID}", handleUpdate(db))
+	mux.HandleFunc("DELETE /dashboards/{dashboardID}", handleDelete(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrFilterNotFound):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		slog.Error("dashboards handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+type widgetBody struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	FilterID string `json:"filter_id"`
+	Limit    int    `json:"limit"`
+}
+
+type dashboardBody struct {
+	Name    string       `json:"name"`
+	Widgets []widgetBody `json:"widgets"`
+}
+
+func (b dashboardBody) widgets() []WidgetParams {
+	out := make([]WidgetParams, len(b.Widgets))
+	for i, w := range b.Widgets {
+		out[i] = WidgetParams{Type: w.Type, Title: w.Title, FilterID: w.FilterID, Limit: w.Limit}
+	}
+	return out
+}
+
+func handleCreate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID := r.PathValue("workspaceID")
+		if err := authz.RequireWorkspaceMembership(r.Context(), db, wsID); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body dashboardBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := CreateParams{
+			WorkspaceID: wsID,
+			OwnerID:     authedUserID,
+			Name:        body.Name,
+			Widgets:     body.widgets(),
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		d, err := Create(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusCreated, d)
+	}
+}
+
+func handleList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID := r.PathValue("workspaceID")
+		if err := authz.RequireWorkspaceMembership(r.Context(), db, wsID); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := List(r.Context(), db, wsID, authedUserID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+// requireDashboard loads a dashboard of the authenticated user, who must
+// still belong to its workspace.
+func requireDashboard(r *http.Request, db *sqlx.DB) (Dashboard, error) {
+	authedUserID, err := authz.UserIDFromContext(r.Context())
+	if err != nil {
+		return Dashboard{}, err
+	}
+	d, err := Get(r.Context(), db, r.PathValue("dashboardID"), authedUserID)
+	if err != nil {
+		return Dashboard{}, err
+	}
+	if err := authz.RequireWorkspaceMembership(r.Context(), db, d.WorkspaceID); err != nil {
+		return Dashboard{}, err
+	}
+	return d, nil
+}
+
+// handleGet answers the dashboard with every widget rendered. Widgets only
+// see the projects authz lets the user read.
+func handleGet(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := requireDashboard(r, db)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		rendered, err := Render(r.Context(), db, d, time.Now(), savedfilters.CheckProject(db))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, rendered)
+	}
+}
+
+func handleUpdate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := requireDashboard(r, db)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body dashboardBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := UpdateParams{
+			DashboardID: d.ID,
+			Name:        body.Name,
+			Widgets:     body.widgets(),
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		updated, err := Update(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, updated)
+	}
+}
+
+func handleDelete(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := requireDashboard(r, db)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if err := Delete(r.Context(), db, d.ID); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
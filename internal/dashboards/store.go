@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package dashboards
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+const dashboardCols = `id, workspace_id, owner_id, name, created_at, updated_at`
+
+const widgetCols = `id, dashboard_id, position, type, title, filter_id, issue_limit`
+
+func createDashboard(ctx context.Context, db *sqlx.DB, params CreateParams) (Dashboard, error) {
+	var d Dashboard
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create dashboard", func(tx *sqlx.Tx) error {
+		if err := checkFilters(ctx, tx, params.WorkspaceID, params.OwnerID, params.Widgets); err != nil {
+			return err
+		}
+		if err := tx.QueryRowxContext(ctx,
+			`INSERT INTO dashboards (workspace_id, owner_id, name)
+			 VALUES ($1, $2, $3)
+			 RETURNING `+dashboardCols,
+			params.WorkspaceID, params.OwnerID, params.Name,
+		).StructScan(&d); err != nil {
+			return fmt.Errorf("insert dashboard: %w", err)
+		}
+		widgets, err := insertWidgets(ctx, tx, d.ID, params.Widgets)
+		if err != nil {
+			return err
+		}
+		d.Widgets = widgets
+		return nil
+	})
+	return d, err
+}
+
+func getDashboard(ctx context.Context, db *sqlx.DB, dashboardID, ownerID string) (Dashboard, error) {
+	var d Dashboard
+	if err := db.GetContext(ctx, &d,
+		`SELECT `+dashboardCols+`
+		 FROM dashboards
+		 WHERE id = $1
+		   AND owner_id = $2`,
+		dashboardID, ownerID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Dashboard{}, ErrNotFound
+		}
+		return Dashboard{}, fmt.Errorf("get dashboard: %w", err)
+	}
+	list := []Dashboard{d}
+	if err := attachWidgets(ctx, db, list); err != nil {
+		return Dashboard{}, err
+	}
+	return list[0], nil
+}
+
+func listDashboards(ctx context.Context, db *sqlx.DB, workspaceID, ownerID string) ([]Dashboard, error) {
+	list := []Dashboard{}
+	if err := db.SelectContext(ctx, &list,
+		`SELECT `+dashboardCols+`
+		 FROM dashboards
+		 WHERE workspace_id = $1
+		   AND owner_id = $2
+		 ORDER BY lower(name), created_at, id`,
+		workspaceID, ownerID,
+	); err != nil {
+		return nil, fmt.Errorf("list dashboards: %w", err)
+	}
+	if err := attachWidgets(ctx, db, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func updateDashboard(ctx context.Context, db *sqlx.DB, params UpdateParams) (Dashboard, error) {
+	var d Dashboard
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update dashboard", func(tx *sqlx.Tx) error {
+		if err := tx.GetContext(ctx, &d,
+			`SELECT `+dashboardCols+` FROM dashboards WHERE id = $1 FOR UPDATE`,
+			params.DashboardID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("lock dashboard: %w", err)
+		}
+		if err := checkFilters(ctx, tx, d.WorkspaceID, d.OwnerID, params.Widgets); err != nil {
+			return err
+		}
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE dashboards SET name = $1 WHERE id = $2 RETURNING `+dashboardCols,
+			params.Name, params.DashboardID,
+		).StructScan(&d); err != nil {
+			return fmt.Errorf("update dashboard: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM dashboard_widgets WHERE dashboard_id = $1`, d.ID); err != nil {
+			return fmt.Errorf("delete dashboard widgets: %w", err)
+		}
+		widgets, err := insertWidgets(ctx, tx, d.ID, params.Widgets)
+		if err != nil {
+			return err
+		}
+		d.Widgets = widgets
+		return nil
+	})
+	return d, err
+}
+
+func deleteDashboard(ctx context.Context, db *sqlx.DB, dashboardID string) error {
+	res, err := db.ExecContext(ctx, `DELETE FROM dashboards WHERE id = $1`, dashboardID)
+	if err != nil {
+		return fmt.Errorf("delete dashboard: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete dashboard rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// checkFilters fails with ErrFilterNotFound unless every widget filter is
+// in the workspace and owned by or shared with ownerID. IDs are compared as
+// text so malformed ones are not found rather than rejected by Postgres.
+func checkFilters(ctx context.Context, tx *sqlx.Tx, workspaceID, ownerID string, widgets []WidgetParams) error {
+	var ids []string
+	for _, w := range widgets {
+		if id := strings.ToLower(w.FilterID); !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	var n int
+	if err := tx.GetContext(ctx, &n,
+		`SELECT COUNT(*)
+		 FROM saved_filters
+		 WHERE id::text = ANY($1)
+		   AND workspace_id = $2
+		   AND (owner_id = $3 OR shared)`,
+		pq.Array(ids), workspaceID, ownerID,
+	); err != nil {
+		return fmt.Errorf("check dashboard filters: %w", err)
+	}
+	if n != len(ids) {
+		return ErrFilterNotFound
+	}
+	return nil
+}
+
+func insertWidgets(ctx context.Context, tx *sqlx.Tx, dashboardID string, widgets []WidgetParams) ([]Widget, error) {
+	out := make([]Widget, 0, len(widgets))
+	for i, w := range widgets {
+		var widget Widget
+		if err := tx.QueryRowxContext(ctx,
+			`INSERT INTO dashboard_widgets (dashboard_id, position, type, title, filter_id, issue_limit)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 RETURNING `+widgetCols,
+			dashboardID, i, w.Type, w.Title, w.FilterID, w.Limit,
+		).StructScan(&widget); err != nil {
+			return nil, fmt.Errorf("insert dashboard widget: %w", err)
+		}
+		out = append(out, widget)
+	}
+	return out, nil
+}
+
+func attachWidgets(ctx context.Context, db *sqlx.DB, list []Dashboard) error {
+	if len(list) == 0 {
+		return nil
+	}
+	ids := make([]string, len(list))
+	for i, d := range list {
+		ids[i] = d.ID
+	}
+	var widgets []Widget
+	if err := db.SelectContext(ctx, &widgets,
+		`SELECT `+widgetCols+`
+		 FROM dashboard_widgets
+		 WHERE dashboard_id = ANY($1)
+		 ORDER BY dashboard_id, position`,
+		pq.Array(ids),
+	); err != nil {
+		return fmt.Errorf("list dashboard widgets: %w", err)
+	}
+	byDashboard := make(map[string][]Widget, len(list))
+	for _, w := range widgets {
+		byDashboard[w.DashboardID] = append(byDashboard[w.DashboardID], w)
+	}
+	for i := range list {
+		list[i].Widgets = byDashboard[list[i].ID]
+		if list[i].Widgets == nil {
+			list[i].Widgets = []Widget{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/savedfilters"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestDashboards(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	alice := testpg.SeedUser(t, db)
+	bob := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	projID := testpg.SeedProject(t, db, wsID, "DSH")
+	var typeID, todoID, doneID string
+	if err := db.GetContext(ctx, &typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projID); err != nil {
+		t.Fatalf("insert issue_type: %v", err)
+	}
+	if err := db.GetContext(ctx, &todoID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, projID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	if err := db.GetContext(ctx, &doneID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'Done', 'done', 1) RETURNING id`, projID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+
+	// Wednesday 4 June 2025; the week runs from 2 to 8 June.
+	now := time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+	day := func(d int) *time.Time { due := time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC); return &due }
+	create := func(title, statusID, assigneeID string, due *time.Time) issues.Issue {
+		t.Helper()
+		issue, err := issues.Create(ctx, db, issues.CreateParams{
+			ProjectID: projID, IssueTypeID: typeID, StatusID: statusID,
+			Title: title, Priority: "medium", AssigneeID: assigneeID, ReporterID: alice, DueDate: due,
+		})
+		if err != nil {
+			t.Fatalf("create issue %s: %v", title, err)
+		}
+		return issue
+	}
+	sunday := create("Sunday", todoID, alice, day(8))
+	monday := create("Monday", todoID, "", day(2))
+	create("Next week", todoID, alice, day(9))
+	create("Last week", doneID, bob, day(1))
+	create("No due date", doneID, "", nil)
+
+	everything, err := savedfilters.Create(ctx, db, savedfilters.CreateParams{WorkspaceID: wsID, OwnerID: alice, Name: "Everything"})
+	if err != nil {
+		t.Fatalf("create filter: %v", err)
+	}
+	bobs, err := savedfilters.Create(ctx, db, savedfilters.CreateParams{WorkspaceID: wsID, OwnerID: bob, Name: "Mine", Query: "assignee_id=me", Shared: true})
+	if err != nil {
+		t.Fatalf("create filter: %v", err)
+	}
+
+	d, err := Create(ctx, db, CreateParams{
+		WorkspaceID: wsID,
+		OwnerID:     alice,
+		Name:        "My week",
+		Widgets: []WidgetParams{
+			{Type: WidgetFilterResults, FilterID: everything.ID, Limit: 2},
+			{Type: WidgetStatusCategoryCounts, FilterID: everything.ID},
+			{Type: WidgetAssigneeCounts, FilterID: everything.ID},
+			{Type: WidgetDueThisWeek, FilterID: everything.ID},
+			{Type: WidgetFilterResults, Title: "Bob's", FilterID: bobs.ID},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if len(d.Widgets) != 5 || d.Widgets[1].Limit != defaultWidgetLimit || d.Widgets[4].Position != 4 {
+		t.Fatalf("Create() widgets = %+v", d.Widgets)
+	}
+
+	if _, err := Get(ctx, db, d.ID, bob); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() by bob error = %v, want ErrNotFound", err)
+	}
+	d, err = Get(ctx, db, d.ID, alice)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	rendered, err := Render(ctx, db, d, now, nil)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	results := make([]Result, len(rendered.Widgets))
+	for i, w := range rendered.Widgets {
+		results[i] = *w.Result
+	}
+	if len(results[0].Issues) != 2 || !results[0].HasMore {
+		t.Fatalf("filter results = %+v, want two issues and more", results[0])
+	}
+	if c := results[1].Counts; len(c) != 2 || c[0] != (issues.Count{Key: "todo", Count: 3}) || c[1] != (issues.Count{Key: "done", Count: 2}) {
+		t.Fatalf("status category counts = %+v", c)
+	}
+	if c := results[2].Counts; len(c) != 3 || c[0].Key != "" || c[0].Count != 2 || c[1].Count != 2 {
+		t.Fatalf("assignee counts = %+v", c)
+	}
+	if got := results[3].Issues; len(got) != 2 || got[0].ID != monday.ID || got[1].ID != sunday.ID {
+		t.Fatalf("due this week = %+v, want Monday then Sunday", got)
+	}
+	if got := results[4].Issues; len(got) != 1 || results[4].Error != "" {
+		t.Fatalf("bob's filter = %+v, want bob's issue", results[4])
+	}
+
+	// A filter that stops being shared turns into a widget error.
+	if _, err := savedfilters.Update(ctx, db, savedfilters.UpdateParams{FilterID: bobs.ID, Name: bobs.Name, Query: bobs.Query}); err != nil {
+		t.Fatalf("unshare filter: %v", err)
+	}
+	rendered, err = Render(ctx, db, d, now, nil)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if r := rendered.Widgets[4].Result; r.Error != ErrFilterNotFound.Error() || r.Issues != nil {
+		t.Fatalf("unshared widget = %+v, want an error", r)
+	}
+	if len(rendered.Widgets[0].Result.Issues) != 2 {
+		t.Fatal("other widgets should still render")
+	}
+
+	if _, err := Update(ctx, db, UpdateParams{DashboardID: d.ID, Name: "Mine", Widgets: []WidgetParams{{Type: WidgetFilterResults, FilterID: bobs.ID}}}); !errors.Is(err, ErrFilterNotFound) {
+		t.Fatalf("Update() with an unshared filter error = %v, want ErrFilterNotFound", err)
+	}
+	d, err = Update(ctx, db, UpdateParams{DashboardID: d.ID, Name: "Mine", Widgets: []WidgetParams{{Type: WidgetAssigneeCounts, FilterID: everything.ID}}})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if d.Name != "Mine" || len(d.Widgets) != 1 || d.Widgets[0].Position != 0 {
+		t.Fatalf("Update() = %+v", d)
+	}
+
+	// Deleting a filter removes its widgets.
+	if err := savedfilters.Delete(ctx, db, everything.ID); err != nil {
+		t.Fatalf("delete filter: %v", err)
+	}
+	list, err := List(ctx, db, wsID, alice)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 1 || len(list[0].Widgets) != 0 {
+		t.Fatalf("List() = %+v, want one dashboard without widgets", list)
+	}
+
+	if err := Delete(ctx, db, d.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := Delete(ctx, db, d.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete() twice error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package digests emails in-app notifications. Each user chooses, per
+// notification type, immediate delivery, an hourly or daily digest, or no
+// email at all. A Worker batches pending notifications on that schedule
+// and sends them with the instance SMTP settings. Every email carries a
+// signed unsubscribe link that works without a session.
+package digests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/issues"
+)
+
+// Deliveries.
+const (
+	DeliveryImmediate = "immediate"
+	DeliveryHourly    = "hourly"
+	DeliveryDaily     = "daily"
+	DeliveryOff       = "off"
+)
+
+// DefaultDelivery applies to notification types a user has not configured.
+const DefaultDelivery = DeliveryDaily
+
+// EventTypes are the notification types preferences can be set for.
+var EventTypes = []string{
+	issues.EventCreated, issues.EventUpdated, issues.EventMoved,
+	issues.EventArchived, issues.EventCommented,
+}
+
+var (
+	ErrInvalidPreferences = errors.New("preferences must map notification types to immediate, hourly, daily or off")
+	ErrInvalidToken       = errors.New("invalid unsubscribe link")
+)
+
+// window is how long a digest collects notifications before it is sent,
+// counted from its oldest notification.
+var window = map[string]time.Duration{
+	DeliveryImmediate: 0,
+	DeliveryHourly:    time.Hour,
+	DeliveryDaily:     24 * time.Hour,
+}
+
+// Preferences maps notification types to deliveries.
+type Preferences map[string]string
+
+// Validate accepts a partial set of known types with known deliveries.
+func (p Preferences) Validate() error {
+	for eventType, delivery := range p {
+		if !isEventType(eventType) {
+			return ErrInvalidPreferences
+		}
+		if _, ok := window[delivery]; !ok && delivery != DeliveryOff {
+			return ErrInvalidPreferences
+		}
+	}
+	return nil
+}
+
+func isEventType(s string) bool {
+	for _, t := range EventTypes {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPreferences returns the delivery of every notification type for a
+// user, defaults included.
+func GetPreferences(ctx context.Context, db *sqlx.DB, userID string) (Preferences, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	return getPreferences(ctx, db, userID)
+}
+
+// SetPreferences changes the deliveries of the types in p, keeps the others
+// and returns the full result.
+func SetPreferences(ctx context.Context, db *sqlx.DB, userID string, p Preferences) (Preferences, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := setPreferences(ctx, db, userID, p); err != nil {
+		return nil, err
+	}
+	return getPreferences(ctx, db, userID)
+}
+
+// Unsubscribe turns notification emails off for every type, given a token
+// from an unsubscribe link.
+func Unsubscribe(ctx context.Context, db *sqlx.DB, userID, token string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if userID == "" || token == "" {
+		return ErrInvalidToken
+	}
+	secret, err := unsubscribeSecret(ctx, db)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(token), []byte(signUnsubscribe(secret, userID))) {
+		return ErrInvalidToken
+	}
+	off := Preferences{}
+	for _, t := range EventTypes {
+		off[t] = DeliveryOff
+	}
+	return setPreferences(ctx, db, userID, off)
+}
+
+// UnsubscribeURL returns the one-click unsubscribe link for a user.
+func UnsubscribeURL(ctx context.Context, db *sqlx.DB, baseURL, userID string) (string, error) {
+	if db == nil {
+		return "", errors.New("db is required")
+	}
+	secret, err := unsubscribeSecret(ctx, db)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{"user": {userID}, "token": {signUnsubscribe(secret, userID)}}
+	return baseURL + "/api/notifications/unsubscribe?" + q.Encode(), nil
+}
+
+func signUnsubscribe(secret, userID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("unsubscribe:" + userID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
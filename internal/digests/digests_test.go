@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package digests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/start-codex/tookly/internal/notifications"
+)
+
+func TestPreferences_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefs   Preferences
+		wantErr bool
+	}{
+		{name: "empty", prefs: Preferences{}},
+		{name: "partial", prefs: Preferences{"commented": "immediate", "moved": "off"}},
+		{name: "all deliveries", prefs: Preferences{"created": "immediate", "updated": "hourly", "moved": "daily", "archived": "off"}},
+		{name: "unknown type", prefs: Preferences{"mentioned": "immediate"}, wantErr: true},
+		{name: "unknown delivery", prefs: Preferences{"updated": "weekly"}, wantErr: true},
+		{name: "empty delivery", prefs: Preferences{"updated": ""}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.prefs.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidPreferences) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidPreferences", err)
+			}
+		})
+	}
+}
+
+func TestSignUnsubscribe(t *testing.T) {
+	a := signUnsubscribe("secret", "user-a")
+	if a != signUnsubscribe("secret", "user-a") {
+		t.Fatal("signature is not deterministic")
+	}
+	if a == signUnsubscribe("secret", "user-b") {
+		t.Fatal("signature does not depend on the user")
+	}
+	if a == signUnsubscribe("other", "user-a") {
+		t.Fatal("signature does not depend on the secret")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	n := func(eventType, data string) notifications.Notification {
+		return notifications.Notification{Type: eventType, Data: json.RawMessage(data)}
+	}
+	long := strings.Repeat("word ", 100)
+	tests := []struct {
+		name        string
+		n           notifications.Notification
+		wantSummary string
+		wantExcerpt string
+	}{
+		{"created", n("created", `{"changes":{"title":{"from":null,"to":"x"}}}`), "created the issue", ""},
+		{"moved", n("moved", `{"changes":{"status_id":{}}}`), "moved the issue", ""},
+		{"updated", n("updated", `{"changes":{"title":{},"assignee_id":{},"custom_fields.team":{}}}`), "changed assignee, team, title", ""},
+		{"updated without changes", n("updated", `{}`), "updated the issue", ""},
+		{"commented", n("commented", `{"comment":{"body":"  Looks\n\ngood  "}}`), "commented", "Looks good"},
+		{"long comment", n("commented", `{"comment":{"body":"`+long+`"}}`), "commented", strings.TrimSpace(long)[:maxExcerptLen] + "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, excerpt := summarize(tt.n)
+			if summary != tt.wantSummary || excerpt != tt.wantExcerpt {
+				t.Fatalf("summarize() = %q, %q; want %q, %q", summary, excerpt, tt.wantSummary, tt.wantExcerpt)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	actor := "Ada"
+	item := func(key, title string) notifications.Notification {
+		return notifications.Notification{
+			IssueID: "issue", ProjectID: "project", IssueKey: key, IssueTitle: title,
+			ActorName: &actor, Type: "updated", Data: json.RawMessage(`{"changes":{"priority":{}}}`),
+			CreatedAt: time.Date(2025, 3, 4, 9, 30, 0, 0, time.UTC),
+		}
+	}
+	to := recipient{Name: "Grace", Email: "grace@example.com"}
+	const unsubscribeURL = "https://tookly.example/api/notifications/unsubscribe?token=t&user=u"
+
+	msg, err := render(to, []notifications.Notification{item("TK-1", "<b>Fix</b> login")}, DeliveryImmediate, "https://tookly.example", unsubscribeURL)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if msg.To != to.Email || msg.Subject != "[Tookly] TK-1 <b>Fix</b> login" {
+		t.Fatalf("message = %+v", msg)
+	}
+	for _, want := range []string{
+		"https://tookly.example/projects/project/issues/issue",
+		"&lt;b&gt;Fix&lt;/b&gt; login",
+		"Ada changed priority",
+		"Mar 4, 09:30 UTC",
+		"https://tookly.example/api/notifications/unsubscribe?token=t&amp;user=u",
+	} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("body does not contain %q", want)
+		}
+	}
+	if msg.Headers["List-Unsubscribe"] != "<"+unsubscribeURL+">" || msg.Headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" {
+		t.Errorf("headers = %v", msg.Headers)
+	}
+
+	msg, err = render(to, []notifications.Notification{item("TK-1", "One"), item("TK-2", "Two")}, DeliveryDaily, "https://tookly.example", unsubscribeURL)
+	if err != nil {
+		t.Fatalf("render digest: %v", err)
+	}
+	if msg.Subject != "[Tookly] Daily digest: 2 updates" {
+		t.Fatalf("digest subject = %q", msg.Subject)
+	}
+	if strings.Index(msg.Body, "TK-2") > strings.Index(msg.Body, "TK-1") {
+		t.Error("digest does not list the newest notification first")
+	}
+}
+
+func TestNilDB(t *testing.T) {
+	ctx := context.Background()
+	if _, err := GetPreferences(ctx, nil, "u"); err == nil {
+		t.Error("GetPreferences: want error for nil db")
+	}
+	if _, err := SetPreferences(ctx, nil, "u", Preferences{}); err == nil {
+		t.Error("SetPreferences: want error for nil db")
+	}
+	if err := Unsubscribe(ctx, nil, "u", "t"); err == nil {
+		t.Error("Unsubscribe: want error for nil db")
+	}
+	if _, err := UnsubscribeURL(ctx, nil, "https://tookly.example", "u"); err == nil {
+		t.Error("UnsubscribeURL: want error for nil db")
+	}
+	if _, err := (&Worker{}).RunOnce(ctx); err == nil {
+		t.Error("RunOnce: want error for nil db")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package digests
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+const unsubscribedPage = `<!DOCTYPE html>
+<html>
+<head><meta charset="UTF-8"><title>Unsubscribed</title></head>
+<body style="font-family: sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
+  <h2 style="color: #111;">You're unsubscribed</h2>
+  <p>Tookly will no longer email you about issues you watch. You can turn emails back on in your preferences.</p>
+</body>
+</html>
+`
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("GET /notifications/preferences", handleGetPreferences(db))
+	mux.HandleFunc("PUT /notifications/preferences", handleSetPreferences(db))
+	// Unsubscribe links are public: the signed token authenticates them.
+	// GET serves links clicked in an email, POST one-click unsubscribes
+	// from mail clients (RFC 8058).
+	mux.HandleFunc("GET /notifications/unsubscribe", handleUnsubscribe(db, true))
+	mux.HandleFunc("POST /notifications/unsubscribe", handleUnsubscribe(db, false))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, ErrInvalidPreferences):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, ErrInvalidToken):
+		respond.Error(w, http.StatusBadRequest, err.Error())
+	default:
+		slog.Error("digests handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func handleGetPreferences(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		prefs, err := GetPreferences(r.Context(), db, authedUserID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, prefs)
+	}
+}
+
+// handleSetPreferences takes a partial map of notification types to
+// deliveries and returns the full preferences.
+func handleSetPreferences(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body Preferences
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		prefs, err := SetPreferences(r.Context(), db, authedUserID, body)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, prefs)
+	}
+}
+
+func handleUnsubscribe(db *sqlx.DB, page bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if err := Unsubscribe(r.Context(), db, q.Get("user"), q.Get("token")); err != nil {
+			fail(w, err)
+			return
+		}
+		if !page {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(unsubscribedPage))
+	}
+}
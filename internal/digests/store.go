@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package digests
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/notifications"
+)
+
+const secretKey = "notification_unsubscribe_secret"
+
+// deliveryOf resolves the delivery of notification n (alias) for its
+// recipient; $1 must be the default delivery.
+const deliveryOf = `COALESCE((
+	SELECT np.delivery
+	FROM notification_preferences np
+	WHERE np.user_id = n.user_id
+	  AND np.event_type = n.event_type
+), $1)`
+
+func getPreferences(ctx context.Context, db *sqlx.DB, userID string) (Preferences, error) {
+	var rows []struct {
+		EventType string `db:"event_type"`
+		Delivery  string `db:"delivery"`
+	}
+	if err := db.SelectContext(ctx, &rows,
+		`SELECT event_type, delivery FROM notification_preferences WHERE user_id = $1`,
+		userID,
+	); err != nil {
+		return nil, fmt.Errorf("get notification preferences: %w", err)
+	}
+	p := Preferences{}
+	for _, t := range EventTypes {
+		p[t] = DefaultDelivery
+	}
+	for _, row := range rows {
+		if isEventType(row.EventType) {
+			p[row.EventType] = row.Delivery
+		}
+	}
+	return p, nil
+}
+
+func setPreferences(ctx context.Context, db *sqlx.DB, userID string, p Preferences) error {
+	types := make([]string, 0, len(p))
+	deliveries := make([]string, 0, len(p))
+	for t, d := range p {
+		types = append(types, t)
+		deliveries = append(deliveries, d)
+	}
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO notification_preferences (user_id, event_type, delivery)
+		 SELECT $1, t, d FROM unnest($2::text[], $3::text[]) AS u(t, d)
+		 ON CONFLICT (user_id, event_type) DO UPDATE SET delivery = EXCLUDED.delivery`,
+		userID, pq.Array(types), pq.Array(deliveries),
+	); err != nil {
+		return fmt.Errorf("set notification preferences: %w", err)
+	}
+	return nil
+}
+
+// unsubscribeSecret returns the key that signs unsubscribe links, creating
+// it on first use.
+func unsubscribeSecret(ctx context.Context, db *sqlx.DB) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate unsubscribe secret: %w", err)
+	}
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO instance_config (key, value) VALUES ($1, $2) ON CONFLICT (key) DO NOTHING`,
+		secretKey, hex.EncodeToString(b),
+	); err != nil {
+		return "", fmt.Errorf("store unsubscribe secret: %w", err)
+	}
+	var secret string
+	if err := db.GetContext(ctx, &secret, `SELECT value FROM instance_config WHERE key = $1`, secretKey); err != nil {
+		return "", fmt.Errorf("load unsubscribe secret: %w", err)
+	}
+	return secret, nil
+}
+
+// skipPending marks pending notifications that will never be emailed as
+// processed: read ones, those whose delivery is off, those the recipient
+// can no longer see and those older than maxAge.
+func skipPending(ctx context.Context, db *sqlx.DB, maxAge time.Duration) error {
+	if _, err := db.ExecContext(ctx,
+		`UPDATE notifications n
+		 SET email_processed_at = NOW()
+		 WHERE n.email_processed_at IS NULL
+		   AND (
+		     n.read_at IS NOT NULL
+		     OR n.created_at < NOW() - make_interval(secs => $2)
+		     OR `+deliveryOf+` = 'off'
+		     OR NOT EXISTS (
+		       SELECT 1
+		       FROM issues i
+		       JOIN projects p ON p.id = i.project_id
+		       JOIN workspace_members wm ON wm.workspace_id = p.workspace_id
+		        AND wm.user_id = n.user_id
+		        AND wm.archived_at IS NULL
+		       JOIN app_users u ON u.id = n.user_id AND u.archived_at IS NULL
+		       WHERE i.id = n.issue_id
+		     )
+		   )`,
+		DefaultDelivery, maxAge.Seconds(),
+	); err != nil {
+		return fmt.Errorf("skip pending notification emails: %w", err)
+	}
+	return nil
+}
+
+// batch is the pending notifications of one user with one delivery.
+type batch struct {
+	UserID   string `db:"user_id"`
+	Delivery string `db:"delivery"`
+}
+
+// dueBatches returns the batches whose oldest notification has waited for
+// its delivery's window.
+func dueBatches(ctx context.Context, db *sqlx.DB, limit int) ([]batch, error) {
+	var batches []batch
+	if err := db.SelectContext(ctx, &batches,
+		`SELECT user_id, delivery
+		 FROM (
+		   SELECT n.user_id, `+deliveryOf+` AS delivery, n.created_at
+		   FROM notifications n
+		   WHERE n.email_processed_at IS NULL
+		 ) pending
+		 WHERE delivery <> 'off'
+		 GROUP BY user_id, delivery
+		 HAVING MIN(created_at) <= NOW() - CASE delivery
+		   WHEN 'hourly' THEN make_interval(secs => $2)
+		   WHEN 'daily' THEN make_interval(secs => $3)
+		   ELSE interval '0'
+		 END
+		 ORDER BY MIN(created_at)
+		 LIMIT $4`,
+		DefaultDelivery, window[DeliveryHourly].Seconds(), window[DeliveryDaily].Seconds(), limit,
+	); err != nil {
+		return nil, fmt.Errorf("find due notification emails: %w", err)
+	}
+	return batches, nil
+}
+
+// recipient is the user a batch is emailed to.
+type recipient struct {
+	Name  string `db:"name"`
+	Email string `db:"email"`
+}
+
+// claimBatch locks the pending notifications of b, oldest first, skipping
+// rows another worker holds.
+func claimBatch(ctx context.Context, tx *sqlx.Tx, b batch) (recipient, []notifications.Notification, error) {
+	var to recipient
+	if err := tx.GetContext(ctx, &to, `SELECT name, email FROM app_users WHERE id = $1`, b.UserID); err != nil {
+		return recipient{}, nil, fmt.Errorf("load recipient: %w", err)
+	}
+	var list []notifications.Notification
+	if err := tx.SelectContext(ctx, &list,
+		`SELECT n.id, n.issue_id, i.project_id, p.key || '-' || i.number AS issue_key,
+		        i.title AS issue_title, n.actor_id, a.name AS actor_name,
+		        n.event_type, n.data, n.read_at, n.created_at
+		 FROM notifications n
+		 JOIN issues i ON i.id = n.issue_id
+		 JOIN projects p ON p.id = i.project_id
+		 LEFT JOIN app_users a ON a.id = n.actor_id
+		 WHERE n.user_id = $2
+		   AND n.email_processed_at IS NULL
+		   AND n.read_at IS NULL
+		   AND `+deliveryOf+` = $3
+		 ORDER BY n.created_at, n.id
+		 FOR UPDATE OF n SKIP LOCKED`,
+		DefaultDelivery, b.UserID, b.Delivery,
+	); err != nil {
+		return recipient{}, nil, fmt.Errorf("claim notifications: %w", err)
+	}
+	return to, list, nil
+}
+
+func markProcessed(ctx context.Context, tx *sqlx.Tx, ids []string) error {
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE notifications SET email_processed_at = NOW() WHERE id = ANY($1)`,
+		pq.Array(ids),
+	); err != nil {
+		return fmt.Errorf("mark notifications emailed: %w", err)
+	}
+	return nil
+}
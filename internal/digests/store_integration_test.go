@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package digests
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/email"
+	"github.com/start-codex/tookly/internal/notifications"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestDigests(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	actorID := testpg.SeedUser(t, db)
+	watcherID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	projectID := testpg.SeedProject(t, db, wsID, "DGS")
+	for _, id := range []string{actorID, watcherID} {
+		if _, err := db.ExecContext(ctx,
+			`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'member')`,
+			wsID, id,
+		); err != nil {
+			t.Fatalf("insert workspace member: %v", err)
+		}
+	}
+	var typeID, statusID, issueID string
+	if err := db.GetContext(ctx, &typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert issue type: %v", err)
+	}
+	if err := db.GetContext(ctx, &statusID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	if err := db.GetContext(ctx, &issueID,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, 1, $2, $3, 'Digest me', '', 'medium', $4, 0) RETURNING id`,
+		projectID, typeID, statusID, actorID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+	notify := func(eventType string) {
+		t.Helper()
+		if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit", func(tx *sqlx.Tx) error {
+			if err := notifications.AutoWatch(ctx, tx, issueID, watcherID); err != nil {
+				return err
+			}
+			return notifications.Notify(ctx, tx, notifications.Event{IssueID: issueID, ActorID: actorID, Type: eventType})
+		}); err != nil {
+			t.Fatalf("notify %s: %v", eventType, err)
+		}
+	}
+
+	prefs, err := GetPreferences(ctx, db, watcherID)
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	if len(prefs) != len(EventTypes) || prefs["updated"] != DefaultDelivery {
+		t.Fatalf("default preferences = %v", prefs)
+	}
+	prefs, err = SetPreferences(ctx, db, watcherID, Preferences{"commented": DeliveryImmediate, "updated": DeliveryHourly})
+	if err != nil {
+		t.Fatalf("SetPreferences: %v", err)
+	}
+	if prefs["commented"] != DeliveryImmediate || prefs["updated"] != DeliveryHourly || prefs["moved"] != DefaultDelivery {
+		t.Fatalf("preferences = %v", prefs)
+	}
+	if _, err := SetPreferences(ctx, db, watcherID, Preferences{"updated": "weekly"}); !errors.Is(err, ErrInvalidPreferences) {
+		t.Fatalf("SetPreferences invalid: error = %v, want ErrInvalidPreferences", err)
+	}
+
+	var sent []email.Message
+	w := &Worker{
+		DB:         db,
+		BaseURL:    "https://tookly.example",
+		SMTPConfig: &email.SMTPConfig{Host: "smtp.test", Port: 25, From: "tookly@test.local"},
+		Send: func(_ *email.SMTPConfig, msg email.Message) error {
+			sent = append(sent, msg)
+			return nil
+		},
+	}
+	run := func(want int) {
+		t.Helper()
+		before := len(sent)
+		if _, err := w.RunOnce(ctx); err != nil {
+			t.Fatalf("RunOnce: %v", err)
+		}
+		if got := len(sent) - before; got != want {
+			t.Fatalf("RunOnce sent %d emails, want %d", got, want)
+		}
+	}
+
+	notify("commented")
+	notify("updated")
+	run(1)
+	if !strings.HasPrefix(sent[0].Subject, "[Tookly] DGS-1") || !strings.Contains(sent[0].Body, "commented") {
+		t.Fatalf("immediate email = %+v", sent[0])
+	}
+	run(0)
+
+	// The hourly batch goes out once its oldest notification is an hour old.
+	if _, err := db.ExecContext(ctx,
+		`UPDATE notifications SET created_at = NOW() - interval '61 minutes'
+		 WHERE user_id = $1 AND event_type = 'updated'`,
+		watcherID,
+	); err != nil {
+		t.Fatalf("backdate notification: %v", err)
+	}
+	run(1)
+	if !strings.Contains(sent[1].Body, "updated the issue") {
+		t.Fatalf("hourly email body = %s", sent[1].Body)
+	}
+
+	// Read notifications are not emailed.
+	notify("commented")
+	if err := notifications.MarkAllRead(ctx, db, watcherID); err != nil {
+		t.Fatalf("MarkAllRead: %v", err)
+	}
+	run(0)
+
+	// A failed send leaves the batch pending.
+	w.Send = func(*email.SMTPConfig, email.Message) error { return errors.New("smtp down") }
+	notify("commented")
+	run(0)
+	w.Send = func(_ *email.SMTPConfig, msg email.Message) error {
+		sent = append(sent, msg)
+		return nil
+	}
+	run(1)
+
+	link, err := url.Parse(strings.Trim(sent[len(sent)-1].Headers["List-Unsubscribe"], "<>"))
+	if err != nil {
+		t.Fatalf("parse unsubscribe link: %v", err)
+	}
+	if err := Unsubscribe(ctx, db, watcherID, "forged"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Unsubscribe forged: error = %v, want ErrInvalidToken", err)
+	}
+	if err := Unsubscribe(ctx, db, actorID, link.Query().Get("token")); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Unsubscribe another user: error = %v, want ErrInvalidToken", err)
+	}
+	if err := Unsubscribe(ctx, db, link.Query().Get("user"), link.Query().Get("token")); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	prefs, err = GetPreferences(ctx, db, watcherID)
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	for eventType, delivery := range prefs {
+		if delivery != DeliveryOff {
+			t.Fatalf("%s delivery after unsubscribe = %s, want off", eventType, delivery)
+		}
+	}
+	notify("commented")
+	run(0)
+}
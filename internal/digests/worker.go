@@ -0,0 +1,285 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package digests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/email"
+	"github.com/start-codex/tookly/internal/instance"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/notifications"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+const (
+	defaultPollInterval = time.Minute
+	defaultBatchSize    = 20
+	// defaultMaxAge drops pending notifications instead of emailing them,
+	// so a long SMTP outage does not end in a flood of stale mail.
+	defaultMaxAge  = 7 * 24 * time.Hour
+	maxExcerptLen  = 200
+	maxDigestItems = 50
+)
+
+// Worker emails due notification batches. The zero value of each field
+// other than DB selects a default.
+type Worker struct {
+	DB *sqlx.DB
+	// BaseURL builds links when the instance has no base_url configured.
+	BaseURL      string
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAge       time.Duration
+	// SMTPConfig overrides the instance SMTP settings.
+	SMTPConfig *email.SMTPConfig
+	// Send delivers a message. Defaults to email.Send.
+	Send func(config *email.SMTPConfig, msg email.Message) error
+}
+
+// Run sends due batches every PollInterval until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("notification email worker error", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends up to BatchSize due batches and returns how many it sent.
+// Without SMTP settings nothing is sent and pending notifications wait,
+// up to MaxAge.
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	if w.DB == nil {
+		return 0, errors.New("db is required")
+	}
+	maxAge := w.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultMaxAge
+	}
+	if err := skipPending(ctx, w.DB, maxAge); err != nil {
+		return 0, err
+	}
+	smtpConfig := w.SMTPConfig
+	if smtpConfig == nil {
+		loaded, err := instance.LoadSMTPConfig(ctx, w.DB)
+		if errors.Is(err, email.ErrSMTPNotConfigured) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		smtpConfig = loaded
+	}
+	size := w.BatchSize
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	batches, err := dueBatches(ctx, w.DB, size)
+	if err != nil {
+		return 0, err
+	}
+	baseURL := w.BaseURL
+	if configured, _ := instance.GetConfig(ctx, w.DB, "base_url"); configured != "" {
+		baseURL = configured
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	sent := 0
+	for _, b := range batches {
+		ok, err := w.sendBatch(ctx, smtpConfig, baseURL, b)
+		if err != nil {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			slog.Error("send notification email", "user_id", b.UserID, "delivery", b.Delivery, "error", err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// sendBatch emails one batch while holding its rows, so a failed send
+// leaves them pending for the next run. It reports false when another
+// worker already holds the batch.
+func (w *Worker) sendBatch(ctx context.Context, smtpConfig *email.SMTPConfig, baseURL string, b batch) (bool, error) {
+	send := w.Send
+	if send == nil {
+		send = email.Send
+	}
+	unsubscribeURL, err := UnsubscribeURL(ctx, w.DB, baseURL, b.UserID)
+	if err != nil {
+		return false, err
+	}
+	sent := false
+	err = pgutil.WithTx(ctx, w.DB, nil, "begin tx", "commit notification email", func(tx *sqlx.Tx) error {
+		to, list, err := claimBatch(ctx, tx, b)
+		if err != nil || len(list) == 0 {
+			return err
+		}
+		msg, err := render(to, list, b.Delivery, baseURL, unsubscribeURL)
+		if err != nil {
+			return err
+		}
+		if err := send(smtpConfig, msg); err != nil {
+			return err
+		}
+		ids := make([]string, len(list))
+		for i, n := range list {
+			ids[i] = n.ID
+		}
+		sent = true
+		return markProcessed(ctx, tx, ids)
+	})
+	return sent && err == nil, err
+}
+
+// digestItem is one notification as shown in an email.
+type digestItem struct {
+	IssueKey   string
+	IssueTitle string
+	IssueURL   string
+	Actor      string
+	Summary    string
+	Excerpt    string
+	At         string
+}
+
+// render builds the email for a batch. Only the newest maxDigestItems
+// notifications are listed.
+func render(to recipient, list []notifications.Notification, delivery, baseURL, unsubscribeURL string) (email.Message, error) {
+	shown := list
+	if len(shown) > maxDigestItems {
+		shown = shown[len(shown)-maxDigestItems:]
+	}
+	items := make([]digestItem, 0, len(shown))
+	for i := len(shown) - 1; i >= 0; i-- {
+		n := shown[i]
+		summary, excerpt := summarize(n)
+		actor := "Someone"
+		if n.ActorName != nil {
+			actor = *n.ActorName
+		}
+		items = append(items, digestItem{
+			IssueKey:   n.IssueKey,
+			IssueTitle: n.IssueTitle,
+			IssueURL:   fmt.Sprintf("%s/projects/%s/issues/%s", baseURL, n.ProjectID, n.IssueID),
+			Actor:      actor,
+			Summary:    summary,
+			Excerpt:    excerpt,
+			At:         n.CreatedAt.UTC().Format("Jan 2, 15:04 UTC"),
+		})
+	}
+
+	var subject, heading string
+	switch {
+	case len(list) == 1:
+		subject = fmt.Sprintf("[Tookly] %s %s", list[0].IssueKey, list[0].IssueTitle)
+		heading = list[0].IssueKey + " was updated"
+	case delivery == DeliveryHourly:
+		subject = fmt.Sprintf("[Tookly] Hourly digest: %d updates", len(list))
+		heading = "Your hourly digest"
+	case delivery == DeliveryDaily:
+		subject = fmt.Sprintf("[Tookly] Daily digest: %d updates", len(list))
+		heading = "Your daily digest"
+	default:
+		subject = fmt.Sprintf("[Tookly] %d updates on issues you watch", len(list))
+		heading = "Updates on issues you watch"
+	}
+	body, err := email.RenderTemplate("notification_digest", struct {
+		Heading        string
+		Name           string
+		Items          []digestItem
+		UnsubscribeURL string
+	}{heading, to.Name, items, unsubscribeURL})
+	if err != nil {
+		return email.Message{}, err
+	}
+	return email.Message{
+		To:      to.Email,
+		Subject: subject,
+		Body:    body,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+// summarize describes a notification after its actor's name, with an
+// excerpt for comments.
+func summarize(n notifications.Notification) (string, string) {
+	var data struct {
+		Changes map[string]json.RawMessage `json:"changes"`
+		Comment struct {
+			Body string `json:"body"`
+		} `json:"comment"`
+	}
+	_ = json.Unmarshal(n.Data, &data)
+
+	switch n.Type {
+	case issues.EventCreated:
+		return "created the issue", ""
+	case issues.EventMoved:
+		return "moved the issue", ""
+	case issues.EventArchived:
+		return "archived the issue", ""
+	case issues.EventCommented:
+		return "commented", excerpt(data.Comment.Body)
+	case issues.EventUpdated:
+		fields := make([]string, 0, len(data.Changes))
+		for field := range data.Changes {
+			fields = append(fields, fieldName(field))
+		}
+		if len(fields) == 0 {
+			return "updated the issue", ""
+		}
+		sort.Strings(fields)
+		return "changed " + strings.Join(fields, ", "), ""
+	default:
+		return n.Type, ""
+	}
+}
+
+// fieldName turns a change key such as "assignee_id" or "custom_fields.team"
+// into the words shown in an email.
+func fieldName(key string) string {
+	if name, ok := strings.CutPrefix(key, "custom_fields."); ok {
+		return name
+	}
+	return strings.ReplaceAll(strings.TrimSuffix(key, "_id"), "_", " ")
+}
+
+func excerpt(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= maxExcerptLen {
+		return s
+	}
+	return string([]rune(s)[:maxExcerptLen]) + "…"
+}
@@ -37,7 +37,8 @@ func (c SMTPConfig) Validate() error {
 type Message struct {
 	To      string
 	Subject string
-	Body    string // HTML body
+	Body    string            // HTML body
+	Headers map[string]string // extra headers, such as List-Unsubscribe
 }
 
 // Send sends a message using the provided SMTP config.
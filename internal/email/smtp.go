@@ -7,7 +7,9 @@ package email
 
 import (
 	"fmt"
+	"mime"
 	"net/smtp"
+	"sort"
 	"strings"
 )
 
@@ -17,10 +19,18 @@ func sendViaSMTP(config SMTPConfig, msg Message) error {
 	headers := []string{
 		fmt.Sprintf("From: %s", config.From),
 		fmt.Sprintf("To: %s", msg.To),
-		fmt.Sprintf("Subject: %s", msg.Subject),
+		fmt.Sprintf("Subject: %s", mime.QEncoding.Encode("UTF-8", msg.Subject)),
 		"MIME-Version: 1.0",
 		"Content-Type: text/html; charset=UTF-8",
 	}
+	names := make([]string, 0, len(msg.Headers))
+	for name := range msg.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		headers = append(headers, fmt.Sprintf("%s: %s", name, msg.Headers[name]))
+	}
 
 	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + msg.Body
 
@@ -0,0 +1,329 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package exports
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/start-codex/tookly/internal/attachments"
+)
+
+// The archive is a zip file holding one JSON file per table plus the
+// attachment contents under attachments/<id>. Version is bumped whenever
+// a file changes in a way older readers cannot follow.
+const (
+	archiveFormat = "tookly-project"
+	Version       = 1
+)
+
+// maxJSONSize bounds a single JSON file read from an archive.
+const maxJSONSize = 256 << 20
+
+const (
+	fileManifest    = "manifest.json"
+	fileProject     = "project.json"
+	fileUsers       = "users.json"
+	fileMembers     = "members.json"
+	fileStatuses    = "statuses.json"
+	fileIssueTypes  = "issue_types.json"
+	fileBoards      = "boards.json"
+	fileIssues      = "issues.json"
+	fileEvents      = "issue_events.json"
+	fileAttachments = "attachments.json"
+	dirAttachments  = "attachments"
+)
+
+type manifest struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	ProjectID  string    `json:"project_id"`
+}
+
+type projectRecord struct {
+	ID              string     `db:"id"          json:"id"`
+	Name            string     `db:"name"        json:"name"`
+	Key             string     `db:"key"         json:"key"`
+	Description     string     `db:"description" json:"description"`
+	LastIssueNumber int        `db:"last_number" json:"last_issue_number"`
+	CreatedAt       time.Time  `db:"created_at"  json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"  json:"updated_at"`
+	ArchivedAt      *time.Time `db:"archived_at" json:"archived_at,omitempty"`
+}
+
+// userRecord identifies a user referenced anywhere in the project. Users
+// are matched by email on restore; accounts themselves are not exported.
+type userRecord struct {
+	ID    string `db:"id"    json:"id"`
+	Email string `db:"email" json:"email"`
+	Name  string `db:"name"  json:"name"`
+}
+
+type memberRecord struct {
+	UserID     string     `db:"user_id"     json:"user_id"`
+	Role       string     `db:"role"        json:"role"`
+	CreatedAt  time.Time  `db:"created_at"  json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"  json:"updated_at"`
+	ArchivedAt *time.Time `db:"archived_at" json:"archived_at,omitempty"`
+}
+
+type statusRecord struct {
+	ID         string     `db:"id"          json:"id"`
+	Name       string     `db:"name"        json:"name"`
+	Category   string     `db:"category"    json:"category"`
+	Position   int        `db:"position"    json:"position"`
+	CreatedAt  time.Time  `db:"created_at"  json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"  json:"updated_at"`
+	ArchivedAt *time.Time `db:"archived_at" json:"archived_at,omitempty"`
+}
+
+type issueTypeRecord struct {
+	ID         string     `db:"id"          json:"id"`
+	Name       string     `db:"name"        json:"name"`
+	Icon       *string    `db:"icon"        json:"icon,omitempty"`
+	Level      int        `db:"level"       json:"level"`
+	CreatedAt  time.Time  `db:"created_at"  json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"  json:"updated_at"`
+	ArchivedAt *time.Time `db:"archived_at" json:"archived_at,omitempty"`
+}
+
+type boardRecord struct {
+	ID          string         `db:"id"           json:"id"`
+	Name        string         `db:"name"         json:"name"`
+	Type        string         `db:"type"         json:"type"`
+	FilterQuery string         `db:"filter_query" json:"filter_query"`
+	CreatedAt   time.Time      `db:"created_at"   json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"   json:"updated_at"`
+	ArchivedAt  *time.Time     `db:"archived_at"  json:"archived_at,omitempty"`
+	Columns     []columnRecord `db:"-"            json:"columns"`
+}
+
+// columnRecord is a board column with the statuses assigned to it.
+type columnRecord struct {
+	ID         string     `db:"id"          json:"id"`
+	BoardID    string     `db:"board_id"    json:"-"`
+	Name       string     `db:"name"        json:"name"`
+	Position   int        `db:"position"    json:"position"`
+	CreatedAt  time.Time  `db:"created_at"  json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"  json:"updated_at"`
+	ArchivedAt *time.Time `db:"archived_at" json:"archived_at,omitempty"`
+	StatusIDs  []string   `db:"-"           json:"status_ids"`
+}
+
+type issueRecord struct {
+	ID                string     `db:"id"                         json:"id"`
+	Number            int        `db:"number"                     json:"number"`
+	IssueTypeID       string     `db:"issue_type_id"              json:"issue_type_id"`
+	StatusID          string     `db:"status_id"                  json:"status_id"`
+	ParentIssueID     *string    `db:"parent_issue_id"            json:"parent_issue_id,omitempty"`
+	Title             string     `db:"title"                      json:"title"`
+	Description       string     `db:"description"                json:"description"`
+	Priority          string     `db:"priority"                   json:"priority"`
+	AssigneeID        *string    `db:"assignee_id"                json:"assignee_id,omitempty"`
+	ReporterID        string     `db:"reporter_id"                json:"reporter_id"`
+	DueDate           *time.Time `db:"due_date"                   json:"due_date,omitempty"`
+	StatusPosition    int        `db:"status_position"            json:"status_position"`
+	Version           int        `db:"version"                    json:"version"`
+	OriginalEstimate  *int       `db:"original_estimate_minutes"  json:"original_estimate_minutes,omitempty"`
+	RemainingEstimate *int       `db:"remaining_estimate_minutes" json:"remaining_estimate_minutes,omitempty"`
+	ExternalRef       *string    `db:"external_ref"               json:"external_ref,omitempty"`
+	CreatedAt         time.Time  `db:"created_at"                 json:"created_at"`
+	UpdatedAt         time.Time  `db:"updated_at"                 json:"updated_at"`
+	ArchivedAt        *time.Time `db:"archived_at"                json:"archived_at,omitempty"`
+}
+
+type eventRecord struct {
+	ID        string          `db:"id"           json:"id"`
+	IssueID   string          `db:"issue_id"     json:"issue_id"`
+	ActorID   string          `db:"actor_id"     json:"actor_id"`
+	EventType string          `db:"event_type"   json:"event_type"`
+	Payload   json.RawMessage `db:"payload_json" json:"payload"`
+	CreatedAt time.Time       `db:"created_at"   json:"created_at"`
+}
+
+// attachmentRecord is attachment metadata; the contents are stored in the
+// archive under attachments/<ID>.
+type attachmentRecord struct {
+	ID          string    `db:"id"           json:"id"`
+	IssueID     string    `db:"issue_id"     json:"issue_id"`
+	UploaderID  string    `db:"uploader_id"  json:"uploader_id"`
+	Filename    string    `db:"filename"     json:"filename"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Size        int64     `db:"size_bytes"   json:"size"`
+	StorageKey  string    `db:"storage_key"  json:"-"`
+	CreatedAt   time.Time `db:"created_at"   json:"created_at"`
+}
+
+// Snapshot is the content of a project export, read in one consistent
+// transaction. Attachment contents are read from storage when it is
+// written.
+type Snapshot struct {
+	manifest    manifest
+	project     projectRecord
+	users       []userRecord
+	members     []memberRecord
+	statuses    []statusRecord
+	issueTypes  []issueTypeRecord
+	boards      []boardRecord
+	issues      []issueRecord
+	events      []eventRecord
+	attachments []attachmentRecord
+}
+
+// Filename suggests a name for the archive, such as "PROJ-export.zip".
+func (s *Snapshot) Filename() string {
+	return s.project.Key + "-export.zip"
+}
+
+// Write writes the archive to w, reading attachment contents from store.
+// A nil store is only accepted when the project has no attachments.
+func (s *Snapshot) Write(ctx context.Context, w io.Writer, store attachments.Storage) error {
+	if store == nil && len(s.attachments) > 0 {
+		return ErrStorageUnavailable
+	}
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		v    any
+	}{
+		{fileManifest, s.manifest},
+		{fileProject, s.project},
+		{fileUsers, s.users},
+		{fileMembers, s.members},
+		{fileStatuses, s.statuses},
+		{fileIssueTypes, s.issueTypes},
+		{fileBoards, s.boards},
+		{fileIssues, s.issues},
+		{fileEvents, s.events},
+		{fileAttachments, s.attachments},
+	}
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: s.manifest.ExportedAt})
+		if err != nil {
+			return fmt.Errorf("write %s: %w", f.name, err)
+		}
+		if err := json.NewEncoder(fw).Encode(f.v); err != nil {
+			return fmt.Errorf("write %s: %w", f.name, err)
+		}
+	}
+	for _, att := range s.attachments {
+		if err := writeAttachment(ctx, zw, store, att, s.manifest.ExportedAt); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("finish archive: %w", err)
+	}
+	return nil
+}
+
+func writeAttachment(ctx context.Context, zw *zip.Writer, store attachments.Storage, att attachmentRecord, modified time.Time) error {
+	rc, err := store.Get(ctx, att.StorageKey)
+	if err != nil {
+		return fmt.Errorf("open attachment %s: %w", att.ID, err)
+	}
+	defer rc.Close()
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: path.Join(dirAttachments, att.ID), Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("write attachment %s: %w", att.ID, err)
+	}
+	if _, err := io.Copy(fw, rc); err != nil {
+		return fmt.Errorf("write attachment %s: %w", att.ID, err)
+	}
+	return nil
+}
+
+// readArchive reads the JSON files of an archive. Attachment contents stay
+// in the zip and are opened with openAttachment.
+func readArchive(r io.ReaderAt, size int64) (*Snapshot, *zip.Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	read := func(name string, v any, required bool) error {
+		f, ok := files[name]
+		if !ok {
+			if required {
+				return fmt.Errorf("%w: %s is missing", ErrInvalidArchive, name)
+			}
+			return nil
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(io.LimitReader(rc, maxJSONSize+1))
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+		}
+		if len(data) > maxJSONSize {
+			return fmt.Errorf("%w: %s is too large", ErrInvalidArchive, name)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+		}
+		return nil
+	}
+
+	var s Snapshot
+	if err := read(fileManifest, &s.manifest, true); err != nil {
+		return nil, nil, err
+	}
+	if s.manifest.Format != archiveFormat {
+		return nil, nil, fmt.Errorf("%w: not a project export", ErrInvalidArchive)
+	}
+	if s.manifest.Version < 1 || s.manifest.Version > Version {
+		return nil, nil, fmt.Errorf("%w: version %d", ErrUnsupportedVersion, s.manifest.Version)
+	}
+	for _, f := range []struct {
+		name     string
+		v        any
+		required bool
+	}{
+		{fileProject, &s.project, true},
+		{fileUsers, &s.users, false},
+		{fileMembers, &s.members, false},
+		{fileStatuses, &s.statuses, false},
+		{fileIssueTypes, &s.issueTypes, false},
+		{fileBoards, &s.boards, false},
+		{fileIssues, &s.issues, false},
+		{fileEvents, &s.events, false},
+		{fileAttachments, &s.attachments, false},
+	} {
+		if err := read(f.name, f.v, f.required); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &s, zr, nil
+}
+
+// openAttachment opens the contents of an attachment in an archive and
+// returns their size.
+func openAttachment(zr *zip.Reader, id string) (io.ReadCloser, int64, error) {
+	name := path.Join(dirAttachments, id)
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: attachment %s: %v", ErrInvalidArchive, id, err)
+		}
+		return rc, int64(f.UncompressedSize64), nil
+	}
+	return nil, 0, fmt.Errorf("%w: contents of attachment %s are missing", ErrInvalidArchive, id)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package exports writes a project to a versioned zip archive and restores
+// such an archive as a new project, in another workspace or on another
+// instance. The archive holds the project, its members, statuses, issue
+// types, boards with their columns, issues with their hierarchy, issue
+// events and attachments. Comments, labels, custom fields, sprints, links,
+// worklogs and watchers are not part of it.
+package exports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/attachments"
+	"github.com/start-codex/tookly/internal/projects"
+)
+
+var (
+	ErrNotFound           = errors.New("project not found")
+	ErrInvalidArchive     = errors.New("invalid project archive")
+	ErrUnsupportedVersion = errors.New("unsupported project archive version")
+	ErrStorageUnavailable = errors.New("attachment storage unavailable")
+	ErrInvalidProject     = errors.New("invalid project")
+)
+
+// Load reads everything an export holds in one repeatable-read
+// transaction, so the archive is consistent even while the project is
+// being changed.
+func Load(ctx context.Context, db *sqlx.DB, projectID string) (*Snapshot, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" {
+		return nil, errors.New("project_id is required")
+	}
+	return loadSnapshot(ctx, db, projectID)
+}
+
+// RestoreParams restores the archive in Archive, of Size bytes, as a new
+// project of WorkspaceID. Key and Name override those of the exported
+// project, e.g. when its key is taken in the workspace.
+//
+// Users are matched by email. References to users missing on this
+// instance fall back to ActorID for reporters, event actors and
+// uploaders, are cleared for assignees, and are dropped for members.
+type RestoreParams struct {
+	WorkspaceID string
+	ActorID     string
+	Key         string
+	Name        string
+	Archive     io.ReaderAt
+	Size        int64
+}
+
+func (params RestoreParams) Validate() error {
+	if params.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if params.ActorID == "" {
+		return errors.New("actor_id is required")
+	}
+	if params.Archive == nil || params.Size <= 0 {
+		return errors.New("archive is required")
+	}
+	return nil
+}
+
+// RestoreResult is the restored project and the emails of the exported
+// users that have no account on this instance.
+type RestoreResult struct {
+	Project       projects.Project `json:"project"`
+	UnmappedUsers []string         `json:"unmapped_users"`
+}
+
+// Restore recreates an archived project with new IDs, keeping issue
+// numbers, positions and timestamps. Either everything is restored or
+// nothing: attachment contents already copied to store are removed again
+// on failure.
+func Restore(ctx context.Context, db *sqlx.DB, store attachments.Storage, params RestoreParams) (RestoreResult, error) {
+	if db == nil {
+		return RestoreResult{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return RestoreResult{}, err
+	}
+	snap, zr, err := readArchive(params.Archive, params.Size)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	if params.Key != "" {
+		snap.project.Key = params.Key
+	}
+	if params.Name != "" {
+		snap.project.Name = params.Name
+	}
+	if err := (projects.CreateParams{
+		WorkspaceID: params.WorkspaceID,
+		Name:        snap.project.Name,
+		Key:         snap.project.Key,
+	}).Validate(); err != nil {
+		return RestoreResult{}, fmt.Errorf("%w: %v", ErrInvalidProject, err)
+	}
+	if store == nil && len(snap.attachments) > 0 {
+		return RestoreResult{}, ErrStorageUnavailable
+	}
+	return restore(ctx, db, store, snap, zr, params)
+}
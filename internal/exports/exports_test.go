@@ -0,0 +1,218 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package exports
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestoreParams_Validate(t *testing.T) {
+	archive := bytes.NewReader([]byte("zip"))
+	valid := RestoreParams{WorkspaceID: "w", ActorID: "u", Archive: archive, Size: 3}
+
+	tests := []struct {
+		name    string
+		params  RestoreParams
+		wantErr bool
+	}{
+		{name: "valid", params: valid, wantErr: false},
+		{name: "valid with overrides", params: func() RestoreParams { c := valid; c.Key = "NEW"; c.Name = "New"; return c }(), wantErr: false},
+		{name: "missing workspace_id", params: func() RestoreParams { c := valid; c.WorkspaceID = ""; return c }(), wantErr: true},
+		{name: "missing actor_id", params: func() RestoreParams { c := valid; c.ActorID = ""; return c }(), wantErr: true},
+		{name: "missing archive", params: func() RestoreParams { c := valid; c.Archive = nil; return c }(), wantErr: true},
+		{name: "empty archive", params: func() RestoreParams { c := valid; c.Size = 0; return c }(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	parent := "i1"
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	snap := &Snapshot{
+		manifest: manifest{Format: archiveFormat, Version: Version, ExportedAt: now, ProjectID: "p1"},
+		project:  projectRecord{ID: "p1", Name: "Project", Key: "PRJ", LastIssueNumber: 2, CreatedAt: now, UpdatedAt: now},
+		statuses: []statusRecord{{ID: "s1", Name: "To do", Category: "todo"}},
+		boards: []boardRecord{{ID: "b1", Name: "Board", Type: "kanban", Columns: []columnRecord{
+			{ID: "c1", BoardID: "b1", Name: "To do", StatusIDs: []string{"s1"}},
+		}}},
+		issues: []issueRecord{
+			{ID: "i1", Number: 1, StatusID: "s1", Title: "Epic"},
+			{ID: "i2", Number: 2, StatusID: "s1", Title: "Story", ParentIssueID: &parent},
+		},
+		events: []eventRecord{{ID: "e1", IssueID: "i1", EventType: "created", Payload: json.RawMessage(`{"status_id":"s1"}`)}},
+	}
+	if got := snap.Filename(); got != "PRJ-export.zip" {
+		t.Fatalf("Filename() = %q, want PRJ-export.zip", got)
+	}
+
+	var buf bytes.Buffer
+	if err := snap.Write(context.Background(), &buf, nil); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, _, err := readArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("readArchive() error = %v", err)
+	}
+	if got.project.Key != "PRJ" || got.project.LastIssueNumber != 2 {
+		t.Fatalf("project = %+v", got.project)
+	}
+	if len(got.issues) != 2 || got.issues[1].ParentIssueID == nil || *got.issues[1].ParentIssueID != "i1" {
+		t.Fatalf("issues = %+v", got.issues)
+	}
+	if len(got.boards) != 1 || len(got.boards[0].Columns) != 1 || got.boards[0].Columns[0].StatusIDs[0] != "s1" {
+		t.Fatalf("boards = %+v", got.boards)
+	}
+	if len(got.events) != 1 || string(got.events[0].Payload) != `{"status_id":"s1"}` {
+		t.Fatalf("events = %+v", got.events)
+	}
+
+	snap.attachments = []attachmentRecord{{ID: "a1", IssueID: "i1"}}
+	if err := snap.Write(context.Background(), &bytes.Buffer{}, nil); !errors.Is(err, ErrStorageUnavailable) {
+		t.Fatalf("Write() with attachments and no store error = %v, want ErrStorageUnavailable", err)
+	}
+}
+
+func TestReadArchive_Invalid(t *testing.T) {
+	archive := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range files {
+			fw, err := zw.Create(name)
+			if err != nil {
+				t.Fatalf("create %s: %v", name, err)
+			}
+			if _, err := fw.Write([]byte(content)); err != nil {
+				t.Fatalf("write %s: %v", name, err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("close zip: %v", err)
+		}
+		return buf.Bytes()
+	}
+	project := `{"id":"p1","name":"Project","key":"PRJ"}`
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "not a zip", data: []byte("not a zip"), wantErr: ErrInvalidArchive},
+		{name: "no manifest", data: archive(map[string]string{fileProject: project}), wantErr: ErrInvalidArchive},
+		{name: "other format", data: archive(map[string]string{
+			fileManifest: `{"format":"other","version":1}`, fileProject: project,
+		}), wantErr: ErrInvalidArchive},
+		{name: "newer version", data: archive(map[string]string{
+			fileManifest: `{"format":"tookly-project","version":99}`, fileProject: project,
+		}), wantErr: ErrUnsupportedVersion},
+		{name: "no project", data: archive(map[string]string{
+			fileManifest: `{"format":"tookly-project","version":1}`,
+		}), wantErr: ErrInvalidArchive},
+		{name: "bad json", data: archive(map[string]string{
+			fileManifest: `{"format":"tookly-project","version":1}`, fileProject: project, fileIssues: `{`,
+		}), wantErr: ErrInvalidArchive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := readArchive(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readArchive() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOrderIssues(t *testing.T) {
+	ref := func(s string) *string { return &s }
+
+	ordered, err := orderIssues([]issueRecord{
+		{ID: "c", ParentIssueID: ref("b")},
+		{ID: "b", ParentIssueID: ref("a")},
+		{ID: "a"},
+		{ID: "d"},
+	})
+	if err != nil {
+		t.Fatalf("orderIssues() error = %v", err)
+	}
+	pos := make(map[string]int, len(ordered))
+	for i, is := range ordered {
+		pos[is.ID] = i
+	}
+	if len(ordered) != 4 || pos["a"] > pos["b"] || pos["b"] > pos["c"] {
+		t.Fatalf("orderIssues() = %+v, want parents first", ordered)
+	}
+
+	if _, err := orderIssues([]issueRecord{{ID: "a", ParentIssueID: ref("b")}, {ID: "b", ParentIssueID: ref("a")}}); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("orderIssues() with a cycle error = %v, want ErrInvalidArchive", err)
+	}
+	if _, err := orderIssues([]issueRecord{{ID: "a", ParentIssueID: ref("x")}}); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("orderIssues() with an unknown parent error = %v, want ErrInvalidArchive", err)
+	}
+	if _, err := orderIssues([]issueRecord{{ID: "a"}, {ID: "a"}}); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("orderIssues() with a duplicate error = %v, want ErrInvalidArchive", err)
+	}
+}
+
+func TestCheckBoards(t *testing.T) {
+	if err := checkBoards([]boardRecord{{Name: "All"}, {Name: "Mine", FilterQuery: "assignee:me priority:high"}}); err != nil {
+		t.Fatalf("checkBoards() error = %v", err)
+	}
+	err := checkBoards([]boardRecord{{Name: "All"}, {Name: "Urgent", FilterQuery: "priority:urgent"}})
+	if !errors.Is(err, ErrInvalidArchive) || !strings.Contains(err.Error(), `"Urgent"`) {
+		t.Fatalf("checkBoards() with a bad filter error = %v, want ErrInvalidArchive naming the board", err)
+	}
+}
+
+func TestRemapIDs(t *testing.T) {
+	ids := map[string]string{"old-status": "new-status", "old-user": "new-user"}
+
+	got, err := remapIDs(json.RawMessage(`{"from":"old-status","to":"other","users":["old-user"],"n":12345678901234567890}`), ids)
+	if err != nil {
+		t.Fatalf("remapIDs() error = %v", err)
+	}
+	var v map[string]any
+	dec := json.NewDecoder(bytes.NewReader(got))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("decode %s: %v", got, err)
+	}
+	if v["from"] != "new-status" || v["to"] != "other" || v["users"].([]any)[0] != "new-user" {
+		t.Fatalf("remapIDs() = %s", got)
+	}
+	if v["n"].(json.Number).String() != "12345678901234567890" {
+		t.Fatalf("remapIDs() changed a number: %s", got)
+	}
+
+	if got, err := remapIDs(nil, ids); err != nil || string(got) != `{}` {
+		t.Fatalf("remapIDs(nil) = %s, %v, want {}", got, err)
+	}
+}
+
+func TestExportsNilDB(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Load(ctx, nil, "p"); err == nil {
+		t.Error("Load(nil db): expected error")
+	}
+	if _, err := Restore(ctx, nil, nil, RestoreParams{}); err == nil {
+		t.Error("Restore(nil db): expected error")
+	}
+}
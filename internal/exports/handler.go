@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package exports
+
+import (
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/attachments"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/projects"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+const (
+	// maxArchiveSize limits an uploaded archive, attachments included.
+	maxArchiveSize = 2 << 30
+	// maxMemory is the part of an upload kept in memory; the rest is
+	// spooled to disk by the multipart parser.
+	maxMemory = 32 << 20
+	// transferTimeout replaces the server's read or write deadline while an
+	// archive is uploaded or downloaded.
+	transferTimeout = 30 * time.Minute
+)
+
+// RegisterRoutes registers the export and restore routes. Without a store,
+// projects with attachments can be neither exported nor restored.
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB, store attachments.Storage) {
+	mux.HandleFunc("GET /projects/{projectID}/export", handleExport(db, store))
+	mux.HandleFunc("POST /workspaces/{workspaceID}/projects/import", handleRestore(db, store))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	var maxBytes *http.MaxBytesError
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound),
+		errors.Is(err, authz.ErrProjectNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInvalidArchive),
+		errors.Is(err, ErrUnsupportedVersion),
+		errors.Is(err, ErrInvalidProject):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, projects.ErrDuplicateKey):
+		respond.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrStorageUnavailable):
+		respond.Error(w, http.StatusServiceUnavailable, err.Error())
+	case errors.As(err, &maxBytes):
+		respond.Error(w, http.StatusRequestEntityTooLarge, "archive is too large")
+	default:
+		slog.Error("exports handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// handleExport streams the project archive. Errors after the first byte
+// has been sent can only be logged; the client sees a truncated zip.
+func handleExport(db *sqlx.DB, store attachments.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		snap, err := Load(r.Context(), db, r.PathValue("projectID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if store == nil && len(snap.attachments) > 0 {
+			fail(w, ErrStorageUnavailable)
+			return
+		}
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(transferTimeout))
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": snap.Filename()}))
+		w.WriteHeader(http.StatusOK)
+		if err := snap.Write(r.Context(), w, store); err != nil && r.Context().Err() == nil {
+			slog.Error("write project export", "project_id", r.PathValue("projectID"), "error", err)
+		}
+	}
+}
+
+// handleRestore accepts a multipart/form-data body whose "file" part is an
+// archive from handleExport. Optional "key" and "name" fields override
+// those of the exported project.
+func handleRestore(db *sqlx.DB, store attachments.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID := r.PathValue("workspaceID")
+		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(transferTimeout))
+		r.Body = http.MaxBytesReader(w, r.Body, maxArchiveSize)
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			var maxBytes *http.MaxBytesError
+			if errors.As(err, &maxBytes) {
+				fail(w, err)
+				return
+			}
+			respond.Error(w, http.StatusBadRequest, "expected a multipart/form-data body")
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, "file is required")
+			return
+		}
+		defer file.Close()
+
+		params := RestoreParams{
+			WorkspaceID: wsID,
+			ActorID:     authedUserID,
+			Key:         r.FormValue("key"),
+			Name:        r.FormValue("name"),
+			Archive:     file,
+			Size:        header.Size,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		result, err := Restore(r.Context(), db, store, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusCreated, result)
+	}
+}
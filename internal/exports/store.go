@@ -0,0 +1,586 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package exports
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/attachments"
+	"github.com/start-codex/tookly/internal/filterquery"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/projects"
+)
+
+func loadSnapshot(ctx context.Context, db *sqlx.DB, projectID string) (*Snapshot, error) {
+	s := &Snapshot{
+		users:       []userRecord{},
+		members:     []memberRecord{},
+		statuses:    []statusRecord{},
+		issueTypes:  []issueTypeRecord{},
+		boards:      []boardRecord{},
+		issues:      []issueRecord{},
+		events:      []eventRecord{},
+		attachments: []attachmentRecord{},
+	}
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	if err := pgutil.WithTx(ctx, db, opts, "begin tx", "commit export", func(tx *sqlx.Tx) error {
+		if err := tx.GetContext(ctx, &s.project,
+			`SELECT p.id, p.name, p.key, p.description, COALESCE(c.last_number, 0) AS last_number,
+			        p.created_at, p.updated_at, p.archived_at
+			 FROM projects p
+			 LEFT JOIN project_issue_counters c ON c.project_id = p.id
+			 WHERE p.id = $1`,
+			projectID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("get project: %w", err)
+		}
+		selects := []struct {
+			label string
+			dest  any
+			query string
+		}{
+			{"members", &s.members,
+				`SELECT user_id, role, created_at, updated_at, archived_at
+				 FROM project_members
+				 WHERE project_id = $1
+				 ORDER BY created_at, user_id`},
+			{"statuses", &s.statuses,
+				`SELECT id, name, category, position, created_at, updated_at, archived_at
+				 FROM statuses
+				 WHERE project_id = $1
+				 ORDER BY position`},
+			{"issue types", &s.issueTypes,
+				`SELECT id, name, icon, level, created_at, updated_at, archived_at
+				 FROM issue_types
+				 WHERE project_id = $1
+				 ORDER BY level, name`},
+			{"boards", &s.boards,
+				`SELECT id, name, type, filter_query, created_at, updated_at, archived_at
+				 FROM boards
+				 WHERE project_id = $1
+				 ORDER BY created_at, id`},
+			{"issues", &s.issues,
+				`SELECT id, number, issue_type_id, status_id, parent_issue_id, title, description,
+				        priority, assignee_id, reporter_id, due_date, status_position, version,
+				        original_estimate_minutes, remaining_estimate_minutes, external_ref,
+				        created_at, updated_at, archived_at
+				 FROM issues
+				 WHERE project_id = $1
+				 ORDER BY number`},
+			{"issue events", &s.events,
+				`SELECT e.id, e.issue_id, e.actor_id, e.event_type, e.payload_json, e.created_at
+				 FROM issue_events e
+				 JOIN issues i ON i.id = e.issue_id
+				 WHERE i.project_id = $1
+				 ORDER BY e.created_at, e.id`},
+			{"attachments", &s.attachments,
+				`SELECT a.id, a.issue_id, a.uploader_id, a.filename, a.content_type, a.size_bytes,
+				        a.storage_key, a.created_at
+				 FROM attachments a
+				 JOIN issues i ON i.id = a.issue_id
+				 WHERE i.project_id = $1
+				 ORDER BY a.created_at, a.id`},
+			{"users", &s.users,
+				`SELECT id, email, name
+				 FROM app_users
+				 WHERE id IN (
+				 	SELECT user_id FROM project_members WHERE project_id = $1
+				 	UNION SELECT assignee_id FROM issues WHERE project_id = $1
+				 	UNION SELECT reporter_id FROM issues WHERE project_id = $1
+				 	UNION SELECT e.actor_id FROM issue_events e JOIN issues i ON i.id = e.issue_id WHERE i.project_id = $1
+				 	UNION SELECT a.uploader_id FROM attachments a JOIN issues i ON i.id = a.issue_id WHERE i.project_id = $1
+				 )
+				 ORDER BY email`},
+		}
+		for _, q := range selects {
+			if err := tx.SelectContext(ctx, q.dest, q.query, projectID); err != nil {
+				return fmt.Errorf("export %s: %w", q.label, err)
+			}
+		}
+		return loadColumns(ctx, tx, projectID, s.boards)
+	}); err != nil {
+		return nil, err
+	}
+	s.manifest = manifest{
+		Format:     archiveFormat,
+		Version:    Version,
+		ExportedAt: time.Now().UTC(),
+		ProjectID:  s.project.ID,
+	}
+	return s, nil
+}
+
+// loadColumns fills in the columns of boards and the statuses assigned to
+// each column.
+func loadColumns(ctx context.Context, tx *sqlx.Tx, projectID string, boards []boardRecord) error {
+	columns := []columnRecord{}
+	if err := tx.SelectContext(ctx, &columns,
+		`SELECT c.id, c.board_id, c.name, c.position, c.created_at, c.updated_at, c.archived_at
+		 FROM board_columns c
+		 JOIN boards b ON b.id = c.board_id
+		 WHERE b.project_id = $1
+		 ORDER BY c.position`,
+		projectID,
+	); err != nil {
+		return fmt.Errorf("export board columns: %w", err)
+	}
+	var assigned []struct {
+		ColumnID string `db:"board_column_id"`
+		StatusID string `db:"status_id"`
+	}
+	if err := tx.SelectContext(ctx, &assigned,
+		`SELECT cs.board_column_id, cs.status_id
+		 FROM board_column_statuses cs
+		 JOIN board_columns c ON c.id = cs.board_column_id
+		 JOIN boards b ON b.id = c.board_id
+		 WHERE b.project_id = $1
+		 ORDER BY cs.created_at, cs.status_id`,
+		projectID,
+	); err != nil {
+		return fmt.Errorf("export board column statuses: %w", err)
+	}
+	statusIDs := map[string][]string{}
+	for _, a := range assigned {
+		statusIDs[a.ColumnID] = append(statusIDs[a.ColumnID], a.StatusID)
+	}
+	byBoard := map[string][]columnRecord{}
+	for _, c := range columns {
+		c.StatusIDs = statusIDs[c.ID]
+		if c.StatusIDs == nil {
+			c.StatusIDs = []string{}
+		}
+		byBoard[c.BoardID] = append(byBoard[c.BoardID], c)
+	}
+	for i := range boards {
+		boards[i].Columns = byBoard[boards[i].ID]
+		if boards[i].Columns == nil {
+			boards[i].Columns = []columnRecord{}
+		}
+	}
+	return nil
+}
+
+// restore inserts the snapshot as a new project. ids maps every exported
+// ID, users included, to the ID it has on this instance.
+func restore(ctx context.Context, db *sqlx.DB, store attachments.Storage, snap *Snapshot, zr *zip.Reader, params RestoreParams) (RestoreResult, error) {
+	issues, err := orderIssues(snap.issues)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	if err := checkBoards(snap.boards); err != nil {
+		return RestoreResult{}, err
+	}
+	var (
+		result RestoreResult
+		stored []string
+	)
+	err = pgutil.WithTx(ctx, db, nil, "begin tx", "commit restore project", func(tx *sqlx.Tx) error {
+		users, unmapped, err := mapUsers(ctx, tx, params.WorkspaceID, snap.users)
+		if err != nil {
+			return err
+		}
+		result.UnmappedUsers = unmapped
+		userOrActor := func(id string) string {
+			if mapped, ok := users[id]; ok {
+				return mapped
+			}
+			return params.ActorID
+		}
+		ids := map[string]string{}
+		for from, to := range users {
+			ids[from] = to
+		}
+		lookup := func(kind, id string) (string, error) {
+			mapped, ok := ids[id]
+			if !ok {
+				return "", fmt.Errorf("%w: unknown %s %s", ErrInvalidArchive, kind, id)
+			}
+			return mapped, nil
+		}
+
+		p := snap.project
+		if err := tx.QueryRowxContext(ctx,
+			`INSERT INTO projects (workspace_id, name, key, description, created_at, updated_at)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 RETURNING id, workspace_id, name, key, description, created_at, updated_at, archived_at`,
+			params.WorkspaceID, p.Name, p.Key, p.Description, p.CreatedAt, p.UpdatedAt,
+		).StructScan(&result.Project); err != nil {
+			if pgutil.IsUniqueViolation(err) {
+				return projects.ErrDuplicateKey
+			}
+			return fmt.Errorf("insert project: %w", err)
+		}
+		projectID := result.Project.ID
+
+		lastNumber := p.LastIssueNumber
+		for _, is := range issues {
+			lastNumber = max(lastNumber, is.Number)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO project_issue_counters (project_id, last_number) VALUES ($1, $2)`,
+			projectID, lastNumber,
+		); err != nil {
+			return restoreErr("issue counter", err)
+		}
+
+		seen := map[string]bool{}
+		for _, m := range snap.members {
+			userID, ok := users[m.UserID]
+			if !ok || seen[userID] {
+				continue
+			}
+			seen[userID] = true
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO project_members (project_id, user_id, role, created_at, updated_at, archived_at)
+				 VALUES ($1, $2, $3, $4, $5, $6)`,
+				projectID, userID, m.Role, m.CreatedAt, m.UpdatedAt, m.ArchivedAt,
+			); err != nil {
+				return restoreErr("member", err)
+			}
+		}
+
+		for _, s := range snap.statuses {
+			if err := insertReturningID(ctx, tx, ids, s.ID, "status",
+				`INSERT INTO statuses (project_id, name, category, position, created_at, updated_at, archived_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7)
+				 RETURNING id`,
+				projectID, s.Name, s.Category, s.Position, s.CreatedAt, s.UpdatedAt, s.ArchivedAt,
+			); err != nil {
+				return err
+			}
+		}
+		for _, t := range snap.issueTypes {
+			if err := insertReturningID(ctx, tx, ids, t.ID, "issue type",
+				`INSERT INTO issue_types (project_id, name, icon, level, created_at, updated_at, archived_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7)
+				 RETURNING id`,
+				projectID, t.Name, t.Icon, t.Level, t.CreatedAt, t.UpdatedAt, t.ArchivedAt,
+			); err != nil {
+				return err
+			}
+		}
+		for _, b := range snap.boards {
+			if err := insertReturningID(ctx, tx, ids, b.ID, "board",
+				`INSERT INTO boards (project_id, name, type, filter_query, created_at, updated_at, archived_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7)
+				 RETURNING id`,
+				projectID, b.Name, b.Type, b.FilterQuery, b.CreatedAt, b.UpdatedAt, b.ArchivedAt,
+			); err != nil {
+				return err
+			}
+			for _, c := range b.Columns {
+				if err := insertReturningID(ctx, tx, ids, c.ID, "board column",
+					`INSERT INTO board_columns (board_id, name, position, created_at, updated_at, archived_at)
+					 VALUES ($1, $2, $3, $4, $5, $6)
+					 RETURNING id`,
+					ids[b.ID], c.Name, c.Position, c.CreatedAt, c.UpdatedAt, c.ArchivedAt,
+				); err != nil {
+					return err
+				}
+				for _, statusID := range c.StatusIDs {
+					mapped, err := lookup("status", statusID)
+					if err != nil {
+						return err
+					}
+					if _, err := tx.ExecContext(ctx,
+						`INSERT INTO board_column_statuses (board_column_id, status_id) VALUES ($1, $2)`,
+						ids[c.ID], mapped,
+					); err != nil {
+						return restoreErr("board column status", err)
+					}
+				}
+			}
+		}
+
+		for _, is := range issues {
+			typeID, err := lookup("issue type", is.IssueTypeID)
+			if err != nil {
+				return err
+			}
+			statusID, err := lookup("status", is.StatusID)
+			if err != nil {
+				return err
+			}
+			var parentID, assigneeID *string
+			if is.ParentIssueID != nil {
+				mapped := ids[*is.ParentIssueID]
+				parentID = &mapped
+			}
+			if is.AssigneeID != nil {
+				if mapped, ok := users[*is.AssigneeID]; ok {
+					assigneeID = &mapped
+				}
+			}
+			if err := insertReturningID(ctx, tx, ids, is.ID, "issue",
+				`INSERT INTO issues (
+					project_id, number, issue_type_id, status_id, parent_issue_id, title, description,
+					priority, assignee_id, reporter_id, due_date, status_position, version,
+					original_estimate_minutes, remaining_estimate_minutes, external_ref,
+					created_at, updated_at, archived_at
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+				RETURNING id`,
+				projectID, is.Number, typeID, statusID, parentID, is.Title, is.Description,
+				is.Priority, assigneeID, userOrActor(is.ReporterID), is.DueDate, is.StatusPosition, is.Version,
+				is.OriginalEstimate, is.RemainingEstimate, is.ExternalRef,
+				is.CreatedAt, is.UpdatedAt, is.ArchivedAt,
+			); err != nil {
+				return err
+			}
+		}
+
+		for _, e := range snap.events {
+			issueID, err := lookup("issue", e.IssueID)
+			if err != nil {
+				return err
+			}
+			payload, err := remapIDs(e.Payload, ids)
+			if err != nil {
+				return fmt.Errorf("%w: event %s: %v", ErrInvalidArchive, e.ID, err)
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO issue_events (issue_id, actor_id, event_type, payload_json, created_at)
+				 VALUES ($1, $2, $3, $4, $5)`,
+				issueID, userOrActor(e.ActorID), e.EventType, payload, e.CreatedAt,
+			); err != nil {
+				return restoreErr("issue event", err)
+			}
+		}
+
+		for _, a := range snap.attachments {
+			issueID, err := lookup("issue", a.IssueID)
+			if err != nil {
+				return err
+			}
+			key, contentType, err := copyAttachment(ctx, store, zr, projectID, issueID, a)
+			if err != nil {
+				return err
+			}
+			stored = append(stored, key)
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO attachments (issue_id, uploader_id, filename, content_type, size_bytes, storage_key, created_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				issueID, userOrActor(a.UploaderID), attachments.CleanFilename(a.Filename), contentType, a.Size, key, a.CreatedAt,
+			); err != nil {
+				return restoreErr("attachment", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for _, key := range stored {
+			if delErr := store.Delete(context.WithoutCancel(ctx), key); delErr != nil {
+				slog.Error("remove restored attachment", "key", key, "error", delErr)
+			}
+		}
+		return RestoreResult{}, err
+	}
+	return result, nil
+}
+
+// copyAttachment copies the contents of an attachment from the archive to
+// store under a new key. As for uploads, the content type is sniffed from
+// the bytes rather than taken from the archive. It returns the key and the
+// content type.
+func copyAttachment(ctx context.Context, store attachments.Storage, zr *zip.Reader, projectID, issueID string, a attachmentRecord) (string, string, error) {
+	rc, size, err := openAttachment(zr, a.ID)
+	if err != nil {
+		return "", "", err
+	}
+	defer rc.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(rc, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", "", fmt.Errorf("%w: attachment %s: %v", ErrInvalidArchive, a.ID, err)
+	}
+	contentType := http.DetectContentType(head[:n])
+	key, err := attachments.NewKey(projectID, issueID)
+	if err != nil {
+		return "", "", err
+	}
+	if err := store.Put(ctx, key, io.MultiReader(bytes.NewReader(head[:n]), rc), size, contentType); err != nil {
+		return "", "", fmt.Errorf("store attachment %s: %w", a.ID, err)
+	}
+	return key, contentType, nil
+}
+
+// insertReturningID runs an insert returning the new row's id and records
+// it in ids under the exported id.
+func insertReturningID(ctx context.Context, tx *sqlx.Tx, ids map[string]string, exportedID, kind, query string, args ...any) error {
+	if _, dup := ids[exportedID]; dup {
+		return fmt.Errorf("%w: duplicate %s %s", ErrInvalidArchive, kind, exportedID)
+	}
+	var id string
+	if err := tx.GetContext(ctx, &id, query, args...); err != nil {
+		return restoreErr(kind, err)
+	}
+	ids[exportedID] = id
+	return nil
+}
+
+// restoreErr reports rows the database rejects, such as a duplicate name or
+// an issue under a parent of the same level, as an invalid archive.
+func restoreErr(kind string, err error) error {
+	for _, code := range []string{"23502", "23505", "23514", "22001", "22P02", "P0001"} {
+		if pgutil.HasCode(err, code) {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, kind, err)
+		}
+	}
+	return fmt.Errorf("insert %s: %w", kind, err)
+}
+
+// mapUsers matches exported users by email to active members of the target
+// workspace. It returns the matched IDs by exported ID and the emails left
+// unmatched; accounts outside the workspace are unmatched too, so a restore
+// does not reveal who else has an account on the instance.
+func mapUsers(ctx context.Context, tx *sqlx.Tx, workspaceID string, exported []userRecord) (map[string]string, []string, error) {
+	emails := make([]string, 0, len(exported))
+	for _, u := range exported {
+		emails = append(emails, strings.ToLower(u.Email))
+	}
+	var found []struct {
+		ID    string `db:"id"`
+		Email string `db:"email"`
+	}
+	if err := tx.SelectContext(ctx, &found,
+		`SELECT u.id, LOWER(u.email) AS email
+		 FROM app_users u
+		 JOIN workspace_members wm ON wm.user_id = u.id
+		 WHERE wm.workspace_id = $1
+		   AND wm.archived_at IS NULL
+		   AND u.archived_at IS NULL
+		   AND LOWER(u.email) = ANY($2)`,
+		workspaceID, pq.Array(emails),
+	); err != nil {
+		return nil, nil, fmt.Errorf("map users: %w", err)
+	}
+	byEmail := make(map[string]string, len(found))
+	for _, u := range found {
+		byEmail[u.Email] = u.ID
+	}
+	users := map[string]string{}
+	unmapped := []string{}
+	for _, u := range exported {
+		if id, ok := byEmail[strings.ToLower(u.Email)]; ok {
+			users[u.ID] = id
+		} else {
+			unmapped = append(unmapped, u.Email)
+		}
+	}
+	sort.Strings(unmapped)
+	return users, unmapped, nil
+}
+
+// checkBoards rejects boards whose filter query would not parse, so a
+// restored board never fails to list its issues.
+func checkBoards(boards []boardRecord) error {
+	for _, b := range boards {
+		if err := filterquery.Validate(b.FilterQuery); err != nil {
+			return fmt.Errorf("%w: board %q: filter query: %v", ErrInvalidArchive, b.Name, err)
+		}
+	}
+	return nil
+}
+
+// orderIssues returns issues with every parent before its children.
+func orderIssues(list []issueRecord) ([]issueRecord, error) {
+	byID := make(map[string]int, len(list))
+	for i, is := range list {
+		if _, dup := byID[is.ID]; dup {
+			return nil, fmt.Errorf("%w: duplicate issue %s", ErrInvalidArchive, is.ID)
+		}
+		byID[is.ID] = i
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(list))
+	ordered := make([]issueRecord, 0, len(list))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: issue %s is its own ancestor", ErrInvalidArchive, list[i].ID)
+		}
+		state[i] = visiting
+		if parent := list[i].ParentIssueID; parent != nil {
+			j, ok := byID[*parent]
+			if !ok {
+				return fmt.Errorf("%w: unknown parent issue %s", ErrInvalidArchive, *parent)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		ordered = append(ordered, list[i])
+		return nil
+	}
+	for i := range list {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// remapIDs replaces every string in a JSON document that is an exported ID
+// with the ID it was restored under, so event payloads keep pointing at
+// the right statuses, users and issues.
+func remapIDs(raw json.RawMessage, ids map[string]string) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return json.RawMessage(`{}`), nil
+	}
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	var walk func(v any) any
+	walk = func(v any) any {
+		switch v := v.(type) {
+		case string:
+			if mapped, ok := ids[v]; ok {
+				return mapped
+			}
+			return v
+		case []any:
+			for i := range v {
+				v[i] = walk(v[i])
+			}
+			return v
+		case map[string]any:
+			for k := range v {
+				v[k] = walk(v[k])
+			}
+			return v
+		default:
+			return v
+		}
+	}
+	return json.Marshal(walk(v))
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package exports
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/attachments"
+	"github.com/start-codex/tookly/internal/projects"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestExportRestore(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	owner := testpg.SeedUser(t, db)
+	outsider := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	otherWS := testpg.SeedWorkspace(t, db)
+	projID := testpg.SeedProject(t, db, wsID, "EXP")
+
+	var typeID, todoID, doneID, boardID, columnID, epicID, storyID string
+	must := func(what string, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", what, err)
+		}
+	}
+	must("insert member", func() error {
+		_, err := db.ExecContext(ctx, `INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, 'admin'), ($1, $3, 'member')`, projID, owner, outsider)
+		return err
+	}())
+	// Only the owner belongs to the workspace the project is restored into.
+	must("insert workspace member", func() error {
+		_, err := db.ExecContext(ctx, `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'admin')`, otherWS, owner)
+		return err
+	}())
+	must("insert issue type", db.GetContext(ctx, &typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projID))
+	must("insert status", db.GetContext(ctx, &todoID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, projID))
+	must("insert status", db.GetContext(ctx, &doneID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'Done', 'done', 1) RETURNING id`, projID))
+	must("insert board", db.GetContext(ctx, &boardID, `INSERT INTO boards (project_id, name, type) VALUES ($1, 'Board', 'kanban') RETURNING id`, projID))
+	must("insert column", db.GetContext(ctx, &columnID, `INSERT INTO board_columns (board_id, name, position) VALUES ($1, 'Open', 0) RETURNING id`, boardID))
+	must("assign column status", func() error {
+		_, err := db.ExecContext(ctx, `INSERT INTO board_column_statuses (board_column_id, status_id) VALUES ($1, $2)`, columnID, todoID)
+		return err
+	}())
+	must("insert counter", func() error {
+		_, err := db.ExecContext(ctx, `INSERT INTO project_issue_counters (project_id, last_number) VALUES ($1, 9)`, projID)
+		return err
+	}())
+	must("insert epic", db.GetContext(ctx, &epicID,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, assignee_id, reporter_id, status_position)
+		 VALUES ($1, 3, $2, $3, 'Epic', '', 'high', $4, $4, 0) RETURNING id`,
+		projID, typeID, todoID, outsider))
+	must("insert story", db.GetContext(ctx, &storyID,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, parent_issue_id, title, description, priority, assignee_id, reporter_id, status_position)
+		 VALUES ($1, 7, $2, $3, $4, 'Story', '', 'medium', $5, $5, 0) RETURNING id`,
+		projID, typeID, doneID, epicID, owner))
+	must("insert event", func() error {
+		_, err := db.ExecContext(ctx,
+			`INSERT INTO issue_events (issue_id, actor_id, event_type, payload_json) VALUES ($1, $2, 'status_changed', $3)`,
+			storyID, owner, `{"from_status_id":"`+todoID+`","to_status_id":"`+doneID+`"}`)
+		return err
+	}())
+
+	store, err := attachments.NewLocalStorage(t.TempDir())
+	must("NewLocalStorage", err)
+	if _, err := attachments.Create(ctx, db, store, attachments.CreateParams{
+		ProjectID: projID, IssueID: storyID, UploaderID: owner, Filename: "notes.txt", Content: bytes.NewReader([]byte("hello")),
+	}); err != nil {
+		t.Fatalf("attachments.Create: %v", err)
+	}
+
+	if _, err := Load(ctx, db, "00000000-0000-0000-0000-000000000000"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load unknown project error = %v, want ErrNotFound", err)
+	}
+	snap, err := Load(ctx, db, projID)
+	must("Load", err)
+	var buf bytes.Buffer
+	must("Write", snap.Write(ctx, &buf, store))
+	archive := bytes.NewReader(buf.Bytes())
+
+	restoreParams := RestoreParams{WorkspaceID: otherWS, ActorID: owner, Archive: archive, Size: archive.Size()}
+	if _, err := Restore(ctx, db, nil, restoreParams); !errors.Is(err, ErrStorageUnavailable) {
+		t.Fatalf("Restore without storage error = %v, want ErrStorageUnavailable", err)
+	}
+	result, err := Restore(ctx, db, store, restoreParams)
+	must("Restore", err)
+	restored := result.Project.ID
+	if restored == projID || result.Project.Key != "EXP" || result.Project.WorkspaceID != otherWS {
+		t.Fatalf("restored project = %+v", result.Project)
+	}
+	var outsiderEmail string
+	must("get email", db.GetContext(ctx, &outsiderEmail, `SELECT email FROM app_users WHERE id = $1`, outsider))
+	if len(result.UnmappedUsers) != 1 || result.UnmappedUsers[0] != outsiderEmail {
+		t.Fatalf("unmapped users = %v, want the user outside the workspace", result.UnmappedUsers)
+	}
+	var members []string
+	must("list members", db.SelectContext(ctx, &members, `SELECT user_id FROM project_members WHERE project_id = $1`, restored))
+	if len(members) != 1 || members[0] != owner {
+		t.Fatalf("restored members = %v, want only the owner", members)
+	}
+	var epic struct {
+		Assignee *string `db:"assignee_id"`
+		Reporter string  `db:"reporter_id"`
+	}
+	must("get restored epic", db.GetContext(ctx, &epic, `SELECT assignee_id, reporter_id FROM issues WHERE project_id = $1 AND number = 3`, restored))
+	if epic.Assignee != nil || epic.Reporter != owner {
+		t.Fatalf("restored epic = %+v, want no assignee and the restoring user as reporter", epic)
+	}
+	if _, err := Restore(ctx, db, store, restoreParams); !errors.Is(err, projects.ErrDuplicateKey) {
+		t.Fatalf("Restore with a taken key error = %v, want projects.ErrDuplicateKey", err)
+	}
+	renamed := restoreParams
+	renamed.Key = "EXQ"
+	if _, err := Restore(ctx, db, store, renamed); err != nil {
+		t.Fatalf("Restore with a new key: %v", err)
+	}
+
+	var lastNumber int
+	must("get counter", db.GetContext(ctx, &lastNumber, `SELECT last_number FROM project_issue_counters WHERE project_id = $1`, restored))
+	if lastNumber != 9 {
+		t.Fatalf("last_number = %d, want 9", lastNumber)
+	}
+	var story struct {
+		ID       string `db:"id"`
+		ParentID string `db:"parent_issue_id"`
+		Parent   int    `db:"parent_number"`
+		Status   string `db:"status_name"`
+		Assignee string `db:"assignee_id"`
+	}
+	must("get restored story", db.GetContext(ctx, &story,
+		`SELECT i.id, i.parent_issue_id, p.number AS parent_number, s.name AS status_name, i.assignee_id
+		 FROM issues i
+		 JOIN issues p ON p.id = i.parent_issue_id
+		 JOIN statuses s ON s.id = i.status_id
+		 WHERE i.project_id = $1 AND i.number = 7`, restored))
+	if story.ID == storyID || story.Parent != 3 || story.Status != "Done" || story.Assignee != owner {
+		t.Fatalf("restored story = %+v", story)
+	}
+
+	var columnStatus string
+	must("get column status", db.GetContext(ctx, &columnStatus,
+		`SELECT s.name FROM board_column_statuses cs
+		 JOIN board_columns c ON c.id = cs.board_column_id
+		 JOIN boards b ON b.id = c.board_id
+		 JOIN statuses s ON s.id = cs.status_id
+		 WHERE b.project_id = $1`, restored))
+	if columnStatus != "To do" {
+		t.Fatalf("column status = %q, want To do", columnStatus)
+	}
+
+	var restoredDone string
+	must("get done status", db.GetContext(ctx, &restoredDone, `SELECT id FROM statuses WHERE project_id = $1 AND name = 'Done'`, restored))
+	var payload string
+	must("get event", db.GetContext(ctx, &payload, `SELECT payload_json::text FROM issue_events WHERE issue_id = $1 AND event_type = 'status_changed'`, story.ID))
+	if !bytes.Contains([]byte(payload), []byte(restoredDone)) || bytes.Contains([]byte(payload), []byte(doneID)) {
+		t.Fatalf("event payload = %s, want the restored status IDs", payload)
+	}
+
+	list, err := attachments.List(ctx, db, restored, story.ID)
+	must("attachments.List", err)
+	if len(list) != 1 || list[0].Filename != "notes.txt" {
+		t.Fatalf("restored attachments = %+v", list)
+	}
+	_, rc, err := attachments.Open(ctx, db, store, restored, story.ID, list[0].ID)
+	must("attachments.Open", err)
+	defer rc.Close()
+	if content, _ := io.ReadAll(rc); string(content) != "hello" {
+		t.Fatalf("restored attachment content = %q, want hello", content)
+	}
+}
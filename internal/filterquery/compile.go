@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package filterquery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Env supplies the context a query is compiled against.
+type Env struct {
+	// Alias is the SQL alias of the issues table in the enclosing query.
+	Alias string
+	// UserID resolves the value "me". Queries using "me" fail to compile without it.
+	UserID string
+}
+
+// Compile turns a parsed query into a SQL boolean expression over the issues
+// table. Placeholders continue from len(args); the returned slice holds args
+// followed by the query's own arguments. A nil node compiles to TRUE.
+func Compile(n Node, env Env, args []any) (string, []any, error) {
+	if env.Alias == "" {
+		return "", nil, errors.New("alias is required")
+	}
+	c := &compiler{env: env, args: args}
+	if n == nil {
+		return "TRUE", c.args, nil
+	}
+	sql, err := c.node(n)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, c.args, nil
+}
+
+type compiler struct {
+	env  Env
+	args []any
+}
+
+func (c *compiler) arg(v any) string {
+	c.args = append(c.args, v)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *compiler) col(name string) string {
+	return c.env.Alias + "." + name
+}
+
+func (c *compiler) node(n Node) (string, error) {
+	switch n := n.(type) {
+	case And:
+		return c.binary(n.Left, n.Right, "AND")
+	case Or:
+		return c.binary(n.Left, n.Right, "OR")
+	case Not:
+		x, err := c.node(n.X)
+		if err != nil {
+			return "", err
+		}
+		return "NOT COALESCE(" + x + ", FALSE)", nil
+	case Text:
+		return c.text(n.Value), nil
+	case Term:
+		return c.term(n)
+	default:
+		return "", fmt.Errorf("unsupported filter node %T", n)
+	}
+}
+
+func (c *compiler) binary(left, right Node, op string) (string, error) {
+	l, err := c.node(left)
+	if err != nil {
+		return "", err
+	}
+	r, err := c.node(right)
+	if err != nil {
+		return "", err
+	}
+	return "(" + l + " " + op + " " + r + ")", nil
+}
+
+func (c *compiler) text(value string) string {
+	p := c.arg("%" + escapeLike(value) + "%")
+	return "(" + c.col("title") + " ILIKE " + p + " OR " + c.col("description") + " ILIKE " + p + ")"
+}
+
+func (c *compiler) term(t Term) (string, error) {
+	spec, _ := lookupField(t.Field)
+	if spec.kind == kindCustom && t.Op != OpEq && t.Op != OpNe {
+		return c.customCompare(t), nil
+	}
+	if spec.kind == kindDate && t.Op != OpEq && t.Op != OpNe {
+		return c.col(spec.column) + " " + t.Op + " " + c.arg(t.Values[0]) + "::date", nil
+	}
+
+	clauses := make([]string, 0, len(t.Values))
+	for _, v := range t.Values {
+		clause, err := c.value(t, spec, v)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, clause)
+	}
+	sql := clauses[0]
+	if len(clauses) > 1 {
+		sql = "(" + strings.Join(clauses, " OR ") + ")"
+	}
+	if t.Op == OpNe {
+		return "NOT COALESCE(" + sql + ", FALSE)", nil
+	}
+	return sql, nil
+}
+
+func (c *compiler) value(t Term, spec fieldSpec, v string) (string, error) {
+	lower := strings.ToLower(v)
+	if lower == "none" && spec.nullable && spec.kind != kindCustom && spec.kind != kindLabel {
+		return c.col(spec.column) + " IS NULL", nil
+	}
+	switch spec.kind {
+	case kindUser:
+		if lower == "me" {
+			if c.env.UserID == "" {
+				return "", &SyntaxError{Pos: t.Pos, Msg: fmt.Sprintf("%s:me requires a signed-in user", t.Field)}
+			}
+			v = c.env.UserID
+		}
+		return c.col(spec.column) + " = " + c.arg(v), nil
+	case kindEnum:
+		return c.col(spec.column) + " = " + c.arg(lower), nil
+	case kindName:
+		return "EXISTS (SELECT 1 FROM issue_types fq_t WHERE fq_t.id = " + c.col("issue_type_id") +
+			" AND lower(fq_t.name) = " + c.arg(lower) + ")", nil
+	case kindDate:
+		return c.col(spec.column) + " = " + c.arg(v) + "::date", nil
+	case kindIssueRef:
+		if uuidRe.MatchString(v) {
+			return c.col(spec.column) + " = " + c.arg(v), nil
+		}
+		m := issueKeyRe.FindStringSubmatch(strings.ToUpper(v))
+		number, err := strconv.Atoi(m[2])
+		if err != nil {
+			return "", &SyntaxError{Pos: t.Pos, Msg: fmt.Sprintf("invalid issue key %q", v)}
+		}
+		return "EXISTS (SELECT 1 FROM issues fq_p JOIN projects fq_pr ON fq_pr.id = fq_p.project_id" +
+			" WHERE fq_p.id = " + c.col(spec.column) +
+			" AND fq_pr.key = " + c.arg(m[1]) +
+			" AND fq_p.number = " + c.arg(number) + ")", nil
+	case kindText:
+		return c.text(v), nil
+	case kindCustom:
+		return c.customEqual(t, v), nil
+	case kindLabel:
+		return c.label(v), nil
+	default:
+		return "", fmt.Errorf("unsupported filter field %q", t.Field)
+	}
+}
+
+// label matches issues carrying a label named v, or no label at all for
+// "none".
+func (c *compiler) label(v string) string {
+	exists := "EXISTS (SELECT 1 FROM issue_labels fq_il JOIN labels fq_l ON fq_l.id = fq_il.label_id" +
+		" WHERE fq_il.issue_id = " + c.col("id")
+	if strings.EqualFold(v, "none") {
+		return "NOT " + exists + ")"
+	}
+	return exists + " AND lower(fq_l.name) = " + c.arg(strings.ToLower(strings.TrimSpace(v))) + ")"
+}
+
+// customValue wraps cond in an EXISTS over the issue's value for the custom
+// field named by t. Values of archived fields never match.
+func (c *compiler) customValue(t Term, cond string) string {
+	key := strings.TrimPrefix(t.Field, customPrefix)
+	return "EXISTS (SELECT 1 FROM issue_custom_values fq_cv" +
+		" JOIN custom_fields fq_cf ON fq_cf.id = fq_cv.custom_field_id" +
+		" WHERE fq_cv.issue_id = " + c.col("id") +
+		" AND fq_cf.archived_at IS NULL" +
+		" AND fq_cf.key = " + c.arg(key) + cond + ")"
+}
+
+// customEqual matches a custom field value case-insensitively. Numbers are
+// compared numerically and multi-selects match any chosen option. The CASE
+// keeps casts from running on values of another JSON type.
+func (c *compiler) customEqual(t Term, v string) string {
+	if strings.EqualFold(v, "none") {
+		return "NOT " + c.customValue(t, "")
+	}
+	p := c.arg(strings.ToLower(v))
+	number := "FALSE"
+	if isNumber(v) {
+		number = "(fq_cv.value)::numeric = " + c.arg(v) + "::numeric"
+	}
+	return c.customValue(t, " AND CASE jsonb_typeof(fq_cv.value)"+
+		" WHEN 'array' THEN EXISTS (SELECT 1 FROM jsonb_array_elements_text(fq_cv.value) fq_e WHERE lower(fq_e) = "+p+")"+
+		" WHEN 'number' THEN "+number+
+		" ELSE lower(fq_cv.value #>> '{}') = "+p+" END")
+}
+
+// customCompare orders numbers and dates. Values of other types never match.
+func (c *compiler) customCompare(t Term) string {
+	v := t.Values[0]
+	if isNumber(v) {
+		return c.customValue(t, " AND CASE WHEN jsonb_typeof(fq_cv.value) = 'number'"+
+			" THEN (fq_cv.value)::numeric "+t.Op+" "+c.arg(v)+"::numeric ELSE FALSE END")
+	}
+	return c.customValue(t, " AND CASE WHEN fq_cf.field_type = 'date'"+
+		" THEN (fq_cv.value #>> '{}')::date "+t.Op+" "+c.arg(v)+"::date ELSE FALSE END")
+}
+
+// escapeLike escapes LIKE wildcards so user text matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
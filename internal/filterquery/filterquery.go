@@ -0,0 +1,363 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package filterquery implements the small query language used by board
+// filters. A query is a boolean combination of field terms and free text:
+//
+//	assignee:me AND priority:high,critical
+//	type:Bug (due<2025-07-01 OR due:none) NOT reporter:me
+//	parent:PROJ-12 "login page"
+//	label:frontend,backend NOT label:wontfix
+//
+// The keywords AND, OR and NOT are upper case; adjacent terms are joined
+// with AND and parentheses group sub-expressions. Field terms use ':'
+// (equals any of a comma-separated list), '!=' (equals none of the list)
+// and, for date fields, '<', '<=', '>' and '>='. Bare words and quoted
+// strings match the issue title or description.
+//
+// Custom fields are addressed as cf.<key>, for example cf.story_points>=5
+// or cf.environment:prod,staging. Multi-select fields match when any chosen
+// option matches; '<', '<=', '>' and '>=' compare numbers and dates.
+//
+// label matches issues carrying a label of that name, ignoring case;
+// label:none matches issues without labels.
+package filterquery
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyntaxError reports an invalid query with the 1-based character position
+// of the offending token.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("filter_query: %s at position %d", e.Msg, e.Pos)
+}
+
+// Node is a parsed query expression.
+type Node interface{ node() }
+
+type And struct{ Left, Right Node }
+type Or struct{ Left, Right Node }
+type Not struct{ X Node }
+
+// Term is a field comparison such as priority:high,low or due<2025-01-01.
+type Term struct {
+	Field  string
+	Op     string
+	Values []string
+	Pos    int
+}
+
+// Text is a free-text match against title and description.
+type Text struct {
+	Value string
+	Pos   int
+}
+
+func (And) node()  {}
+func (Or) node()   {}
+func (Not) node()  {}
+func (Term) node() {}
+func (Text) node() {}
+
+const (
+	OpEq  = ":"
+	OpNe  = "!="
+	OpLt  = "<"
+	OpLte = "<="
+	OpGt  = ">"
+	OpGte = ">="
+)
+
+type fieldKind int
+
+const (
+	kindUser fieldKind = iota
+	kindEnum
+	kindName
+	kindDate
+	kindIssueRef
+	kindText
+	kindCustom
+	kindLabel
+)
+
+// customPrefix introduces a custom field term such as cf.severity:high.
+const customPrefix = "cf."
+
+type fieldSpec struct {
+	kind     fieldKind
+	column   string
+	nullable bool
+	enum     map[string]bool
+}
+
+var fields = map[string]fieldSpec{
+	"assignee": {kind: kindUser, column: "assignee_id", nullable: true},
+	"reporter": {kind: kindUser, column: "reporter_id"},
+	"priority": {kind: kindEnum, column: "priority", enum: map[string]bool{
+		"low": true, "medium": true, "high": true, "critical": true,
+	}},
+	"type":   {kind: kindName},
+	"due":    {kind: kindDate, column: "due_date", nullable: true},
+	"parent": {kind: kindIssueRef, column: "parent_issue_id", nullable: true},
+	"text":   {kind: kindText},
+	"label":  {kind: kindLabel, nullable: true},
+}
+
+var (
+	uuidRe      = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	issueKeyRe  = regexp.MustCompile(`^([A-Z][A-Z0-9]{1,9})-([1-9][0-9]*)$`)
+	customKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+)
+
+// Parse parses a query. An empty or blank query returns a nil Node, which
+// matches every issue.
+func Parse(query string) (Node, error) {
+	toks, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	if p.peek().kind == tokEOF {
+		return nil, nil
+	}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t.describe())}
+	}
+	return n, nil
+}
+
+// Validate reports whether query is syntactically and semantically valid.
+func Validate(query string) error {
+	_, err := Parse(query)
+	return err
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case t.isKeyword("AND"):
+			p.next()
+		case t.kind == tokEOF, t.kind == tokRParen, t.isKeyword("OR"):
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	t := p.peek()
+	switch {
+	case t.isKeyword("NOT"):
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{X: x}, nil
+	case t.kind == tokLParen:
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &SyntaxError{Pos: closing.pos, Msg: fmt.Sprintf("expected ')' but found %s", closing.describe())}
+		}
+		return x, nil
+	case t.kind == tokWord && p.toks[p.i+1].kind == tokOp:
+		return p.parseTerm()
+	case t.kind == tokWord, t.kind == tokString:
+		if t.isKeyword("AND") || t.isKeyword("OR") {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t.describe())}
+		}
+		if strings.TrimSpace(t.text) == "" {
+			return nil, &SyntaxError{Pos: t.pos, Msg: "empty text match"}
+		}
+		p.next()
+		return Text{Value: t.text, Pos: t.pos}, nil
+	default:
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t.describe())}
+	}
+}
+
+func (p *parser) parseTerm() (Node, error) {
+	fieldTok := p.next()
+	opTok := p.next()
+	name := strings.ToLower(fieldTok.text)
+	spec, ok := lookupField(name)
+	if !ok {
+		return nil, &SyntaxError{Pos: fieldTok.pos, Msg: fmt.Sprintf("unknown field %q", fieldTok.text)}
+	}
+	if spec.kind != kindDate && spec.kind != kindCustom && opTok.text != OpEq && opTok.text != OpNe {
+		return nil, &SyntaxError{Pos: opTok.pos, Msg: fmt.Sprintf("operator %q is not supported for field %q", opTok.text, name)}
+	}
+
+	var values []string
+	for {
+		v := p.next()
+		if v.kind != tokWord && v.kind != tokString {
+			return nil, &SyntaxError{Pos: v.pos, Msg: fmt.Sprintf("expected value for %q but found %s", name, v.describe())}
+		}
+		if err := checkValue(name, spec, opTok.text, v); err != nil {
+			return nil, err
+		}
+		values = append(values, v.text)
+		if p.peek().kind != tokComma {
+			break
+		}
+		comma := p.next()
+		if opTok.text != OpEq && opTok.text != OpNe {
+			return nil, &SyntaxError{Pos: comma.pos, Msg: fmt.Sprintf("operator %q takes a single value", opTok.text)}
+		}
+	}
+	return Term{Field: name, Op: opTok.text, Values: values, Pos: fieldTok.pos}, nil
+}
+
+// lookupField resolves a built-in field or a cf.<key> custom field.
+func lookupField(name string) (fieldSpec, bool) {
+	if key, ok := strings.CutPrefix(name, customPrefix); ok {
+		if !customKeyRe.MatchString(key) {
+			return fieldSpec{}, false
+		}
+		return fieldSpec{kind: kindCustom, nullable: true}, true
+	}
+	spec, ok := fields[name]
+	return spec, ok
+}
+
+// LabelTerm returns a node matching issues that carry the label name,
+// using the same rules as label:name in a query.
+func LabelTerm(name string) (Node, error) {
+	spec := fields["label"]
+	if err := checkValue("label", spec, OpEq, token{kind: tokString, text: name, pos: 1}); err != nil {
+		return nil, err
+	}
+	return Term{Field: "label", Op: OpEq, Values: []string{name}, Pos: 1}, nil
+}
+
+// CustomFieldTerm returns a node matching issues whose custom field key
+// equals value, using the same rules as cf.<key>:value in a query.
+func CustomFieldTerm(key, value string) (Node, error) {
+	name := customPrefix + key
+	spec, ok := lookupField(name)
+	if !ok {
+		return nil, &SyntaxError{Pos: 1, Msg: fmt.Sprintf("unknown field %q", name)}
+	}
+	if err := checkValue(name, spec, OpEq, token{kind: tokString, text: value, pos: 1}); err != nil {
+		return nil, err
+	}
+	return Term{Field: name, Op: OpEq, Values: []string{value}, Pos: 1}, nil
+}
+
+func checkValue(name string, spec fieldSpec, op string, v token) error {
+	bad := func(format string, args ...any) error {
+		return &SyntaxError{Pos: v.pos, Msg: fmt.Sprintf(format, args...)}
+	}
+	lower := strings.ToLower(v.text)
+	if lower == "none" && spec.nullable {
+		if op != OpEq && op != OpNe {
+			return bad("%q cannot be compared with %q", name, op)
+		}
+		return nil
+	}
+	switch spec.kind {
+	case kindUser:
+		if lower != "me" && !uuidRe.MatchString(v.text) {
+			if spec.nullable {
+				return bad("%s must be a user ID, 'me' or 'none'", name)
+			}
+			return bad("%s must be a user ID or 'me'", name)
+		}
+	case kindEnum:
+		if !spec.enum[lower] {
+			return bad("invalid %s %q", name, v.text)
+		}
+	case kindName, kindText, kindLabel:
+		if strings.TrimSpace(v.text) == "" {
+			return bad("%s must not be empty", name)
+		}
+	case kindDate:
+		if _, err := time.Parse("2006-01-02", v.text); err != nil {
+			return bad("%s must be a YYYY-MM-DD date or 'none'", name)
+		}
+	case kindIssueRef:
+		if !uuidRe.MatchString(v.text) && !issueKeyRe.MatchString(strings.ToUpper(v.text)) {
+			return bad("%s must be an issue ID, an issue key like PROJ-12 or 'none'", name)
+		}
+	case kindCustom:
+		if strings.TrimSpace(v.text) == "" {
+			return bad("%s must not be empty", name)
+		}
+		if op != OpEq && op != OpNe && !isNumber(v.text) && !isDate(v.text) {
+			return bad("%s must be a number or a YYYY-MM-DD date with %q", name, op)
+		}
+	}
+	return nil
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package filterquery
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const userID = "11111111-2222-3333-4444-555555555555"
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantPos int
+	}{
+		{name: "unknown field", query: "priority:high colour:red", wantPos: 15},
+		{name: "invalid priority", query: "priority:urgent", wantPos: 10},
+		{name: "bad date", query: "due<2025-13-01", wantPos: 5},
+		{name: "comparison on non-date", query: "priority>high", wantPos: 9},
+		{name: "missing value", query: "assignee:", wantPos: 10},
+		{name: "unbalanced open paren", query: "(priority:high", wantPos: 15},
+		{name: "unbalanced close paren", query: "priority:high)", wantPos: 14},
+		{name: "unterminated string", query: `text:"abc`, wantPos: 6},
+		{name: "dangling OR", query: "priority:high OR", wantPos: 17},
+		{name: "leading AND", query: "AND priority:high", wantPos: 1},
+		{name: "bad character", query: "priority:high ; drop", wantPos: 15},
+		{name: "bare bang", query: "priority!high", wantPos: 9},
+		{name: "reporter none", query: "reporter:none", wantPos: 10},
+		{name: "bad parent", query: "parent:nope", wantPos: 8},
+		{name: "list with comparison", query: "due<2025-01-01,2025-02-01", wantPos: 15},
+		{name: "empty quoted text", query: `""`, wantPos: 1},
+		{name: "bad custom field key", query: "cf.Story-Points:5", wantPos: 1},
+		{name: "custom comparison on text", query: "cf.env>prod", wantPos: 8},
+		{name: "label comparison", query: "label>bug", wantPos: 6},
+		{name: "blank label", query: `label:" "`, wantPos: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want *SyntaxError", tt.query, err)
+			}
+			if syntaxErr.Pos != tt.wantPos {
+				t.Fatalf("Parse(%q) position = %d, want %d (%v)", tt.query, syntaxErr.Pos, tt.wantPos, err)
+			}
+		})
+	}
+}
+
+func TestParse_Valid(t *testing.T) {
+	queries := []string{
+		"",
+		"   ",
+		"assignee:me",
+		"assignee:none",
+		"assignee:" + userID,
+		"priority:high,critical",
+		"type:Bug",
+		`type:"User Story"`,
+		"reporter:me AND due>=2025-01-01 AND due<2025-02-01",
+		"due:none OR due<=2025-06-30",
+		"parent:PROJ-12",
+		"parent:none",
+		"NOT (priority:low OR priority:medium)",
+		"assignee!=me",
+		`login "error page"`,
+		"text:timeout",
+		"cf.story_points>=5",
+		"cf.environment:prod,staging",
+		"cf.release<2025-07-01",
+		"cf.customer:none",
+		"label:frontend,backend",
+		`label:"needs review"`,
+		"label:none",
+		"label!=wontfix",
+	}
+	for _, q := range queries {
+		if err := Validate(q); err != nil {
+			t.Errorf("Validate(%q) error = %v", q, err)
+		}
+	}
+}
+
+func TestParse_Precedence(t *testing.T) {
+	n, err := Parse("priority:high assignee:me OR type:Bug")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	or, ok := n.(Or)
+	if !ok {
+		t.Fatalf("root = %T, want Or", n)
+	}
+	if _, ok := or.Left.(And); !ok {
+		t.Fatalf("left = %T, want And", or.Left)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "empty",
+			query:    "",
+			wantSQL:  "TRUE",
+			wantArgs: []any{"p"},
+		},
+		{
+			name:     "assignee me and priority list",
+			query:    "assignee:me priority:high,critical",
+			wantSQL:  "(i.assignee_id = $2 AND (i.priority = $3 OR i.priority = $4))",
+			wantArgs: []any{"p", userID, "high", "critical"},
+		},
+		{
+			name:     "not equal with none",
+			query:    "assignee!=none",
+			wantSQL:  "NOT COALESCE(i.assignee_id IS NULL, FALSE)",
+			wantArgs: []any{"p"},
+		},
+		{
+			name:     "due range",
+			query:    "due>=2025-01-01 due<2025-02-01",
+			wantSQL:  "(i.due_date >= $2::date AND i.due_date < $3::date)",
+			wantArgs: []any{"p", "2025-01-01", "2025-02-01"},
+		},
+		{
+			name:     "text escapes wildcards",
+			query:    `"50%_off"`,
+			wantSQL:  "(i.title ILIKE $2 OR i.description ILIKE $2)",
+			wantArgs: []any{"p", `%50\%\_off%`},
+		},
+		{
+			name:     "type by name",
+			query:    "type:Bug",
+			wantSQL:  "EXISTS (SELECT 1 FROM issue_types fq_t WHERE fq_t.id = i.issue_type_id AND lower(fq_t.name) = $2)",
+			wantArgs: []any{"p", "bug"},
+		},
+		{
+			name:     "parent by key",
+			query:    "parent:proj-7",
+			wantSQL:  "EXISTS (SELECT 1 FROM issues fq_p JOIN projects fq_pr ON fq_pr.id = fq_p.project_id WHERE fq_p.id = i.parent_issue_id AND fq_pr.key = $2 AND fq_p.number = $3)",
+			wantArgs: []any{"p", "PROJ", 7},
+		},
+		{
+			name:  "custom field equals",
+			query: "cf.environment:Prod",
+			wantSQL: "EXISTS (SELECT 1 FROM issue_custom_values fq_cv JOIN custom_fields fq_cf ON fq_cf.id = fq_cv.custom_field_id" +
+				" WHERE fq_cv.issue_id = i.id AND fq_cf.archived_at IS NULL AND fq_cf.key = $3" +
+				" AND CASE jsonb_typeof(fq_cv.value)" +
+				" WHEN 'array' THEN EXISTS (SELECT 1 FROM jsonb_array_elements_text(fq_cv.value) fq_e WHERE lower(fq_e) = $2)" +
+				" WHEN 'number' THEN FALSE" +
+				" ELSE lower(fq_cv.value #>> '{}') = $2 END)",
+			wantArgs: []any{"p", "prod", "environment"},
+		},
+		{
+			name:  "custom field number comparison",
+			query: "cf.story_points>=5",
+			wantSQL: "EXISTS (SELECT 1 FROM issue_custom_values fq_cv JOIN custom_fields fq_cf ON fq_cf.id = fq_cv.custom_field_id" +
+				" WHERE fq_cv.issue_id = i.id AND fq_cf.archived_at IS NULL AND fq_cf.key = $3" +
+				" AND CASE WHEN jsonb_typeof(fq_cv.value) = 'number' THEN (fq_cv.value)::numeric >= $2::numeric ELSE FALSE END)",
+			wantArgs: []any{"p", "5", "story_points"},
+		},
+		{
+			name:  "custom field none",
+			query: "cf.customer:none",
+			wantSQL: "NOT EXISTS (SELECT 1 FROM issue_custom_values fq_cv JOIN custom_fields fq_cf ON fq_cf.id = fq_cv.custom_field_id" +
+				" WHERE fq_cv.issue_id = i.id AND fq_cf.archived_at IS NULL AND fq_cf.key = $2)",
+			wantArgs: []any{"p", "customer"},
+		},
+		{
+			name:  "label by name",
+			query: "label:Frontend",
+			wantSQL: "EXISTS (SELECT 1 FROM issue_labels fq_il JOIN labels fq_l ON fq_l.id = fq_il.label_id" +
+				" WHERE fq_il.issue_id = i.id AND lower(fq_l.name) = $2)",
+			wantArgs: []any{"p", "frontend"},
+		},
+		{
+			name:  "label none",
+			query: "label:none",
+			wantSQL: "NOT EXISTS (SELECT 1 FROM issue_labels fq_il JOIN labels fq_l ON fq_l.id = fq_il.label_id" +
+				" WHERE fq_il.issue_id = i.id)",
+			wantArgs: []any{"p"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			sql, args, err := Compile(n, Env{Alias: "i", UserID: userID}, []any{"p"})
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Fatalf("Compile() sql =\n  %s\nwant\n  %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Fatalf("Compile() args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompile_MeWithoutUser(t *testing.T) {
+	n, err := Parse("reporter:me")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, _, err := Compile(n, Env{Alias: "i"}, nil); err == nil {
+		t.Fatal("Compile() without user: expected error")
+	}
+}
+
+func TestCustomFieldTerm(t *testing.T) {
+	n, err := CustomFieldTerm("severity", "high")
+	if err != nil {
+		t.Fatalf("CustomFieldTerm() error = %v", err)
+	}
+	want := Term{Field: "cf.severity", Op: OpEq, Values: []string{"high"}, Pos: 1}
+	if !reflect.DeepEqual(n, want) {
+		t.Fatalf("CustomFieldTerm() = %#v, want %#v", n, want)
+	}
+	if _, err := CustomFieldTerm("Bad Key", "x"); err == nil {
+		t.Fatal("CustomFieldTerm() with invalid key: expected error")
+	}
+	if _, err := CustomFieldTerm("severity", " "); err == nil {
+		t.Fatal("CustomFieldTerm() with blank value: expected error")
+	}
+}
+
+func TestLabelTerm(t *testing.T) {
+	n, err := LabelTerm("bug")
+	if err != nil {
+		t.Fatalf("LabelTerm() error = %v", err)
+	}
+	want := Term{Field: "label", Op: OpEq, Values: []string{"bug"}, Pos: 1}
+	if !reflect.DeepEqual(n, want) {
+		t.Fatalf("LabelTerm() = %#v, want %#v", n, want)
+	}
+	if _, err := LabelTerm(""); err == nil {
+		t.Fatal("LabelTerm() with empty name: expected error")
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package filterquery
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokComma
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based character position
+}
+
+func (t token) isKeyword(kw string) bool {
+	return t.kind == tokWord && t.text == kw
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// isWordRune reports whether r may appear in an unquoted word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.@/#+", r)
+}
+
+func lex(input string) ([]token, error) {
+	var toks []token
+	pos := 0 // character index
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		start := pos + 1
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			pos++
+		case r == '(':
+			toks = append(toks, token{kind: tokLParen, text: "(", pos: start})
+			i += size
+			pos++
+		case r == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")", pos: start})
+			i += size
+			pos++
+		case r == ',':
+			toks = append(toks, token{kind: tokComma, text: ",", pos: start})
+			i += size
+			pos++
+		case r == ':':
+			toks = append(toks, token{kind: tokOp, text: OpEq, pos: start})
+			i += size
+			pos++
+		case r == '<' || r == '>' || r == '!':
+			op := string(r)
+			i += size
+			pos++
+			if i < len(input) && input[i] == '=' {
+				op += "="
+				i++
+				pos++
+			}
+			if op == "!" {
+				return nil, &SyntaxError{Pos: start, Msg: "unexpected '!' (did you mean '!='?)"}
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: start})
+		case r == '"':
+			var b strings.Builder
+			i += size
+			pos++
+			closed := false
+			for i < len(input) {
+				c, n := utf8.DecodeRuneInString(input[i:])
+				i += n
+				pos++
+				if c == '\\' && i < len(input) {
+					e, m := utf8.DecodeRuneInString(input[i:])
+					i += m
+					pos++
+					b.WriteRune(e)
+					continue
+				}
+				if c == '"' {
+					closed = true
+					break
+				}
+				b.WriteRune(c)
+			}
+			if !closed {
+				return nil, &SyntaxError{Pos: start, Msg: "unterminated string"}
+			}
+			toks = append(toks, token{kind: tokString, text: b.String(), pos: start})
+		case isWordRune(r):
+			j := i
+			for j < len(input) {
+				c, n := utf8.DecodeRuneInString(input[j:])
+				if !isWordRune(c) {
+					break
+				}
+				j += n
+				pos++
+			}
+			toks = append(toks, token{kind: tokWord, text: input[i:j], pos: start})
+			i = j
+		default:
+			return nil, &SyntaxError{Pos: start, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	toks = append(toks, token{kind: tokEOF, pos: pos + 1})
+	return toks, nil
+}
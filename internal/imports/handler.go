@@ -0,0 +1,194 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package imports
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+// maxFileSize limits the uploaded file; multipartOverhead leaves room for
+// the other form fields.
+const (
+	maxFileSize       = 10 << 20
+	multipartOverhead = 1 << 20
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("POST /projects/{projectID}/imports", handleCreate(db))
+	mux.HandleFunc("GET /projects/{projectID}/imports", handleList(db))
+	mux.HandleFunc("GET /projects/{projectID}/imports/{importID}", handleGet(db))
+	mux.HandleFunc("POST /projects/{projectID}/imports/{importID}/commit", handleCommit(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	var maxBytes *http.MaxBytesError
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound),
+		errors.Is(err, authz.ErrProjectNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInvalidFile):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, ErrNotCommittable):
+		respond.Error(w, http.StatusConflict, err.Error())
+	case errors.As(err, &maxBytes):
+		respond.Error(w, http.StatusRequestEntityTooLarge, "file is too large")
+	default:
+		slog.Error("imports handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// handleCreate accepts a multipart/form-data body with the "file" to
+// import and optional "format" ("csv" or "jira", by default taken from the
+// file extension), "mapping" (a JSON object of field to CSV column) and
+// "dry_run" fields. The import runs in the background; the response is
+// the queued job.
+func handleCreate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxFileSize+multipartOverhead)
+		if err := r.ParseMultipartForm(maxFileSize + multipartOverhead); err != nil {
+			var maxBytes *http.MaxBytesError
+			if errors.As(err, &maxBytes) {
+				fail(w, err)
+				return
+			}
+			respond.Error(w, http.StatusBadRequest, "expected a multipart/form-data body")
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, "file is required")
+			return
+		}
+		defer file.Close()
+		data, err := io.ReadAll(io.LimitReader(file, maxFileSize+1))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if len(data) > maxFileSize {
+			respond.Error(w, http.StatusRequestEntityTooLarge, "file is too large")
+			return
+		}
+
+		format := r.FormValue("format")
+		if format == "" {
+			switch strings.ToLower(path.Ext(header.Filename)) {
+			case ".csv":
+				format = FormatCSV
+			case ".json":
+				format = FormatJira
+			}
+		}
+		var mapping map[string]string
+		if raw := r.FormValue("mapping"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+				respond.Error(w, http.StatusUnprocessableEntity, "mapping must be a JSON object of field to column")
+				return
+			}
+		}
+		var dryRun bool
+		if raw := r.FormValue("dry_run"); raw != "" {
+			if dryRun, err = strconv.ParseBool(raw); err != nil {
+				respond.Error(w, http.StatusUnprocessableEntity, "dry_run must be a boolean")
+				return
+			}
+		}
+
+		params := CreateParams{
+			ProjectID: r.PathValue("projectID"),
+			CreatedBy: authedUserID,
+			Format:    format,
+			Data:      data,
+			Mapping:   mapping,
+			DryRun:    dryRun,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		imp, err := Create(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusAccepted, imp)
+	}
+}
+
+func handleList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := List(r.Context(), db, r.PathValue("projectID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+func handleGet(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		imp, err := Get(r.Context(), db, r.PathValue("projectID"), r.PathValue("importID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, imp)
+	}
+}
+
+// handleCommit queues a previewed dry run for a real run.
+func handleCommit(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		imp, err := Commit(r.Context(), db, r.PathValue("projectID"), r.PathValue("importID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusAccepted, imp)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package imports creates issues in bulk from a CSV file or a Jira JSON
+// export. An import runs as a background job; a dry run only validates the
+// file and reports the outcome of each row, and can then be committed.
+package imports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJira = "jira"
+)
+
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusPreviewed = "previewed"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// maxRows caps the number of issues in one import.
+const maxRows = 5000
+
+var (
+	ErrNotFound       = errors.New("import not found")
+	ErrInvalidFile    = errors.New("invalid import file")
+	ErrNotCommittable = errors.New("only a dry run without row errors can be committed")
+)
+
+// Import is an import job. Report is set once the job has run.
+type Import struct {
+	ID         string          `db:"id"          json:"id"`
+	ProjectID  string          `db:"project_id"  json:"project_id"`
+	CreatedBy  string          `db:"created_by"  json:"created_by"`
+	Format     string          `db:"format"      json:"format"`
+	Mapping    json.RawMessage `db:"mapping"     json:"mapping"`
+	DryRun     bool            `db:"dry_run"     json:"dry_run"`
+	Status     string          `db:"status"      json:"status"`
+	Report     *Report         `db:"report"      json:"report,omitempty"`
+	Error      string          `db:"error"       json:"error,omitempty"`
+	CreatedAt  time.Time       `db:"created_at"  json:"created_at"`
+	StartedAt  *time.Time      `db:"started_at"  json:"started_at,omitempty"`
+	FinishedAt *time.Time      `db:"finished_at" json:"finished_at,omitempty"`
+}
+
+// Report is the outcome of an import, row by row. For a dry run the New
+// lists and row results describe what a commit would do.
+type Report struct {
+	Rows           int         `json:"rows"`
+	RowsWithErrors int         `json:"rows_with_errors"`
+	NewStatuses    []string    `json:"new_statuses"`
+	NewIssueTypes  []string    `json:"new_issue_types"`
+	Results        []RowResult `json:"results"`
+}
+
+// RowResult is the outcome of one issue of the file. Row is its 1-based
+// position, not counting the CSV header. IssueID and IssueNumber are set
+// once the issue has been created.
+type RowResult struct {
+	Row         int      `json:"row"`
+	Key         string   `json:"key,omitempty"`
+	Title       string   `json:"title"`
+	IssueID     string   `json:"issue_id,omitempty"`
+	IssueNumber int      `json:"issue_number,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+// Scan reads a report from a JSONB column.
+func (r *Report) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("scan report: unexpected type %T", src)
+	}
+	return json.Unmarshal(b, r)
+}
+
+// CreateParams queues an import of Data into a project. Mapping applies to
+// CSV only: it maps a field (see Fields) to the header of the column that
+// holds it. Fields without a mapping are read from the column named after
+// the field, ignoring case.
+type CreateParams struct {
+	ProjectID string
+	CreatedBy string
+	Format    string
+	Data      []byte
+	Mapping   map[string]string
+	DryRun    bool
+}
+
+func (params CreateParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.CreatedBy == "" {
+		return errors.New("created_by is required")
+	}
+	if params.Format != FormatCSV && params.Format != FormatJira {
+		return errors.New("format must be 'csv' or 'jira'")
+	}
+	if len(params.Data) == 0 {
+		return errors.New("file is required")
+	}
+	if params.Format == FormatJira && len(params.Mapping) > 0 {
+		return errors.New("mapping applies to csv imports only")
+	}
+	for field, column := range params.Mapping {
+		if !knownFields[field] {
+			return fmt.Errorf("mapping: unknown field %q", field)
+		}
+		if column == "" {
+			return fmt.Errorf("mapping: column for %q is required", field)
+		}
+	}
+	return nil
+}
+
+// Create parses the file and queues the import. A file that cannot be
+// read at all fails with ErrInvalidFile; problems with single rows are
+// reported once the job has run.
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Import, error) {
+	if db == nil {
+		return Import{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Import{}, err
+	}
+	if _, err := parse(params.Format, params.Data, params.Mapping); err != nil {
+		return Import{}, err
+	}
+	return createImport(ctx, db, params)
+}
+
+func Get(ctx context.Context, db *sqlx.DB, projectID, importID string) (Import, error) {
+	if db == nil {
+		return Import{}, errors.New("db is required")
+	}
+	if projectID == "" {
+		return Import{}, errors.New("project_id is required")
+	}
+	if importID == "" {
+		return Import{}, errors.New("import_id is required")
+	}
+	return getImport(ctx, db, projectID, importID)
+}
+
+// List returns the imports of a project, newest first.
+func List(ctx context.Context, db *sqlx.DB, projectID string) ([]Import, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" {
+		return nil, errors.New("project_id is required")
+	}
+	return listImports(ctx, db, projectID)
+}
+
+// Commit queues a previewed dry run for a real run. The file is validated
+// again when it runs, against the project as it is then.
+func Commit(ctx context.Context, db *sqlx.DB, projectID, importID string) (Import, error) {
+	if db == nil {
+		return Import{}, errors.New("db is required")
+	}
+	if projectID == "" {
+		return Import{}, errors.New("project_id is required")
+	}
+	if importID == "" {
+		return Import{}, errors.New("import_id is required")
+	}
+	return commitImport(ctx, db, projectID, importID)
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package imports
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCreateImportParams_Validate(t *testing.T) {
+	valid := CreateParams{ProjectID: "p", CreatedBy: "u", Format: FormatCSV, Data: []byte("title\nA\n")}
+
+	tests := []struct {
+		name    string
+		params  CreateParams
+		wantErr bool
+	}{
+		{name: "valid", params: valid, wantErr: false},
+		{name: "valid mapping", params: func() CreateParams { c := valid; c.Mapping = map[string]string{"title": "Summary"}; return c }(), wantErr: false},
+		{name: "missing project_id", params: func() CreateParams { c := valid; c.ProjectID = ""; return c }(), wantErr: true},
+		{name: "missing created_by", params: func() CreateParams { c := valid; c.CreatedBy = ""; return c }(), wantErr: true},
+		{name: "unknown format", params: func() CreateParams { c := valid; c.Format = "xml"; return c }(), wantErr: true},
+		{name: "empty file", params: func() CreateParams { c := valid; c.Data = nil; return c }(), wantErr: true},
+		{name: "unknown field", params: func() CreateParams { c := valid; c.Mapping = map[string]string{"summary": "Summary"}; return c }(), wantErr: true},
+		{name: "empty column", params: func() CreateParams { c := valid; c.Mapping = map[string]string{"title": ""}; return c }(), wantErr: true},
+		{name: "mapping with jira", params: func() CreateParams {
+			c := valid
+			c.Format = FormatJira
+			c.Mapping = map[string]string{"title": "Summary"}
+			return c
+		}(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	data := "\xef\xbb\xbfIssue key,Summary,Type,Parent, Assignee \n" +
+		"OLD-1,First,Epic,,ada@example.com\n" +
+		",,,,\n" +
+		"OLD-2,\"Second, with comma\",Story,OLD-1\n"
+	records, err := parseCSV([]byte(data), map[string]string{"key": "issue key", "title": "Summary"})
+	if err != nil {
+		t.Fatalf("parseCSV() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2 (blank rows skipped)", len(records))
+	}
+	first := records[0]
+	if first.Row != 1 || first.Key != "OLD-1" || first.Title != "First" || first.Type != "Epic" || first.Assignee != "ada@example.com" {
+		t.Fatalf("records[0] = %+v", first)
+	}
+	second := records[1]
+	if second.Row != 2 || second.Title != "Second, with comma" || second.Parent != "OLD-1" || second.Assignee != "" {
+		t.Fatalf("records[1] = %+v", second)
+	}
+
+	if _, err := parseCSV([]byte("Summary\nA\n"), map[string]string{"title": "Name"}); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("missing mapped column error = %v, want ErrInvalidFile", err)
+	}
+	if _, err := parseCSV([]byte("key,status\nA,Done\n"), nil); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("missing title column error = %v, want ErrInvalidFile", err)
+	}
+	if _, err := parse(FormatCSV, []byte("title\n"), nil); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("no rows error = %v, want ErrInvalidFile", err)
+	}
+}
+
+func TestParseJira(t *testing.T) {
+	data := `{"issues": [
+		{"key": "JRA-1", "fields": {
+			"summary": "Epic",
+			"issuetype": {"name": "Epic"},
+			"status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}},
+			"priority": {"name": "Highest"},
+			"reporter": {"emailAddress": "ada@example.com"},
+			"duedate": "2025-07-01",
+			"description": {"type": "doc", "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "Line one"}, {"type": "hardBreak"}, {"type": "text", "text": "line two"}]},
+				{"type": "paragraph", "content": [{"type": "text", "text": "Second paragraph"}]}
+			]}
+		}},
+		{"key": "JRA-2", "fields": {
+			"summary": "Story",
+			"description": "Plain text",
+			"parent": {"key": "JRA-1"},
+			"assignee": null
+		}}
+	]}`
+	records, err := parseJira([]byte(data))
+	if err != nil {
+		t.Fatalf("parseJira() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+	epic := records[0]
+	if epic.Key != "JRA-1" || epic.Type != "Epic" || epic.Status != "In Progress" || epic.StatusCategory != "doing" ||
+		epic.Priority != "Highest" || epic.Reporter != "ada@example.com" || epic.DueDate != "2025-07-01" {
+		t.Fatalf("records[0] = %+v", epic)
+	}
+	if want := "Line one\nline two\nSecond paragraph"; epic.Description != want {
+		t.Fatalf("description = %q, want %q", epic.Description, want)
+	}
+	story := records[1]
+	if story.Description != "Plain text" || story.Parent != "JRA-1" || story.Assignee != "" {
+		t.Fatalf("records[1] = %+v", story)
+	}
+
+	if _, err := parseJira([]byte(`{"issues": 1}`)); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("invalid export error = %v, want ErrInvalidFile", err)
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	p := project{
+		statuses: []existingStatus{
+			{ID: "s-todo", Name: "To do", Category: "todo"},
+			{ID: "s-old", Name: "Old", Category: "todo", Archived: true},
+		},
+		types: []existingType{{ID: "t-task", Name: "Task", Level: 1}},
+		refs:  []existingRef{{Ref: "OLD-9", ID: "i-9", Level: 0}},
+		userID: func(email string) (string, error) {
+			if email == "ada@example.com" {
+				return "u-ada", nil
+			}
+			return "", nil
+		},
+	}
+	records := []record{
+		{Row: 1, Key: "A-3", Title: "Sub-task", Type: "Sub-task", Parent: "A-2"},
+		{Row: 2, Key: "A-2", Title: "Story", Type: "story", Parent: "A-1", Status: "Review", Reporter: "ADA@example.com"},
+		{Row: 3, Key: "A-1", Title: "Epic", Type: "Epic", Status: "review", Priority: "Blocker", Assignee: "bob@example.com"},
+		{Row: 4, Key: "A-4", Title: "Under an earlier import", Parent: "OLD-9", DueDate: "2025-07-01T10:00:00Z"},
+	}
+	pl, err := buildPlan(records, p, "u-importer")
+	if err != nil {
+		t.Fatalf("buildPlan() error = %v", err)
+	}
+	if pl.report.RowsWithErrors != 0 {
+		t.Fatalf("rows with errors = %d, results %+v", pl.report.RowsWithErrors, pl.report.Results)
+	}
+	var order []string
+	for _, it := range pl.items {
+		order = append(order, it.Key)
+	}
+	if got := strings.Join(order, ","); got != "A-1,A-4,A-2,A-3" {
+		t.Fatalf("creation order = %s, want parents first", got)
+	}
+	if len(pl.statuses) != 1 || pl.statuses[0] != (newStatus{Name: "Review", Category: "todo"}) {
+		t.Fatalf("new statuses = %+v, want Review once", pl.statuses)
+	}
+	if len(pl.types) != 3 {
+		t.Fatalf("new types = %+v, want Sub-task, story and Epic", pl.types)
+	}
+	byKey := map[string]item{}
+	for _, it := range pl.items {
+		byKey[it.Key] = it
+	}
+	if it := byKey["A-1"]; it.Priority != "critical" || it.Status != "Review" || it.AssigneeID != "" || it.ReporterID != "u-importer" {
+		t.Fatalf("A-1 = %+v", it)
+	}
+	if it := byKey["A-2"]; it.ReporterID != "u-ada" || it.ParentKey != "A-1" || it.Priority != "medium" {
+		t.Fatalf("A-2 = %+v", it)
+	}
+	if it := byKey["A-4"]; it.Status != "To do" || it.Type != "Task" || it.ParentID != "i-9" || it.DueDate == nil || it.DueDate.Format("2006-01-02") != "2025-07-01" {
+		t.Fatalf("A-4 = %+v", it)
+	}
+	if warnings := pl.report.Results[2].Warnings; len(warnings) != 1 || !strings.Contains(warnings[0], "bob@example.com") {
+		t.Fatalf("A-1 warnings = %v, want the unknown assignee", warnings)
+	}
+}
+
+func TestBuildPlan_RowErrors(t *testing.T) {
+	p := project{
+		statuses: []existingStatus{
+			{ID: "s-todo", Name: "To do", Category: "todo"},
+			{ID: "s-old", Name: "Old", Category: "todo", Archived: true},
+		},
+		types:  []existingType{{ID: "t-task", Name: "Task", Level: 1}, {ID: "t-epic", Name: "Epic", Level: 0}},
+		refs:   []existingRef{{Ref: "DONE-1", ID: "i-1", Level: 1}},
+		userID: func(string) (string, error) { return "", nil },
+	}
+	records := []record{
+		{Row: 1, Key: "K-1", Title: ""},
+		{Row: 2, Key: "K-2", Title: "Duplicate", Parent: ""},
+		{Row: 3, Key: "K-2", Title: "Duplicate again"},
+		{Row: 4, Key: "DONE-1", Title: "Already imported"},
+		{Row: 5, Title: "Bad priority", Priority: "urgent"},
+		{Row: 6, Title: "Bad date", DueDate: "July 1st"},
+		{Row: 7, Title: "Archived status", Status: "old"},
+		{Row: 8, Title: "Bad category", Status: "New", StatusCategory: "later"},
+		{Row: 9, Title: "Unknown parent", Parent: "NOPE-1"},
+		{Row: 10, Key: "C-1", Title: "Cycle one", Parent: "C-2"},
+		{Row: 11, Key: "C-2", Title: "Cycle two", Parent: "C-1"},
+		{Row: 12, Key: "E-1", Title: "Epic under a task", Type: "Epic", Parent: "K-2"},
+		{Row: 13, Title: "Fine"},
+	}
+	pl, err := buildPlan(records, p, "u")
+	if err != nil {
+		t.Fatalf("buildPlan() error = %v", err)
+	}
+	if pl.report.Rows != 13 || pl.report.RowsWithErrors != 11 {
+		t.Fatalf("rows = %d, rows with errors = %d, want 13 and 11", pl.report.Rows, pl.report.RowsWithErrors)
+	}
+	for i, res := range pl.report.Results {
+		wantErr := res.Row != 2 && res.Row != 13
+		if (len(res.Errors) > 0) != wantErr {
+			t.Errorf("row %d errors = %v, want errors %v", res.Row, res.Errors, wantErr)
+		}
+		if res.Row != i+1 {
+			t.Errorf("results[%d].Row = %d", i, res.Row)
+		}
+	}
+	if len(pl.items) != 2 {
+		t.Fatalf("items = %+v, want the two valid rows", pl.items)
+	}
+
+	p.statuses = nil
+	if pl, _ := buildPlan([]record{{Row: 1, Title: "No status"}}, p, "u"); pl.report.RowsWithErrors != 1 {
+		t.Fatal("a row without status in a project without a todo status: expected an error")
+	}
+}
+
+func TestPriorities(t *testing.T) {
+	for name, want := range map[string]string{
+		"": "medium", "Highest": "critical", "Blocker": "critical", "High": "high", "Major": "high",
+		"Normal": "medium", "Low": "low", "Trivial": "low", "critical": "critical",
+	} {
+		if got := priorities[strings.ToLower(name)]; got != want {
+			t.Errorf("priority %q = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestImportsNilDB(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Create(ctx, nil, CreateParams{}); err == nil {
+		t.Error("Create(nil db): expected error")
+	}
+	if _, err := Get(ctx, nil, "p", "i"); err == nil {
+		t.Error("Get(nil db): expected error")
+	}
+	if _, err := List(ctx, nil, "p"); err == nil {
+		t.Error("List(nil db): expected error")
+	}
+	if _, err := Commit(ctx, nil, "p", "i"); err == nil {
+		t.Error("Commit(nil db): expected error")
+	}
+	if _, err := (&Worker{}).RunOnce(ctx); err == nil {
+		t.Error("RunOnce(nil db): expected error")
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package imports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Fields an import reads for each issue. Users are given by email and
+// parent by the key of another issue, in the file or imported before.
+const (
+	FieldKey            = "key"
+	FieldTitle          = "title"
+	FieldDescription    = "description"
+	FieldType           = "type"
+	FieldStatus         = "status"
+	FieldStatusCategory = "status_category"
+	FieldPriority       = "priority"
+	FieldAssignee       = "assignee"
+	FieldReporter       = "reporter"
+	FieldParent         = "parent"
+	FieldDueDate        = "due_date"
+)
+
+var knownFields = map[string]bool{
+	FieldKey: true, FieldTitle: true, FieldDescription: true, FieldType: true,
+	FieldStatus: true, FieldStatusCategory: true, FieldPriority: true,
+	FieldAssignee: true, FieldReporter: true, FieldParent: true, FieldDueDate: true,
+}
+
+// record is one issue read from a file, with every field as text.
+type record struct {
+	Row            int
+	Key            string
+	Title          string
+	Description    string
+	Type           string
+	Status         string
+	StatusCategory string
+	Priority       string
+	Assignee       string
+	Reporter       string
+	Parent         string
+	DueDate        string
+}
+
+func parse(format string, data []byte, mapping map[string]string) ([]record, error) {
+	var (
+		records []record
+		err     error
+	)
+	switch format {
+	case FormatCSV:
+		records, err = parseCSV(data, mapping)
+	case FormatJira:
+		records, err = parseJira(data)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: no issues found", ErrInvalidFile)
+	}
+	if len(records) > maxRows {
+		return nil, fmt.Errorf("%w: at most %d issues can be imported at once", ErrInvalidFile, maxRows)
+	}
+	return records, nil
+}
+
+func parseCSV(data []byte, mapping map[string]string) ([]record, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: read header: %v", ErrInvalidFile, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	index := make(map[string]int, len(knownFields))
+	for field := range knownFields {
+		column, mapped := mapping[field]
+		if !mapped {
+			column = field
+		}
+		i, ok := columns[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("%w: column %q mapped to %s not found", ErrInvalidFile, column, field)
+			}
+			continue
+		}
+		index[field] = i
+	}
+	if _, ok := index[FieldTitle]; !ok {
+		return nil, fmt.Errorf("%w: a title column is required", ErrInvalidFile)
+	}
+
+	var records []record
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if blank(row) {
+			continue
+		}
+		if len(records) == maxRows {
+			return nil, fmt.Errorf("%w: at most %d issues can be imported at once", ErrInvalidFile, maxRows)
+		}
+		get := func(field string) string {
+			i, ok := index[field]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+		records = append(records, record{
+			Row:            len(records) + 1,
+			Key:            get(FieldKey),
+			Title:          get(FieldTitle),
+			Description:    get(FieldDescription),
+			Type:           get(FieldType),
+			Status:         get(FieldStatus),
+			StatusCategory: strings.ToLower(get(FieldStatusCategory)),
+			Priority:       get(FieldPriority),
+			Assignee:       get(FieldAssignee),
+			Reporter:       get(FieldReporter),
+			Parent:         get(FieldParent),
+			DueDate:        get(FieldDueDate),
+		})
+	}
+	return records, nil
+}
+
+func blank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// jiraExport is the subset of a Jira search export that is imported.
+type jiraExport struct {
+	Issues []struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary     string          `json:"summary"`
+			Description json.RawMessage `json:"description"`
+			IssueType   *jiraNamed      `json:"issuetype"`
+			Status      *struct {
+				Name           string `json:"name"`
+				StatusCategory *struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+			Priority *jiraNamed `json:"priority"`
+			Assignee *jiraUser  `json:"assignee"`
+			Reporter *jiraUser  `json:"reporter"`
+			Parent   *struct {
+				Key string `json:"key"`
+			} `json:"parent"`
+			DueDate string `json:"duedate"`
+		} `json:"fields"`
+	} `json:"issues"`
+}
+
+type jiraNamed struct {
+	Name string `json:"name"`
+}
+
+type jiraUser struct {
+	EmailAddress string `json:"emailAddress"`
+}
+
+// jiraCategories maps Jira status category keys to Tookly categories.
+var jiraCategories = map[string]string{
+	"new":           "todo",
+	"indeterminate": "doing",
+	"done":          "done",
+}
+
+func parseJira(data []byte) ([]record, error) {
+	var export jiraExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if len(export.Issues) > maxRows {
+		return nil, fmt.Errorf("%w: at most %d issues can be imported at once", ErrInvalidFile, maxRows)
+	}
+	records := make([]record, 0, len(export.Issues))
+	for i, issue := range export.Issues {
+		f := issue.Fields
+		rec := record{
+			Row:     i + 1,
+			Key:     strings.TrimSpace(issue.Key),
+			Title:   strings.TrimSpace(f.Summary),
+			DueDate: strings.TrimSpace(f.DueDate),
+		}
+		description, err := jiraText(f.Description)
+		if err != nil {
+			return nil, fmt.Errorf("%w: issue %d: description: %v", ErrInvalidFile, i+1, err)
+		}
+		rec.Description = description
+		if f.IssueType != nil {
+			rec.Type = strings.TrimSpace(f.IssueType.Name)
+		}
+		if f.Status != nil {
+			rec.Status = strings.TrimSpace(f.Status.Name)
+			if f.Status.StatusCategory != nil {
+				rec.StatusCategory = jiraCategories[f.Status.StatusCategory.Key]
+			}
+		}
+		if f.Priority != nil {
+			rec.Priority = strings.TrimSpace(f.Priority.Name)
+		}
+		if f.Assignee != nil {
+			rec.Assignee = strings.TrimSpace(f.Assignee.EmailAddress)
+		}
+		if f.Reporter != nil {
+			rec.Reporter = strings.TrimSpace(f.Reporter.EmailAddress)
+		}
+		if f.Parent != nil {
+			rec.Parent = strings.TrimSpace(f.Parent.Key)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// jiraText reads a description, which is plain text in older exports and an
+// Atlassian Document Format tree in newer ones.
+func jiraText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	doc.write(&b)
+	return strings.TrimSpace(b.String()), nil
+}
+
+// adfNode is a node of an Atlassian Document Format tree.
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+}
+
+// adfBlocks are the node types that end a line of text.
+var adfBlocks = map[string]bool{
+	"paragraph": true, "heading": true, "codeBlock": true,
+	"blockquote": true, "listItem": true, "rule": true,
+}
+
+func (n adfNode) write(b *strings.Builder) {
+	switch n.Type {
+	case "text":
+		b.WriteString(n.Text)
+	case "hardBreak":
+		b.WriteString("\n")
+	}
+	for _, child := range n.Content {
+		child.write(b)
+	}
+	if adfBlocks[n.Type] && !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package imports
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultType is the issue type of rows that do not name one.
+const defaultType = "Task"
+
+// priorities maps the priority names of common trackers, lowercased, to
+// Tookly priorities.
+var priorities = map[string]string{
+	"":         "medium",
+	"highest":  "critical",
+	"blocker":  "critical",
+	"critical": "critical",
+	"high":     "high",
+	"major":    "high",
+	"medium":   "medium",
+	"normal":   "medium",
+	"low":      "low",
+	"lowest":   "low",
+	"minor":    "low",
+	"trivial":  "low",
+}
+
+// existingStatus and existingType are the statuses and issue types of the
+// project, archived ones included since their names stay taken.
+type existingStatus struct {
+	ID       string `db:"id"`
+	Name     string `db:"name"`
+	Category string `db:"category"`
+	Archived bool   `db:"archived"`
+}
+
+type existingType struct {
+	ID       string `db:"id"`
+	Name     string `db:"name"`
+	Level    int    `db:"level"`
+	Archived bool   `db:"archived"`
+}
+
+// existingRef is an issue of the project imported before, by external_ref.
+type existingRef struct {
+	Ref      string `db:"external_ref"`
+	ID       string `db:"id"`
+	Level    int    `db:"level"`
+	Archived bool   `db:"archived"`
+}
+
+// project is what a plan is checked against. userID returns "" for an
+// email that is not an active member of the project's workspace.
+type project struct {
+	statuses []existingStatus
+	types    []existingType
+	refs     []existingRef
+	userID   func(email string) (string, error)
+}
+
+// plan is a validated import: the statuses and issue types to create and
+// the issues to create, parents first.
+type plan struct {
+	report   Report
+	statuses []newStatus
+	types    []newType
+	items    []item
+}
+
+type newStatus struct {
+	Name     string
+	Category string
+}
+
+type newType struct {
+	Name  string
+	Level int
+}
+
+// item is one issue to create. Status and type are given by name, parent
+// either by the key of an earlier item or by the ID of an existing issue.
+type item struct {
+	result      int
+	Key         string
+	Title       string
+	Description string
+	Type        string
+	Status      string
+	Priority    string
+	AssigneeID  string
+	ReporterID  string
+	ParentKey   string
+	ParentID    string
+	DueDate     *time.Time
+}
+
+// buildPlan validates records against the project. Rows with errors are
+// reported and left out of the plan; importedBy reports rows whose
+// reporter is unknown.
+func buildPlan(records []record, p project, importedBy string) (plan, error) {
+	var out plan
+	out.report = Report{Rows: len(records), NewStatuses: []string{}, NewIssueTypes: []string{}}
+	results := make([]RowResult, len(records))
+
+	statusByName := map[string]existingStatus{}
+	var defaultStatus string
+	for _, s := range p.statuses {
+		statusByName[strings.ToLower(s.Name)] = s
+		if defaultStatus == "" && !s.Archived && s.Category == "todo" {
+			defaultStatus = s.Name
+		}
+	}
+	typeByName := map[string]existingType{}
+	for _, t := range p.types {
+		typeByName[strings.ToLower(t.Name)] = t
+	}
+	refByKey := map[string]existingRef{}
+	for _, r := range p.refs {
+		refByKey[r.Ref] = r
+	}
+	users := map[string]string{}
+	lookupUser := func(email string) (string, error) {
+		email = strings.ToLower(email)
+		if id, ok := users[email]; ok {
+			return id, nil
+		}
+		id, err := p.userID(email)
+		if err != nil {
+			return "", err
+		}
+		users[email] = id
+		return id, nil
+	}
+	newStatuses := map[string]string{}
+	newTypes := map[string]newType{}
+
+	items := make([]item, len(records))
+	levels := make([]int, len(records))
+	rowByKey := map[string]int{}
+	for i, rec := range records {
+		res := &results[i]
+		*res = RowResult{Row: rec.Row, Key: rec.Key, Title: rec.Title}
+		it := item{result: i, Key: rec.Key, Title: rec.Title, Description: rec.Description}
+
+		if rec.Title == "" {
+			res.Errors = append(res.Errors, "title is required")
+		}
+		if rec.Key != "" {
+			if _, dup := rowByKey[rec.Key]; dup {
+				res.Errors = append(res.Errors, fmt.Sprintf("key %q appears more than once", rec.Key))
+			} else {
+				rowByKey[rec.Key] = i
+			}
+			if _, ok := refByKey[rec.Key]; ok {
+				res.Errors = append(res.Errors, fmt.Sprintf("key %q was already imported", rec.Key))
+			}
+		}
+
+		priority, ok := priorities[strings.ToLower(rec.Priority)]
+		if !ok {
+			res.Errors = append(res.Errors, fmt.Sprintf("unknown priority %q", rec.Priority))
+		}
+		it.Priority = priority
+
+		if rec.DueDate != "" {
+			due, err := parseDate(rec.DueDate)
+			if err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("due date %q must be YYYY-MM-DD", rec.DueDate))
+			} else {
+				it.DueDate = &due
+			}
+		}
+
+		typeName := rec.Type
+		if typeName == "" {
+			typeName = defaultType
+		}
+		if t, ok := typeByName[strings.ToLower(typeName)]; ok {
+			if t.Archived {
+				res.Errors = append(res.Errors, fmt.Sprintf("issue type %q is archived", t.Name))
+			}
+			it.Type, levels[i] = t.Name, t.Level
+		} else if t, ok := newTypes[strings.ToLower(typeName)]; ok {
+			it.Type, levels[i] = t.Name, t.Level
+		} else {
+			t := newType{Name: typeName, Level: levelOf(typeName)}
+			newTypes[strings.ToLower(typeName)] = t
+			out.types = append(out.types, t)
+			it.Type, levels[i] = t.Name, t.Level
+		}
+
+		switch s, ok := statusByName[strings.ToLower(rec.Status)]; {
+		case rec.Status == "":
+			if defaultStatus == "" {
+				res.Errors = append(res.Errors, "status is required: the project has no active todo status")
+			}
+			it.Status = defaultStatus
+		case ok:
+			if s.Archived {
+				res.Errors = append(res.Errors, fmt.Sprintf("status %q is archived", s.Name))
+			}
+			it.Status = s.Name
+		default:
+			category := rec.StatusCategory
+			key := strings.ToLower(rec.Status)
+			switch {
+			case category != "" && category != "todo" && category != "doing" && category != "done":
+				res.Errors = append(res.Errors, fmt.Sprintf("status category %q must be todo, doing or done", category))
+			case newStatuses[key] != "":
+				it.Status = newStatuses[key]
+			default:
+				if category == "" {
+					category = "todo"
+					res.Warnings = append(res.Warnings, fmt.Sprintf("status %q will be created in the todo category", rec.Status))
+				}
+				newStatuses[key] = rec.Status
+				out.statuses = append(out.statuses, newStatus{Name: rec.Status, Category: category})
+				it.Status = rec.Status
+			}
+		}
+
+		reporterID := importedBy
+		if rec.Reporter != "" {
+			id, err := lookupUser(rec.Reporter)
+			if err != nil {
+				return plan{}, err
+			}
+			if id == "" {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("reporter %s not found; the importing user is the reporter", rec.Reporter))
+			} else {
+				reporterID = id
+			}
+		}
+		it.ReporterID = reporterID
+		if rec.Assignee != "" {
+			id, err := lookupUser(rec.Assignee)
+			if err != nil {
+				return plan{}, err
+			}
+			if id == "" {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("assignee %s not found; left unassigned", rec.Assignee))
+			}
+			it.AssigneeID = id
+		}
+		items[i] = it
+	}
+
+	// Link parents, then order rows so each parent is created before its
+	// children.
+	parents := make([]int, len(records))
+	for i, rec := range records {
+		parents[i] = -1
+		if rec.Parent == "" {
+			continue
+		}
+		res := &results[i]
+		if j, ok := rowByKey[rec.Parent]; ok {
+			parents[i] = j
+			items[i].ParentKey = rec.Parent
+			if levels[j] >= levels[i] {
+				res.Errors = append(res.Errors, fmt.Sprintf("a %s cannot be the parent of a %s", items[j].Type, items[i].Type))
+			}
+			continue
+		}
+		ref, ok := refByKey[rec.Parent]
+		switch {
+		case !ok:
+			res.Errors = append(res.Errors, fmt.Sprintf("parent %q not found", rec.Parent))
+		case ref.Archived:
+			res.Errors = append(res.Errors, fmt.Sprintf("parent %q is archived", rec.Parent))
+		case ref.Level >= levels[i]:
+			res.Errors = append(res.Errors, fmt.Sprintf("parent %q cannot be the parent of a %s", rec.Parent, items[i].Type))
+		default:
+			items[i].ParentID = ref.ID
+		}
+	}
+	rowDepths := depths(parents, results)
+
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return rowDepths[order[a]] < rowDepths[order[b]] })
+	for _, i := range order {
+		if len(results[i].Errors) > 0 {
+			out.report.RowsWithErrors++
+			continue
+		}
+		out.items = append(out.items, items[i])
+	}
+	for _, s := range out.statuses {
+		out.report.NewStatuses = append(out.report.NewStatuses, s.Name)
+	}
+	for _, t := range out.types {
+		out.report.NewIssueTypes = append(out.report.NewIssueTypes, t.Name)
+	}
+	out.report.Results = results
+	return out, nil
+}
+
+// depths returns how many in-file ancestors each row has. Rows on a
+// parent cycle get an error and are treated as having no parent.
+func depths(parents []int, results []RowResult) []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(parents))
+	depth := make([]int, len(parents))
+	for i := range parents {
+		var path []int
+		j := i
+		for j >= 0 && state[j] == unvisited {
+			state[j] = visiting
+			path = append(path, j)
+			j = parents[j]
+		}
+		if j >= 0 && state[j] == visiting {
+			for k := len(path) - 1; k >= 0; k-- {
+				c := path[k]
+				results[c].Errors = append(results[c].Errors, "parent links form a cycle")
+				parents[c] = -1
+				if c == j {
+					break
+				}
+			}
+		}
+		for k := len(path) - 1; k >= 0; k-- {
+			c := path[k]
+			if p := parents[c]; p >= 0 {
+				depth[c] = depth[p] + 1
+			}
+			state[c] = visited
+		}
+	}
+	return depth
+}
+
+// levelOf guesses the hierarchy level of a new issue type from its name:
+// epics sit above stories and tasks, sub-tasks below them.
+func levelOf(name string) int {
+	switch strings.ToLower(strings.ReplaceAll(name, "-", "")) {
+	case "epic":
+		return 0
+	case "subtask", "sub task":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// parseDate reads a date, ignoring the time of day of a timestamp.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
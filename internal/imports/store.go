@@ -0,0 +1,243 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package imports
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const importCols = `id, project_id, created_by, format, mapping, dry_run, status,
+	report, error, created_at, started_at, finished_at`
+
+func createImport(ctx context.Context, db *sqlx.DB, params CreateParams) (Import, error) {
+	mapping := params.Mapping
+	if mapping == nil {
+		mapping = map[string]string{}
+	}
+	mappingJSON, err := json.Marshal(mapping)
+	if err != nil {
+		return Import{}, fmt.Errorf("marshal mapping: %w", err)
+	}
+	var imp Import
+	if err := db.QueryRowxContext(ctx,
+		`INSERT INTO imports (project_id, created_by, format, mapping, source, dry_run)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING `+importCols,
+		params.ProjectID, params.CreatedBy, params.Format, mappingJSON, params.Data, params.DryRun,
+	).StructScan(&imp); err != nil {
+		return Import{}, fmt.Errorf("insert import: %w", err)
+	}
+	return imp, nil
+}
+
+func getImport(ctx context.Context, db *sqlx.DB, projectID, importID string) (Import, error) {
+	var imp Import
+	if err := db.GetContext(ctx, &imp,
+		`SELECT `+importCols+`
+		 FROM imports
+		 WHERE id = $1 AND project_id = $2`,
+		importID, projectID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Import{}, ErrNotFound
+		}
+		return Import{}, fmt.Errorf("get import: %w", err)
+	}
+	return imp, nil
+}
+
+func listImports(ctx context.Context, db *sqlx.DB, projectID string) ([]Import, error) {
+	list := []Import{}
+	if err := db.SelectContext(ctx, &list,
+		`SELECT `+importCols+`
+		 FROM imports
+		 WHERE project_id = $1
+		 ORDER BY created_at DESC`,
+		projectID,
+	); err != nil {
+		return nil, fmt.Errorf("list imports: %w", err)
+	}
+	return list, nil
+}
+
+func commitImport(ctx context.Context, db *sqlx.DB, projectID, importID string) (Import, error) {
+	var imp Import
+	err := db.GetContext(ctx, &imp,
+		`UPDATE imports
+		 SET dry_run     = FALSE,
+		     status      = 'queued',
+		     started_at  = NULL,
+		     finished_at = NULL
+		 WHERE id = $1 AND project_id = $2
+		   AND status = 'previewed'
+		   AND (report->>'rows_with_errors')::int = 0
+		 RETURNING `+importCols,
+		importID, projectID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err := getImport(ctx, db, projectID, importID); err != nil {
+			return Import{}, err
+		}
+		return Import{}, ErrNotCommittable
+	}
+	if err != nil {
+		return Import{}, fmt.Errorf("commit import: %w", err)
+	}
+	return imp, nil
+}
+
+// job is a claimed import with its file.
+type job struct {
+	Import
+	Source []byte `db:"source"`
+}
+
+// claimImport starts the oldest queued import, or one left running for
+// longer than lease by a worker that stopped. It returns false when there
+// is none.
+func claimImport(ctx context.Context, db *sqlx.DB, lease time.Duration) (job, bool, error) {
+	var j job
+	err := db.GetContext(ctx, &j,
+		`WITH next AS (
+			SELECT id
+			FROM imports
+			WHERE status = 'queued'
+			   OR (status = 'running' AND started_at < NOW() - make_interval(secs => $1))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE imports i
+		SET status = 'running', started_at = NOW()
+		FROM next
+		WHERE i.id = next.id
+		RETURNING i.id, i.project_id, i.created_by, i.format, i.mapping, i.dry_run, i.status,
+		          i.report, i.error, i.created_at, i.started_at, i.finished_at, i.source`,
+		lease.Seconds(),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return job{}, false, nil
+	}
+	if err != nil {
+		return job{}, false, fmt.Errorf("claim import: %w", err)
+	}
+	return j, true, nil
+}
+
+// errClaimLost means another worker re-claimed an import after its lease ran
+// out; the first run must then leave it alone.
+var errClaimLost = errors.New("import claimed by another worker")
+
+// lockImport locks a claimed import for the rest of tx, which keeps
+// claimImport from handing it to another worker while the run commits.
+func lockImport(ctx context.Context, tx *sqlx.Tx, j job) error {
+	var id string
+	err := tx.GetContext(ctx, &id,
+		`SELECT id FROM imports
+		 WHERE id = $1 AND status = 'running' AND started_at = $2
+		 FOR UPDATE`,
+		j.ID, j.StartedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errClaimLost
+	}
+	if err != nil {
+		return fmt.Errorf("lock import: %w", err)
+	}
+	return nil
+}
+
+// finishImport records the outcome of a run, unless another worker has
+// claimed the job since. The file is kept only for a preview, which may
+// still be committed.
+func finishImport(ctx context.Context, q sqlx.ExecerContext, j job, status string, report *Report, failure string) error {
+	var reportJSON []byte
+	if report != nil {
+		var err error
+		if reportJSON, err = json.Marshal(report); err != nil {
+			return fmt.Errorf("marshal import report: %w", err)
+		}
+	}
+	if _, err := q.ExecContext(ctx,
+		`UPDATE imports
+		 SET status      = $3,
+		     report      = $4,
+		     error       = $5,
+		     finished_at = NOW(),
+		     source      = CASE WHEN $3 = 'previewed' THEN source END
+		 WHERE id = $1 AND status = 'running' AND started_at = $2`,
+		j.ID, j.StartedAt, status, reportJSON, failure,
+	); err != nil {
+		return fmt.Errorf("finish import: %w", err)
+	}
+	return nil
+}
+
+// loadProject reads the statuses, issue types and imported issues of a
+// project for planning an import.
+func loadProject(ctx context.Context, q sqlx.QueryerContext, projectID string) (project, error) {
+	var p project
+	if err := sqlx.SelectContext(ctx, q, &p.statuses,
+		`SELECT id, name, category, archived_at IS NOT NULL AS archived
+		 FROM statuses
+		 WHERE project_id = $1
+		 ORDER BY position`,
+		projectID,
+	); err != nil {
+		return project{}, fmt.Errorf("load statuses: %w", err)
+	}
+	if err := sqlx.SelectContext(ctx, q, &p.types,
+		`SELECT id, name, level, archived_at IS NOT NULL AS archived
+		 FROM issue_types
+		 WHERE project_id = $1`,
+		projectID,
+	); err != nil {
+		return project{}, fmt.Errorf("load issue types: %w", err)
+	}
+	if err := sqlx.SelectContext(ctx, q, &p.refs,
+		`SELECT i.external_ref, i.id, t.level, i.archived_at IS NOT NULL AS archived
+		 FROM issues i
+		 JOIN issue_types t ON t.id = i.issue_type_id
+		 WHERE i.project_id = $1 AND i.external_ref IS NOT NULL`,
+		projectID,
+	); err != nil {
+		return project{}, fmt.Errorf("load imported issues: %w", err)
+	}
+	return p, nil
+}
+
+// memberID returns the ID of the active user with an email who is an active
+// member of the project's workspace, or "" when there is none, so that an
+// import cannot tell which emails have an account elsewhere on the
+// instance.
+func memberID(ctx context.Context, q sqlx.QueryerContext, projectID, email string) (string, error) {
+	var id string
+	err := sqlx.GetContext(ctx, q, &id,
+		`SELECT u.id
+		 FROM app_users u
+		 JOIN workspace_members wm ON wm.user_id = u.id
+		 JOIN projects p ON p.workspace_id = wm.workspace_id
+		 WHERE p.id = $1
+		   AND lower(u.email) = lower($2)
+		   AND u.archived_at IS NULL
+		   AND wm.archived_at IS NULL`,
+		projectID, email,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get workspace member by email: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package imports
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestImports(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	admin := testpg.SeedUser(t, db)
+	ada := testpg.SeedUser(t, db)
+	outsider := testpg.SeedUser(t, db)
+	var adaEmail, outsiderEmail string
+	if err := db.GetContext(ctx, &adaEmail, `SELECT email FROM app_users WHERE id = $1`, ada); err != nil {
+		t.Fatalf("get email: %v", err)
+	}
+	if err := db.GetContext(ctx, &outsiderEmail, `SELECT email FROM app_users WHERE id = $1`, outsider); err != nil {
+		t.Fatalf("get email: %v", err)
+	}
+	wsID := testpg.SeedWorkspace(t, db)
+	if _, err := db.ExecContext(ctx, `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'member')`, wsID, ada); err != nil {
+		t.Fatalf("insert member: %v", err)
+	}
+	projID := testpg.SeedProject(t, db, wsID, "IMP")
+	if _, err := db.ExecContext(ctx, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1)`, projID); err != nil {
+		t.Fatalf("insert issue_type: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0)`, projID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	worker := &Worker{DB: db}
+	run := func(want int) {
+		t.Helper()
+		n, err := worker.RunOnce(ctx)
+		if err != nil {
+			t.Fatalf("RunOnce: %v", err)
+		}
+		if n != want {
+			t.Fatalf("RunOnce ran %d imports, want %d", n, want)
+		}
+	}
+	get := func(id string) Import {
+		t.Helper()
+		imp, err := Get(ctx, db, projID, id)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		return imp
+	}
+	countIssues := func() int {
+		t.Helper()
+		var n int
+		if err := db.GetContext(ctx, &n, `SELECT COUNT(*) FROM issues WHERE project_id = $1`, projID); err != nil {
+			t.Fatalf("count issues: %v", err)
+		}
+		return n
+	}
+
+	if _, err := Create(ctx, db, CreateParams{ProjectID: projID, CreatedBy: admin, Format: FormatJira, Data: []byte("not json")}); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("Create with an unreadable file error = %v, want ErrInvalidFile", err)
+	}
+
+	// A dry run reports the plan without creating anything.
+	// Users outside the workspace are not found, although they have an
+	// account on the instance.
+	csvData := "Key,Summary,Type,State,Reporter,Assignee,Parent\n" +
+		"OLD-1,Epic,Epic,In review," + adaEmail + ",,\n" +
+		"OLD-2,Story,Task,,," + outsiderEmail + ",OLD-1\n"
+	dry, err := Create(ctx, db, CreateParams{
+		ProjectID: projID, CreatedBy: admin, Format: FormatCSV, Data: []byte(csvData),
+		Mapping: map[string]string{"title": "Summary", "status": "State"}, DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("Create dry run: %v", err)
+	}
+	if dry.Status != StatusQueued {
+		t.Fatalf("status = %q, want queued", dry.Status)
+	}
+	run(1)
+	dry = get(dry.ID)
+	if dry.Status != StatusPreviewed || dry.Report == nil || dry.Report.Rows != 2 || dry.Report.RowsWithErrors != 0 {
+		t.Fatalf("dry run = %+v, report %+v", dry, dry.Report)
+	}
+	if w := dry.Report.Results[1].Warnings; len(w) != 1 || w[0] != "assignee "+outsiderEmail+" not found; left unassigned" {
+		t.Fatalf("outsider warnings = %v, want the assignee not found", w)
+	}
+	if len(dry.Report.NewStatuses) != 1 || len(dry.Report.NewIssueTypes) != 1 {
+		t.Fatalf("new statuses %v, new types %v, want In review and Epic", dry.Report.NewStatuses, dry.Report.NewIssueTypes)
+	}
+	if n := countIssues(); n != 0 {
+		t.Fatalf("issues after dry run = %d, want 0", n)
+	}
+
+	// Committing the preview runs it for real.
+	committed, err := Commit(ctx, db, projID, dry.ID)
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if committed.Status != StatusQueued || committed.DryRun {
+		t.Fatalf("committed = %+v, want a queued real run", committed)
+	}
+	run(1)
+	done := get(dry.ID)
+	if done.Status != StatusCompleted {
+		t.Fatalf("status = %q (%s), want completed", done.Status, done.Error)
+	}
+	for _, res := range done.Report.Results {
+		if res.IssueID == "" || res.IssueNumber == 0 {
+			t.Fatalf("result %+v: expected the created issue", res)
+		}
+	}
+	var story struct {
+		ParentID   string  `db:"parent_issue_id"`
+		ReporterID string  `db:"reporter_id"`
+		AssigneeID *string `db:"assignee_id"`
+	}
+	if err := db.GetContext(ctx, &story,
+		`SELECT parent_issue_id, reporter_id, assignee_id FROM issues WHERE project_id = $1 AND external_ref = 'OLD-2'`, projID,
+	); err != nil {
+		t.Fatalf("get imported story: %v", err)
+	}
+	if story.ParentID != done.Report.Results[0].IssueID || story.ReporterID != admin || story.AssigneeID != nil {
+		t.Fatalf("story = %+v, want parent OLD-1, the importing user as reporter and no assignee", story)
+	}
+	var epicReporter string
+	if err := db.GetContext(ctx, &epicReporter,
+		`SELECT reporter_id FROM issues WHERE project_id = $1 AND external_ref = 'OLD-1'`, projID,
+	); err != nil || epicReporter != ada {
+		t.Fatalf("epic reporter = %q, %v, want the user mapped by email", epicReporter, err)
+	}
+	if _, err := Commit(ctx, db, projID, dry.ID); !errors.Is(err, ErrNotCommittable) {
+		t.Fatalf("Commit twice error = %v, want ErrNotCommittable", err)
+	}
+
+	// A later import links to earlier imports by key, and refuses keys
+	// imported before; one bad row fails the whole import.
+	jira := `{"issues": [
+		{"key": "OLD-3", "fields": {"summary": "Another story", "parent": {"key": "OLD-1"}}},
+		{"key": "OLD-2", "fields": {"summary": "Imported again"}}
+	]}`
+	again, err := Create(ctx, db, CreateParams{ProjectID: projID, CreatedBy: admin, Format: FormatJira, Data: []byte(jira)})
+	if err != nil {
+		t.Fatalf("Create jira import: %v", err)
+	}
+	run(1)
+	again = get(again.ID)
+	if again.Status != StatusFailed || again.Report == nil || again.Report.RowsWithErrors != 1 {
+		t.Fatalf("import with a reused key = %+v, want failed with one row error", again)
+	}
+	if n := countIssues(); n != 2 {
+		t.Fatalf("issues after a failed import = %d, want 2", n)
+	}
+
+	fixed, err := Create(ctx, db, CreateParams{ProjectID: projID, CreatedBy: admin, Format: FormatJira, Data: []byte(`{"issues": [
+		{"key": "OLD-3", "fields": {"summary": "Another story", "parent": {"key": "OLD-1"}}}
+	]}`)})
+	if err != nil {
+		t.Fatalf("Create jira import: %v", err)
+	}
+	run(1)
+	if fixed = get(fixed.ID); fixed.Status != StatusCompleted {
+		t.Fatalf("status = %q (%s), want completed", fixed.Status, fixed.Error)
+	}
+	if n := countIssues(); n != 3 {
+		t.Fatalf("issues = %d, want 3", n)
+	}
+
+	list, err := List(ctx, db, projID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 3 || list[0].ID != fixed.ID {
+		t.Fatalf("List = %d imports, want 3 newest first", len(list))
+	}
+	if _, err := Get(ctx, db, projID, "00000000-0000-0000-0000-000000000000"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get unknown error = %v, want ErrNotFound", err)
+	}
+
+	// A run whose lease ran out and that another worker re-claimed imports
+	// nothing, so a row without a key is not created twice.
+	stale, err := Create(ctx, db, CreateParams{ProjectID: projID, CreatedBy: admin, Format: FormatJira, Data: []byte(`{"issues": [
+		{"fields": {"summary": "No key"}}
+	]}`)})
+	if err != nil {
+		t.Fatalf("Create jira import: %v", err)
+	}
+	j, ok, err := claimImport(ctx, db, defaultLease)
+	if err != nil || !ok || j.ID != stale.ID {
+		t.Fatalf("claimImport() = %s, %v, %v, want the new import", j.ID, ok, err)
+	}
+	db.MustExec(`UPDATE imports SET started_at = started_at + interval '1 second' WHERE id = $1`, j.ID)
+	if err := worker.run(ctx, j); err != nil {
+		t.Fatalf("run with a lost claim: %v", err)
+	}
+	if n := countIssues(); n != 3 {
+		t.Fatalf("issues after a lost claim = %d, want 3", n)
+	}
+	if got := get(stale.ID); got.Status != StatusRunning {
+		t.Fatalf("status after a lost claim = %q, want running for the new owner", got.Status)
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package imports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/issuetypes"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/statuses"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	// defaultLease is how long a running import may take before another
+	// worker assumes its worker stopped and runs it again.
+	defaultLease = 15 * time.Minute
+)
+
+// Worker runs queued imports one at a time. The zero value of each field
+// other than DB selects a default.
+type Worker struct {
+	DB           *sqlx.DB
+	PollInterval time.Duration
+	Lease        time.Duration
+}
+
+// Run runs queued imports every PollInterval until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("import worker error", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs queued imports until none is left and returns how many it
+// ran.
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	if w.DB == nil {
+		return 0, errors.New("db is required")
+	}
+	lease := w.Lease
+	if lease <= 0 {
+		lease = defaultLease
+	}
+	ran := 0
+	for {
+		j, ok, err := claimImport(ctx, w.DB, lease)
+		if err != nil || !ok {
+			return ran, err
+		}
+		if err := w.run(ctx, j); err != nil {
+			return ran, err
+		}
+		ran++
+	}
+}
+
+// run validates a claimed import and, unless it is a dry run, creates
+// everything in one transaction: either every row is imported or none.
+func (w *Worker) run(ctx context.Context, j job) error {
+	var mapping map[string]string
+	if err := json.Unmarshal(j.Mapping, &mapping); err != nil {
+		return finishImport(ctx, w.DB, j, StatusFailed, nil, "invalid mapping")
+	}
+	records, err := parse(j.Format, j.Source, mapping)
+	if err != nil {
+		return finishImport(ctx, w.DB, j, StatusFailed, nil, err.Error())
+	}
+
+	var (
+		report  Report
+		failure string
+	)
+	err = pgutil.WithTx(ctx, w.DB, nil, "begin tx", "commit import", func(tx *sqlx.Tx) error {
+		if err := lockImport(ctx, tx, j); err != nil {
+			return err
+		}
+		p, err := loadProject(ctx, tx, j.ProjectID)
+		if err != nil {
+			return err
+		}
+		p.userID = func(email string) (string, error) {
+			return memberID(ctx, tx, j.ProjectID, email)
+		}
+		pl, err := buildPlan(records, p, j.CreatedBy)
+		if err != nil {
+			return err
+		}
+		report = pl.report
+		// apply fills in pl.report; report stays as planned for a failure.
+		report.Results = append([]RowResult(nil), pl.report.Results...)
+		switch {
+		case j.DryRun:
+			return finishImport(ctx, tx, j, StatusPreviewed, &report, "")
+		case report.RowsWithErrors > 0:
+			failure = fmt.Sprintf("%d of %d rows have errors; nothing was imported", report.RowsWithErrors, report.Rows)
+			return finishImport(ctx, tx, j, StatusFailed, &report, failure)
+		}
+		if err := apply(ctx, tx, j, p, pl); err != nil {
+			var rowErr rowError
+			if errors.As(err, &rowErr) {
+				failure = err.Error()
+			}
+			return err
+		}
+		return finishImport(ctx, tx, j, StatusCompleted, &pl.report, "")
+	})
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, errClaimLost) {
+		slog.Warn("import claimed by another worker", "import_id", j.ID)
+		return nil
+	}
+	if failure == "" {
+		if ctx.Err() != nil {
+			return err
+		}
+		slog.Error("import failed", "import_id", j.ID, "error", err)
+		failure = "internal error"
+	}
+	return finishImport(ctx, w.DB, j, StatusFailed, &report, failure)
+}
+
+// rowError is a row that the database rejected although it passed the
+// plan, such as one missing a required custom field.
+type rowError struct {
+	row int
+	err error
+}
+
+func (e rowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.row, e.err)
+}
+
+// apply creates the statuses, issue types and issues of a plan and records
+// the created issues in its report.
+func apply(ctx context.Context, tx *sqlx.Tx, j job, p project, pl plan) error {
+	statusIDs := map[string]string{}
+	for _, s := range p.statuses {
+		statusIDs[s.Name] = s.ID
+	}
+	for _, s := range pl.statuses {
+		created, err := statuses.CreateTx(ctx, tx, statuses.CreateParams{
+			ProjectID: j.ProjectID,
+			Name:      s.Name,
+			Category:  s.Category,
+		})
+		if err != nil {
+			return fmt.Errorf("create status %q: %w", s.Name, err)
+		}
+		statusIDs[s.Name] = created.ID
+	}
+	typeIDs := map[string]string{}
+	for _, t := range p.types {
+		typeIDs[t.Name] = t.ID
+	}
+	for _, t := range pl.types {
+		created, err := issuetypes.CreateTx(ctx, tx, issuetypes.CreateParams{
+			ProjectID: j.ProjectID,
+			Name:      t.Name,
+			Level:     t.Level,
+		})
+		if err != nil {
+			return fmt.Errorf("create issue type %q: %w", t.Name, err)
+		}
+		typeIDs[t.Name] = created.ID
+	}
+
+	issueIDs := map[string]string{}
+	for _, it := range pl.items {
+		parentID := it.ParentID
+		if it.ParentKey != "" {
+			parentID = issueIDs[it.ParentKey]
+		}
+		issue, err := issues.CreateTx(ctx, tx, issues.CreateParams{
+			ProjectID:     j.ProjectID,
+			IssueTypeID:   typeIDs[it.Type],
+			StatusID:      statusIDs[it.Status],
+			ParentIssueID: parentID,
+			Title:         it.Title,
+			Description:   it.Description,
+			Priority:      it.Priority,
+			AssigneeID:    it.AssigneeID,
+			ReporterID:    it.ReporterID,
+			DueDate:       it.DueDate,
+			ExternalRef:   it.Key,
+		})
+		if err != nil {
+			return rowError{row: pl.report.Results[it.result].Row, err: err}
+		}
+		if it.Key != "" {
+			issueIDs[it.Key] = issue.ID
+		}
+		res := &pl.report.Results[it.result]
+		res.IssueID, res.IssueNumber = issue.ID, issue.Number
+	}
+	return nil
+}
@@ -14,8 +14,10 @@ import (
 	"strconv"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/audit"
 	"github.com/start-codex/tookly/internal/auth"
 	"github.com/start-codex/tookly/internal/email"
+	"github.com/start-codex/tookly/internal/pgutil"
 	"github.com/start-codex/tookly/internal/sessions"
 )
 
@@ -180,7 +182,16 @@ func ResolveBaseURL(ctx context.Context, db *sqlx.DB, r *http.Request) string {
 	return fmt.Sprintf("%s://%s", proto, r.Host)
 }
 
+// SaveSMTPConfig stores config and records the change in the audit log with
+// passwords masked.
 func SaveSMTPConfig(ctx context.Context, db *sqlx.DB, config email.SMTPConfig) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	before, err := LoadSMTPConfig(ctx, db)
+	if err != nil && !errors.Is(err, email.ErrSMTPNotConfigured) {
+		return err
+	}
 	keys := map[string]string{
 		"smtp_host":     config.Host,
 		"smtp_port":     strconv.Itoa(config.Port),
@@ -188,10 +199,29 @@ func SaveSMTPConfig(ctx context.Context, db *sqlx.DB, config email.SMTPConfig) e
 		"smtp_username": config.Username,
 		"smtp_password": config.Password,
 	}
-	for k, v := range keys {
-		if err := SetConfig(ctx, db, k, v); err != nil {
-			return err
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit smtp config", func(tx *sqlx.Tx) error {
+		for k, v := range keys {
+			if err := setConfig(ctx, tx, k, v); err != nil {
+				return err
+			}
 		}
+		return audit.Record(ctx, tx, audit.Change{
+			Action:     audit.ActionSMTPUpdated,
+			TargetType: audit.TargetInstanceConfig,
+			TargetID:   "smtp",
+			Before:     maskSMTP(before),
+			After:      maskSMTP(&config),
+		})
+	})
+}
+
+func maskSMTP(c *email.SMTPConfig) any {
+	if c == nil {
+		return nil
+	}
+	masked := *c
+	if masked.Password != "" {
+		masked.Password = maskedPassword
 	}
-	return nil
+	return masked
 }
@@ -28,7 +28,7 @@ func getConfig(ctx context.Context, db *sqlx.DB, key string) (string, error) {
 	return value, nil
 }
 
-func setConfig(ctx context.Context, db *sqlx.DB, key, value string) error {
+func setConfig(ctx context.Context, db sqlx.ExecerContext, key, value string) error {
 	_, err := db.ExecContext(ctx,
 		`INSERT INTO instance_config (key, value, updated_at)
 		 VALUES ($1, $2, NOW())
@@ -13,6 +13,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/start-codex/tookly/internal/sessions"
+	"github.com/start-codex/tookly/internal/webhooks"
 )
 
 const TTL = 7 * 24 * time.Hour
@@ -137,17 +138,37 @@ func Accept(ctx context.Context, db *sqlx.DB, rawToken, userID string) error {
 	}
 	defer tx.Rollback()
 
-	// Add workspace member via tx
-	_, err = tx.ExecContext(ctx,
+	// Add workspace member via tx; member.added only fires for a new row
+	res, err := tx.ExecContext(ctx,
 		`INSERT INTO workspace_members (workspace_id, user_id, role)
 		 VALUES ($1, $2, $3)
-		 ON CONFLICT (workspace_id, user_id)
-		 DO UPDATE SET role = excluded.role, archived_at = NULL`,
+		 ON CONFLICT (workspace_id, user_id) DO NOTHING`,
 		inv.WorkspaceID, userID, inv.Role,
 	)
 	if err != nil {
 		return fmt.Errorf("add member: %w", err)
 	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("add member rows affected: %w", err)
+	}
+	if inserted == 0 {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE workspace_members SET role = $3, archived_at = NULL
+			 WHERE workspace_id = $1 AND user_id = $2`,
+			inv.WorkspaceID, userID, inv.Role,
+		)
+		if err != nil {
+			return fmt.Errorf("update member: %w", err)
+		}
+	} else if err := webhooks.Enqueue(ctx, tx, webhooks.Event{
+		WorkspaceID: inv.WorkspaceID,
+		Type:        webhooks.EventMemberAdded,
+		ActorID:     userID,
+		Data:        webhooks.MemberData{WorkspaceID: inv.WorkspaceID, UserID: userID, Role: inv.Role},
+	}); err != nil {
+		return err
+	}
 
 	// Mark invitation as accepted
 	_, err = tx.ExecContext(ctx,
@@ -8,10 +8,12 @@ package invitations
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/audit"
 	"github.com/start-codex/tookly/internal/pgutil"
 )
 
@@ -79,18 +81,29 @@ func listPending(ctx context.Context, db *sqlx.DB, workspaceID string) ([]Invita
 }
 
 func revokeInvitation(ctx context.Context, db *sqlx.DB, id string) error {
-	res, err := db.ExecContext(ctx,
-		`UPDATE invitations SET status = 'revoked' WHERE id = $1 AND status = 'pending'`,
-		id,
-	)
-	if err != nil {
-		return fmt.Errorf("revoke invitation: %w", err)
-	}
-	n, _ := res.RowsAffected()
-	if n == 0 {
-		return ErrNotFound
-	}
-	return nil
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit revoke invitation", func(tx *sqlx.Tx) error {
+		var inv Invitation
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE invitations SET status = 'revoked' WHERE id = $1 AND status = 'pending'
+			 RETURNING `+invCols,
+			id,
+		).StructScan(&inv); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("revoke invitation: %w", err)
+		}
+		before := inv
+		before.Status = "pending"
+		return audit.Record(ctx, tx, audit.Change{
+			WorkspaceID: inv.WorkspaceID,
+			Action:      audit.ActionInvitationRevoked,
+			TargetType:  audit.TargetInvitation,
+			TargetID:    inv.ID,
+			Before:      before,
+			After:       inv,
+		})
+	})
 }
 
 func acceptInvitation(ctx context.Context, db *sqlx.DB, tokenHash string) error {
@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package issuelinks manages typed links between issues of the same
+// workspace: blocks, relates to, duplicates and clones.
+package issuelinks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrNotFound            = errors.New("issue link not found")
+	ErrIssueNotFound       = errors.New("issue not found")
+	ErrTargetNotFound      = errors.New("target issue not found in workspace")
+	ErrInvalidType         = errors.New("type must be one of blocks, is_blocked_by, relates_to, duplicates, is_duplicated_by, clones, is_cloned_by")
+	ErrSelfLink            = errors.New("an issue cannot be linked to itself")
+	ErrDuplicate           = errors.New("issues are already linked with this type")
+	ErrCycle               = errors.New("link would create a cycle of blocking issues")
+	ErrDifferentWorkspaces = errors.New("linked issues must belong to the same workspace")
+)
+
+// Link types as seen from the issue they are listed on. Only blocks,
+// relates_to, duplicates and clones are stored; the is_* types are the same
+// rows read from the other end.
+const (
+	TypeBlocks         = "blocks"
+	TypeIsBlockedBy    = "is_blocked_by"
+	TypeRelatesTo      = "relates_to"
+	TypeDuplicates     = "duplicates"
+	TypeIsDuplicatedBy = "is_duplicated_by"
+	TypeClones         = "clones"
+	TypeIsClonedBy     = "is_cloned_by"
+)
+
+// inverses maps each link type to the type seen from the linked issue.
+var inverses = map[string]string{
+	TypeBlocks:         TypeIsBlockedBy,
+	TypeIsBlockedBy:    TypeBlocks,
+	TypeRelatesTo:      TypeRelatesTo,
+	TypeDuplicates:     TypeIsDuplicatedBy,
+	TypeIsDuplicatedBy: TypeDuplicates,
+	TypeClones:         TypeIsClonedBy,
+	TypeIsClonedBy:     TypeClones,
+}
+
+// storedTypes are the link types kept in issue_links.link_type.
+var storedTypes = map[string]bool{
+	TypeBlocks: true, TypeRelatesTo: true, TypeDuplicates: true, TypeClones: true,
+}
+
+// LinkedIssue summarises the issue at the other end of a link.
+type LinkedIssue struct {
+	ID             string     `json:"id"`
+	ProjectID      string     `json:"project_id"`
+	Key            string     `json:"key"`
+	Title          string     `json:"title"`
+	StatusID       string     `json:"status_id"`
+	StatusCategory string     `json:"status_category"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`
+}
+
+// Link is an issue link as seen from IssueID. Type is directional: an issue
+// that blocks another lists the link as "blocks", the other as "is_blocked_by".
+type Link struct {
+	ID          string      `json:"id"`
+	IssueID     string      `json:"issue_id"`
+	Type        string      `json:"type"`
+	LinkedIssue LinkedIssue `json:"linked_issue"`
+	CreatedBy   string      `json:"created_by"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type CreateParams struct {
+	ProjectID     string
+	IssueID       string
+	TargetIssueID string
+	Type          string
+	ActorID       string
+}
+
+func (params CreateParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.IssueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if params.TargetIssueID == "" {
+		return errors.New("target_issue_id is required")
+	}
+	if params.ActorID == "" {
+		return errors.New("actor_id is required")
+	}
+	if _, ok := inverses[params.Type]; !ok {
+		return ErrInvalidType
+	}
+	if params.IssueID == params.TargetIssueID {
+		return ErrSelfLink
+	}
+	return nil
+}
+
+// Create links an active issue to another active issue in the same workspace.
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Link, error) {
+	if db == nil {
+		return Link{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Link{}, err
+	}
+	return createLink(ctx, db, params)
+}
+
+// List returns every link of an issue in both directions, oldest first.
+func List(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Link, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" {
+		return nil, errors.New("project_id is required")
+	}
+	if issueID == "" {
+		return nil, errors.New("issue_id is required")
+	}
+	return listLinks(ctx, db, projectID, issueID)
+}
+
+// Delete removes a link from either of its ends.
+func Delete(ctx context.Context, db *sqlx.DB, projectID, issueID, linkID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if projectID == "" {
+		return errors.New("project_id is required")
+	}
+	if issueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if linkID == "" {
+		return errors.New("link_id is required")
+	}
+	return deleteLink(ctx, db, projectID, issueID, linkID)
+}
+
+// storedLink returns the row orientation for a link of typ from issueID to
+// targetID: inverse types are stored from the target's side, and the
+// symmetric relates_to from the lower issue ID, so the UNIQUE constraint
+// holds it once per pair.
+func storedLink(issueID, targetID, typ string) (source, target, stored string) {
+	if typ == TypeRelatesTo && strings.ToLower(targetID) < strings.ToLower(issueID) {
+		return targetID, issueID, typ
+	}
+	if storedTypes[typ] {
+		return issueID, targetID, typ
+	}
+	return targetID, issueID, inverses[typ]
+}
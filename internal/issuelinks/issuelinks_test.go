@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package issuelinks
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCreateLinkParams_Validate(t *testing.T) {
+	valid := CreateParams{ProjectID: "p", IssueID: "a", TargetIssueID: "b", Type: TypeBlocks, ActorID: "u"}
+
+	tests := []struct {
+		name    string
+		params  CreateParams
+		wantErr bool
+	}{
+		{name: "valid", params: valid, wantErr: false},
+		{name: "inverse type", params: func() CreateParams { c := valid; c.Type = TypeIsBlockedBy; return c }(), wantErr: false},
+		{name: "missing project_id", params: func() CreateParams { c := valid; c.ProjectID = ""; return c }(), wantErr: true},
+		{name: "missing issue_id", params: func() CreateParams { c := valid; c.IssueID = ""; return c }(), wantErr: true},
+		{name: "missing target_issue_id", params: func() CreateParams { c := valid; c.TargetIssueID = ""; return c }(), wantErr: true},
+		{name: "missing actor_id", params: func() CreateParams { c := valid; c.ActorID = ""; return c }(), wantErr: true},
+		{name: "unknown type", params: func() CreateParams { c := valid; c.Type = "depends_on"; return c }(), wantErr: true},
+		{name: "self link", params: func() CreateParams { c := valid; c.TargetIssueID = "a"; return c }(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStoredLink(t *testing.T) {
+	tests := []struct {
+		typ                        string
+		wantSource, wantTarget, st string
+	}{
+		{typ: TypeBlocks, wantSource: "a", wantTarget: "b", st: TypeBlocks},
+		{typ: TypeIsBlockedBy, wantSource: "b", wantTarget: "a", st: TypeBlocks},
+		{typ: TypeRelatesTo, wantSource: "a", wantTarget: "b", st: TypeRelatesTo},
+		{typ: TypeIsDuplicatedBy, wantSource: "b", wantTarget: "a", st: TypeDuplicates},
+		{typ: TypeIsClonedBy, wantSource: "b", wantTarget: "a", st: TypeClones},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			source, target, stored := storedLink("a", "b", tt.typ)
+			if source != tt.wantSource || target != tt.wantTarget || stored != tt.st {
+				t.Fatalf("storedLink() = (%s, %s, %s), want (%s, %s, %s)", source, target, stored, tt.wantSource, tt.wantTarget, tt.st)
+			}
+		})
+	}
+
+	// relates_to is stored from the lower ID whichever side creates it.
+	for _, ends := range [][2]string{{"a", "b"}, {"b", "a"}, {"A", "b"}, {"b", "A"}} {
+		source, target, _ := storedLink(ends[0], ends[1], TypeRelatesTo)
+		if !strings.EqualFold(source, "a") || target != "b" {
+			t.Fatalf("storedLink(%s, %s, relates_to) = (%s, %s), want a first", ends[0], ends[1], source, target)
+		}
+	}
+}
+
+func TestLinkRow_Link(t *testing.T) {
+	row := linkRow{ID: "l", LinkType: TypeBlocks, OtherID: "b", ProjectKey: "PRJ", Number: 12}
+
+	row.Outward = true
+	if got := row.link("a"); got.Type != TypeBlocks || got.LinkedIssue.Key != "PRJ-12" || got.IssueID != "a" {
+		t.Fatalf("outward link = %+v", got)
+	}
+	row.Outward = false
+	if got := row.link("a"); got.Type != TypeIsBlockedBy {
+		t.Fatalf("inward link type = %q, want %q", got.Type, TypeIsBlockedBy)
+	}
+}
+
+func TestCreateLink_NilDB(t *testing.T) {
+	_, err := Create(context.Background(), nil, CreateParams{ProjectID: "p", IssueID: "a", TargetIssueID: "b", Type: TypeBlocks, ActorID: "u"})
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("Create() error = %v, want %q", err, "db is required")
+	}
+}
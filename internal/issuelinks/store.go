@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package issuelinks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+// SQLSTATEs raised by the validate_issue_link trigger.
+const (
+	codeDifferentWorkspaces = "TK001"
+	codeCycle               = "TK002"
+)
+
+// linkRow is a link joined with the issue at its other end, as seen from $1.
+type linkRow struct {
+	ID             string     `db:"id"`
+	LinkType       string     `db:"link_type"`
+	Outward        bool       `db:"outward"`
+	CreatedBy      string     `db:"created_by"`
+	CreatedAt      time.Time  `db:"created_at"`
+	OtherID        string     `db:"other_id"`
+	OtherProjectID string     `db:"other_project_id"`
+	ProjectKey     string     `db:"project_key"`
+	Number         int        `db:"number"`
+	Title          string     `db:"title"`
+	StatusID       string     `db:"status_id"`
+	StatusCategory string     `db:"status_category"`
+	ArchivedAt     *time.Time `db:"archived_at"`
+}
+
+// linkSelect reads links touching issue $1 with the opposite issue joined as o.
+const linkSelect = `SELECT l.id, l.link_type, l.source_issue_id = $1 AS outward,
+	l.created_by, l.created_at,
+	o.id AS other_id, o.project_id AS other_project_id, p.key AS project_key,
+	o.number, o.title, o.status_id, s.category AS status_category, o.archived_at
+	FROM issue_links l
+	JOIN issues o ON o.id = CASE WHEN l.source_issue_id = $1 THEN l.target_issue_id ELSE l.source_issue_id END
+	JOIN projects p ON p.id = o.project_id
+	JOIN statuses s ON s.id = o.status_id`
+
+func (r linkRow) link(issueID string) Link {
+	typ := r.LinkType
+	if !r.Outward {
+		typ = inverses[typ]
+	}
+	return Link{
+		ID:      r.ID,
+		IssueID: issueID,
+		Type:    typ,
+		LinkedIssue: LinkedIssue{
+			ID:             r.OtherID,
+			ProjectID:      r.OtherProjectID,
+			Key:            fmt.Sprintf("%s-%d", r.ProjectKey, r.Number),
+			Title:          r.Title,
+			StatusID:       r.StatusID,
+			StatusCategory: r.StatusCategory,
+			ArchivedAt:     r.ArchivedAt,
+		},
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func createLink(ctx context.Context, db *sqlx.DB, params CreateParams) (Link, error) {
+	var link Link
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create issue link", func(tx *sqlx.Tx) error {
+		var workspaceID string
+		if err := tx.GetContext(ctx, &workspaceID,
+			`SELECT p.workspace_id
+			 FROM issues i
+			 JOIN projects p ON p.id = i.project_id
+			 WHERE i.id = $1
+			   AND i.project_id = $2
+			   AND i.archived_at IS NULL
+			 FOR SHARE OF i`,
+			params.IssueID, params.ProjectID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrIssueNotFound
+			}
+			return fmt.Errorf("lock issue: %w", err)
+		}
+
+		var targetID string
+		if err := tx.GetContext(ctx, &targetID,
+			`SELECT i.id
+			 FROM issues i
+			 JOIN projects p ON p.id = i.project_id
+			 WHERE i.id = $1
+			   AND p.workspace_id = $2
+			   AND i.archived_at IS NULL
+			 FOR SHARE OF i`,
+			params.TargetIssueID, workspaceID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrTargetNotFound
+			}
+			return fmt.Errorf("lock target issue: %w", err)
+		}
+
+		source, target, stored := storedLink(params.IssueID, targetID, params.Type)
+		var id string
+		if err := tx.GetContext(ctx, &id,
+			`INSERT INTO issue_links (source_issue_id, target_issue_id, link_type, created_by)
+			 VALUES ($1, $2, $3, $4)
+			 RETURNING id`,
+			source, target, stored, params.ActorID,
+		); err != nil {
+			switch {
+			case pgutil.IsUniqueViolation(err):
+				return ErrDuplicate
+			case pgutil.HasCode(err, codeCycle):
+				return ErrCycle
+			case pgutil.HasCode(err, codeDifferentWorkspaces):
+				return ErrDifferentWorkspaces
+			}
+			return fmt.Errorf("insert issue link: %w", err)
+		}
+
+		var row linkRow
+		if err := tx.GetContext(ctx, &row, linkSelect+` WHERE l.id = $2`, params.IssueID, id); err != nil {
+			return fmt.Errorf("load created issue link: %w", err)
+		}
+		link = row.link(params.IssueID)
+		return nil
+	}); err != nil {
+		return Link{}, err
+	}
+	return link, nil
+}
+
+func listLinks(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Link, error) {
+	var exists bool
+	if err := db.GetContext(ctx, &exists,
+		`SELECT EXISTS(SELECT 1 FROM issues WHERE id = $1 AND project_id = $2)`,
+		issueID, projectID,
+	); err != nil {
+		return nil, fmt.Errorf("check issue: %w", err)
+	}
+	if !exists {
+		return nil, ErrIssueNotFound
+	}
+
+	rows := []linkRow{}
+	if err := db.SelectContext(ctx, &rows,
+		linkSelect+`
+		 WHERE l.source_issue_id = $1 OR l.target_issue_id = $1
+		 ORDER BY l.created_at ASC, l.id ASC`,
+		issueID,
+	); err != nil {
+		return nil, fmt.Errorf("list issue links: %w", err)
+	}
+	links := make([]Link, len(rows))
+	for i, r := range rows {
+		links[i] = r.link(issueID)
+	}
+	return links, nil
+}
+
+func deleteLink(ctx context.Context, db *sqlx.DB, projectID, issueID, linkID string) error {
+	res, err := db.ExecContext(ctx,
+		`DELETE FROM issue_links l
+		 USING issues i
+		 WHERE i.id = $2
+		   AND i.project_id = $3
+		   AND l.id = $1
+		   AND (l.source_issue_id = i.id OR l.target_issue_id = i.id)`,
+		linkID, issueID, projectID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete issue link: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete issue link rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
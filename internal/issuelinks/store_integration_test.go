@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package issuelinks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+type projectFixture struct {
+	projectID string
+	typeID    string
+	statusID  string
+	userID    string
+	next      int
+}
+
+func seedProject(t *testing.T, db *sqlx.DB, wsID, key, userID string) *projectFixture {
+	t.Helper()
+	ctx := context.Background()
+	fx := &projectFixture{projectID: testpg.SeedProject(t, db, wsID, key), userID: userID}
+	if err := db.GetContext(ctx, &fx.typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, fx.projectID); err != nil {
+		t.Fatalf("insert issue_type: %v", err)
+	}
+	if err := db.GetContext(ctx, &fx.statusID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, fx.projectID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	return fx
+}
+
+func (fx *projectFixture) issue(t *testing.T, db *sqlx.DB) string {
+	t.Helper()
+	fx.next++
+	var id string
+	if err := db.GetContext(context.Background(), &id,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, $2, $3, $4, 'Issue', '', 'medium', $5, $6) RETURNING id`,
+		fx.projectID, fx.next, fx.typeID, fx.statusID, fx.userID, fx.next-1,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+	return id
+}
+
+func TestIssueLinks(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	p1 := seedProject(t, db, wsID, "LNA", userID)
+	p2 := seedProject(t, db, wsID, "LNB", userID)
+	a, b := p1.issue(t, db), p1.issue(t, db)
+	c := p2.issue(t, db)
+
+	link := func(projectID, issueID, target, typ string) (Link, error) {
+		return Create(ctx, db, CreateParams{ProjectID: projectID, IssueID: issueID, TargetIssueID: target, Type: typ, ActorID: userID})
+	}
+
+	ab, err := link(p1.projectID, a, b, TypeBlocks)
+	if err != nil {
+		t.Fatalf("a blocks b: %v", err)
+	}
+	if ab.Type != TypeBlocks || ab.LinkedIssue.ID != b || ab.LinkedIssue.Key != "LNA-2" {
+		t.Fatalf("created link = %+v", ab)
+	}
+
+	// Cross-project, expressed from the blocked side.
+	if _, err := link(p2.projectID, c, b, TypeIsBlockedBy); err != nil {
+		t.Fatalf("c is blocked by b: %v", err)
+	}
+
+	if _, err := link(p2.projectID, c, a, TypeBlocks); !errors.Is(err, ErrCycle) {
+		t.Fatalf("c blocks a: error = %v, want ErrCycle", err)
+	}
+	if _, err := link(p1.projectID, a, b, TypeBlocks); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("duplicate link: error = %v, want ErrDuplicate", err)
+	}
+	if _, err := link(p1.projectID, a, c, TypeRelatesTo); err != nil {
+		t.Fatalf("a relates to c: %v", err)
+	}
+	if _, err := link(p2.projectID, c, a, TypeRelatesTo); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("reverse relates_to: error = %v, want ErrDuplicate", err)
+	}
+
+	links, err := List(ctx, db, p1.projectID, b)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	got := map[string]string{}
+	for _, l := range links {
+		got[l.LinkedIssue.ID] = l.Type
+	}
+	if len(links) != 2 || got[a] != TypeIsBlockedBy || got[c] != TypeBlocks {
+		t.Fatalf("links of b = %+v", links)
+	}
+
+	if err := Delete(ctx, db, p1.projectID, b, ab.ID); err != nil {
+		t.Fatalf("delete from target side: %v", err)
+	}
+	if err := Delete(ctx, db, p1.projectID, b, ab.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("delete twice: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCreateLink_OtherWorkspace(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	p1 := seedProject(t, db, testpg.SeedWorkspace(t, db), "LWA", userID)
+	p2 := seedProject(t, db, testpg.SeedWorkspace(t, db), "LWB", userID)
+	a, b := p1.issue(t, db), p2.issue(t, db)
+
+	_, err := Create(ctx, db, CreateParams{ProjectID: p1.projectID, IssueID: a, TargetIssueID: b, Type: TypeRelatesTo, ActorID: userID})
+	if !errors.Is(err, ErrTargetNotFound) {
+		t.Fatalf("Create() error = %v, want ErrTargetNotFound", err)
+	}
+}
@@ -0,0 +1,447 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package issues
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/labels"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/statuses"
+)
+
+// Bulk operations.
+const (
+	BulkSetAssignee = "set_assignee"
+	BulkSetPriority = "set_priority"
+	BulkSetStatus   = "set_status"
+	BulkSetParent   = "set_parent"
+	BulkSetSprint   = "set_sprint"
+	BulkArchive     = "archive"
+	BulkAddLabel    = "add_label"
+	BulkRemoveLabel = "remove_label"
+)
+
+// Per-issue outcomes of a bulk operation. Issues that succeeded in a batch
+// that was then rolled back report BulkRolledBack.
+const (
+	BulkUpdated    = "updated"
+	BulkUnchanged  = "unchanged"
+	BulkFailed     = "failed"
+	BulkRolledBack = "rolled_back"
+)
+
+const maxBulkIssues = 100
+
+var (
+	ErrBulkFailed       = errors.New("bulk operation failed for some issues; no issues were changed")
+	ErrInvalidOperation = errors.New("operation must be one of set_assignee, set_priority, set_status, set_parent, set_sprint, archive, add_label, remove_label")
+	ErrStatusNotFound   = errors.New("status not found in project")
+	ErrSprintNotFound   = errors.New("sprint not found in project")
+	ErrSprintClosed     = errors.New("sprint is closed")
+	ErrInvalidParent    = errors.New("parent must be another active issue of the project and not one of its sub-issues")
+)
+
+var validBulkOperations = map[string]bool{
+	BulkSetAssignee: true, BulkSetPriority: true, BulkSetStatus: true,
+	BulkSetParent: true, BulkSetSprint: true, BulkArchive: true,
+	BulkAddLabel: true, BulkRemoveLabel: true,
+}
+
+// BulkParams applies one operation to up to 100 issues of a project.
+// AssigneeID, ParentIssueID and SprintID are the values set by their
+// operations; nil unassigns, clears the parent or sends issues to the
+// backlog. set_status appends the issues to the target status in the order
+// given. add_label and remove_label take LabelID, which must be available
+// to the project. Unless BestEffort is set, a failure on any issue rolls back the
+// whole batch.
+type BulkParams struct {
+	ProjectID       string
+	ActorID         string
+	ActorIsAdmin    bool
+	IssueIDs        []string
+	Operation       string
+	AssigneeID      *string
+	Priority        string
+	StatusID        string
+	ParentIssueID   *string
+	SprintID        *string
+	LabelID         string
+	RejectIfBlocked bool
+	BestEffort      bool
+}
+
+func (params BulkParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.ActorID == "" {
+		return errors.New("actor_id is required")
+	}
+	if len(params.IssueIDs) == 0 {
+		return errors.New("issue_ids is required")
+	}
+	if len(params.IssueIDs) > maxBulkIssues {
+		return errors.New("issue_ids must not contain more than 100 issues")
+	}
+	seen := make(map[string]bool, len(params.IssueIDs))
+	for _, id := range params.IssueIDs {
+		if id == "" {
+			return errors.New("issue_ids must not contain empty values")
+		}
+		if seen[id] {
+			return errors.New("issue_ids must not contain duplicates")
+		}
+		seen[id] = true
+	}
+	if !validBulkOperations[params.Operation] {
+		return ErrInvalidOperation
+	}
+	switch params.Operation {
+	case BulkSetPriority:
+		if !validPriorities[params.Priority] {
+			return ErrInvalidPriority
+		}
+	case BulkSetStatus:
+		if params.StatusID == "" {
+			return errors.New("status_id is required")
+		}
+	case BulkAddLabel, BulkRemoveLabel:
+		if params.LabelID == "" {
+			return errors.New("label_id is required")
+		}
+	}
+	return nil
+}
+
+// BulkResult is the outcome for one issue of a bulk operation.
+type BulkResult struct {
+	IssueID string `json:"issue_id"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Bulk applies params.Operation to every listed issue in one transaction.
+// Problems with an individual issue, such as a missing issue or a workflow
+// violation, are reported in its result; without BestEffort they also roll
+// back the batch and Bulk returns ErrBulkFailed alongside the results.
+func Bulk(ctx context.Context, db *sqlx.DB, params BulkParams) ([]BulkResult, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return bulkUpdate(ctx, db, params)
+}
+
+func bulkUpdate(ctx context.Context, db *sqlx.DB, params BulkParams) ([]BulkResult, error) {
+	results := make([]BulkResult, len(params.IssueIDs))
+	for i, id := range params.IssueIDs {
+		results[i].IssueID = id
+	}
+	err := pgutil.WithTx(ctx, db, &sql.TxOptions{Isolation: sql.LevelReadCommitted}, "begin tx", "commit bulk update", func(tx *sqlx.Tx) error {
+		if err := prepareBulk(ctx, tx, params); err != nil {
+			return err
+		}
+		failed := false
+		for i, id := range params.IssueIDs {
+			if _, err := tx.ExecContext(ctx, `SAVEPOINT bulk_issue`); err != nil {
+				return fmt.Errorf("bulk savepoint: %w", err)
+			}
+			changed, err := applyBulk(ctx, tx, params, id)
+			if err != nil {
+				if !isBulkIssueError(err) {
+					return err
+				}
+				if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT bulk_issue`); err != nil {
+					return fmt.Errorf("bulk rollback to savepoint: %w", err)
+				}
+				results[i].Status, results[i].Error = BulkFailed, err.Error()
+				failed = true
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT bulk_issue`); err != nil {
+				return fmt.Errorf("bulk release savepoint: %w", err)
+			}
+			results[i].Status = BulkUnchanged
+			if changed {
+				results[i].Status = BulkUpdated
+			}
+		}
+		if failed && !params.BestEffort {
+			for i := range results {
+				if results[i].Status != BulkFailed {
+					results[i].Status = BulkRolledBack
+				}
+			}
+			return ErrBulkFailed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrBulkFailed) {
+		return nil, err
+	}
+	return results, err
+}
+
+// isBulkIssueError reports whether err concerns a single issue of the
+// batch rather than the request as a whole.
+func isBulkIssueError(err error) bool {
+	return errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrBlocked) ||
+		errors.Is(err, ErrInvalidParent) ||
+		errors.Is(err, statuses.ErrTransitionNotAllowed)
+}
+
+// prepareBulk checks the target of the operation and takes the row locks
+// up front, in id order, so a batch cannot deadlock with single-issue
+// writes. Status changes lock statuses before issues, as moveIssue does.
+func prepareBulk(ctx context.Context, tx *sqlx.Tx, params BulkParams) error {
+	switch params.Operation {
+	case BulkSetStatus:
+		var exists bool
+		if err := tx.GetContext(ctx, &exists,
+			`SELECT EXISTS(SELECT 1 FROM statuses WHERE id = $1 AND project_id = $2 AND archived_at IS NULL)`,
+			params.StatusID, params.ProjectID,
+		); err != nil {
+			return fmt.Errorf("check bulk status: %w", err)
+		}
+		if !exists {
+			return ErrStatusNotFound
+		}
+		var locked []string
+		if err := tx.SelectContext(ctx, &locked,
+			`SELECT id
+			 FROM statuses
+			 WHERE project_id = $1
+			   AND (id = $2 OR id IN (SELECT status_id FROM issues WHERE id = ANY($3) AND project_id = $1))
+			 ORDER BY id
+			 FOR UPDATE`,
+			params.ProjectID, params.StatusID, pq.Array(params.IssueIDs),
+		); err != nil {
+			return fmt.Errorf("lock bulk statuses: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`SELECT id
+			 FROM issues
+			 WHERE project_id = $1
+			   AND archived_at IS NULL
+			   AND status_id = ANY($2)
+			 ORDER BY id
+			 FOR UPDATE`,
+			params.ProjectID, pq.Array(locked),
+		); err != nil {
+			return fmt.Errorf("lock bulk status issues: %w", err)
+		}
+		return nil
+	case BulkSetSprint:
+		if params.SprintID != nil {
+			var state string
+			if err := tx.GetContext(ctx, &state,
+				`SELECT s.state
+				 FROM sprints s
+				 JOIN boards b ON b.id = s.board_id
+				 WHERE s.id = $1
+				   AND b.project_id = $2
+				 FOR SHARE OF s`,
+				*params.SprintID, params.ProjectID,
+			); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return ErrSprintNotFound
+				}
+				return fmt.Errorf("lock bulk sprint: %w", err)
+			}
+			if state == "closed" {
+				return ErrSprintClosed
+			}
+		}
+	case BulkAddLabel, BulkRemoveLabel:
+		var exists bool
+		if err := tx.GetContext(ctx, &exists,
+			`SELECT EXISTS(
+				SELECT 1
+				FROM labels l
+				JOIN projects p ON p.id = $2
+				WHERE l.id = $1
+				  AND l.workspace_id = p.workspace_id
+				  AND (l.project_id IS NULL OR l.project_id = p.id))`,
+			params.LabelID, params.ProjectID,
+		); err != nil {
+			return fmt.Errorf("check bulk label: %w", err)
+		}
+		if !exists {
+			return labels.ErrInvalidLabel
+		}
+	case BulkSetParent:
+		if params.ParentIssueID != nil {
+			var exists bool
+			if err := tx.GetContext(ctx, &exists,
+				`SELECT EXISTS(SELECT 1 FROM issues WHERE id = $1 AND project_id = $2 AND archived_at IS NULL)`,
+				*params.ParentIssueID, params.ProjectID,
+			); err != nil {
+				return fmt.Errorf("check bulk parent: %w", err)
+			}
+			if !exists {
+				return ErrInvalidParent
+			}
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`SELECT id
+		 FROM issues
+		 WHERE id = ANY($1)
+		   AND project_id = $2
+		 ORDER BY id
+		 FOR UPDATE`,
+		pq.Array(params.IssueIDs), params.ProjectID,
+	); err != nil {
+		return fmt.Errorf("lock bulk issues: %w", err)
+	}
+	return nil
+}
+
+// applyBulk applies the operation to one issue and reports whether it
+// changed.
+func applyBulk(ctx context.Context, tx *sqlx.Tx, params BulkParams, issueID string) (bool, error) {
+	switch params.Operation {
+	case BulkSetStatus:
+		current, err := getIssuePositionForUpdate(ctx, tx, params.ProjectID, issueID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return false, ErrNotFound
+			}
+			return false, err
+		}
+		if current.StatusID == params.StatusID {
+			return false, nil
+		}
+		return moveInTx(ctx, tx, MoveParams{
+			ProjectID:       params.ProjectID,
+			IssueID:         issueID,
+			ActorID:         params.ActorID,
+			TargetStatusID:  params.StatusID,
+			TargetPosition:  math.MaxInt32,
+			RejectIfBlocked: params.RejectIfBlocked,
+			ActorIsAdmin:    params.ActorIsAdmin,
+		})
+	case BulkArchive:
+		if err := archiveInTx(ctx, tx, params.ProjectID, issueID, params.ActorID); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	before, err := getIssueForUpdate(ctx, tx, params.ProjectID, issueID)
+	if err != nil {
+		return false, err
+	}
+	if params.Operation == BulkAddLabel || params.Operation == BulkRemoveLabel {
+		return applyBulkLabel(ctx, tx, params, before)
+	}
+	var column string
+	var from, to any
+	switch params.Operation {
+	case BulkSetAssignee:
+		column, from, to = "assignee_id", stringOrNil(before.AssigneeID), stringOrNil(params.AssigneeID)
+	case BulkSetPriority:
+		column, from, to = "priority", before.Priority, params.Priority
+	case BulkSetParent:
+		column, from, to = "parent_issue_id", stringOrNil(before.ParentIssueID), stringOrNil(params.ParentIssueID)
+		if params.ParentIssueID != nil && from != to {
+			if err := checkParent(ctx, tx, issueID, *params.ParentIssueID); err != nil {
+				return false, err
+			}
+		}
+	case BulkSetSprint:
+		column, from, to = "sprint_id", stringOrNil(before.SprintID), stringOrNil(params.SprintID)
+	}
+	if from == to {
+		return false, nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE issues
+		 SET `+column+` = $1,
+		     version = version + 1
+		 WHERE id = $2
+		   AND project_id = $3`,
+		to, issueID, params.ProjectID,
+	); err != nil {
+		return false, fmt.Errorf("bulk update issue: %w", err)
+	}
+	if err := insertEvent(ctx, tx, issueID, params.ActorID, EventUpdated, map[string]FieldChange{
+		column: {From: from, To: to},
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// applyBulkLabel adds or removes params.LabelID on issue.
+func applyBulkLabel(ctx context.Context, tx *sqlx.Tx, params BulkParams, issue Issue) (bool, error) {
+	current, err := labels.Load(ctx, tx, []string{issue.ID})
+	if err != nil {
+		return false, err
+	}
+	var ids []string
+	for _, label := range current[issue.ID] {
+		if label.ID != params.LabelID {
+			ids = append(ids, label.ID)
+		}
+	}
+	if params.Operation == BulkAddLabel {
+		ids = append(ids, params.LabelID)
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	changes := map[string]FieldChange{}
+	if err := setLabels(ctx, tx, issue, ids, changes); err != nil {
+		return false, err
+	}
+	if len(changes) == 0 {
+		return false, nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE issues SET version = version + 1 WHERE id = $1 AND project_id = $2`,
+		issue.ID, params.ProjectID,
+	); err != nil {
+		return false, fmt.Errorf("bulk update issue: %w", err)
+	}
+	if err := insertEvent(ctx, tx, issue.ID, params.ActorID, EventUpdated, changes); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkParent rejects parentID as the parent of issueID when it is the
+// issue itself or one of its sub-issues.
+func checkParent(ctx context.Context, tx *sqlx.Tx, issueID, parentID string) error {
+	var cycle bool
+	if err := tx.GetContext(ctx, &cycle,
+		`WITH RECURSIVE ancestors AS (
+			SELECT id, parent_issue_id FROM issues WHERE id = $1
+			UNION
+			SELECT i.id, i.parent_issue_id
+			FROM issues i
+			JOIN ancestors a ON i.id = a.parent_issue_id
+		)
+		SELECT EXISTS(SELECT 1 FROM ancestors WHERE id = $2)`,
+		parentID, issueID,
+	); err != nil {
+		return fmt.Errorf("check parent: %w", err)
+	}
+	if cycle {
+		return ErrInvalidParent
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package issues
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultSort = "position"
+
+// sortKey is one column of a list ordering. value extracts the key from an
+// issue as text; cast is the SQL type the text is compared as.
+type sortKey struct {
+	expr  string
+	cast  string
+	value func(Issue) string
+}
+
+var numberKey = sortKey{
+	expr:  "number",
+	cast:  "int",
+	value: func(i Issue) string { return strconv.Itoa(i.Number) },
+}
+
+// idKey ends the ordering when issues of several projects are listed.
+var idKey = sortKey{
+	expr:  "id",
+	cast:  "uuid",
+	value: func(i Issue) string { return i.ID },
+}
+
+// sortKeys maps each sort name to its ordering columns. Every ordering ends
+// with number, which is unique per project, so keyset pagination is stable.
+var sortKeys = map[string][]sortKey{
+	"position": {
+		{expr: "status_id", cast: "uuid", value: func(i Issue) string { return i.StatusID }},
+		{expr: "status_position", cast: "int", value: func(i Issue) string { return strconv.Itoa(i.StatusPosition) }},
+		numberKey,
+	},
+	"number": {numberKey},
+	"priority": {
+		{
+			expr:  "CASE priority WHEN 'low' THEN 0 WHEN 'medium' THEN 1 WHEN 'high' THEN 2 ELSE 3 END",
+			cast:  "int",
+			value: func(i Issue) string { return strconv.Itoa(priorityRank[i.Priority]) },
+		},
+		numberKey,
+	},
+	"due_date": {
+		{
+			expr: "COALESCE(due_date, 'infinity'::date)",
+			cast: "date",
+			value: func(i Issue) string {
+				if i.DueDate == nil {
+					return "infinity"
+				}
+				return i.DueDate.Format("2006-01-02")
+			},
+		},
+		numberKey,
+	},
+	"created_at": {
+		{expr: "created_at", cast: "timestamptz", value: func(i Issue) string { return i.CreatedAt.Format(time.RFC3339Nano) }},
+		numberKey,
+	},
+	"updated_at": {
+		{expr: "updated_at", cast: "timestamptz", value: func(i Issue) string { return i.UpdatedAt.Format(time.RFC3339Nano) }},
+		numberKey,
+	},
+}
+
+var priorityRank = map[string]int{"low": 0, "medium": 1, "high": 2, "critical": 3}
+
+type sortSpec struct {
+	name string
+	keys []sortKey
+	desc bool
+}
+
+// lookupSort resolves a sort parameter such as "-created_at". An empty
+// sort selects board order.
+func lookupSort(sort string) (sortSpec, bool) {
+	if sort == "" {
+		sort = defaultSort
+	}
+	name, desc := strings.CutPrefix(sort, "-")
+	keys, ok := sortKeys[name]
+	if !ok {
+		return sortSpec{}, false
+	}
+	return sortSpec{name: sort, keys: keys, desc: desc}, true
+}
+
+// cursor is the decoded form of an opaque page cursor: the sort it was
+// issued for and the sort key values of the last issue on the page.
+type cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+func encodeCursor(spec sortSpec, last Issue) string {
+	c := cursor{Sort: spec.name, Values: make([]string, len(spec.keys))}
+	for i, k := range spec.keys {
+		c.Values[i] = k.value(last)
+	}
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(spec sortSpec, s string) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != spec.name || len(c.Values) != len(spec.keys) {
+		return nil, ErrInvalidCursor
+	}
+	return c.Values, nil
+}
+
+// orderBy renders the ORDER BY clause for spec.
+func (spec sortSpec) orderBy() string {
+	dir := "ASC"
+	if spec.desc {
+		dir = "DESC"
+	}
+	cols := make([]string, len(spec.keys))
+	for i, k := range spec.keys {
+		cols[i] = k.expr + " " + dir
+	}
+	return strings.Join(cols, ", ")
+}
+
+// after renders a row comparison selecting the issues that sort after the
+// cursor values, appending the values to args.
+func (spec sortSpec) after(values []string, args []any) (string, []any) {
+	cols := make([]string, len(spec.keys))
+	params := make([]string, len(spec.keys))
+	for i, k := range spec.keys {
+		args = append(args, values[i])
+		cols[i] = k.expr
+		params[i] = fmt.Sprintf("$%d::%s", len(args), k.cast)
+	}
+	op := ">"
+	if spec.desc {
+		op = "<"
+	}
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(cols, ", "), op, strings.Join(params, ", ")), args
+}
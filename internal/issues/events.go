@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	EventCreated   = "created"
+	EventUpdated   = "updated"
+	EventMoved     = "moved"
+	EventArchived  = "archived"
+	EventCommented = "commented"
+)
+
+const (
+	defaultEventLimit = 50
+	maxEventLimit     = 200
+)
+
+// Event is a single entry in an issue's activity history.
+type Event struct {
+	ID          string          `db:"id"           json:"id"`
+	IssueID     string          `db:"issue_id"     json:"issue_id"`
+	ActorID     string          `db:"actor_id"     json:"actor_id"`
+	ActorName   string          `db:"actor_name"   json:"actor_name"`
+	EventType   string          `db:"event_type"   json:"event_type"`
+	PayloadJSON []byte          `db:"payload_json" json:"-"`
+	Payload     json.RawMessage `db:"-"            json:"payload"`
+	CreatedAt   time.Time       `db:"created_at"   json:"created_at"`
+}
+
+// WebhookData is the data of an issue.<event> webhook delivery. Issue and Key
+// are filled in by PublishEvent.
+type WebhookData struct {
+	Issue   Issue                  `json:"issue"`
+	Key     string                 `json:"key"`
+	Changes map[string]FieldChange `json:"changes,omitempty"`
+	Comment any                    `json:"comment,omitempty"`
+}
+
+// PublishEvent queues the issue.<eventType> webhook and the watcher
+// notifications for an issue event recorded outside this package, inside
+// the caller's transaction.
+func PublishEvent(ctx context.Context, tx *sqlx.Tx, issueID, actorID, eventType string, data WebhookData) error {
+	if tx == nil {
+		return errors.New("tx is required")
+	}
+	if issueID == "" || eventType == "" {
+		return errors.New("issue_id and event_type are required")
+	}
+	issue, err := loadEventIssue(ctx, tx, issueID)
+	if err != nil {
+		return err
+	}
+	if err := enqueueWebhook(ctx, tx, issue, actorID, eventType, data); err != nil {
+		return err
+	}
+	return notifyWatchers(ctx, tx, issue, actorID, eventType, notificationData{Changes: data.Changes, Comment: data.Comment})
+}
+
+// RecordEventTx records an issue event changed outside this package, inside
+// the caller's transaction, and publishes it like the package's own changes:
+// the issue_events row, the issue.<eventType> webhook, watcher notifications
+// and the board stream event.
+func RecordEventTx(ctx context.Context, tx *sqlx.Tx, issueID, actorID, eventType string, changes map[string]FieldChange) error {
+	if tx == nil {
+		return errors.New("tx is required")
+	}
+	if issueID == "" || eventType == "" {
+		return errors.New("issue_id and event_type are required")
+	}
+	return insertEvent(ctx, tx, issueID, actorID, eventType, changes)
+}
+
+// BoardEventData is the data of a board stream event. Positions lists every
+// active issue of the statuses the change touched, in order.
+type BoardEventData struct {
+	Issue     Issue                  `json:"issue"`
+	Key       string                 `json:"key"`
+	Changes   map[string]FieldChange `json:"changes,omitempty"`
+	Positions []BoardPosition        `json:"positions"`
+}
+
+// BoardPosition is the place of one issue on the board after a change.
+type BoardPosition struct {
+	IssueID        string `db:"id"              json:"issue_id"`
+	StatusID       string `db:"status_id"       json:"status_id"`
+	StatusPosition int    `db:"status_position" json:"status_position"`
+}
+
+// FieldChange records the value of a single field before and after a mutation.
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// EventPayload is the JSON body stored in issue_events.payload_json.
+type EventPayload struct {
+	Changes map[string]FieldChange `json:"changes"`
+}
+
+type ListEventsParams struct {
+	ProjectID string
+	IssueID   string
+	Limit     int
+	Offset    int
+}
+
+func (params ListEventsParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.IssueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if params.Limit < 0 {
+		return errors.New("limit must be >= 0")
+	}
+	if params.Limit > maxEventLimit {
+		return errors.New("limit must be <= 200")
+	}
+	if params.Offset < 0 {
+		return errors.New("offset must be >= 0")
+	}
+	return nil
+}
+
+// ListEvents returns the activity history of an issue, newest first.
+func ListEvents(ctx context.Context, db *sqlx.DB, params ListEventsParams) ([]Event, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultEventLimit
+	}
+	return listEvents(ctx, db, params)
+}
+
+// diffIssues returns the tracked fields whose values differ between before and after.
+func diffIssues(before, after Issue) map[string]FieldChange {
+	changes := map[string]FieldChange{}
+	add := func(field string, from, to any) {
+		if from != to {
+			changes[field] = FieldChange{From: from, To: to}
+		}
+	}
+	add("title", before.Title, after.Title)
+	add("description", before.Description, after.Description)
+	add("priority", before.Priority, after.Priority)
+	add("issue_type_id", before.IssueTypeID, after.IssueTypeID)
+	add("status_id", before.StatusID, after.StatusID)
+	add("status_position", before.StatusPosition, after.StatusPosition)
+	add("parent_issue_id", stringOrNil(before.ParentIssueID), stringOrNil(after.ParentIssueID))
+	add("assignee_id", stringOrNil(before.AssigneeID), stringOrNil(after.AssigneeID))
+	add("due_date", dateOrNil(before.DueDate), dateOrNil(after.DueDate))
+	add("original_estimate_minutes", intOrNil(before.OriginalEstimate), intOrNil(after.OriginalEstimate))
+	add("remaining_estimate_minutes", intOrNil(before.RemainingEstimate), intOrNil(after.RemainingEstimate))
+	return changes
+}
+
+func stringOrNil(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func intOrNil(n *int) any {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+func dateOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format("2006-01-02")
+}
@@ -6,14 +6,22 @@
 package issues
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/customfields"
+	"github.com/start-codex/tookly/internal/labels"
 	"github.com/start-codex/tookly/internal/respond"
+	"github.com/start-codex/tookly/internal/statuses"
 )
 
 func parseDueDate(s *string) (*time.Time, error) {
@@ -32,12 +40,19 @@ func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
 	mux.HandleFunc("GET /projects/{projectID}/issues", handleList(db))
 	mux.HandleFunc("GET /projects/{projectID}/issues/{issueID}", handleGet(db))
 	mux.HandleFunc("PUT /projects/{projectID}/issues/{issueID}", handleUpdate(db))
+	mux.HandleFunc("PATCH /projects/{projectID}/issues/{issueID}", handlePatch(db))
 	mux.HandleFunc("DELETE /projects/{projectID}/issues/{issueID}", handleArchive(db))
 	mux.HandleFunc("POST /projects/{projectID}/issues/{issueID}/move", handleMove(db))
+	mux.HandleFunc("POST /projects/{projectID}/issues/bulk", handleBulk(db))
+	mux.HandleFunc("GET /projects/{projectID}/issues/{issueID}/activity", handleActivity(db))
 }
 
 func fail(w http.ResponseWriter, err error) {
+	var stale *StaleError
 	switch {
+	case errors.As(err, &stale):
+		w.Header().Set("ETag", ETag(stale.Current))
+		respond.ErrorWithData(w, http.StatusPreconditionFailed, err.Error(), stale.Current)
 	case errors.Is(err, authz.ErrUnauthenticated):
 		respond.Error(w, http.StatusUnauthorized, "authentication required")
 	case errors.Is(err, authz.ErrForbidden):
@@ -47,7 +62,21 @@ func fail(w http.ResponseWriter, err error) {
 		respond.Error(w, http.StatusNotFound, err.Error())
 	case errors.Is(err, ErrNotFound):
 		respond.Error(w, http.StatusNotFound, err.Error())
-	case errors.Is(err, ErrInvalidPriority):
+	case errors.Is(err, ErrBlocked),
+		errors.Is(err, ErrSprintClosed),
+		errors.Is(err, statuses.ErrTransitionNotAllowed):
+		respond.Error(w, http.StatusConflict, err.Error())
+	case errors.As(err, new(*customfields.ValueError)),
+		errors.Is(err, labels.ErrInvalidLabel):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, ErrInvalidPriority),
+		errors.Is(err, ErrInvalidOperation),
+		errors.Is(err, ErrStatusNotFound),
+		errors.Is(err, ErrSprintNotFound),
+		errors.Is(err, ErrInvalidParent),
+		errors.Is(err, ErrInvalidSort),
+		errors.Is(err, ErrInvalidStatusCategory),
+		errors.Is(err, ErrInvalidCursor):
 		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
 	default:
 		slog.Error("issues handler error", "error", err)
@@ -57,7 +86,7 @@ func fail(w http.ResponseWriter, err error) {
 
 func handleCreate(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleMember); err != nil {
 			fail(w, err)
 			return
 		}
@@ -67,14 +96,18 @@ func handleCreate(db *sqlx.DB) http.HandlerFunc {
 			return
 		}
 		var body struct {
-			IssueTypeID   string  `json:"issue_type_id"`
-			StatusID      string  `json:"status_id"`
-			ParentIssueID string  `json:"parent_issue_id"`
-			Title         string  `json:"title"`
-			Description   string  `json:"description"`
-			Priority      string  `json:"priority"`
-			AssigneeID    string  `json:"assignee_id"`
-			DueDate       *string `json:"due_date"`
+			IssueTypeID       string                     `json:"issue_type_id"`
+			StatusID          string                     `json:"status_id"`
+			ParentIssueID     string                     `json:"parent_issue_id"`
+			Title             string                     `json:"title"`
+			Description       string                     `json:"description"`
+			Priority          string                     `json:"priority"`
+			AssigneeID        string                     `json:"assignee_id"`
+			DueDate           *string                    `json:"due_date"`
+			OriginalEstimate  *int                       `json:"original_estimate_minutes"`
+			RemainingEstimate *int                       `json:"remaining_estimate_minutes"`
+			CustomFields      map[string]json.RawMessage `json:"custom_fields"`
+			LabelIDs          []string                   `json:"label_ids"`
 		}
 		if err := respond.Decode(r, &body); err != nil {
 			respond.Error(w, http.StatusBadRequest, "invalid JSON")
@@ -86,16 +119,20 @@ func handleCreate(db *sqlx.DB) http.HandlerFunc {
 			return
 		}
 		params := CreateParams{
-			ProjectID:     r.PathValue("projectID"),
-			IssueTypeID:   body.IssueTypeID,
-			StatusID:      body.StatusID,
-			ParentIssueID: body.ParentIssueID,
-			Title:         body.Title,
-			Description:   body.Description,
-			Priority:      body.Priority,
-			AssigneeID:    body.AssigneeID,
-			ReporterID:    authedUserID,
-			DueDate:       dueDate,
+			ProjectID:         r.PathValue("projectID"),
+			IssueTypeID:       body.IssueTypeID,
+			StatusID:          body.StatusID,
+			ParentIssueID:     body.ParentIssueID,
+			Title:             body.Title,
+			Description:       body.Description,
+			Priority:          body.Priority,
+			AssigneeID:        body.AssigneeID,
+			ReporterID:        authedUserID,
+			DueDate:           dueDate,
+			OriginalEstimate:  body.OriginalEstimate,
+			RemainingEstimate: body.RemainingEstimate,
+			CustomFields:      body.CustomFields,
+			LabelIDs:          body.LabelIDs,
 		}
 		if err := params.Validate(); err != nil {
 			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
@@ -116,17 +153,21 @@ func handleList(db *sqlx.DB) http.HandlerFunc {
 			fail(w, err)
 			return
 		}
-		q := r.URL.Query()
-		list, err := List(r.Context(), db, ListParams{
-			ProjectID:  r.PathValue("projectID"),
-			StatusID:   q.Get("status_id"),
-			AssigneeID: q.Get("assignee_id"),
-		})
+		params, err := parseListParams(r)
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		page, err := List(r.Context(), db, params)
 		if err != nil {
 			fail(w, err)
 			return
 		}
-		respond.JSON(w, http.StatusOK, list)
+		respond.Page(w, http.StatusOK, page.Issues, page.NextCursor, page.HasMore)
 	}
 }
 
@@ -141,22 +182,32 @@ func handleGet(db *sqlx.DB) http.HandlerFunc {
 			fail(w, err)
 			return
 		}
+		w.Header().Set("ETag", ETag(issue))
 		respond.JSON(w, http.StatusOK, issue)
 	}
 }
 
 func handleUpdate(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleMember); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
 			fail(w, err)
 			return
 		}
 		var body struct {
-			Title       string  `json:"title"`
-			Description string  `json:"description"`
-			Priority    string  `json:"priority"`
-			AssigneeID  *string `json:"assignee_id"`
-			DueDate     *string `json:"due_date"`
+			Title             string                     `json:"title"`
+			Description       string                     `json:"description"`
+			Priority          string                     `json:"priority"`
+			AssigneeID        *string                    `json:"assignee_id"`
+			DueDate           *string                    `json:"due_date"`
+			OriginalEstimate  *int                       `json:"original_estimate_minutes"`
+			RemainingEstimate *int                       `json:"remaining_estimate_minutes"`
+			CustomFields      map[string]json.RawMessage `json:"custom_fields"`
+			LabelIDs          []string                   `json:"label_ids"`
 		}
 		if err := respond.Decode(r, &body); err != nil {
 			respond.Error(w, http.StatusBadRequest, "invalid JSON")
@@ -168,13 +219,19 @@ func handleUpdate(db *sqlx.DB) http.HandlerFunc {
 			return
 		}
 		params := UpdateParams{
-			IssueID:     r.PathValue("issueID"),
-			ProjectID:   r.PathValue("projectID"),
-			Title:       body.Title,
-			Description: body.Description,
-			Priority:    body.Priority,
-			AssigneeID:  body.AssigneeID,
-			DueDate:     dueDate,
+			IssueID:           r.PathValue("issueID"),
+			ProjectID:         r.PathValue("projectID"),
+			ActorID:           authedUserID,
+			Title:             body.Title,
+			Description:       body.Description,
+			Priority:          body.Priority,
+			AssigneeID:        body.AssigneeID,
+			DueDate:           dueDate,
+			OriginalEstimate:  body.OriginalEstimate,
+			RemainingEstimate: body.RemainingEstimate,
+			CustomFields:      body.CustomFields,
+			LabelIDs:          body.LabelIDs,
+			IfMatch:           r.Header.Get("If-Match"),
 		}
 		if err := params.Validate(); err != nil {
 			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
@@ -185,17 +242,124 @@ func handleUpdate(db *sqlx.DB) http.HandlerFunc {
 			fail(w, err)
 			return
 		}
+		w.Header().Set("ETag", ETag(issue))
 		respond.JSON(w, http.StatusOK, issue)
 	}
 }
 
+// handlePatch updates only the members present in the body. For
+// assignee_id, due_date, the estimates and label_ids an explicit null
+// clears the field.
+func handlePatch(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleMember); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body map[string]json.RawMessage
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params, err := parsePatch(body)
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		params.IssueID = r.PathValue("issueID")
+		params.ProjectID = r.PathValue("projectID")
+		params.ActorID = authedUserID
+		params.IfMatch = r.Header.Get("If-Match")
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		issue, err := Patch(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		w.Header().Set("ETag", ETag(issue))
+		respond.JSON(w, http.StatusOK, issue)
+	}
+}
+
+// parsePatch maps a PATCH body onto PatchParams. Unknown members are
+// rejected so a typo does not silently turn into a no-op.
+func parsePatch(body map[string]json.RawMessage) (PatchParams, error) {
+	var params PatchParams
+	text := map[string]**string{"title": &params.Title, "description": &params.Description, "priority": &params.Priority}
+	for name, raw := range body {
+		var err error
+		if dst, ok := text[name]; ok {
+			var v string
+			if string(raw) == "null" || json.Unmarshal(raw, &v) != nil {
+				return PatchParams{}, fmt.Errorf("%s must be a string", name)
+			}
+			*dst = &v
+			continue
+		}
+		switch name {
+		case "assignee_id":
+			params.SetAssignee = true
+			if json.Unmarshal(raw, &params.AssigneeID) != nil {
+				err = errors.New("assignee_id must be a string or null")
+			}
+		case "due_date":
+			params.SetDueDate = true
+			var s *string
+			if json.Unmarshal(raw, &s) != nil {
+				err = errors.New("due_date must be a string or null")
+			} else {
+				params.DueDate, err = parseDueDate(s)
+			}
+		case "original_estimate_minutes":
+			params.SetOriginalEstimate = true
+			if json.Unmarshal(raw, &params.OriginalEstimate) != nil {
+				err = errors.New("original_estimate_minutes must be an integer or null")
+			}
+		case "remaining_estimate_minutes":
+			params.SetRemainingEstimate = true
+			if json.Unmarshal(raw, &params.RemainingEstimate) != nil {
+				err = errors.New("remaining_estimate_minutes must be an integer or null")
+			}
+		case "custom_fields":
+			if json.Unmarshal(raw, &params.CustomFields) != nil {
+				err = errors.New("custom_fields must be an object")
+			}
+		case "label_ids":
+			if json.Unmarshal(raw, &params.LabelIDs) != nil {
+				err = errors.New("label_ids must be an array of strings or null")
+			} else if params.LabelIDs == nil {
+				params.LabelIDs = []string{}
+			}
+		default:
+			err = fmt.Errorf("%s cannot be patched", name)
+		}
+		if err != nil {
+			return PatchParams{}, err
+		}
+	}
+	return params, nil
+}
+
 func handleArchive(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleMember); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
 			fail(w, err)
 			return
 		}
-		if err := Archive(r.Context(), db, r.PathValue("projectID"), r.PathValue("issueID")); err != nil {
+		if err := Archive(r.Context(), db, r.PathValue("projectID"), r.PathValue("issueID"), authedUserID); err != nil {
 			fail(w, err)
 			return
 		}
@@ -205,23 +369,36 @@ func handleArchive(db *sqlx.DB) http.HandlerFunc {
 
 func handleMove(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+		_, role, err := authz.ProjectRole(r.Context(), db, r.PathValue("projectID"))
+		if err == nil && !authz.HasRole(role, authz.RoleMember) {
+			err = authz.ErrForbidden
+		}
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
 			fail(w, err)
 			return
 		}
 		var body struct {
-			TargetStatusID string `json:"target_status_id"`
-			TargetPosition int    `json:"target_position"`
+			TargetStatusID  string `json:"target_status_id"`
+			TargetPosition  int    `json:"target_position"`
+			RejectIfBlocked bool   `json:"reject_if_blocked"`
 		}
 		if err := respond.Decode(r, &body); err != nil {
 			respond.Error(w, http.StatusBadRequest, "invalid JSON")
 			return
 		}
 		params := MoveParams{
-			ProjectID:      r.PathValue("projectID"),
-			IssueID:        r.PathValue("issueID"),
-			TargetStatusID: body.TargetStatusID,
-			TargetPosition: body.TargetPosition,
+			ProjectID:       r.PathValue("projectID"),
+			IssueID:         r.PathValue("issueID"),
+			ActorID:         authedUserID,
+			TargetStatusID:  body.TargetStatusID,
+			TargetPosition:  body.TargetPosition,
+			RejectIfBlocked: body.RejectIfBlocked,
+			ActorIsAdmin:    role == authz.RoleAdmin,
 		}
 		if err := params.Validate(); err != nil {
 			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
@@ -234,3 +411,195 @@ func handleMove(db *sqlx.DB) http.HandlerFunc {
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+// handleBulk answers 200 with one result per issue. A batch that was rolled
+// back answers 422 with the same results in data.
+func handleBulk(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, role, err := authz.ProjectRole(r.Context(), db, r.PathValue("projectID"))
+		if err == nil && !authz.HasRole(role, authz.RoleMember) {
+			err = authz.ErrForbidden
+		}
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body struct {
+			IssueIDs        []string `json:"issue_ids"`
+			Operation       string   `json:"operation"`
+			AssigneeID      *string  `json:"assignee_id"`
+			Priority        string   `json:"priority"`
+			StatusID        string   `json:"status_id"`
+			ParentIssueID   *string  `json:"parent_issue_id"`
+			SprintID        *string  `json:"sprint_id"`
+			LabelID         string   `json:"label_id"`
+			RejectIfBlocked bool     `json:"reject_if_blocked"`
+			BestEffort      bool     `json:"best_effort"`
+		}
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := BulkParams{
+			ProjectID:       r.PathValue("projectID"),
+			ActorID:         authedUserID,
+			ActorIsAdmin:    role == authz.RoleAdmin,
+			IssueIDs:        body.IssueIDs,
+			Operation:       body.Operation,
+			AssigneeID:      body.AssigneeID,
+			Priority:        body.Priority,
+			StatusID:        body.StatusID,
+			ParentIssueID:   body.ParentIssueID,
+			SprintID:        body.SprintID,
+			LabelID:         body.LabelID,
+			RejectIfBlocked: body.RejectIfBlocked,
+			BestEffort:      body.BestEffort,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		results, err := Bulk(r.Context(), db, params)
+		if errors.Is(err, ErrBulkFailed) {
+			respond.ErrorWithData(w, http.StatusUnprocessableEntity, err.Error(), results)
+			return
+		}
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, results)
+	}
+}
+
+func handleActivity(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+			fail(w, err)
+			return
+		}
+		q := r.URL.Query()
+		limit, err := parseIntParam(q.Get("limit"))
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, "limit must be an integer")
+			return
+		}
+		offset, err := parseIntParam(q.Get("offset"))
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, "offset must be an integer")
+			return
+		}
+		params := ListEventsParams{
+			ProjectID: r.PathValue("projectID"),
+			IssueID:   r.PathValue("issueID"),
+			Limit:     limit,
+			Offset:    offset,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if _, err := Get(r.Context(), db, params.ProjectID, params.IssueID); err != nil {
+			fail(w, err)
+			return
+		}
+		events, err := ListEvents(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, events)
+	}
+}
+
+func parseListParams(r *http.Request) (ListParams, error) {
+	params, err := ParseListQuery(r.URL.Query())
+	if err != nil {
+		return ListParams{}, err
+	}
+	params.ProjectID = r.PathValue("projectID")
+	return params, nil
+}
+
+// ParseListQuery reads the filter, sort and page parameters of the issue
+// list from a query string, leaving the project unset. Date bounds accept
+// YYYY-MM-DD or RFC 3339 timestamps; status_category and label may repeat.
+func ParseListQuery(q url.Values) (ListParams, error) {
+	params := ListParams{
+		StatusID:         q.Get("status_id"),
+		StatusCategories: q["status_category"],
+		AssigneeID:       q.Get("assignee_id"),
+		ReporterID:       q.Get("reporter_id"),
+		IssueTypeID:      q.Get("issue_type_id"),
+		ParentIssueID:    q.Get("parent_issue_id"),
+		Priority:         q.Get("priority"),
+		Sort:             q.Get("sort"),
+		Cursor:           q.Get("cursor"),
+	}
+	bounds := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"due_before", &params.DueBefore},
+		{"due_after", &params.DueAfter},
+		{"created_before", &params.CreatedBefore},
+		{"created_after", &params.CreatedAfter},
+		{"updated_before", &params.UpdatedBefore},
+		{"updated_after", &params.UpdatedAfter},
+	}
+	for _, b := range bounds {
+		t, err := parseTimeParam(q.Get(b.name))
+		if err != nil {
+			return ListParams{}, fmt.Errorf("%s must be YYYY-MM-DD or RFC 3339", b.name)
+		}
+		*b.dst = t
+	}
+	if s := q.Get("include_archived"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return ListParams{}, errors.New("include_archived must be a boolean")
+		}
+		params.IncludeArchived = v
+	}
+	limit, err := parseIntParam(q.Get("limit"))
+	if err != nil {
+		return ListParams{}, errors.New("limit must be an integer")
+	}
+	params.Limit = limit
+	for name, values := range q {
+		if key, ok := strings.CutPrefix(name, "cf."); ok {
+			if params.CustomFields == nil {
+				params.CustomFields = map[string]string{}
+			}
+			params.CustomFields[key] = values[0]
+		}
+	}
+	params.Labels = q["label"]
+	return params, nil
+}
+
+func parseTimeParam(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func parseIntParam(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
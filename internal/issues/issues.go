@@ -7,21 +7,45 @@ package issues
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/customfields"
+	"github.com/start-codex/tookly/internal/filterquery"
+	"github.com/start-codex/tookly/internal/labels"
 )
 
 var (
-	ErrNotFound        = errors.New("issue not found")
-	ErrInvalidPriority = errors.New("priority must be 'low', 'medium', 'high' or 'critical'")
+	ErrNotFound              = errors.New("issue not found")
+	ErrInvalidPriority       = errors.New("priority must be 'low', 'medium', 'high' or 'critical'")
+	ErrInvalidStatusCategory = errors.New("status_category must be 'todo', 'doing' or 'done'")
+	ErrInvalidSort           = errors.New("sort must be one of position, number, priority, due_date, created_at, updated_at, optionally prefixed with '-'")
+	ErrInvalidCursor         = errors.New("cursor is invalid or does not match sort")
+	ErrBlocked               = errors.New("issue is blocked by open issues")
+	ErrStale                 = errors.New("issue was modified since it was read")
+	// ErrDuplicateExternalRef rejects a second issue imported under the
+	// same external key into one project.
+	ErrDuplicateExternalRef = errors.New("an issue with this external reference already exists in the project")
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
 )
 
 var validPriorities = map[string]bool{
 	"low": true, "medium": true, "high": true, "critical": true,
 }
 
+var validStatusCategories = map[string]bool{
+	"todo": true, "doing": true, "done": true,
+}
+
 type Issue struct {
 	ID             string     `db:"id"              json:"id"`
 	ProjectID      string     `db:"project_id"      json:"project_id"`
@@ -29,6 +53,7 @@ type Issue struct {
 	IssueTypeID    string     `db:"issue_type_id"   json:"issue_type_id"`
 	StatusID       string     `db:"status_id"       json:"status_id"`
 	ParentIssueID  *string    `db:"parent_issue_id" json:"parent_issue_id,omitempty"`
+	SprintID       *string    `db:"sprint_id"       json:"sprint_id,omitempty"`
 	Title          string     `db:"title"           json:"title"`
 	Description    string     `db:"description"     json:"description"`
 	Priority       string     `db:"priority"        json:"priority"`
@@ -36,9 +61,28 @@ type Issue struct {
 	ReporterID     string     `db:"reporter_id"     json:"reporter_id"`
 	DueDate        *time.Time `db:"due_date"        json:"due_date,omitempty"`
 	StatusPosition int        `db:"status_position" json:"status_position"`
+	Version        int        `db:"version"         json:"version"`
 	CreatedAt      time.Time  `db:"created_at"      json:"created_at"`
 	UpdatedAt      time.Time  `db:"updated_at"      json:"updated_at"`
 	ArchivedAt     *time.Time `db:"archived_at"     json:"archived_at,omitempty"`
+
+	OriginalEstimate  *int    `db:"original_estimate_minutes"  json:"original_estimate_minutes,omitempty"`
+	RemainingEstimate *int    `db:"remaining_estimate_minutes" json:"remaining_estimate_minutes,omitempty"`
+	ExternalRef       *string `db:"external_ref"               json:"external_ref,omitempty"`
+
+	CustomFields customfields.Values `db:"-" json:"custom_fields,omitempty"`
+	Labels       []labels.Label      `db:"-" json:"labels"`
+	TimeTracking TimeTracking        `db:"-" json:"time_tracking"`
+}
+
+// TimeTracking sums the work logged on an issue and its estimates, in
+// minutes. The Total fields also count every active sub-issue below it,
+// at any depth, via parent_issue_id.
+type TimeTracking struct {
+	TimeSpent              int `db:"time_spent"               json:"time_spent_minutes"`
+	TotalOriginalEstimate  int `db:"total_original_estimate"  json:"total_original_estimate_minutes"`
+	TotalRemainingEstimate int `db:"total_remaining_estimate" json:"total_remaining_estimate_minutes"`
+	TotalTimeSpent         int `db:"total_time_spent"         json:"total_time_spent_minutes"`
 }
 
 type CreateParams struct {
@@ -52,6 +96,14 @@ type CreateParams struct {
 	AssigneeID    string
 	ReporterID    string
 	DueDate       *time.Time
+	// RemainingEstimate defaults to OriginalEstimate when only that is set.
+	OriginalEstimate  *int
+	RemainingEstimate *int
+	CustomFields      map[string]json.RawMessage
+	LabelIDs          []string
+	// ExternalRef is the key of the issue in the tracker it was imported
+	// from; it is unique per project.
+	ExternalRef string
 }
 
 func (params CreateParams) Validate() error {
@@ -77,17 +129,40 @@ func (params CreateParams) Validate() error {
 	if !validPriorities[priority] {
 		return ErrInvalidPriority
 	}
+	return validateEstimates(params.OriginalEstimate, params.RemainingEstimate)
+}
+
+func validateEstimates(original, remaining *int) error {
+	if original != nil && *original < 0 {
+		return errors.New("original_estimate_minutes must be >= 0")
+	}
+	if remaining != nil && *remaining < 0 {
+		return errors.New("remaining_estimate_minutes must be >= 0")
+	}
 	return nil
 }
 
+// UpdateParams replaces the editable fields of an issue. A non-empty
+// IfMatch is the If-Match header of the request; the update then fails with
+// a *StaleError unless it matches the ETag of the stored issue.
 type UpdateParams struct {
 	IssueID     string
 	ProjectID   string
+	ActorID     string
 	Title       string
 	Description string
 	Priority    string
 	AssigneeID  *string
 	DueDate     *time.Time
+	// OriginalEstimate and RemainingEstimate keep their stored value when nil.
+	OriginalEstimate  *int
+	RemainingEstimate *int
+	// CustomFields holds only the custom fields to change; JSON null clears one.
+	CustomFields map[string]json.RawMessage
+	// LabelIDs replaces the issue's labels; nil leaves them untouched and an
+	// empty slice removes them all.
+	LabelIDs []string
+	IfMatch  string
 }
 
 func (params UpdateParams) Validate() error {
@@ -97,19 +172,212 @@ func (params UpdateParams) Validate() error {
 	if params.ProjectID == "" {
 		return errors.New("project_id is required")
 	}
+	if params.ActorID == "" {
+		return errors.New("actor_id is required")
+	}
 	if params.Title == "" {
 		return errors.New("title is required")
 	}
 	if !validPriorities[params.Priority] {
 		return ErrInvalidPriority
 	}
-	return nil
+	return validateEstimates(params.OriginalEstimate, params.RemainingEstimate)
+}
+
+// PatchParams changes only the fields that are set. Nil pointers leave
+// Title, Description and Priority untouched; AssigneeID, DueDate and the
+// estimates are only written when their Set flag is true, so nil clears them.
+type PatchParams struct {
+	IssueID              string
+	ProjectID            string
+	ActorID              string
+	Title                *string
+	Description          *string
+	Priority             *string
+	SetAssignee          bool
+	AssigneeID           *string
+	SetDueDate           bool
+	DueDate              *time.Time
+	SetOriginalEstimate  bool
+	OriginalEstimate     *int
+	SetRemainingEstimate bool
+	RemainingEstimate    *int
+	CustomFields         map[string]json.RawMessage
+	LabelIDs             []string
+	IfMatch              string
+}
+
+func (params PatchParams) Validate() error {
+	if params.IssueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.ActorID == "" {
+		return errors.New("actor_id is required")
+	}
+	if params.Title != nil && *params.Title == "" {
+		return errors.New("title must not be empty")
+	}
+	if params.Priority != nil && !validPriorities[*params.Priority] {
+		return ErrInvalidPriority
+	}
+	return validateEstimates(params.OriginalEstimate, params.RemainingEstimate)
+}
+
+// apply returns the full update that patching issue amounts to.
+func (params PatchParams) apply(issue Issue) UpdateParams {
+	update := UpdateParams{
+		IssueID:           params.IssueID,
+		ProjectID:         params.ProjectID,
+		ActorID:           params.ActorID,
+		Title:             issue.Title,
+		Description:       issue.Description,
+		Priority:          issue.Priority,
+		AssigneeID:        issue.AssigneeID,
+		DueDate:           issue.DueDate,
+		OriginalEstimate:  issue.OriginalEstimate,
+		RemainingEstimate: issue.RemainingEstimate,
+		CustomFields:      params.CustomFields,
+		LabelIDs:          params.LabelIDs,
+		IfMatch:           params.IfMatch,
+	}
+	if params.Title != nil {
+		update.Title = *params.Title
+	}
+	if params.Description != nil {
+		update.Description = *params.Description
+	}
+	if params.Priority != nil {
+		update.Priority = *params.Priority
+	}
+	if params.SetAssignee {
+		update.AssigneeID = params.AssigneeID
+	}
+	if params.SetDueDate {
+		update.DueDate = params.DueDate
+	}
+	if params.SetOriginalEstimate {
+		update.OriginalEstimate = params.OriginalEstimate
+	}
+	if params.SetRemainingEstimate {
+		update.RemainingEstimate = params.RemainingEstimate
+	}
+	return update
 }
 
+// StaleError rejects a conditional write whose If-Match no longer matches.
+// Current is the stored issue the client should merge its change into.
+type StaleError struct {
+	Current Issue
+}
+
+func (e *StaleError) Error() string { return ErrStale.Error() }
+
+func (e *StaleError) Unwrap() error { return ErrStale }
+
+// ETag returns the strong entity tag of issue, derived from its version.
+// The version grows on every update, patch, move and archive.
+func ETag(issue Issue) string {
+	return `"` + strconv.Itoa(issue.Version) + `"`
+}
+
+// etagMatches evaluates an If-Match header against issue: "*" or any listed
+// strong tag equal to ETag(issue) matches. Weak tags never match.
+func etagMatches(ifMatch string, issue Issue) bool {
+	if strings.TrimSpace(ifMatch) == "*" {
+		return true
+	}
+	want := ETag(issue)
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(tag) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// ListParams filters, sorts and paginates a project's issues, or those of
+// several projects when ProjectIDs is set instead of ProjectID. Date bounds
+// are exclusive: DueBefore matches due_date < DueBefore, CreatedAfter
+// matches created_at > CreatedAfter, and so on. StatusCategories matches
+// issues whose status is in any of the given categories. CustomFields maps
+// field keys to values matched like cf.<key>:<value> in a board filter.
+// Labels holds label names, all of which an issue must carry. Cursor is the
+// NextCursor of a previous page requested with the same Sort.
 type ListParams struct {
-	ProjectID  string
-	StatusID   string
-	AssigneeID string
+	ProjectID        string
+	ProjectIDs       []string
+	StatusID         string
+	StatusCategories []string
+	AssigneeID       string
+	ReporterID       string
+	IssueTypeID      string
+	ParentIssueID    string
+	Priority         string
+	DueBefore        *time.Time
+	DueAfter         *time.Time
+	CreatedBefore    *time.Time
+	CreatedAfter     *time.Time
+	UpdatedBefore    *time.Time
+	UpdatedAfter     *time.Time
+	IncludeArchived  bool
+	CustomFields     map[string]string
+	Labels           []string
+	Sort             string
+	Cursor           string
+	Limit            int
+}
+
+func (params ListParams) Validate() error {
+	if params.ProjectID == "" && len(params.ProjectIDs) == 0 {
+		return errors.New("project_id is required")
+	}
+	if params.ProjectID != "" && len(params.ProjectIDs) > 0 {
+		return errors.New("set project_id or project_ids, not both")
+	}
+	return params.ValidateFilter()
+}
+
+// ValidateFilter is Validate without the project check, for filters that
+// are stored before the projects they run on are known.
+func (params ListParams) ValidateFilter() error {
+	for _, category := range params.StatusCategories {
+		if !validStatusCategories[category] {
+			return ErrInvalidStatusCategory
+		}
+	}
+	if params.Priority != "" && !validPriorities[params.Priority] {
+		return ErrInvalidPriority
+	}
+	if _, ok := lookupSort(params.Sort); !ok {
+		return ErrInvalidSort
+	}
+	for key, value := range params.CustomFields {
+		if _, err := filterquery.CustomFieldTerm(key, value); err != nil {
+			return err
+		}
+	}
+	for _, name := range params.Labels {
+		if _, err := filterquery.LabelTerm(name); err != nil {
+			return err
+		}
+	}
+	if params.Limit < 0 {
+		return errors.New("limit must be >= 0")
+	}
+	if params.Limit > maxListLimit {
+		return errors.New("limit must be <= 200")
+	}
+	return nil
+}
+
+// Page is one page of a List result. NextCursor is empty when HasMore is false.
+type Page struct {
+	Issues     []Issue
+	NextCursor string
+	HasMore    bool
 }
 
 func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Issue, error) {
@@ -125,6 +393,25 @@ func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Issue, error
 	return createIssue(ctx, db, params)
 }
 
+// CreateTx creates an issue inside the caller's transaction, recording the
+// same events as Create.
+func CreateTx(ctx context.Context, tx *sqlx.Tx, params CreateParams) (Issue, error) {
+	if tx == nil {
+		return Issue{}, errors.New("tx is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Issue{}, err
+	}
+	if params.Priority == "" {
+		params.Priority = "medium"
+	}
+	issue, err := createInTx(ctx, tx, params)
+	if err != nil {
+		return Issue{}, err
+	}
+	return withDetails(ctx, tx, issue)
+}
+
 func Get(ctx context.Context, db *sqlx.DB, projectID, issueID string) (Issue, error) {
 	if db == nil {
 		return Issue{}, errors.New("db is required")
@@ -138,16 +425,47 @@ func Get(ctx context.Context, db *sqlx.DB, projectID, issueID string) (Issue, er
 	return getIssue(ctx, db, projectID, issueID)
 }
 
-func List(ctx context.Context, db *sqlx.DB, params ListParams) ([]Issue, error) {
+func List(ctx context.Context, db *sqlx.DB, params ListParams) (Page, error) {
 	if db == nil {
-		return nil, errors.New("db is required")
+		return Page{}, errors.New("db is required")
 	}
-	if params.ProjectID == "" {
-		return nil, errors.New("project_id is required")
+	if err := params.Validate(); err != nil {
+		return Page{}, err
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultListLimit
 	}
 	return listIssues(ctx, db, params)
 }
 
+// CountBy groups.
+const (
+	CountByStatusCategory = "status_category"
+	CountByAssignee       = "assignee"
+)
+
+// Count is the number of issues in one group of a CountBy result. Key is
+// the status category or the assignee ID, empty for unassigned issues.
+type Count struct {
+	Key   string `db:"key"   json:"key"`
+	Count int    `db:"count" json:"count"`
+}
+
+// CountBy counts the issues matching the filters of params per group,
+// largest group first. Sort, Cursor and Limit are ignored.
+func CountBy(ctx context.Context, db *sqlx.DB, params ListParams, group string) ([]Count, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if _, ok := countExprs[group]; !ok {
+		return nil, fmt.Errorf("unknown count group %q", group)
+	}
+	return countIssues(ctx, db, params, group)
+}
+
 func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Issue, error) {
 	if db == nil {
 		return Issue{}, errors.New("db is required")
@@ -158,7 +476,18 @@ func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Issue, error
 	return updateIssue(ctx, db, params)
 }
 
-func Archive(ctx context.Context, db *sqlx.DB, projectID, issueID string) error {
+// Patch updates only the fields set in params.
+func Patch(ctx context.Context, db *sqlx.DB, params PatchParams) (Issue, error) {
+	if db == nil {
+		return Issue{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Issue{}, err
+	}
+	return patchIssue(ctx, db, params)
+}
+
+func Archive(ctx context.Context, db *sqlx.DB, projectID, issueID, actorID string) error {
 	if db == nil {
 		return errors.New("db is required")
 	}
@@ -168,20 +497,42 @@ func Archive(ctx context.Context, db *sqlx.DB, projectID, issueID string) error
 	if issueID == "" {
 		return errors.New("issue_id is required")
 	}
-	return archiveIssue(ctx, db, projectID, issueID)
+	if actorID == "" {
+		return errors.New("actor_id is required")
+	}
+	return archiveIssue(ctx, db, projectID, issueID, actorID)
 }
 
+// LoadDetails fills in the custom field values and labels of issues loaded
+// outside this package, such as board and sprint listings.
+func LoadDetails(ctx context.Context, db sqlx.QueryerContext, list []Issue) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	return attachDetails(ctx, db, list)
+}
+
+// MoveParams describes a move. With RejectIfBlocked set, moving the issue
+// into a 'done' category status fails with ErrBlocked while any issue that
+// blocks it is active and not done. Status changes must also follow the
+// project workflow; ActorIsAdmin unlocks admin-only transitions.
 type MoveParams struct {
-	ProjectID      string
-	IssueID        string
-	TargetStatusID string
-	TargetPosition int
+	ProjectID       string
+	IssueID         string
+	ActorID         string
+	TargetStatusID  string
+	TargetPosition  int
+	RejectIfBlocked bool
+	ActorIsAdmin    bool
 }
 
 func (params MoveParams) Validate() error {
 	if params.ProjectID == "" || params.IssueID == "" {
 		return errors.New("project_id and issue_id are required")
 	}
+	if params.ActorID == "" {
+		return errors.New("actor_id is required")
+	}
 	if params.TargetPosition < 0 {
 		return errors.New("target_position must be >= 0")
 	}
@@ -7,8 +7,14 @@ package issues
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/start-codex/tookly/internal/statuses"
 )
 
 func TestMoveIssueParams_Validate(t *testing.T) {
@@ -19,22 +25,27 @@ func TestMoveIssueParams_Validate(t *testing.T) {
 	}{
 		{
 			name:    "valid params",
-			params:  MoveParams{ProjectID: "proj-1", IssueID: "issue-1", TargetPosition: 0},
+			params:  MoveParams{ProjectID: "proj-1", IssueID: "issue-1", ActorID: "user-1", TargetPosition: 0},
 			wantErr: false,
 		},
 		{
 			name:    "missing project_id",
-			params:  MoveParams{ProjectID: "", IssueID: "issue-1", TargetPosition: 0},
+			params:  MoveParams{ProjectID: "", IssueID: "issue-1", ActorID: "user-1", TargetPosition: 0},
 			wantErr: true,
 		},
 		{
 			name:    "missing issue_id",
-			params:  MoveParams{ProjectID: "proj-1", IssueID: "", TargetPosition: 0},
+			params:  MoveParams{ProjectID: "proj-1", IssueID: "", ActorID: "user-1", TargetPosition: 0},
+			wantErr: true,
+		},
+		{
+			name:    "missing actor_id",
+			params:  MoveParams{ProjectID: "proj-1", IssueID: "issue-1", TargetPosition: 0},
 			wantErr: true,
 		},
 		{
 			name:    "negative target_position",
-			params:  MoveParams{ProjectID: "proj-1", IssueID: "issue-1", TargetPosition: -1},
+			params:  MoveParams{ProjectID: "proj-1", IssueID: "issue-1", ActorID: "user-1", TargetPosition: -1},
 			wantErr: true,
 		},
 	}
@@ -62,6 +73,7 @@ func TestMoveIssue_NilDB(t *testing.T) {
 
 func TestCreateIssueParams_Validate(t *testing.T) {
 	due := time.Now()
+	negative := -1
 	valid := CreateParams{
 		ProjectID:   "p",
 		IssueTypeID: "t",
@@ -85,6 +97,7 @@ func TestCreateIssueParams_Validate(t *testing.T) {
 		{name: "missing title", params: func() CreateParams { c := valid; c.Title = ""; return c }(), wantErr: true},
 		{name: "missing reporter_id", params: func() CreateParams { c := valid; c.ReporterID = ""; return c }(), wantErr: true},
 		{name: "invalid priority", params: func() CreateParams { c := valid; c.Priority = "urgent"; return c }(), wantErr: true},
+		{name: "negative estimate", params: func() CreateParams { c := valid; c.OriginalEstimate = &negative; return c }(), wantErr: true},
 	}
 
 	for _, tt := range tests {
@@ -101,6 +114,7 @@ func TestUpdateIssueParams_Validate(t *testing.T) {
 	valid := UpdateParams{
 		IssueID:   "i",
 		ProjectID: "p",
+		ActorID:   "u",
 		Title:     "Fix bug",
 		Priority:  "low",
 	}
@@ -113,6 +127,7 @@ func TestUpdateIssueParams_Validate(t *testing.T) {
 		{name: "valid", params: valid, wantErr: false},
 		{name: "missing issue_id", params: func() UpdateParams { c := valid; c.IssueID = ""; return c }(), wantErr: true},
 		{name: "missing project_id", params: func() UpdateParams { c := valid; c.ProjectID = ""; return c }(), wantErr: true},
+		{name: "missing actor_id", params: func() UpdateParams { c := valid; c.ActorID = ""; return c }(), wantErr: true},
 		{name: "missing title", params: func() UpdateParams { c := valid; c.Title = ""; return c }(), wantErr: true},
 		{name: "invalid priority", params: func() UpdateParams { c := valid; c.Priority = "asap"; return c }(), wantErr: true},
 		{name: "empty priority invalid", params: func() UpdateParams { c := valid; c.Priority = ""; return c }(), wantErr: true},
@@ -161,8 +176,412 @@ func TestUpdateIssue_NilDB(t *testing.T) {
 }
 
 func TestArchiveIssue_NilDB(t *testing.T) {
-	err := Archive(context.Background(), nil, "p", "i")
+	err := Archive(context.Background(), nil, "p", "i", "u")
 	if err == nil || err.Error() != "db is required" {
 		t.Fatalf("Archive() error = %v, want %q", err, "db is required")
 	}
 }
+
+func TestListEventsParams_Validate(t *testing.T) {
+	valid := ListEventsParams{ProjectID: "p", IssueID: "i", Limit: 20}
+
+	tests := []struct {
+		name    string
+		params  ListEventsParams
+		wantErr bool
+	}{
+		{name: "valid", params: valid, wantErr: false},
+		{name: "zero limit uses default", params: func() ListEventsParams { c := valid; c.Limit = 0; return c }(), wantErr: false},
+		{name: "missing project_id", params: func() ListEventsParams { c := valid; c.ProjectID = ""; return c }(), wantErr: true},
+		{name: "missing issue_id", params: func() ListEventsParams { c := valid; c.IssueID = ""; return c }(), wantErr: true},
+		{name: "negative limit", params: func() ListEventsParams { c := valid; c.Limit = -1; return c }(), wantErr: true},
+		{name: "limit too large", params: func() ListEventsParams { c := valid; c.Limit = 201; return c }(), wantErr: true},
+		{name: "negative offset", params: func() ListEventsParams { c := valid; c.Offset = -1; return c }(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListEvents_NilDB(t *testing.T) {
+	_, err := ListEvents(context.Background(), nil, ListEventsParams{ProjectID: "p", IssueID: "i"})
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("ListEvents() error = %v, want %q", err, "db is required")
+	}
+}
+
+func TestDiffIssues(t *testing.T) {
+	assignee := "user-1"
+	due := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	estimate := 60
+	before := Issue{Title: "Old", Priority: "low", StatusID: "s1"}
+	after := Issue{Title: "New", Priority: "low", StatusID: "s1", AssigneeID: &assignee, DueDate: &due, OriginalEstimate: &estimate}
+
+	changes := diffIssues(before, after)
+	if len(changes) != 4 {
+		t.Fatalf("diffIssues() = %v, want 4 changes", changes)
+	}
+	if c := changes["original_estimate_minutes"]; c.From != nil || c.To != 60 {
+		t.Errorf("original_estimate_minutes change = %+v", c)
+	}
+	if c := changes["title"]; c.From != "Old" || c.To != "New" {
+		t.Errorf("title change = %+v", c)
+	}
+	if c := changes["assignee_id"]; c.From != nil || c.To != "user-1" {
+		t.Errorf("assignee_id change = %+v", c)
+	}
+	if c := changes["due_date"]; c.From != nil || c.To != "2025-03-14" {
+		t.Errorf("due_date change = %+v", c)
+	}
+	if len(diffIssues(after, after)) != 0 {
+		t.Error("diffIssues() on identical issues should be empty")
+	}
+}
+
+func TestListParams_Validate(t *testing.T) {
+	valid := ListParams{ProjectID: "p"}
+
+	tests := []struct {
+		name    string
+		params  ListParams
+		wantErr error
+	}{
+		{name: "valid", params: valid},
+		{name: "descending sort", params: func() ListParams { c := valid; c.Sort = "-updated_at"; return c }()},
+		{name: "missing project_id", params: func() ListParams { c := valid; c.ProjectID = ""; return c }(), wantErr: errAny},
+		{name: "invalid priority", params: func() ListParams { c := valid; c.Priority = "urgent"; return c }(), wantErr: ErrInvalidPriority},
+		{name: "unknown sort", params: func() ListParams { c := valid; c.Sort = "title"; return c }(), wantErr: ErrInvalidSort},
+		{name: "negative limit", params: func() ListParams { c := valid; c.Limit = -1; return c }(), wantErr: errAny},
+		{name: "limit too large", params: func() ListParams { c := valid; c.Limit = 201; return c }(), wantErr: errAny},
+		{name: "labels", params: func() ListParams { c := valid; c.Labels = []string{"bug", "ui"}; return c }()},
+		{name: "blank label", params: func() ListParams { c := valid; c.Labels = []string{" "}; return c }(), wantErr: errAny},
+		{name: "several projects", params: ListParams{ProjectIDs: []string{"p", "q"}}},
+		{name: "project and projects", params: func() ListParams { c := valid; c.ProjectIDs = []string{"q"}; return c }(), wantErr: errAny},
+		{name: "status categories", params: func() ListParams { c := valid; c.StatusCategories = []string{"todo", "doing"}; return c }()},
+		{name: "invalid status category", params: func() ListParams { c := valid; c.StatusCategories = []string{"open"}; return c }(), wantErr: ErrInvalidStatusCategory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("Validate() error = %v, want nil", err)
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("Validate() error = nil, want error")
+			case tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+var errAny = errors.New("any error")
+
+func TestParseListQuery(t *testing.T) {
+	q := url.Values{
+		"status_category": {"todo", "doing"},
+		"priority":        {"critical"},
+		"due_before":      {"2025-03-10"},
+		"label":           {"bug"},
+		"cf.team":         {"core"},
+		"sort":            {"-updated_at"},
+	}
+	params, err := ParseListQuery(q)
+	if err != nil {
+		t.Fatalf("ParseListQuery() error = %v", err)
+	}
+	if params.ProjectID != "" || len(params.StatusCategories) != 2 || params.Priority != "critical" || params.Sort != "-updated_at" {
+		t.Fatalf("ParseListQuery() = %+v", params)
+	}
+	if params.DueBefore == nil || params.DueBefore.Format("2006-01-02") != "2025-03-10" {
+		t.Fatalf("DueBefore = %v, want 2025-03-10", params.DueBefore)
+	}
+	if len(params.Labels) != 1 || params.CustomFields["team"] != "core" {
+		t.Fatalf("labels %v, custom fields %v", params.Labels, params.CustomFields)
+	}
+	if _, err := ParseListQuery(url.Values{"created_after": {"yesterday"}}); err == nil {
+		t.Fatal("ParseListQuery() with a bad date: expected error")
+	}
+}
+
+func TestCountBy_NilDB(t *testing.T) {
+	if _, err := CountBy(context.Background(), nil, ListParams{ProjectID: "p"}, CountByAssignee); err == nil {
+		t.Fatal("expected error for nil db")
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	due := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	issue := Issue{
+		Number:         7,
+		StatusID:       "s1",
+		StatusPosition: 3,
+		Priority:       "high",
+		DueDate:        &due,
+		CreatedAt:      time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC),
+	}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{sort: "", want: []string{"s1", "3", "7"}},
+		{sort: "-number", want: []string{"7"}},
+		{sort: "priority", want: []string{"2", "7"}},
+		{sort: "due_date", want: []string{"2025-03-14", "7"}},
+		{sort: "-created_at", want: []string{"2025-01-02T03:04:05.000006Z", "7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			spec, ok := lookupSort(tt.sort)
+			if !ok {
+				t.Fatalf("lookupSort(%q) not found", tt.sort)
+			}
+			got, err := decodeCursor(spec, encodeCursor(spec, issue))
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("decodeCursor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	asc, _ := lookupSort("created_at")
+	desc, _ := lookupSort("-created_at")
+	fromDesc := encodeCursor(desc, Issue{Number: 1})
+
+	for name, in := range map[string]string{
+		"not base64":    "!!!",
+		"not json":      "bm90LWpzb24",
+		"sort mismatch": fromDesc,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodeCursor(asc, in); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("decodeCursor() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestSortSpec_After(t *testing.T) {
+	spec, _ := lookupSort("-updated_at")
+	cond, args := spec.after([]string{"2025-01-01T00:00:00Z", "4"}, []any{"p"})
+	want := "(updated_at, number) < ($2::timestamptz, $3::int)"
+	if cond != want {
+		t.Fatalf("after() = %q, want %q", cond, want)
+	}
+	if len(args) != 3 {
+		t.Fatalf("args = %v, want 3 values", args)
+	}
+	if got := spec.orderBy(); got != "updated_at DESC, number DESC" {
+		t.Fatalf("orderBy() = %q", got)
+	}
+}
+
+func TestPatchParams_Validate(t *testing.T) {
+	empty, bad, ok := "", "urgent", "high"
+	negative := -5
+	base := PatchParams{IssueID: "i", ProjectID: "p", ActorID: "u"}
+	tests := []struct {
+		name    string
+		mutate  func(*PatchParams)
+		wantErr bool
+	}{
+		{name: "no fields", mutate: func(*PatchParams) {}},
+		{name: "valid priority", mutate: func(p *PatchParams) { p.Priority = &ok }},
+		{name: "empty title", mutate: func(p *PatchParams) { p.Title = &empty }, wantErr: true},
+		{name: "invalid priority", mutate: func(p *PatchParams) { p.Priority = &bad }, wantErr: true},
+		{name: "clear estimate", mutate: func(p *PatchParams) { p.SetRemainingEstimate = true }},
+		{name: "negative estimate", mutate: func(p *PatchParams) { p.SetRemainingEstimate, p.RemainingEstimate = true, &negative }, wantErr: true},
+		{name: "missing actor", mutate: func(p *PatchParams) { p.ActorID = "" }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := base
+			tt.mutate(&params)
+			if err := params.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPatchParams_Apply(t *testing.T) {
+	assignee := "u2"
+	due := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	original, remaining := 480, 120
+	issue := Issue{Title: "Old", Description: "Keep", Priority: "low", AssigneeID: &assignee, DueDate: &due,
+		OriginalEstimate: &original, RemainingEstimate: &remaining}
+	title := "New"
+
+	got := PatchParams{Title: &title, SetAssignee: true, SetRemainingEstimate: true}.apply(issue)
+	if got.Title != "New" || got.Description != "Keep" || got.Priority != "low" {
+		t.Fatalf("apply() = %+v, want only the title changed", got)
+	}
+	if got.AssigneeID != nil {
+		t.Fatalf("AssigneeID = %v, want cleared", *got.AssigneeID)
+	}
+	if got.DueDate == nil || !got.DueDate.Equal(due) {
+		t.Fatalf("DueDate = %v, want kept", got.DueDate)
+	}
+	if got.OriginalEstimate == nil || *got.OriginalEstimate != 480 || got.RemainingEstimate != nil {
+		t.Fatalf("estimates = %v, %v, want 480 kept and remaining cleared", got.OriginalEstimate, got.RemainingEstimate)
+	}
+}
+
+func TestParsePatch(t *testing.T) {
+	decode := func(t *testing.T, body string) (PatchParams, error) {
+		t.Helper()
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(body), &raw); err != nil {
+			t.Fatal(err)
+		}
+		return parsePatch(raw)
+	}
+
+	params, err := decode(t, `{"title":"T","assignee_id":null,"due_date":"2025-05-01"}`)
+	if err != nil {
+		t.Fatalf("parsePatch: %v", err)
+	}
+	if params.Title == nil || *params.Title != "T" || params.Description != nil {
+		t.Fatalf("text fields = %+v", params)
+	}
+	if !params.SetAssignee || params.AssigneeID != nil {
+		t.Fatal("assignee_id null should clear the assignee")
+	}
+	if !params.SetDueDate || params.DueDate == nil || params.DueDate.Format("2006-01-02") != "2025-05-01" {
+		t.Fatalf("due_date = %v", params.DueDate)
+	}
+
+	params, err = decode(t, `{"original_estimate_minutes":90,"remaining_estimate_minutes":null}`)
+	if err != nil || !params.SetOriginalEstimate || params.OriginalEstimate == nil || *params.OriginalEstimate != 90 {
+		t.Fatalf("original_estimate_minutes = %v, %v", params.OriginalEstimate, err)
+	}
+	if !params.SetRemainingEstimate || params.RemainingEstimate != nil {
+		t.Fatal("remaining_estimate_minutes null should clear the estimate")
+	}
+
+	params, err = decode(t, `{"label_ids":null}`)
+	if err != nil || params.LabelIDs == nil || len(params.LabelIDs) != 0 {
+		t.Fatalf("label_ids null = %#v, %v, want an empty slice", params.LabelIDs, err)
+	}
+
+	for _, body := range []string{
+		`{"title":null}`,
+		`{"label_ids":"l"}`,
+		`{"priority":3}`,
+		`{"due_date":"tomorrow"}`,
+		`{"original_estimate_minutes":"2h"}`,
+		`{"status_id":"s"}`,
+	} {
+		if _, err := decode(t, body); err == nil {
+			t.Fatalf("parsePatch(%s): expected error", body)
+		}
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	issue := Issue{Version: 3}
+	if ETag(issue) != `"3"` {
+		t.Fatalf("ETag() = %s", ETag(issue))
+	}
+	for header, want := range map[string]bool{
+		`"3"`:        true,
+		`*`:          true,
+		`"1", "3"`:   true,
+		`"2"`:        false,
+		`W/"3"`:      false,
+		`3`:          false,
+		`"2","4"`:    false,
+		`"3"garbage`: false,
+	} {
+		if got := etagMatches(header, issue); got != want {
+			t.Fatalf("etagMatches(%s) = %v, want %v", header, got, want)
+		}
+	}
+}
+
+func TestStaleError(t *testing.T) {
+	err := error(&StaleError{Current: Issue{ID: "i", Version: 2}})
+	if !errors.Is(err, ErrStale) {
+		t.Fatal("StaleError does not match ErrStale")
+	}
+	var stale *StaleError
+	if !errors.As(err, &stale) || stale.Current.Version != 2 {
+		t.Fatal("StaleError does not carry the current issue")
+	}
+}
+
+func TestPatch_NilDB(t *testing.T) {
+	_, err := Patch(context.Background(), nil, PatchParams{IssueID: "i", ProjectID: "p", ActorID: "u"})
+	if err == nil {
+		t.Fatal("expected error for nil db")
+	}
+}
+
+func TestBulkParams_Validate(t *testing.T) {
+	base := BulkParams{ProjectID: "p", ActorID: "u", IssueIDs: []string{"a", "b"}, Operation: BulkArchive}
+	tooMany := make([]string, maxBulkIssues+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("x", i+1)
+	}
+	tests := []struct {
+		name    string
+		mutate  func(*BulkParams)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(*BulkParams) {}},
+		{name: "no issues", mutate: func(p *BulkParams) { p.IssueIDs = nil }, wantErr: true},
+		{name: "too many issues", mutate: func(p *BulkParams) { p.IssueIDs = tooMany }, wantErr: true},
+		{name: "duplicate issue", mutate: func(p *BulkParams) { p.IssueIDs = []string{"a", "a"} }, wantErr: true},
+		{name: "empty issue id", mutate: func(p *BulkParams) { p.IssueIDs = []string{""} }, wantErr: true},
+		{name: "unknown operation", mutate: func(p *BulkParams) { p.Operation = "set_title" }, wantErr: true},
+		{name: "priority without value", mutate: func(p *BulkParams) { p.Operation = BulkSetPriority }, wantErr: true},
+		{name: "priority", mutate: func(p *BulkParams) { p.Operation, p.Priority = BulkSetPriority, "high" }},
+		{name: "status without id", mutate: func(p *BulkParams) { p.Operation = BulkSetStatus }, wantErr: true},
+		{name: "clear assignee", mutate: func(p *BulkParams) { p.Operation = BulkSetAssignee }},
+		{name: "label without id", mutate: func(p *BulkParams) { p.Operation = BulkAddLabel }, wantErr: true},
+		{name: "remove label", mutate: func(p *BulkParams) { p.Operation, p.LabelID = BulkRemoveLabel, "l" }},
+		{name: "missing actor", mutate: func(p *BulkParams) { p.ActorID = "" }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := base
+			tt.mutate(&params)
+			if err := params.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsBulkIssueError(t *testing.T) {
+	for _, err := range []error{ErrNotFound, ErrBlocked, ErrInvalidParent, &statuses.TransitionError{}} {
+		if !isBulkIssueError(err) {
+			t.Fatalf("isBulkIssueError(%v) = false, want true", err)
+		}
+	}
+	for _, err := range []error{ErrSprintClosed, ErrStatusNotFound, errors.New("connection reset")} {
+		if isBulkIssueError(err) {
+			t.Fatalf("isBulkIssueError(%v) = true, want false", err)
+		}
+	}
+}
+
+func TestBulk_NilDB(t *testing.T) {
+	_, err := Bulk(context.Background(), nil, BulkParams{ProjectID: "p", ActorID: "u", IssueIDs: []string{"a"}, Operation: BulkArchive})
+	if err == nil {
+		t.Fatal("expected error for nil db")
+	}
+}
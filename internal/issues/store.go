@@ -8,63 +8,113 @@ package issues
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/boardevents"
+	"github.com/start-codex/tookly/internal/customfields"
+	"github.com/start-codex/tookly/internal/filterquery"
+	"github.com/start-codex/tookly/internal/labels"
+	"github.com/start-codex/tookly/internal/notifications"
 	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/statuses"
+	"github.com/start-codex/tookly/internal/webhooks"
 )
 
 const reorderOffset = 1000000
 
-const issueCols = `id, project_id, number, issue_type_id, status_id, parent_issue_id,
+const issueCols = `id, project_id, number, issue_type_id, status_id, parent_issue_id, sprint_id,
 	title, description, priority, assignee_id, reporter_id, due_date,
-	status_position, created_at, updated_at, archived_at`
+	original_estimate_minutes, remaining_estimate_minutes, external_ref,
+	status_position, version, created_at, updated_at, archived_at`
+
+const prefixedIssueCols = `i.id, i.project_id, i.number, i.issue_type_id, i.status_id, i.parent_issue_id, i.sprint_id,
+	i.title, i.description, i.priority, i.assignee_id, i.reporter_id, i.due_date,
+	i.original_estimate_minutes, i.remaining_estimate_minutes, i.external_ref,
+	i.status_position, i.version, i.created_at, i.updated_at, i.archived_at`
 
 func createIssue(ctx context.Context, db *sqlx.DB, params CreateParams) (Issue, error) {
 	var issue Issue
 	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create issue", func(tx *sqlx.Tx) error {
-		var number int
-		if err := tx.QueryRowxContext(ctx,
-			`INSERT INTO project_issue_counters (project_id, last_number)
-			 VALUES ($1, 1)
-			 ON CONFLICT (project_id)
-			 DO UPDATE SET last_number = project_issue_counters.last_number + 1
-			 RETURNING last_number`,
-			params.ProjectID,
-		).Scan(&number); err != nil {
-			return fmt.Errorf("upsert issue counter: %w", err)
-		}
+		var err error
+		issue, err = createInTx(ctx, tx, params)
+		return err
+	}); err != nil {
+		return Issue{}, err
+	}
+	return withDetails(ctx, db, issue)
+}
 
-		var parentIssueID *string
-		if params.ParentIssueID != "" {
-			parentIssueID = &params.ParentIssueID
-		}
-		var assigneeID *string
-		if params.AssigneeID != "" {
-			assigneeID = &params.AssigneeID
-		}
+func createInTx(ctx context.Context, tx *sqlx.Tx, params CreateParams) (Issue, error) {
+	var number int
+	if err := tx.QueryRowxContext(ctx,
+		`INSERT INTO project_issue_counters (project_id, last_number)
+		 VALUES ($1, 1)
+		 ON CONFLICT (project_id)
+		 DO UPDATE SET last_number = project_issue_counters.last_number + 1
+		 RETURNING last_number`,
+		params.ProjectID,
+	).Scan(&number); err != nil {
+		return Issue{}, fmt.Errorf("upsert issue counter: %w", err)
+	}
 
-		if err := tx.QueryRowxContext(ctx,
-			`INSERT INTO issues (
-				project_id, number, issue_type_id, status_id, parent_issue_id,
-				title, description, priority, assignee_id, reporter_id, due_date,
-				status_position
-			) VALUES (
-				$1, $2, $3, $4, $5,
-				$6, $7, $8, $9, $10, $11,
-				(SELECT COALESCE(MAX(status_position), -1) + 1
-				 FROM issues
-				 WHERE project_id = $1 AND status_id = $4 AND archived_at IS NULL)
-			)
-			RETURNING `+issueCols,
-			params.ProjectID, number, params.IssueTypeID, params.StatusID, parentIssueID,
-			params.Title, params.Description, params.Priority, assigneeID, params.ReporterID, params.DueDate,
-		).StructScan(&issue); err != nil {
-			return fmt.Errorf("insert issue: %w", err)
+	var parentIssueID *string
+	if params.ParentIssueID != "" {
+		parentIssueID = &params.ParentIssueID
+	}
+	var assigneeID *string
+	if params.AssigneeID != "" {
+		assigneeID = &params.AssigneeID
+	}
+	remaining := params.RemainingEstimate
+	if remaining == nil {
+		remaining = params.OriginalEstimate
+	}
+	var externalRef *string
+	if params.ExternalRef != "" {
+		externalRef = &params.ExternalRef
+	}
+
+	var issue Issue
+	if err := tx.QueryRowxContext(ctx,
+		`INSERT INTO issues (
+			project_id, number, issue_type_id, status_id, parent_issue_id,
+			title, description, priority, assignee_id, reporter_id, due_date,
+			original_estimate_minutes, remaining_estimate_minutes, external_ref,
+			status_position
+		) VALUES (
+			$1, $2, $3, $4, $5,
+			$6, $7, $8, $9, $10, $11,
+			$12, $13, $14,
+			(SELECT COALESCE(MAX(status_position), -1) + 1
+			 FROM issues
+			 WHERE project_id = $1 AND status_id = $4 AND archived_at IS NULL)
+		)
+		RETURNING `+issueCols,
+		params.ProjectID, number, params.IssueTypeID, params.StatusID, parentIssueID,
+		params.Title, params.Description, params.Priority, assigneeID, params.ReporterID, params.DueDate,
+		params.OriginalEstimate, remaining, externalRef,
+	).StructScan(&issue); err != nil {
+		if pgutil.IsUniqueViolationOf(err, "uq_issues_project_external_ref") {
+			return Issue{}, ErrDuplicateExternalRef
 		}
-		return nil
-	}); err != nil {
+		return Issue{}, fmt.Errorf("insert issue: %w", err)
+	}
+	changes := diffIssues(Issue{}, issue)
+	if err := setCustomFields(ctx, tx, issue, params.CustomFields, true, changes); err != nil {
+		return Issue{}, err
+	}
+	if len(params.LabelIDs) > 0 {
+		if err := setLabels(ctx, tx, issue, params.LabelIDs, changes); err != nil {
+			return Issue{}, err
+		}
+	}
+	if err := insertEvent(ctx, tx, issue.ID, params.ReporterID, EventCreated, changes); err != nil {
 		return Issue{}, err
 	}
 	return issue, nil
@@ -84,84 +134,400 @@ func getIssue(ctx context.Context, db *sqlx.DB, projectID, issueID string) (Issu
 		}
 		return Issue{}, fmt.Errorf("get issue: %w", err)
 	}
-	return issue, nil
+	return withDetails(ctx, db, issue)
 }
 
-func listIssues(ctx context.Context, db *sqlx.DB, params ListParams) ([]Issue, error) {
+func listIssues(ctx context.Context, db *sqlx.DB, params ListParams) (Page, error) {
+	spec, _ := lookupSort(params.Sort)
+	if len(params.ProjectIDs) > 0 {
+		// Numbers repeat across projects, so the ID breaks ties.
+		spec.keys = append(slices.Clip(spec.keys), idKey)
+	}
+
+	where, args, err := listWhere(params)
+	if err != nil {
+		return Page{}, err
+	}
 	query := `SELECT ` + issueCols + `
 		 FROM issues
-		 WHERE project_id = $1
-		   AND archived_at IS NULL`
-	args := []any{params.ProjectID}
+		 WHERE ` + where
 
-	if params.StatusID != "" {
-		args = append(args, params.StatusID)
-		query += fmt.Sprintf(" AND status_id = $%d", len(args))
-	}
-	if params.AssigneeID != "" {
-		args = append(args, params.AssigneeID)
-		query += fmt.Sprintf(" AND assignee_id = $%d", len(args))
+	if params.Cursor != "" {
+		values, err := decodeCursor(spec, params.Cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		var cond string
+		cond, args = spec.after(values, args)
+		query += " AND " + cond
 	}
 
-	query += ` ORDER BY status_id, status_position ASC`
+	args = append(args, params.Limit+1)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", spec.orderBy(), len(args))
 
 	issues := []Issue{}
 	if err := db.SelectContext(ctx, &issues, query, args...); err != nil {
-		return nil, fmt.Errorf("list issues: %w", err)
+		return Page{}, fmt.Errorf("list issues: %w", err)
+	}
+	page := Page{Issues: issues}
+	if len(issues) > params.Limit {
+		page.Issues = issues[:params.Limit]
+		page.HasMore = true
+		page.NextCursor = encodeCursor(spec, page.Issues[len(page.Issues)-1])
 	}
-	return issues, nil
+	if err := attachDetails(ctx, db, page.Issues); err != nil {
+		return Page{}, err
+	}
+	return page, nil
+}
+
+// countExprs maps each CountBy group to the expression issues are grouped by.
+var countExprs = map[string]string{
+	CountByStatusCategory: `(SELECT s.category FROM statuses s WHERE s.id = issues.status_id)`,
+	CountByAssignee:       `COALESCE(assignee_id::text, '')`,
+}
+
+func countIssues(ctx context.Context, db *sqlx.DB, params ListParams, group string) ([]Count, error) {
+	where, args, err := listWhere(params)
+	if err != nil {
+		return nil, err
+	}
+	counts := []Count{}
+	if err := db.SelectContext(ctx, &counts,
+		`SELECT `+countExprs[group]+` AS key, COUNT(*) AS count
+		 FROM issues
+		 WHERE `+where+`
+		 GROUP BY 1
+		 ORDER BY 2 DESC, 1`,
+		args...,
+	); err != nil {
+		return nil, fmt.Errorf("count issues: %w", err)
+	}
+	return counts, nil
+}
+
+// listWhere renders the conditions of a list or count query on issues,
+// without the cursor.
+func listWhere(params ListParams) (string, []any, error) {
+	query := "project_id = $1"
+	args := []any{params.ProjectID}
+	if len(params.ProjectIDs) > 0 {
+		query = "project_id = ANY($1)"
+		args = []any{pq.Array(params.ProjectIDs)}
+	}
+
+	if !params.IncludeArchived {
+		query += " AND archived_at IS NULL"
+	}
+	eq := func(col, v string) {
+		if v != "" {
+			args = append(args, v)
+			query += fmt.Sprintf(" AND %s = $%d", col, len(args))
+		}
+	}
+	eq("status_id", params.StatusID)
+	eq("assignee_id", params.AssigneeID)
+	eq("reporter_id", params.ReporterID)
+	eq("issue_type_id", params.IssueTypeID)
+	eq("parent_issue_id", params.ParentIssueID)
+	eq("priority", params.Priority)
+
+	if len(params.StatusCategories) > 0 {
+		args = append(args, pq.Array(params.StatusCategories))
+		query += fmt.Sprintf(" AND status_id IN (SELECT id FROM statuses WHERE category = ANY($%d))", len(args))
+	}
+
+	keys := make([]string, 0, len(params.CustomFields))
+	for key := range params.CustomFields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		node, err := filterquery.CustomFieldTerm(key, params.CustomFields[key])
+		if err != nil {
+			return "", nil, err
+		}
+		var cond string
+		cond, args, err = filterquery.Compile(node, filterquery.Env{Alias: "issues"}, args)
+		if err != nil {
+			return "", nil, err
+		}
+		query += " AND " + cond
+	}
+
+	for _, name := range params.Labels {
+		node, err := filterquery.LabelTerm(name)
+		if err != nil {
+			return "", nil, err
+		}
+		var cond string
+		cond, args, err = filterquery.Compile(node, filterquery.Env{Alias: "issues"}, args)
+		if err != nil {
+			return "", nil, err
+		}
+		query += " AND " + cond
+	}
+
+	bound := func(col, op, cast string, v *time.Time) {
+		if v != nil {
+			args = append(args, v.Format(time.RFC3339Nano))
+			query += fmt.Sprintf(" AND %s %s $%d::%s", col, op, len(args), cast)
+		}
+	}
+	bound("due_date", "<", "date", params.DueBefore)
+	bound("due_date", ">", "date", params.DueAfter)
+	bound("created_at", "<", "timestamptz", params.CreatedBefore)
+	bound("created_at", ">", "timestamptz", params.CreatedAfter)
+	bound("updated_at", "<", "timestamptz", params.UpdatedBefore)
+	bound("updated_at", ">", "timestamptz", params.UpdatedAfter)
+	return query, args, nil
 }
 
 func updateIssue(ctx context.Context, db *sqlx.DB, params UpdateParams) (Issue, error) {
+	return writeIssue(ctx, db, params.ProjectID, params.IssueID, params.IfMatch, func(before Issue) (UpdateParams, error) {
+		if params.OriginalEstimate == nil {
+			params.OriginalEstimate = before.OriginalEstimate
+		}
+		if params.RemainingEstimate == nil {
+			params.RemainingEstimate = before.RemainingEstimate
+		}
+		return params, nil
+	})
+}
+
+func patchIssue(ctx context.Context, db *sqlx.DB, params PatchParams) (Issue, error) {
+	return writeIssue(ctx, db, params.ProjectID, params.IssueID, params.IfMatch, func(before Issue) (UpdateParams, error) {
+		update := params.apply(before)
+		return update, update.Validate()
+	})
+}
+
+// writeIssue locks the issue, checks ifMatch against it and stores the
+// update that build derives from it, bumping the version.
+func writeIssue(ctx context.Context, db *sqlx.DB, projectID, issueID, ifMatch string, build func(before Issue) (UpdateParams, error)) (Issue, error) {
 	var issue Issue
-	err := db.QueryRowxContext(ctx,
-		`UPDATE issues
-		 SET title       = $1,
-		     description = $2,
-		     priority    = $3,
-		     assignee_id = $4,
-		     due_date    = $5
-		 WHERE id = $6
-		   AND project_id = $7
-		   AND archived_at IS NULL
-		 RETURNING `+issueCols,
-		params.Title, params.Description, params.Priority, params.AssigneeID, params.DueDate,
-		params.IssueID, params.ProjectID,
-	).StructScan(&issue)
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update issue", func(tx *sqlx.Tx) error {
+		before, err := getIssueForUpdate(ctx, tx, projectID, issueID)
+		if err != nil {
+			return err
+		}
+		if ifMatch != "" && !etagMatches(ifMatch, before) {
+			current, err := withDetails(ctx, tx, before)
+			if err != nil {
+				return err
+			}
+			return &StaleError{Current: current}
+		}
+		params, err := build(before)
+		if err != nil {
+			return err
+		}
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE issues
+			 SET title                      = $1,
+			     description                = $2,
+			     priority                   = $3,
+			     assignee_id                = $4,
+			     due_date                   = $5,
+			     original_estimate_minutes  = $6,
+			     remaining_estimate_minutes = $7,
+			     version                    = version + 1
+			 WHERE id = $8
+			   AND project_id = $9
+			 RETURNING `+issueCols,
+			params.Title, params.Description, params.Priority, params.AssigneeID, params.DueDate,
+			params.OriginalEstimate, params.RemainingEstimate,
+			params.IssueID, params.ProjectID,
+		).StructScan(&issue); err != nil {
+			return fmt.Errorf("update issue: %w", err)
+		}
+		changes := diffIssues(before, issue)
+		if len(params.CustomFields) > 0 {
+			if err := setCustomFields(ctx, tx, issue, params.CustomFields, false, changes); err != nil {
+				return err
+			}
+		}
+		if params.LabelIDs != nil {
+			if err := setLabels(ctx, tx, issue, params.LabelIDs, changes); err != nil {
+				return err
+			}
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return insertEvent(ctx, tx, issue.ID, params.ActorID, EventUpdated, changes)
+	}); err != nil {
+		return Issue{}, err
+	}
+	return withDetails(ctx, db, issue)
+}
+
+// setCustomFields writes custom field input for issue and records each
+// changed value in changes under "custom_fields.<key>".
+func setCustomFields(ctx context.Context, tx *sqlx.Tx, issue Issue, values map[string]json.RawMessage, creating bool, changes map[string]FieldChange) error {
+	changed, err := customfields.SetValues(ctx, tx, customfields.SetParams{
+		ProjectID:   issue.ProjectID,
+		IssueID:     issue.ID,
+		IssueTypeID: issue.IssueTypeID,
+		Values:      values,
+		Creating:    creating,
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return Issue{}, ErrNotFound
+		return err
+	}
+	for key, c := range changed {
+		changes["custom_fields."+key] = FieldChange{From: c.From, To: c.To}
+	}
+	return nil
+}
+
+// setLabels replaces the labels of issue and records the change in
+// changes under "labels".
+func setLabels(ctx context.Context, tx *sqlx.Tx, issue Issue, labelIDs []string, changes map[string]FieldChange) error {
+	change, err := labels.Set(ctx, tx, issue.ProjectID, issue.ID, labelIDs)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(change.From, change.To) {
+		changes["labels"] = FieldChange{From: change.From, To: change.To}
+	}
+	return nil
+}
+
+func withDetails(ctx context.Context, q sqlx.QueryerContext, issue Issue) (Issue, error) {
+	list := []Issue{issue}
+	if err := attachDetails(ctx, q, list); err != nil {
+		return Issue{}, err
+	}
+	return list[0], nil
+}
+
+// attachDetails sets CustomFields, Labels and TimeTracking on each issue in
+// list in place.
+func attachDetails(ctx context.Context, q sqlx.QueryerContext, list []Issue) error {
+	ids := make([]string, len(list))
+	for i, issue := range list {
+		ids[i] = issue.ID
+	}
+	values, err := customfields.Load(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+	byIssue, err := labels.Load(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+	tracking, err := loadTimeTracking(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		list[i].CustomFields = values[list[i].ID]
+		list[i].Labels = byIssue[list[i].ID]
+		if list[i].Labels == nil {
+			list[i].Labels = []labels.Label{}
 		}
-		return Issue{}, fmt.Errorf("update issue: %w", err)
+		list[i].TimeTracking = tracking[list[i].ID]
 	}
-	return issue, nil
+	return nil
+}
+
+// loadTimeTracking rolls up estimates and logged work for each issue in ids
+// over the tree of active sub-issues below it.
+func loadTimeTracking(ctx context.Context, q sqlx.QueryerContext, ids []string) (map[string]TimeTracking, error) {
+	out := map[string]TimeTracking{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	var rows []struct {
+		IssueID string `db:"issue_id"`
+		TimeTracking
+	}
+	// UNION rather than UNION ALL stops the walk should parent links ever
+	// form a cycle.
+	if err := sqlx.SelectContext(ctx, q, &rows,
+		`WITH RECURSIVE tree (root_id, id) AS (
+			SELECT id, id FROM issues WHERE id = ANY($1)
+			UNION
+			SELECT t.root_id, c.id
+			FROM tree t
+			JOIN issues c ON c.parent_issue_id = t.id AND c.archived_at IS NULL
+		 )
+		 SELECT t.root_id AS issue_id,
+		        COALESCE(SUM(w.minutes) FILTER (WHERE t.id = t.root_id), 0)::bigint AS time_spent,
+		        COALESCE(SUM(i.original_estimate_minutes), 0) AS total_original_estimate,
+		        COALESCE(SUM(i.remaining_estimate_minutes), 0) AS total_remaining_estimate,
+		        COALESCE(SUM(w.minutes), 0)::bigint AS total_time_spent
+		 FROM tree t
+		 JOIN issues i ON i.id = t.id
+		 LEFT JOIN LATERAL (
+			SELECT SUM(minutes) AS minutes FROM worklogs WHERE issue_id = t.id
+		 ) w ON true
+		 GROUP BY t.root_id`,
+		pq.Array(ids),
+	); err != nil {
+		return nil, fmt.Errorf("load time tracking: %w", err)
+	}
+	for _, row := range rows {
+		out[row.IssueID] = row.TimeTracking
+	}
+	return out, nil
+}
+
+func archiveIssue(ctx context.Context, db *sqlx.DB, projectID, issueID, actorID string) error {
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit archive issue", func(tx *sqlx.Tx) error {
+		return archiveInTx(ctx, tx, projectID, issueID, actorID)
+	})
 }
 
-func archiveIssue(ctx context.Context, db *sqlx.DB, projectID, issueID string) error {
-	res, err := db.ExecContext(ctx,
+func archiveInTx(ctx context.Context, tx *sqlx.Tx, projectID, issueID, actorID string) error {
+	var archivedAt time.Time
+	if err := tx.QueryRowxContext(ctx,
 		`UPDATE issues
-		 SET archived_at = NOW()
+		 SET archived_at = NOW(),
+		     version     = version + 1
 		 WHERE id = $1
 		   AND project_id = $2
-		   AND archived_at IS NULL`,
+		   AND archived_at IS NULL
+		 RETURNING archived_at`,
 		issueID, projectID,
-	)
-	if err != nil {
+	).Scan(&archivedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
 		return fmt.Errorf("archive issue: %w", err)
 	}
-	n, err := res.RowsAffected()
+	return insertEvent(ctx, tx, issueID, actorID, EventArchived, map[string]FieldChange{
+		"archived_at": {From: nil, To: archivedAt},
+	})
+}
+
+// getIssueForUpdate loads an active issue and locks its row for the rest of the transaction.
+func getIssueForUpdate(ctx context.Context, tx *sqlx.Tx, projectID, issueID string) (Issue, error) {
+	var issue Issue
+	err := tx.GetContext(ctx, &issue,
+		`SELECT `+issueCols+`
+		 FROM issues
+		 WHERE id = $1
+		   AND project_id = $2
+		   AND archived_at IS NULL
+		 FOR UPDATE`,
+		issueID, projectID,
+	)
 	if err != nil {
-		return fmt.Errorf("archive issue rows affected: %w", err)
-	}
-	if n == 0 {
-		return ErrNotFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return Issue{}, ErrNotFound
+		}
+		return Issue{}, fmt.Errorf("load issue for update: %w", err)
 	}
-	return nil
+	return issue, nil
 }
 
 type issuePosition struct {
-	StatusID       string `db:"status_id"`
-	StatusPosition int    `db:"status_position"`
+	StatusID       string  `db:"status_id"`
+	StatusPosition int     `db:"status_position"`
+	IssueTypeID    string  `db:"issue_type_id"`
+	AssigneeID     *string `db:"assignee_id"`
 }
 
 // moveIssue persists the move of an issue to a target status/position.
@@ -173,12 +539,23 @@ func moveIssue(ctx context.Context, db *sqlx.DB, params MoveParams) error {
 	}
 	defer tx.Rollback()
 
+	if _, err := moveInTx(ctx, tx, params); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit move issue: %w", err)
+	}
+	return nil
+}
+
+// moveInTx runs a move inside tx and reports whether the issue changed place.
+func moveInTx(ctx context.Context, tx *sqlx.Tx, params MoveParams) (bool, error) {
 	current, err := getIssuePositionForUpdate(ctx, tx, params.ProjectID, params.IssueID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return ErrNotFound
+			return false, ErrNotFound
 		}
-		return err
+		return false, err
 	}
 
 	sourceStatusID := current.StatusID
@@ -188,39 +565,55 @@ func moveIssue(ctx context.Context, db *sqlx.DB, params MoveParams) error {
 	}
 
 	if err := lockStatuses(ctx, tx, params.ProjectID, sourceStatusID, targetStatusID); err != nil {
-		return err
+		return false, err
+	}
+
+	if sourceStatusID != targetStatusID {
+		if err := statuses.CheckTransition(ctx, tx, statuses.TransitionCheck{
+			ProjectID:    params.ProjectID,
+			IssueTypeID:  current.IssueTypeID,
+			FromStatusID: sourceStatusID,
+			ToStatusID:   targetStatusID,
+			HasAssignee:  current.AssigneeID != nil,
+			ActorIsAdmin: params.ActorIsAdmin,
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	if params.RejectIfBlocked && sourceStatusID != targetStatusID {
+		if err := checkNotBlocked(ctx, tx, params.IssueID, targetStatusID); err != nil {
+			return false, err
+		}
 	}
 
 	if err := lockAffectedIssues(ctx, tx, params.ProjectID, sourceStatusID, targetStatusID); err != nil {
-		return err
+		return false, err
 	}
 
 	targetPos, err := clampTargetPosition(ctx, tx, params.ProjectID, targetStatusID, params.TargetPosition, sourceStatusID == targetStatusID)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if sourceStatusID == targetStatusID && targetPos == current.StatusPosition {
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit no-op move: %w", err)
-		}
-		return nil
+		return false, nil
 	}
 
 	if err := parkIssueAtTempPosition(ctx, tx, params.ProjectID, params.IssueID, sourceStatusID); err != nil {
-		return err
+		return false, err
 	}
 
 	if sourceStatusID == targetStatusID {
 		if err := reorderWithinSameStatus(ctx, tx, params.ProjectID, params.IssueID, sourceStatusID, current.StatusPosition, targetPos); err != nil {
-			return err
+			return false, err
 		}
 	} else {
 		if err := collapseSourceStatus(ctx, tx, params.ProjectID, params.IssueID, sourceStatusID, current.StatusPosition); err != nil {
-			return err
+			return false, err
 		}
 		if err := openGapInTargetStatus(ctx, tx, params.ProjectID, targetStatusID, targetPos); err != nil {
-			return err
+			return false, err
 		}
 	}
 
@@ -228,7 +621,8 @@ func moveIssue(ctx context.Context, db *sqlx.DB, params MoveParams) error {
 		ctx,
 		`UPDATE issues
 		 SET status_id = $1,
-		     status_position = $2
+		     status_position = $2,
+		     version = version + 1
 		 WHERE id = $3
 		   AND project_id = $4`,
 		targetStatusID,
@@ -236,14 +630,20 @@ func moveIssue(ctx context.Context, db *sqlx.DB, params MoveParams) error {
 		params.IssueID,
 		params.ProjectID,
 	); err != nil {
-		return fmt.Errorf("place moved issue: %w", err)
+		return false, fmt.Errorf("place moved issue: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit move issue: %w", err)
+	changes := map[string]FieldChange{}
+	if sourceStatusID != targetStatusID {
+		changes["status_id"] = FieldChange{From: sourceStatusID, To: targetStatusID}
 	}
-
-	return nil
+	if current.StatusPosition != targetPos {
+		changes["status_position"] = FieldChange{From: current.StatusPosition, To: targetPos}
+	}
+	if err := insertEvent(ctx, tx, params.IssueID, params.ActorID, EventMoved, changes); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func getIssuePositionForUpdate(ctx context.Context, tx *sqlx.Tx, projectID, issueID string) (issuePosition, error) {
@@ -251,7 +651,7 @@ func getIssuePositionForUpdate(ctx context.Context, tx *sqlx.Tx, projectID, issu
 	err := tx.GetContext(
 		ctx,
 		&pos,
-		`SELECT status_id, status_position
+		`SELECT status_id, status_position, issue_type_id, assignee_id
 		 FROM issues
 		 WHERE id = $1
 		   AND project_id = $2
@@ -303,6 +703,33 @@ func lockStatuses(ctx context.Context, tx *sqlx.Tx, projectID, sourceStatusID, t
 	return nil
 }
 
+// checkNotBlocked returns ErrBlocked when targetStatusID is a 'done' status
+// and the issue has a blocker that is active and not done. Archived
+// blockers count as resolved.
+func checkNotBlocked(ctx context.Context, tx *sqlx.Tx, issueID, targetStatusID string) error {
+	var blocked bool
+	if err := tx.GetContext(ctx, &blocked,
+		`SELECT EXISTS(
+			SELECT 1
+			FROM statuses t
+			JOIN issue_links l ON l.target_issue_id = $1 AND l.link_type = 'blocks'
+			JOIN issues b ON b.id = l.source_issue_id
+			JOIN statuses bs ON bs.id = b.status_id
+			WHERE t.id = $2
+			  AND t.category = 'done'
+			  AND b.archived_at IS NULL
+			  AND bs.category <> 'done'
+		)`,
+		issueID, targetStatusID,
+	); err != nil {
+		return fmt.Errorf("check blockers: %w", err)
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
+
 func lockAffectedIssues(ctx context.Context, tx *sqlx.Tx, projectID, sourceStatusID, targetStatusID string) error {
 	if _, err := tx.ExecContext(
 		ctx,
@@ -517,3 +944,150 @@ func shiftDownRange(ctx context.Context, tx *sqlx.Tx, projectID, issueID, status
 	}
 	return nil
 }
+
+// --- event store ---
+
+func insertEvent(ctx context.Context, tx *sqlx.Tx, issueID, actorID, eventType string, changes map[string]FieldChange) error {
+	payload, err := json.Marshal(EventPayload{Changes: changes})
+	if err != nil {
+		return fmt.Errorf("marshal issue event payload: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO issue_events (issue_id, actor_id, event_type, payload_json)
+		 VALUES ($1, $2, $3, $4)`,
+		issueID, actorID, eventType, payload,
+	); err != nil {
+		return fmt.Errorf("insert issue event: %w", err)
+	}
+	issue, err := loadEventIssue(ctx, tx, issueID)
+	if err != nil {
+		return err
+	}
+	if err := enqueueWebhook(ctx, tx, issue, actorID, eventType, WebhookData{Changes: changes}); err != nil {
+		return err
+	}
+	if err := notifyWatchers(ctx, tx, issue, actorID, eventType, notificationData{Changes: changes}); err != nil {
+		return err
+	}
+	return publishBoardEvent(ctx, tx, issue, eventType, changes)
+}
+
+// eventIssue is an issue with the project details event consumers need.
+type eventIssue struct {
+	Issue
+	WorkspaceID string `db:"workspace_id"`
+	ProjectKey  string `db:"project_key"`
+}
+
+func (e eventIssue) key() string {
+	return fmt.Sprintf("%s-%d", e.ProjectKey, e.Number)
+}
+
+func loadEventIssue(ctx context.Context, tx *sqlx.Tx, issueID string) (eventIssue, error) {
+	var row eventIssue
+	if err := tx.GetContext(ctx, &row,
+		`SELECT `+prefixedIssueCols+`, p.workspace_id, p.key AS project_key
+		 FROM issues i
+		 JOIN projects p ON p.id = i.project_id
+		 WHERE i.id = $1`,
+		issueID,
+	); err != nil {
+		return eventIssue{}, fmt.Errorf("load issue for event: %w", err)
+	}
+	return row, nil
+}
+
+func enqueueWebhook(ctx context.Context, tx *sqlx.Tx, issue eventIssue, actorID, eventType string, data WebhookData) error {
+	data.Issue = issue.Issue
+	data.Key = issue.key()
+	return webhooks.Enqueue(ctx, tx, webhooks.Event{
+		WorkspaceID: issue.WorkspaceID,
+		Type:        "issue." + eventType,
+		ActorID:     actorID,
+		Data:        data,
+	})
+}
+
+// notificationData is the data of a watcher notification.
+type notificationData struct {
+	Changes map[string]FieldChange `json:"changes,omitempty"`
+	Comment any                    `json:"comment,omitempty"`
+}
+
+// notifyWatchers adds the reporter of a new issue, a new assignee and a
+// commenter as watchers, then notifies every other watcher of the event.
+func notifyWatchers(ctx context.Context, tx *sqlx.Tx, issue eventIssue, actorID, eventType string, data notificationData) error {
+	var watch []string
+	switch eventType {
+	case EventCreated:
+		watch = append(watch, issue.ReporterID)
+	case EventCommented:
+		watch = append(watch, actorID)
+	}
+	if c, ok := data.Changes["assignee_id"]; ok {
+		if to, ok := c.To.(string); ok {
+			watch = append(watch, to)
+		}
+	}
+	if err := notifications.AutoWatch(ctx, tx, issue.ID, watch...); err != nil {
+		return err
+	}
+	return notifications.Notify(ctx, tx, notifications.Event{
+		IssueID: issue.ID,
+		ActorID: actorID,
+		Type:    eventType,
+		Data:    data,
+	})
+}
+
+// publishBoardEvent streams the change to open boards together with the
+// resulting positions in every status it touched, so clients can re-sort
+// without refetching.
+func publishBoardEvent(ctx context.Context, tx *sqlx.Tx, issue eventIssue, eventType string, changes map[string]FieldChange) error {
+	statusIDs := []string{issue.StatusID}
+	if c, ok := changes["status_id"]; ok {
+		if from, ok := c.From.(string); ok && from != issue.StatusID {
+			statusIDs = append(statusIDs, from)
+		}
+	}
+	positions := []BoardPosition{}
+	if err := tx.SelectContext(ctx, &positions,
+		`SELECT id, status_id, status_position
+		 FROM issues
+		 WHERE project_id = $1
+		   AND status_id = ANY($2)
+		   AND archived_at IS NULL
+		 ORDER BY status_id, status_position`,
+		issue.ProjectID, pq.Array(statusIDs),
+	); err != nil {
+		return fmt.Errorf("load board positions: %w", err)
+	}
+	return boardevents.Publish(ctx, tx, issue.ProjectID, issue.ID, "issue."+eventType, BoardEventData{
+		Issue:     issue.Issue,
+		Key:       issue.key(),
+		Changes:   changes,
+		Positions: positions,
+	})
+}
+
+func listEvents(ctx context.Context, db *sqlx.DB, params ListEventsParams) ([]Event, error) {
+	events := []Event{}
+	if err := db.SelectContext(ctx, &events,
+		`SELECT e.id, e.issue_id, e.actor_id, u.name AS actor_name,
+		        e.event_type, e.payload_json, e.created_at
+		 FROM issue_events e
+		 JOIN issues i ON i.id = e.issue_id
+		 JOIN app_users u ON u.id = e.actor_id
+		 WHERE e.issue_id = $1
+		   AND i.project_id = $2
+		 ORDER BY e.created_at DESC, e.id DESC
+		 LIMIT $3 OFFSET $4`,
+		params.IssueID, params.ProjectID, params.Limit, params.Offset,
+	); err != nil {
+		return nil, fmt.Errorf("list issue events: %w", err)
+	}
+	for i := range events {
+		events[i].Payload = json.RawMessage(events[i].PayloadJSON)
+	}
+	return events, nil
+}
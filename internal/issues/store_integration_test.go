@@ -7,13 +7,20 @@ package issues
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/customfields"
+	"github.com/start-codex/tookly/internal/labels"
+	"github.com/start-codex/tookly/internal/notifications"
+	"github.com/start-codex/tookly/internal/statuses"
 	"github.com/start-codex/tookly/internal/testpg"
 )
 
@@ -32,7 +39,7 @@ func TestMoveIssue(t *testing.T) {
 				a := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
 				b := insertIssue(t, db, seed, issueSeed{number: 2, title: "B", statusID: seed.statusTodoID, statusPosition: 1})
 				c := insertIssue(t, db, seed, issueSeed{number: 3, title: "C", statusID: seed.statusTodoID, statusPosition: 2})
-				params := MoveParams{ProjectID: seed.projectID, IssueID: c, TargetStatusID: seed.statusTodoID, TargetPosition: 0}
+				params := MoveParams{ProjectID: seed.projectID, IssueID: c, ActorID: seed.reporterID, TargetStatusID: seed.statusTodoID, TargetPosition: 0}
 				return params, func(t *testing.T) {
 					assertOrder(t,
 						fetchStatusOrder(t, db, seed.projectID, seed.statusTodoID),
@@ -48,7 +55,7 @@ func TestMoveIssue(t *testing.T) {
 				b := insertIssue(t, db, seed, issueSeed{number: 2, title: "B", statusID: seed.statusTodoID, statusPosition: 1})
 				d := insertIssue(t, db, seed, issueSeed{number: 3, title: "D", statusID: seed.statusDoingID, statusPosition: 0})
 				e := insertIssue(t, db, seed, issueSeed{number: 4, title: "E", statusID: seed.statusDoingID, statusPosition: 1})
-				params := MoveParams{ProjectID: seed.projectID, IssueID: b, TargetStatusID: seed.statusDoingID, TargetPosition: 1}
+				params := MoveParams{ProjectID: seed.projectID, IssueID: b, ActorID: seed.reporterID, TargetStatusID: seed.statusDoingID, TargetPosition: 1}
 				return params, func(t *testing.T) {
 					assertOrder(t,
 						fetchStatusOrder(t, db, seed.projectID, seed.statusTodoID),
@@ -66,7 +73,7 @@ func TestMoveIssue(t *testing.T) {
 			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (MoveParams, func(*testing.T)) {
 				a := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
 				b := insertIssue(t, db, seed, issueSeed{number: 2, title: "B", statusID: seed.statusTodoID, statusPosition: 1})
-				params := MoveParams{ProjectID: seed.projectID, IssueID: a, TargetStatusID: seed.statusTodoID, TargetPosition: 0}
+				params := MoveParams{ProjectID: seed.projectID, IssueID: a, ActorID: seed.reporterID, TargetStatusID: seed.statusTodoID, TargetPosition: 0}
 				return params, func(t *testing.T) {
 					assertOrder(t,
 						fetchStatusOrder(t, db, seed.projectID, seed.statusTodoID),
@@ -82,6 +89,7 @@ func TestMoveIssue(t *testing.T) {
 				params := MoveParams{
 					ProjectID:      seed.projectID,
 					IssueID:        "00000000-0000-0000-0000-000000000000",
+					ActorID:        seed.reporterID,
 					TargetStatusID: seed.statusTodoID,
 					TargetPosition: 0,
 				}
@@ -94,7 +102,7 @@ func TestMoveIssue(t *testing.T) {
 				a := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
 				b := insertIssue(t, db, seed, issueSeed{number: 2, title: "B", statusID: seed.statusTodoID, statusPosition: 1})
 				c := insertIssue(t, db, seed, issueSeed{number: 3, title: "C", statusID: seed.statusTodoID, statusPosition: 2})
-				params := MoveParams{ProjectID: seed.projectID, IssueID: a, TargetStatusID: seed.statusTodoID, TargetPosition: 999}
+				params := MoveParams{ProjectID: seed.projectID, IssueID: a, ActorID: seed.reporterID, TargetStatusID: seed.statusTodoID, TargetPosition: 999}
 				return params, func(t *testing.T) {
 					assertOrder(t,
 						fetchStatusOrder(t, db, seed.projectID, seed.statusTodoID),
@@ -109,7 +117,7 @@ func TestMoveIssue(t *testing.T) {
 				a := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
 				d := insertIssue(t, db, seed, issueSeed{number: 2, title: "D", statusID: seed.statusDoingID, statusPosition: 0})
 				e := insertIssue(t, db, seed, issueSeed{number: 3, title: "E", statusID: seed.statusDoingID, statusPosition: 1})
-				params := MoveParams{ProjectID: seed.projectID, IssueID: a, TargetStatusID: seed.statusDoingID, TargetPosition: 0}
+				params := MoveParams{ProjectID: seed.projectID, IssueID: a, ActorID: seed.reporterID, TargetStatusID: seed.statusDoingID, TargetPosition: 0}
 				return params, func(t *testing.T) {
 					assertOrder(t,
 						fetchStatusOrder(t, db, seed.projectID, seed.statusDoingID),
@@ -163,6 +171,7 @@ func TestMoveIssue_ConcurrentMoves(t *testing.T) {
 			errCh <- Move(context.Background(), db, MoveParams{
 				ProjectID:      seed.projectID,
 				IssueID:        issueID,
+				ActorID:        seed.reporterID,
 				TargetStatusID: seed.statusDoingID,
 				TargetPosition: 0,
 			})
@@ -235,6 +244,7 @@ func TestMoveIssue_ConcurrentMixedMoves(t *testing.T) {
 			errCh <- Move(context.Background(), db, MoveParams{
 				ProjectID:      seed.projectID,
 				IssueID:        mc.issueID,
+				ActorID:        seed.reporterID,
 				TargetStatusID: mc.statusID,
 				TargetPosition: mc.targetPos,
 			})
@@ -556,7 +566,7 @@ func TestListIssues(t *testing.T) {
 			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (ListParams, func(*testing.T, []Issue)) {
 				a := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
 				insertIssue(t, db, seed, issueSeed{number: 2, title: "B", statusID: seed.statusTodoID, statusPosition: 1})
-				if err := Archive(context.Background(), db, seed.projectID, a); err != nil {
+				if err := Archive(context.Background(), db, seed.projectID, a, seed.reporterID); err != nil {
 					t.Fatalf("archive issue: %v", err)
 				}
 				return ListParams{ProjectID: seed.projectID}, func(t *testing.T, got []Issue) {
@@ -581,6 +591,56 @@ func TestListIssues(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "include archived",
+			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (ListParams, func(*testing.T, []Issue)) {
+				a := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
+				insertIssue(t, db, seed, issueSeed{number: 2, title: "B", statusID: seed.statusTodoID, statusPosition: 1})
+				if err := Archive(context.Background(), db, seed.projectID, a, seed.reporterID); err != nil {
+					t.Fatalf("archive issue: %v", err)
+				}
+				return ListParams{ProjectID: seed.projectID, IncludeArchived: true}, func(t *testing.T, got []Issue) {
+					if len(got) != 2 {
+						t.Fatalf("len: got %d, want 2", len(got))
+					}
+				}
+			},
+		},
+		{
+			name: "filter by priority and due date",
+			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (ListParams, func(*testing.T, []Issue)) {
+				a := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
+				b := insertIssue(t, db, seed, issueSeed{number: 2, title: "B", statusID: seed.statusTodoID, statusPosition: 1})
+				insertIssue(t, db, seed, issueSeed{number: 3, title: "C", statusID: seed.statusTodoID, statusPosition: 2})
+				db.MustExec(`UPDATE issues SET priority = 'high', due_date = '2025-03-01' WHERE id = $1`, a)
+				db.MustExec(`UPDATE issues SET priority = 'high', due_date = '2025-06-01' WHERE id = $1`, b)
+				before := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+				return ListParams{ProjectID: seed.projectID, Priority: "high", DueBefore: &before}, func(t *testing.T, got []Issue) {
+					if len(got) != 1 || got[0].ID != a {
+						t.Fatalf("got %+v, want only %s", got, a)
+					}
+				}
+			},
+		},
+		{
+			name: "filter by status category",
+			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (ListParams, func(*testing.T, []Issue)) {
+				insertIssue(t, db, seed, issueSeed{number: 1, title: "Todo", statusID: seed.statusTodoID, statusPosition: 0})
+				doing := insertIssue(t, db, seed, issueSeed{number: 2, title: "Doing", statusID: seed.statusDoingID, statusPosition: 0})
+				return ListParams{ProjectID: seed.projectID, StatusCategories: []string{"doing", "done"}}, func(t *testing.T, got []Issue) {
+					if len(got) != 1 || got[0].ID != doing {
+						t.Fatalf("got %+v, want only %s", got, doing)
+					}
+				}
+			},
+		},
+		{
+			name:    "invalid cursor",
+			wantErr: ErrInvalidCursor,
+			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (ListParams, func(*testing.T, []Issue)) {
+				return ListParams{ProjectID: seed.projectID, Cursor: "not-a-cursor"}, nil
+			},
+		},
 		{
 			name: "empty project returns empty slice",
 			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (ListParams, func(*testing.T, []Issue)) {
@@ -602,12 +662,121 @@ func TestListIssues(t *testing.T) {
 				t.Fatalf("List() error = %v, wantErr = %v", err, tt.wantErr)
 			}
 			if check != nil {
-				check(t, got)
+				check(t, got.Issues)
 			}
 		})
 	}
 }
 
+func TestListIssues_CursorPagination(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+
+	for _, sort := range []string{"", "-number", "priority", "-created_at", "due_date"} {
+		t.Run("sort="+sort, func(t *testing.T) {
+			seed := seedProject(t, db)
+			want := map[string]bool{}
+			for n := 1; n <= 5; n++ {
+				want[insertIssue(t, db, seed, issueSeed{number: n, title: "I", statusID: seed.statusTodoID, statusPosition: n - 1})] = true
+			}
+
+			seen := map[string]bool{}
+			params := ListParams{ProjectID: seed.projectID, Sort: sort, Limit: 2}
+			for pages := 0; ; pages++ {
+				if pages > 5 {
+					t.Fatal("pagination did not terminate")
+				}
+				page, err := List(context.Background(), db, params)
+				if err != nil {
+					t.Fatalf("List() error = %v", err)
+				}
+				for _, issue := range page.Issues {
+					if seen[issue.ID] {
+						t.Fatalf("issue %s returned twice", issue.ID)
+					}
+					seen[issue.ID] = true
+				}
+				if !page.HasMore {
+					if page.NextCursor != "" {
+						t.Fatalf("next_cursor set on last page")
+					}
+					break
+				}
+				params.Cursor = page.NextCursor
+			}
+			if len(seen) != len(want) {
+				t.Fatalf("saw %d issues, want %d", len(seen), len(want))
+			}
+		})
+	}
+}
+
+func TestListIssues_SeveralProjects(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	first, second := seedProject(t, db), seedProject(t, db)
+	want := map[string]bool{}
+	for _, seed := range []projectSeed{first, second} {
+		for n := 1; n <= 3; n++ {
+			want[insertIssue(t, db, seed, issueSeed{number: n, title: "I", statusID: seed.statusTodoID, statusPosition: n - 1})] = true
+		}
+	}
+	db.MustExec(`UPDATE issues SET status_id = $1, status_position = 0 WHERE project_id = $2 AND number = 3`, first.statusDoingID, first.projectID)
+	db.MustExec(`UPDATE issues SET assignee_id = $1 WHERE project_id = $2`, first.reporterID, first.projectID)
+
+	// Issue numbers repeat across the projects, so pages must not skip or
+	// repeat issues with equal numbers.
+	seen := map[string]bool{}
+	params := ListParams{ProjectIDs: []string{first.projectID, second.projectID}, Sort: "number", Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := List(ctx, db, params)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		for _, issue := range page.Issues {
+			if seen[issue.ID] || !want[issue.ID] {
+				t.Fatalf("issue %s returned twice or not asked for", issue.ID)
+			}
+			seen[issue.ID] = true
+		}
+		if !page.HasMore {
+			break
+		}
+		params.Cursor = page.NextCursor
+	}
+	if len(seen) != len(want) {
+		t.Fatalf("saw %d issues, want %d", len(seen), len(want))
+	}
+
+	single, err := List(ctx, db, ListParams{ProjectID: first.projectID, Sort: "number", Limit: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if _, err := List(ctx, db, ListParams{ProjectIDs: params.ProjectIDs, Sort: "number", Cursor: single.NextCursor}); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("List() with a single-project cursor error = %v, want ErrInvalidCursor", err)
+	}
+
+	byCategory, err := CountBy(ctx, db, ListParams{ProjectIDs: params.ProjectIDs}, CountByStatusCategory)
+	if err != nil {
+		t.Fatalf("CountBy() error = %v", err)
+	}
+	if len(byCategory) != 2 || byCategory[0] != (Count{Key: "todo", Count: 5}) || byCategory[1] != (Count{Key: "doing", Count: 1}) {
+		t.Fatalf("CountBy(status_category) = %+v, want 5 todo and 1 doing", byCategory)
+	}
+	byAssignee, err := CountBy(ctx, db, ListParams{ProjectIDs: params.ProjectIDs}, CountByAssignee)
+	if err != nil {
+		t.Fatalf("CountBy() error = %v", err)
+	}
+	if len(byAssignee) != 2 || byAssignee[0] != (Count{Key: "", Count: 3}) || byAssignee[1] != (Count{Key: first.reporterID, Count: 3}) {
+		t.Fatalf("CountBy(assignee) = %+v, want 3 assigned and 3 unassigned", byAssignee)
+	}
+}
+
 func TestUpdateIssue(t *testing.T) {
 	db := testpg.Open(t)
 	testpg.EnsureMigrated(t, db)
@@ -621,7 +790,7 @@ func TestUpdateIssue(t *testing.T) {
 			name: "updates title and priority",
 			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (UpdateParams, func(*testing.T)) {
 				id := insertIssue(t, db, seed, issueSeed{number: 1, title: "Old", statusID: seed.statusTodoID, statusPosition: 0})
-				params := UpdateParams{IssueID: id, ProjectID: seed.projectID, Title: "New", Priority: "high"}
+				params := UpdateParams{IssueID: id, ProjectID: seed.projectID, ActorID: seed.reporterID, Title: "New", Priority: "high"}
 				return params, func(t *testing.T) {}
 			},
 		},
@@ -629,7 +798,7 @@ func TestUpdateIssue(t *testing.T) {
 			name: "clears assignee when nil",
 			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (UpdateParams, func(*testing.T)) {
 				id := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
-				params := UpdateParams{IssueID: id, ProjectID: seed.projectID, Title: "A", Priority: "medium", AssigneeID: nil}
+				params := UpdateParams{IssueID: id, ProjectID: seed.projectID, ActorID: seed.reporterID, Title: "A", Priority: "medium", AssigneeID: nil}
 				return params, func(t *testing.T) {}
 			},
 		},
@@ -639,7 +808,7 @@ func TestUpdateIssue(t *testing.T) {
 			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (UpdateParams, func(*testing.T)) {
 				params := UpdateParams{
 					IssueID:   "00000000-0000-0000-0000-000000000000",
-					ProjectID: seed.projectID, Title: "X", Priority: "low",
+					ProjectID: seed.projectID, ActorID: seed.reporterID, Title: "X", Priority: "low",
 				}
 				return params, nil
 			},
@@ -649,10 +818,10 @@ func TestUpdateIssue(t *testing.T) {
 			wantErr: ErrNotFound,
 			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (UpdateParams, func(*testing.T)) {
 				id := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
-				if err := Archive(context.Background(), db, seed.projectID, id); err != nil {
+				if err := Archive(context.Background(), db, seed.projectID, id, seed.reporterID); err != nil {
 					t.Fatalf("archive: %v", err)
 				}
-				return UpdateParams{IssueID: id, ProjectID: seed.projectID, Title: "X", Priority: "low"}, nil
+				return UpdateParams{IssueID: id, ProjectID: seed.projectID, ActorID: seed.reporterID, Title: "X", Priority: "low"}, nil
 			},
 		},
 	}
@@ -680,6 +849,156 @@ func TestUpdateIssue(t *testing.T) {
 	}
 }
 
+func TestUpdateIssue_IfMatch(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	seed := seedProject(t, db)
+	id := insertIssue(t, db, seed, issueSeed{number: 1, title: "Old", statusID: seed.statusTodoID, statusPosition: 0})
+
+	read, err := Get(ctx, db, seed.projectID, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	etag := ETag(read)
+
+	first, err := Update(ctx, db, UpdateParams{IssueID: id, ProjectID: seed.projectID, ActorID: seed.reporterID, Title: "First", Priority: "high", IfMatch: etag})
+	if err != nil {
+		t.Fatalf("first Update: %v", err)
+	}
+	if first.Version != read.Version+1 {
+		t.Fatalf("version = %d, want %d", first.Version, read.Version+1)
+	}
+
+	_, err = Update(ctx, db, UpdateParams{IssueID: id, ProjectID: seed.projectID, ActorID: seed.reporterID, Title: "Second", Priority: "low", IfMatch: etag})
+	var stale *StaleError
+	if !errors.As(err, &stale) {
+		t.Fatalf("stale Update error = %v, want *StaleError", err)
+	}
+	if stale.Current.Title != "First" || ETag(stale.Current) != ETag(first) {
+		t.Fatalf("stale current = %+v, want the first write", stale.Current)
+	}
+
+	desc := "Patched"
+	patched, err := Patch(ctx, db, PatchParams{IssueID: id, ProjectID: seed.projectID, ActorID: seed.reporterID, Description: &desc, IfMatch: ETag(first)})
+	if err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if patched.Title != "First" || patched.Priority != "high" || patched.Description != "Patched" {
+		t.Fatalf("Patch touched other fields: %+v", patched)
+	}
+	if _, err := Patch(ctx, db, PatchParams{IssueID: id, ProjectID: seed.projectID, ActorID: seed.reporterID, Description: &desc, IfMatch: ETag(first)}); !errors.Is(err, ErrStale) {
+		t.Fatalf("stale Patch error = %v, want ErrStale", err)
+	}
+
+	if err := Move(ctx, db, MoveParams{ProjectID: seed.projectID, IssueID: id, ActorID: seed.reporterID, TargetStatusID: seed.statusDoingID}); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	moved, err := Get(ctx, db, seed.projectID, id)
+	if err != nil {
+		t.Fatalf("Get after move: %v", err)
+	}
+	if moved.Version != patched.Version+1 {
+		t.Fatalf("version after move = %d, want %d", moved.Version, patched.Version+1)
+	}
+}
+
+func TestBulk(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	seed := seedProject(t, db)
+
+	a := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
+	b := insertIssue(t, db, seed, issueSeed{number: 2, title: "B", statusID: seed.statusTodoID, statusPosition: 1})
+	c := insertIssue(t, db, seed, issueSeed{number: 3, title: "C", statusID: seed.statusTodoID, statusPosition: 2})
+	d := insertIssue(t, db, seed, issueSeed{number: 4, title: "D", statusID: seed.statusDoingID, statusPosition: 0})
+	missing := "00000000-0000-0000-0000-000000000000"
+	bulk := func(params BulkParams) ([]BulkResult, error) {
+		params.ProjectID, params.ActorID = seed.projectID, seed.reporterID
+		return Bulk(ctx, db, params)
+	}
+	statusesOf := func(results []BulkResult) []string {
+		out := make([]string, len(results))
+		for i, r := range results {
+			out[i] = r.Status
+		}
+		return out
+	}
+
+	// set_status appends in the given order; D is already there.
+	results, err := bulk(BulkParams{IssueIDs: []string{c, a, d}, Operation: BulkSetStatus, StatusID: seed.statusDoingID})
+	if err != nil {
+		t.Fatalf("set_status: %v", err)
+	}
+	if got := strings.Join(statusesOf(results), ","); got != "updated,updated,unchanged" {
+		t.Fatalf("set_status results = %s", got)
+	}
+	assertOrder(t, fetchStatusOrder(t, db, seed.projectID, seed.statusDoingID), []orderedIssue{
+		{ID: d, Pos: 0}, {ID: c, Pos: 1}, {ID: a, Pos: 2},
+	})
+	assertContiguousPositions(t, fetchStatusOrder(t, db, seed.projectID, seed.statusTodoID))
+
+	// All-or-nothing: one missing issue rolls back the batch.
+	results, err = bulk(BulkParams{IssueIDs: []string{a, missing}, Operation: BulkSetPriority, Priority: "critical"})
+	if !errors.Is(err, ErrBulkFailed) {
+		t.Fatalf("atomic bulk error = %v, want ErrBulkFailed", err)
+	}
+	if got := strings.Join(statusesOf(results), ","); got != "rolled_back,failed" {
+		t.Fatalf("atomic results = %s", got)
+	}
+	if issue, _ := Get(ctx, db, seed.projectID, a); issue.Priority != "medium" {
+		t.Fatalf("priority = %q after rollback, want medium", issue.Priority)
+	}
+
+	// Best effort keeps what succeeded.
+	results, err = bulk(BulkParams{IssueIDs: []string{a, missing}, Operation: BulkSetPriority, Priority: "critical", BestEffort: true})
+	if err != nil {
+		t.Fatalf("best-effort bulk: %v", err)
+	}
+	if got := strings.Join(statusesOf(results), ","); got != "updated,failed" {
+		t.Fatalf("best-effort results = %s", got)
+	}
+	if issue, _ := Get(ctx, db, seed.projectID, a); issue.Priority != "critical" {
+		t.Fatalf("priority = %q, want critical", issue.Priority)
+	}
+
+	// set_parent rejects cycles per issue.
+	if _, err := bulk(BulkParams{IssueIDs: []string{b}, Operation: BulkSetParent, ParentIssueID: &a}); err != nil {
+		t.Fatalf("set_parent: %v", err)
+	}
+	results, err = bulk(BulkParams{IssueIDs: []string{a}, Operation: BulkSetParent, ParentIssueID: &b, BestEffort: true})
+	if err != nil || results[0].Status != BulkFailed || results[0].Error != ErrInvalidParent.Error() {
+		t.Fatalf("cyclic set_parent = %+v, %v", results, err)
+	}
+
+	// set_sprint needs an open sprint of the project.
+	var boardID, sprintID string
+	if err := db.GetContext(ctx, &boardID, `INSERT INTO boards (project_id, name, type) VALUES ($1, 'Scrum', 'scrum') RETURNING id`, seed.projectID); err != nil {
+		t.Fatalf("insert board: %v", err)
+	}
+	if err := db.GetContext(ctx, &sprintID, `INSERT INTO sprints (board_id, name) VALUES ($1, 'S1') RETURNING id`, boardID); err != nil {
+		t.Fatalf("insert sprint: %v", err)
+	}
+	if _, err := bulk(BulkParams{IssueIDs: []string{a, b}, Operation: BulkSetSprint, SprintID: &sprintID}); err != nil {
+		t.Fatalf("set_sprint: %v", err)
+	}
+	if issue, _ := Get(ctx, db, seed.projectID, b); issue.SprintID == nil || *issue.SprintID != sprintID {
+		t.Fatalf("sprint_id = %v, want %s", issue.SprintID, sprintID)
+	}
+	db.MustExec(`UPDATE sprints SET state = 'closed' WHERE id = $1`, sprintID)
+	if _, err := bulk(BulkParams{IssueIDs: []string{c}, Operation: BulkSetSprint, SprintID: &sprintID}); !errors.Is(err, ErrSprintClosed) {
+		t.Fatalf("closed sprint error = %v, want ErrSprintClosed", err)
+	}
+
+	if _, err := bulk(BulkParams{IssueIDs: []string{a, b, c, d}, Operation: BulkArchive}); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if issue, _ := Get(ctx, db, seed.projectID, d); issue.ArchivedAt == nil {
+		t.Fatal("bulk archive did not archive the issue")
+	}
+}
+
 func TestArchiveIssue(t *testing.T) {
 	db := testpg.Open(t)
 	testpg.EnsureMigrated(t, db)
@@ -708,7 +1027,7 @@ func TestArchiveIssue(t *testing.T) {
 			wantErr: ErrNotFound,
 			arrange: func(t *testing.T, db *sqlx.DB, seed projectSeed) (string, string) {
 				id := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
-				if err := Archive(context.Background(), db, seed.projectID, id); err != nil {
+				if err := Archive(context.Background(), db, seed.projectID, id, seed.reporterID); err != nil {
 					t.Fatalf("first archive: %v", err)
 				}
 				return seed.projectID, id
@@ -720,7 +1039,7 @@ func TestArchiveIssue(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			seed := seedProject(t, db)
 			projID, issueID := tt.arrange(t, db, seed)
-			err := Archive(context.Background(), db, projID, issueID)
+			err := Archive(context.Background(), db, projID, issueID, seed.reporterID)
 			if !errors.Is(err, tt.wantErr) {
 				t.Fatalf("Archive() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -736,3 +1055,506 @@ func TestArchiveIssue(t *testing.T) {
 		})
 	}
 }
+
+func TestIssueEvents(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+
+	seed := seedProject(t, db)
+	ctx := context.Background()
+
+	issue, err := Create(ctx, db, CreateParams{
+		ProjectID: seed.projectID, IssueTypeID: seed.issueTypeID, StatusID: seed.statusTodoID,
+		Title: "Original", ReporterID: seed.reporterID, Priority: "low",
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := Update(ctx, db, UpdateParams{
+		IssueID: issue.ID, ProjectID: seed.projectID, ActorID: seed.reporterID,
+		Title: "Renamed", Priority: "low",
+	}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := Update(ctx, db, UpdateParams{
+		IssueID: issue.ID, ProjectID: seed.projectID, ActorID: seed.reporterID,
+		Title: "Renamed", Priority: "low",
+	}); err != nil {
+		t.Fatalf("no-op update: %v", err)
+	}
+	if err := Move(ctx, db, MoveParams{
+		ProjectID: seed.projectID, IssueID: issue.ID, ActorID: seed.reporterID,
+		TargetStatusID: seed.statusDoingID, TargetPosition: 0,
+	}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if err := Archive(ctx, db, seed.projectID, issue.ID, seed.reporterID); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	events, err := ListEvents(ctx, db, ListEventsParams{ProjectID: seed.projectID, IssueID: issue.ID})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	wantTypes := []string{EventArchived, EventMoved, EventUpdated, EventCreated}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("events: got %d, want %d", len(events), len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if events[i].EventType != want {
+			t.Fatalf("events[%d].EventType = %q, want %q", i, events[i].EventType, want)
+		}
+		if events[i].ActorID != seed.reporterID {
+			t.Fatalf("events[%d].ActorID = %q, want %q", i, events[i].ActorID, seed.reporterID)
+		}
+	}
+
+	var updated EventPayload
+	if err := json.Unmarshal(events[2].Payload, &updated); err != nil {
+		t.Fatalf("unmarshal update payload: %v", err)
+	}
+	if c, ok := updated.Changes["title"]; !ok || c.From != "Original" || c.To != "Renamed" {
+		t.Fatalf("update title change = %+v", updated.Changes)
+	}
+	if len(updated.Changes) != 1 {
+		t.Fatalf("update changes: got %d, want 1", len(updated.Changes))
+	}
+
+	var moved EventPayload
+	if err := json.Unmarshal(events[1].Payload, &moved); err != nil {
+		t.Fatalf("unmarshal move payload: %v", err)
+	}
+	if c := moved.Changes["status_id"]; c.From != seed.statusTodoID || c.To != seed.statusDoingID {
+		t.Fatalf("move status change = %+v", c)
+	}
+
+	page, err := ListEvents(ctx, db, ListEventsParams{ProjectID: seed.projectID, IssueID: issue.ID, Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("ListEvents() page error = %v", err)
+	}
+	if len(page) != 2 || page[0].EventType != EventUpdated || page[1].EventType != EventCreated {
+		t.Fatalf("second page = %+v", page)
+	}
+}
+
+func TestMoveIssue_PublishesBoardEvent(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	seed := seedProject(t, db)
+
+	a := insertIssue(t, db, seed, issueSeed{number: 1, title: "A", statusID: seed.statusTodoID, statusPosition: 0})
+	b := insertIssue(t, db, seed, issueSeed{number: 2, title: "B", statusID: seed.statusTodoID, statusPosition: 1})
+	if err := Move(ctx, db, MoveParams{ProjectID: seed.projectID, IssueID: a, ActorID: seed.reporterID, TargetStatusID: seed.statusDoingID}); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+
+	var row struct {
+		EventType string `db:"event_type"`
+		Payload   []byte `db:"payload"`
+	}
+	if err := db.GetContext(ctx, &row,
+		`SELECT event_type, payload FROM board_events WHERE project_id = $1 ORDER BY id DESC LIMIT 1`,
+		seed.projectID,
+	); err != nil {
+		t.Fatalf("load board event: %v", err)
+	}
+	if row.EventType != "issue.moved" {
+		t.Fatalf("event_type = %q, want issue.moved", row.EventType)
+	}
+	var data BoardEventData
+	if err := json.Unmarshal(row.Payload, &data); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if data.Issue.ID != a || data.Issue.StatusID != seed.statusDoingID {
+		t.Fatalf("payload issue = %+v, want %s in doing", data.Issue, a)
+	}
+	want := map[string]BoardPosition{
+		a: {IssueID: a, StatusID: seed.statusDoingID, StatusPosition: 0},
+		b: {IssueID: b, StatusID: seed.statusTodoID, StatusPosition: 0},
+	}
+	if len(data.Positions) != len(want) {
+		t.Fatalf("positions = %+v, want both columns", data.Positions)
+	}
+	for _, p := range data.Positions {
+		if want[p.IssueID] != p {
+			t.Fatalf("position %+v, want %+v", p, want[p.IssueID])
+		}
+	}
+}
+
+func TestMoveIssue_RejectIfBlocked(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	seed := seedProject(t, db)
+
+	var doneID string
+	if err := db.GetContext(ctx, &doneID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'Hecho', 'done', 2) RETURNING id`, seed.projectID); err != nil {
+		t.Fatalf("insert done status: %v", err)
+	}
+	blocker := insertIssue(t, db, seed, issueSeed{number: 1, title: "Blocker", statusID: seed.statusTodoID, statusPosition: 0})
+	blocked := insertIssue(t, db, seed, issueSeed{number: 2, title: "Blocked", statusID: seed.statusTodoID, statusPosition: 1})
+	db.MustExec(`INSERT INTO issue_links (source_issue_id, target_issue_id, link_type, created_by) VALUES ($1, $2, 'blocks', $3)`, blocker, blocked, seed.reporterID)
+
+	move := func(issueID, statusID string, reject bool) error {
+		return Move(ctx, db, MoveParams{ProjectID: seed.projectID, IssueID: issueID, ActorID: seed.reporterID, TargetStatusID: statusID, RejectIfBlocked: reject})
+	}
+
+	if err := move(blocked, doneID, true); !errors.Is(err, ErrBlocked) {
+		t.Fatalf("move blocked issue to done: error = %v, want ErrBlocked", err)
+	}
+	if err := move(blocked, seed.statusDoingID, true); err != nil {
+		t.Fatalf("move blocked issue to doing: %v", err)
+	}
+	if err := move(blocker, doneID, true); err != nil {
+		t.Fatalf("move blocker to done: %v", err)
+	}
+	if err := move(blocked, doneID, true); err != nil {
+		t.Fatalf("move unblocked issue to done: %v", err)
+	}
+}
+
+func TestIssueCustomFields(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	seed := seedProject(t, db)
+
+	db.MustExec(`INSERT INTO custom_fields (project_id, key, name, field_type, required, position) VALUES ($1, 'points', 'Points', 'number', true, 0)`, seed.projectID)
+	db.MustExec(`INSERT INTO custom_fields (project_id, key, name, field_type, options, position) VALUES ($1, 'team', 'Team', 'multi_select', '["web","api"]', 1)`, seed.projectID)
+
+	create := func(title string, fields string) (Issue, error) {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(fields), &values); err != nil {
+			t.Fatalf("decode fields: %v", err)
+		}
+		return Create(ctx, db, CreateParams{
+			ProjectID: seed.projectID, IssueTypeID: seed.issueTypeID, StatusID: seed.statusTodoID,
+			Title: title, Priority: "medium", ReporterID: seed.reporterID, CustomFields: values,
+		})
+	}
+
+	var ve *customfields.ValueError
+	if _, err := create("Missing", `{}`); !errors.As(err, &ve) || ve.Key != "points" {
+		t.Fatalf("create without required field: error = %v, want ValueError for points", err)
+	}
+	if _, err := create("Unknown", `{"points": 1, "color": "red"}`); !errors.As(err, &ve) || ve.Key != "color" {
+		t.Fatalf("create with unknown field: error = %v, want ValueError for color", err)
+	}
+
+	small, err := create("Small", `{"points": 2, "team": ["api"]}`)
+	if err != nil {
+		t.Fatalf("create small: %v", err)
+	}
+	if string(small.CustomFields["points"]) != "2" || string(small.CustomFields["team"]) != `["api"]` {
+		t.Fatalf("created custom fields = %v", small.CustomFields)
+	}
+	big, err := create("Big", `{"points": 8}`)
+	if err != nil {
+		t.Fatalf("create big: %v", err)
+	}
+
+	updated, err := Update(ctx, db, UpdateParams{
+		ProjectID: seed.projectID, IssueID: big.ID, ActorID: seed.reporterID,
+		Title: big.Title, Priority: big.Priority,
+		CustomFields: map[string]json.RawMessage{"team": json.RawMessage(`["web","api"]`)},
+	})
+	if err != nil {
+		t.Fatalf("update big: %v", err)
+	}
+	if string(updated.CustomFields["points"]) != "8" || string(updated.CustomFields["team"]) != `["web","api"]` {
+		t.Fatalf("updated custom fields = %v", updated.CustomFields)
+	}
+	if _, err := Update(ctx, db, UpdateParams{
+		ProjectID: seed.projectID, IssueID: big.ID, ActorID: seed.reporterID,
+		Title: big.Title, Priority: big.Priority,
+		CustomFields: map[string]json.RawMessage{"points": json.RawMessage(`null`)},
+	}); !errors.As(err, &ve) || ve.Key != "points" {
+		t.Fatalf("clear required field: error = %v, want ValueError for points", err)
+	}
+
+	var payload string
+	if err := db.GetContext(ctx, &payload, `SELECT payload_json::text FROM issue_events WHERE issue_id = $1 AND event_type = 'updated'`, big.ID); err != nil {
+		t.Fatalf("load update event: %v", err)
+	}
+	var event EventPayload
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		t.Fatalf("decode update event: %v", err)
+	}
+	if _, ok := event.Changes["custom_fields.team"]; !ok || len(event.Changes) != 1 {
+		t.Fatalf("update event changes = %v, want only custom_fields.team", event.Changes)
+	}
+
+	for _, tc := range []struct {
+		filter map[string]string
+		want   []string
+	}{
+		{filter: map[string]string{"points": "8.0"}, want: []string{big.ID}},
+		{filter: map[string]string{"team": "api"}, want: []string{small.ID, big.ID}},
+		{filter: map[string]string{"team": "web", "points": "8"}, want: []string{big.ID}},
+		{filter: map[string]string{"team": "none"}, want: []string{}},
+	} {
+		page, err := List(ctx, db, ListParams{ProjectID: seed.projectID, CustomFields: tc.filter, Sort: "number"})
+		if err != nil {
+			t.Fatalf("list %v: %v", tc.filter, err)
+		}
+		got := []string{}
+		for _, issue := range page.Issues {
+			got = append(got, issue.ID)
+		}
+		if len(got) != len(tc.want) || (len(got) > 0 && got[0] != tc.want[0]) || (len(got) > 1 && got[1] != tc.want[1]) {
+			t.Fatalf("list %v = %v, want %v", tc.filter, got, tc.want)
+		}
+		if len(page.Issues) > 0 && page.Issues[0].CustomFields == nil {
+			t.Fatalf("list %v: custom fields not loaded", tc.filter)
+		}
+	}
+}
+
+func TestIssueLabels(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	seed := seedProject(t, db)
+
+	var bugID, uiID string
+	if err := db.GetContext(ctx, &bugID, `INSERT INTO labels (workspace_id, project_id, name, color) VALUES ($1, $2, 'Bug', '#d73a4a') RETURNING id`, seed.workspaceID, seed.projectID); err != nil {
+		t.Fatalf("insert project label: %v", err)
+	}
+	if err := db.GetContext(ctx, &uiID, `INSERT INTO labels (workspace_id, name, color) VALUES ($1, 'UI', '#1f6feb') RETURNING id`, seed.workspaceID); err != nil {
+		t.Fatalf("insert workspace label: %v", err)
+	}
+	other := seedProject(t, db)
+	var foreignID string
+	if err := db.GetContext(ctx, &foreignID, `INSERT INTO labels (workspace_id, name, color) VALUES ($1, 'UI', '#1f6feb') RETURNING id`, other.workspaceID); err != nil {
+		t.Fatalf("insert foreign label: %v", err)
+	}
+
+	create := func(title string, labelIDs ...string) (Issue, error) {
+		return Create(ctx, db, CreateParams{
+			ProjectID: seed.projectID, IssueTypeID: seed.issueTypeID, StatusID: seed.statusTodoID,
+			Title: title, ReporterID: seed.reporterID, LabelIDs: labelIDs,
+		})
+	}
+	if _, err := create("Foreign", foreignID); !errors.Is(err, labels.ErrInvalidLabel) {
+		t.Fatalf("create with another workspace's label: error = %v, want ErrInvalidLabel", err)
+	}
+	both, err := create("Both", uiID, bugID)
+	if err != nil {
+		t.Fatalf("create both: %v", err)
+	}
+	if len(both.Labels) != 2 || both.Labels[0].Name != "Bug" || both.Labels[1].Name != "UI" {
+		t.Fatalf("created labels = %+v, want Bug and UI", both.Labels)
+	}
+	plain, err := create("Plain")
+	if err != nil {
+		t.Fatalf("create plain: %v", err)
+	}
+	if plain.Labels == nil || len(plain.Labels) != 0 {
+		t.Fatalf("plain labels = %#v, want an empty slice", plain.Labels)
+	}
+
+	// Leaving LabelIDs nil keeps the labels; an empty slice clears them.
+	updated, err := Update(ctx, db, UpdateParams{
+		ProjectID: seed.projectID, IssueID: both.ID, ActorID: seed.reporterID,
+		Title: "Both renamed", Priority: both.Priority,
+	})
+	if err != nil || len(updated.Labels) != 2 {
+		t.Fatalf("update without label_ids = %+v, %v", updated.Labels, err)
+	}
+	patched, err := Patch(ctx, db, PatchParams{
+		ProjectID: seed.projectID, IssueID: plain.ID, ActorID: seed.reporterID, LabelIDs: []string{bugID},
+	})
+	if err != nil || len(patched.Labels) != 1 || patched.Labels[0].ID != bugID {
+		t.Fatalf("patch label_ids = %+v, %v", patched.Labels, err)
+	}
+
+	var payload string
+	if err := db.GetContext(ctx, &payload, `SELECT payload_json::text FROM issue_events WHERE issue_id = $1 AND event_type = 'updated'`, plain.ID); err != nil {
+		t.Fatalf("load update event: %v", err)
+	}
+	var event EventPayload
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		t.Fatalf("decode update event: %v", err)
+	}
+	if _, ok := event.Changes["labels"]; !ok || len(event.Changes) != 1 {
+		t.Fatalf("patch event changes = %v, want only labels", event.Changes)
+	}
+
+	list := func(names ...string) []string {
+		t.Helper()
+		page, err := List(ctx, db, ListParams{ProjectID: seed.projectID, Labels: names, Sort: "number"})
+		if err != nil {
+			t.Fatalf("list %v: %v", names, err)
+		}
+		got := []string{}
+		for _, issue := range page.Issues {
+			got = append(got, issue.ID)
+		}
+		return got
+	}
+	if got := list("bug"); !slices.Equal(got, []string{both.ID, plain.ID}) {
+		t.Fatalf("list bug = %v", got)
+	}
+	if got := list("bug", "ui"); !slices.Equal(got, []string{both.ID}) {
+		t.Fatalf("list bug and ui = %v", got)
+	}
+
+	// Bulk label operations report issues that already match as unchanged.
+	results, err := Bulk(ctx, db, BulkParams{
+		ProjectID: seed.projectID, ActorID: seed.reporterID,
+		IssueIDs: []string{both.ID, plain.ID}, Operation: BulkRemoveLabel, LabelID: uiID,
+	})
+	if err != nil || results[0].Status != BulkUpdated || results[1].Status != BulkUnchanged {
+		t.Fatalf("remove_label = %+v, %v", results, err)
+	}
+	if got := list("ui"); len(got) != 0 {
+		t.Fatalf("list ui after remove_label = %v", got)
+	}
+	if _, err := Bulk(ctx, db, BulkParams{
+		ProjectID: seed.projectID, ActorID: seed.reporterID,
+		IssueIDs: []string{plain.ID}, Operation: BulkAddLabel, LabelID: foreignID,
+	}); !errors.Is(err, labels.ErrInvalidLabel) {
+		t.Fatalf("add_label with another workspace's label: error = %v, want ErrInvalidLabel", err)
+	}
+}
+
+func TestIssueWatchers(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	seed := seedProject(t, db)
+	assigneeID := testpg.SeedUser(t, db)
+	for _, id := range []string{seed.reporterID, assigneeID} {
+		if _, err := db.ExecContext(ctx,
+			`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'member')`,
+			seed.workspaceID, id,
+		); err != nil {
+			t.Fatalf("insert workspace member: %v", err)
+		}
+	}
+	inbox := func(userID string) []notifications.Notification {
+		t.Helper()
+		list, err := notifications.List(ctx, db, notifications.ListParams{UserID: userID})
+		if err != nil {
+			t.Fatalf("List notifications: %v", err)
+		}
+		return list
+	}
+
+	issue, err := Create(ctx, db, CreateParams{
+		ProjectID: seed.projectID, IssueTypeID: seed.issueTypeID, StatusID: seed.statusTodoID,
+		Title: "Watched", ReporterID: seed.reporterID, AssigneeID: assigneeID, Priority: "low",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	watchers, err := notifications.ListWatchers(ctx, db, seed.projectID, issue.ID)
+	if err != nil {
+		t.Fatalf("ListWatchers: %v", err)
+	}
+	if len(watchers) != 2 {
+		t.Fatalf("watchers = %+v, want reporter and assignee", watchers)
+	}
+	if got := inbox(seed.reporterID); len(got) != 0 {
+		t.Fatalf("reporter notified of their own change: %+v", got)
+	}
+	if got := inbox(assigneeID); len(got) != 1 || got[0].Type != EventCreated {
+		t.Fatalf("assignee notifications = %+v, want one created", got)
+	}
+
+	if _, err := Update(ctx, db, UpdateParams{IssueID: issue.ID, ProjectID: seed.projectID, ActorID: assigneeID, Title: "Renamed", Priority: "low", AssigneeID: &assigneeID}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got := inbox(seed.reporterID)
+	if len(got) != 1 || got[0].Type != EventUpdated || got[0].IssueTitle != "Renamed" || got[0].ActorID == nil || *got[0].ActorID != assigneeID {
+		t.Fatalf("reporter notifications = %+v, want one updated by the assignee", got)
+	}
+	var data struct {
+		Changes map[string]FieldChange `json:"changes"`
+	}
+	if err := json.Unmarshal(got[0].Data, &data); err != nil || data.Changes["title"].To != "Renamed" {
+		t.Fatalf("notification data = %s, %v", got[0].Data, err)
+	}
+
+	if err := notifications.Unwatch(ctx, db, seed.projectID, issue.ID, seed.reporterID); err != nil {
+		t.Fatalf("Unwatch: %v", err)
+	}
+	if err := Move(ctx, db, MoveParams{ProjectID: seed.projectID, IssueID: issue.ID, ActorID: assigneeID, TargetStatusID: seed.statusDoingID}); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if got := inbox(seed.reporterID); len(got) != 1 {
+		t.Fatalf("unwatched reporter notifications = %d, want still 1", len(got))
+	}
+}
+
+func TestMoveIssue_Workflow(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	seed := seedProject(t, db)
+
+	var doneID, bugTypeID string
+	if err := db.GetContext(ctx, &doneID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'Hecho', 'done', 2) RETURNING id`, seed.projectID); err != nil {
+		t.Fatalf("insert done status: %v", err)
+	}
+	if err := db.GetContext(ctx, &bugTypeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Bug', 1) RETURNING id`, seed.projectID); err != nil {
+		t.Fatalf("insert bug type: %v", err)
+	}
+	task := insertIssue(t, db, seed, issueSeed{number: 1, title: "Task", statusID: seed.statusTodoID, statusPosition: 0})
+	bug := insertIssue(t, db, seed, issueSeed{number: 2, title: "Bug", statusID: seed.statusTodoID, statusPosition: 1})
+	db.MustExec(`UPDATE issues SET issue_type_id = $1 WHERE id = $2`, bugTypeID, bug)
+
+	move := func(issueID, statusID string, admin bool) error {
+		return Move(ctx, db, MoveParams{ProjectID: seed.projectID, IssueID: issueID, ActorID: seed.reporterID, TargetStatusID: statusID, ActorIsAdmin: admin})
+	}
+
+	// Without a graph every move is allowed.
+	if err := move(task, doneID, false); err != nil {
+		t.Fatalf("free move: %v", err)
+	}
+	if err := move(task, seed.statusTodoID, false); err != nil {
+		t.Fatalf("free move back: %v", err)
+	}
+
+	if _, err := statuses.ReplaceTransitions(ctx, db, statuses.ReplaceTransitionsParams{
+		ProjectID: seed.projectID,
+		Transitions: []statuses.TransitionRule{
+			{FromStatusID: seed.statusTodoID, ToStatusID: seed.statusDoingID, RequireAssignee: true},
+			{FromStatusID: seed.statusDoingID, ToStatusID: doneID, AdminOnly: true},
+		},
+	}); err != nil {
+		t.Fatalf("replace project transitions: %v", err)
+	}
+	if _, err := statuses.ReplaceTransitions(ctx, db, statuses.ReplaceTransitionsParams{
+		ProjectID:   seed.projectID,
+		IssueTypeID: bugTypeID,
+		Transitions: []statuses.TransitionRule{{FromStatusID: seed.statusTodoID, ToStatusID: doneID}},
+	}); err != nil {
+		t.Fatalf("replace bug transitions: %v", err)
+	}
+
+	var terr *statuses.TransitionError
+	if err := move(task, doneID, true); !errors.As(err, &terr) || terr.Reason != "is not allowed by the workflow" {
+		t.Fatalf("skip doing: error = %v, want TransitionError", err)
+	}
+	if err := move(task, seed.statusDoingID, false); !errors.As(err, &terr) || terr.Reason != "requires an assignee" {
+		t.Fatalf("unassigned to doing: error = %v, want TransitionError", err)
+	}
+	db.MustExec(`UPDATE issues SET assignee_id = $1 WHERE id = $2`, seed.reporterID, task)
+	if err := move(task, seed.statusDoingID, false); err != nil {
+		t.Fatalf("assigned to doing: %v", err)
+	}
+	if err := move(task, doneID, false); !errors.Is(err, statuses.ErrTransitionNotAllowed) {
+		t.Fatalf("member to done: error = %v, want ErrTransitionNotAllowed", err)
+	}
+	if err := move(task, doneID, true); err != nil {
+		t.Fatalf("admin to done: %v", err)
+	}
+
+	// Bugs follow their own graph instead of the project graph.
+	if err := move(bug, doneID, false); err != nil {
+		t.Fatalf("bug to done: %v", err)
+	}
+}
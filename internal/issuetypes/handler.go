@@ -43,12 +43,7 @@ func fail(w http.ResponseWriter, err error) {
 func handleCreate(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		projID := r.PathValue("projectID")
-		wsID, err := authz.RequireProjectMembership(r.Context(), db, projID)
-		if err != nil {
-			fail(w, err)
-			return
-		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -99,12 +94,7 @@ func handleList(db *sqlx.DB) http.HandlerFunc {
 func handleArchive(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		projID := r.PathValue("projectID")
-		wsID, err := authz.RequireProjectMembership(r.Context(), db, projID)
-		if err != nil {
-			fail(w, err)
-			return
-		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -59,6 +59,17 @@ func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Type, error)
 	return createIssueType(ctx, db, params)
 }
 
+// CreateTx creates an issue type inside the caller's transaction.
+func CreateTx(ctx context.Context, tx *sqlx.Tx, params CreateParams) (Type, error) {
+	if tx == nil {
+		return Type{}, errors.New("tx is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Type{}, err
+	}
+	return createIssueType(ctx, tx, params)
+}
+
 func List(ctx context.Context, db *sqlx.DB, projectID string) ([]Type, error) {
 	if db == nil {
 		return nil, errors.New("db is required")
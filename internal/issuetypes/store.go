@@ -15,9 +15,9 @@ import (
 
 const issueTypeCols = `id, project_id, name, icon, level, created_at, updated_at, archived_at`
 
-func createIssueType(ctx context.Context, db *sqlx.DB, params CreateParams) (Type, error) {
+func createIssueType(ctx context.Context, q sqlx.QueryerContext, params CreateParams) (Type, error) {
 	var issueType Type
-	err := db.QueryRowxContext(ctx,
+	err := q.QueryRowxContext(ctx,
 		`INSERT INTO issue_types (project_id, name, icon, level)
 		 VALUES ($1, $2, $3, $4)
 		 RETURNING `+issueTypeCols,
@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package labels
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("POST /projects/{projectID}/labels", handleCreate(db, projectScope))
+	mux.HandleFunc("GET /projects/{projectID}/labels", handleList(db, projectScope))
+	mux.HandleFunc("PUT /projects/{projectID}/labels/{labelID}", handleUpdate(db, projectScope))
+	mux.HandleFunc("DELETE /projects/{projectID}/labels/{labelID}", handleDelete(db, projectScope))
+	mux.HandleFunc("POST /workspaces/{workspaceID}/labels", handleCreate(db, workspaceScope))
+	mux.HandleFunc("GET /workspaces/{workspaceID}/labels", handleList(db, workspaceScope))
+	mux.HandleFunc("PUT /workspaces/{workspaceID}/labels/{labelID}", handleUpdate(db, workspaceScope))
+	mux.HandleFunc("DELETE /workspaces/{workspaceID}/labels/{labelID}", handleDelete(db, workspaceScope))
+}
+
+// scopeFunc authorizes a request against the label scope in its path and
+// returns the scope's workspace and project IDs. manage asks for the admin
+// role; otherwise membership is enough.
+type scopeFunc func(ctx context.Context, db *sqlx.DB, r *http.Request, manage bool) (workspaceID, projectID string, err error)
+
+func projectScope(ctx context.Context, db *sqlx.DB, r *http.Request, manage bool) (string, string, error) {
+	projID := r.PathValue("projectID")
+	var (
+		wsID string
+		err  error
+	)
+	if manage {
+		wsID, err = authz.RequireProjectRole(ctx, db, projID, authz.RoleAdmin)
+	} else {
+		wsID, err = authz.RequireProjectMembership(ctx, db, projID)
+	}
+	return wsID, projID, err
+}
+
+func workspaceScope(ctx context.Context, db *sqlx.DB, r *http.Request, manage bool) (string, string, error) {
+	wsID := r.PathValue("workspaceID")
+	var err error
+	if manage {
+		err = authz.RequireWorkspaceAdmin(ctx, db, wsID)
+	} else {
+		err = authz.RequireWorkspaceMembership(ctx, db, wsID)
+	}
+	return wsID, "", err
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound),
+		errors.Is(err, authz.ErrProjectNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrDuplicate):
+		respond.Error(w, http.StatusConflict, err.Error())
+	default:
+		slog.Error("labels handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+type labelBody struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func handleCreate(db *sqlx.DB, scope scopeFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID, projID, err := scope(r.Context(), db, r, true)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body labelBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := CreateParams{
+			WorkspaceID: wsID,
+			ProjectID:   projID,
+			Name:        body.Name,
+			Color:       body.Color,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		label, err := Create(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusCreated, label)
+	}
+}
+
+func handleList(db *sqlx.DB, scope scopeFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID, projID, err := scope(r.Context(), db, r, false)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := List(r.Context(), db, wsID, projID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+func handleUpdate(db *sqlx.DB, scope scopeFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID, projID, err := scope(r.Context(), db, r, true)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body labelBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := UpdateParams{
+			WorkspaceID: wsID,
+			ProjectID:   projID,
+			LabelID:     r.PathValue("labelID"),
+			Name:        body.Name,
+			Color:       body.Color,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		label, err := Update(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, label)
+	}
+}
+
+func handleDelete(db *sqlx.DB, scope scopeFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID, projID, err := scope(r.Context(), db, r, true)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if err := Delete(r.Context(), db, wsID, projID, r.PathValue("labelID")); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package labels manages issue labels. A label belongs to a project, or to
+// a workspace when it has no project, in which case every project of the
+// workspace can use it. Names are unique per scope, ignoring case.
+package labels
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrNotFound     = errors.New("label not found")
+	ErrDuplicate    = errors.New("label name already exists in scope")
+	ErrInvalidColor = errors.New("color must be a hex color such as #1f6feb")
+	ErrInvalidLabel = errors.New("label_ids must reference labels available to the project")
+)
+
+const maxNameLen = 50
+
+var colorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Label is a project label, or a workspace label when ProjectID is nil.
+type Label struct {
+	ID          string    `db:"id"           json:"id"`
+	WorkspaceID string    `db:"workspace_id" json:"workspace_id"`
+	ProjectID   *string   `db:"project_id"   json:"project_id,omitempty"`
+	Name        string    `db:"name"         json:"name"`
+	Color       string    `db:"color"        json:"color"`
+	CreatedAt   time.Time `db:"created_at"   json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"   json:"updated_at"`
+}
+
+// CreateParams creates a workspace label when ProjectID is empty.
+type CreateParams struct {
+	WorkspaceID string
+	ProjectID   string
+	Name        string
+	Color       string
+}
+
+func (params CreateParams) Validate() error {
+	if params.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	return validate(params.Name, params.Color)
+}
+
+// UpdateParams renames or recolours a label of the scope given by
+// WorkspaceID and ProjectID.
+type UpdateParams struct {
+	WorkspaceID string
+	ProjectID   string
+	LabelID     string
+	Name        string
+	Color       string
+}
+
+func (params UpdateParams) Validate() error {
+	if params.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if params.LabelID == "" {
+		return errors.New("label_id is required")
+	}
+	return validate(params.Name, params.Color)
+}
+
+func validate(name, color string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > maxNameLen {
+		return errors.New("name must be at most 50 characters")
+	}
+	if !colorRe.MatchString(color) {
+		return ErrInvalidColor
+	}
+	return nil
+}
+
+// Change is the set of label IDs of an issue before and after a write,
+// each sorted.
+type Change struct {
+	From []string
+	To   []string
+}
+
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Label, error) {
+	if db == nil {
+		return Label{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Label{}, err
+	}
+	params.Name = strings.TrimSpace(params.Name)
+	params.Color = strings.ToLower(params.Color)
+	return createLabel(ctx, db, params)
+}
+
+// List returns the labels of a workspace, or, when projectID is set, the
+// labels usable in that project: its own and its workspace's.
+func List(ctx context.Context, db *sqlx.DB, workspaceID, projectID string) ([]Label, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if workspaceID == "" {
+		return nil, errors.New("workspace_id is required")
+	}
+	return listLabels(ctx, db, workspaceID, projectID)
+}
+
+func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Label, error) {
+	if db == nil {
+		return Label{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Label{}, err
+	}
+	params.Name = strings.TrimSpace(params.Name)
+	params.Color = strings.ToLower(params.Color)
+	return updateLabel(ctx, db, params)
+}
+
+// Delete removes a label and takes it off every issue.
+func Delete(ctx context.Context, db *sqlx.DB, workspaceID, projectID, labelID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if workspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if labelID == "" {
+		return errors.New("label_id is required")
+	}
+	return deleteLabel(ctx, db, workspaceID, projectID, labelID)
+}
+
+// Set replaces the labels of an issue inside the caller's transaction.
+// Every label must be available to the project, otherwise Set fails with
+// ErrInvalidLabel.
+func Set(ctx context.Context, tx *sqlx.Tx, projectID, issueID string, labelIDs []string) (Change, error) {
+	if tx == nil {
+		return Change{}, errors.New("tx is required")
+	}
+	if projectID == "" || issueID == "" {
+		return Change{}, errors.New("project_id and issue_id are required")
+	}
+	return setIssueLabels(ctx, tx, projectID, issueID, labelIDs)
+}
+
+// Load returns the labels of the given issues ordered by name, keyed by
+// issue ID. Issues without labels are absent from the map.
+func Load(ctx context.Context, q sqlx.QueryerContext, issueIDs []string) (map[string][]Label, error) {
+	if q == nil {
+		return nil, errors.New("db is required")
+	}
+	if len(issueIDs) == 0 {
+		return map[string][]Label{}, nil
+	}
+	return loadLabels(ctx, q, issueIDs)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package labels
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCreateParams_Validate(t *testing.T) {
+	valid := CreateParams{WorkspaceID: "w", ProjectID: "p", Name: "frontend", Color: "#1F6FEB"}
+
+	tests := []struct {
+		name    string
+		params  CreateParams
+		wantErr error
+	}{
+		{name: "valid", params: valid},
+		{name: "workspace label", params: func() CreateParams { c := valid; c.ProjectID = ""; return c }()},
+		{name: "missing workspace_id", params: func() CreateParams { c := valid; c.WorkspaceID = ""; return c }(), wantErr: errAny},
+		{name: "blank name", params: func() CreateParams { c := valid; c.Name = "  "; return c }(), wantErr: errAny},
+		{name: "long name", params: func() CreateParams { c := valid; c.Name = strings.Repeat("x", 51); return c }(), wantErr: errAny},
+		{name: "named color", params: func() CreateParams { c := valid; c.Color = "red"; return c }(), wantErr: ErrInvalidColor},
+		{name: "short hex", params: func() CreateParams { c := valid; c.Color = "#fff"; return c }(), wantErr: ErrInvalidColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("Validate() error = %v, want nil", err)
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("Validate() error = nil, want error")
+			case tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// errAny marks cases that expect some error without a sentinel.
+var errAny = errors.New("any error")
+
+func TestUpdateParams_Validate(t *testing.T) {
+	valid := UpdateParams{WorkspaceID: "w", LabelID: "l", Name: "bug", Color: "#d73a4a"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for _, p := range []UpdateParams{
+		{LabelID: "l", Name: "bug", Color: "#d73a4a"},
+		{WorkspaceID: "w", Name: "bug", Color: "#d73a4a"},
+		{WorkspaceID: "w", LabelID: "l", Color: "#d73a4a"},
+		{WorkspaceID: "w", LabelID: "l", Name: "bug"},
+	} {
+		if err := p.Validate(); err == nil {
+			t.Fatalf("Validate(%+v) error = nil, want error", p)
+		}
+	}
+}
+
+func TestCreate_NilDB(t *testing.T) {
+	_, err := Create(context.Background(), nil, CreateParams{WorkspaceID: "w", Name: "bug", Color: "#d73a4a"})
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("Create() error = %v, want %q", err, "db is required")
+	}
+}
+
+func TestSet_NilTx(t *testing.T) {
+	_, err := Set(context.Background(), nil, "p", "i", nil)
+	if err == nil || err.Error() != "tx is required" {
+		t.Fatalf("Set() error = %v, want %q", err, "tx is required")
+	}
+}
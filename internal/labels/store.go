@@ -0,0 +1,181 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package labels
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+const labelCols = `id, workspace_id, project_id, name, color, created_at, updated_at`
+
+// scope matches the labels of one scope: the workspace labels when
+// projectID is empty, the project's own labels otherwise.
+const scope = `workspace_id = $1 AND project_id IS NOT DISTINCT FROM $2`
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func createLabel(ctx context.Context, db *sqlx.DB, params CreateParams) (Label, error) {
+	var label Label
+	err := db.QueryRowxContext(ctx,
+		`INSERT INTO labels (workspace_id, project_id, name, color)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+labelCols,
+		params.WorkspaceID, nullIfEmpty(params.ProjectID), params.Name, params.Color,
+	).StructScan(&label)
+	if err != nil {
+		if pgutil.IsUniqueViolation(err) {
+			return Label{}, ErrDuplicate
+		}
+		return Label{}, fmt.Errorf("create label: %w", err)
+	}
+	return label, nil
+}
+
+func listLabels(ctx context.Context, db *sqlx.DB, workspaceID, projectID string) ([]Label, error) {
+	list := []Label{}
+	if err := db.SelectContext(ctx, &list,
+		`SELECT `+labelCols+`
+		 FROM labels
+		 WHERE workspace_id = $1
+		   AND (project_id IS NULL OR project_id = $2)
+		 ORDER BY lower(name), project_id NULLS FIRST`,
+		workspaceID, nullIfEmpty(projectID),
+	); err != nil {
+		return nil, fmt.Errorf("list labels: %w", err)
+	}
+	return list, nil
+}
+
+func updateLabel(ctx context.Context, db *sqlx.DB, params UpdateParams) (Label, error) {
+	var label Label
+	err := db.QueryRowxContext(ctx,
+		`UPDATE labels
+		 SET name  = $3,
+		     color = $4
+		 WHERE `+scope+`
+		   AND id::text = $5
+		 RETURNING `+labelCols,
+		params.WorkspaceID, nullIfEmpty(params.ProjectID), params.Name, params.Color, params.LabelID,
+	).StructScan(&label)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Label{}, ErrNotFound
+		}
+		if pgutil.IsUniqueViolation(err) {
+			return Label{}, ErrDuplicate
+		}
+		return Label{}, fmt.Errorf("update label: %w", err)
+	}
+	return label, nil
+}
+
+func deleteLabel(ctx context.Context, db *sqlx.DB, workspaceID, projectID, labelID string) error {
+	res, err := db.ExecContext(ctx,
+		`DELETE FROM labels WHERE `+scope+` AND id::text = $3`,
+		workspaceID, nullIfEmpty(projectID), labelID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete label: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete label rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func setIssueLabels(ctx context.Context, tx *sqlx.Tx, projectID, issueID string, labelIDs []string) (Change, error) {
+	want := slices.Sorted(slices.Values(labelIDs))
+	want = slices.Compact(want)
+	if want == nil {
+		want = []string{}
+	}
+
+	if len(want) > 0 {
+		var available int
+		if err := tx.GetContext(ctx, &available,
+			`SELECT COUNT(*)
+			 FROM labels l
+			 JOIN projects p ON p.id = $1
+			 WHERE l.id::text = ANY($2)
+			   AND l.workspace_id = p.workspace_id
+			   AND (l.project_id IS NULL OR l.project_id = p.id)`,
+			projectID, pq.Array(want),
+		); err != nil {
+			return Change{}, fmt.Errorf("check labels: %w", err)
+		}
+		if available != len(want) {
+			return Change{}, ErrInvalidLabel
+		}
+	}
+
+	have := []string{}
+	if err := tx.SelectContext(ctx, &have,
+		`SELECT label_id::text FROM issue_labels WHERE issue_id = $1 ORDER BY label_id::text`,
+		issueID,
+	); err != nil {
+		return Change{}, fmt.Errorf("load issue labels: %w", err)
+	}
+	if slices.Equal(have, want) {
+		return Change{From: have, To: want}, nil
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM issue_labels WHERE issue_id = $1 AND NOT (label_id::text = ANY($2))`,
+		issueID, pq.Array(want),
+	); err != nil {
+		return Change{}, fmt.Errorf("remove issue labels: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO issue_labels (issue_id, label_id)
+		 SELECT $1, unnest($2::uuid[])
+		 ON CONFLICT DO NOTHING`,
+		issueID, pq.Array(want),
+	); err != nil {
+		return Change{}, fmt.Errorf("add issue labels: %w", err)
+	}
+	return Change{From: have, To: want}, nil
+}
+
+type issueLabel struct {
+	IssueID string `db:"issue_id"`
+	Label
+}
+
+func loadLabels(ctx context.Context, q sqlx.QueryerContext, issueIDs []string) (map[string][]Label, error) {
+	rows := []issueLabel{}
+	if err := sqlx.SelectContext(ctx, q, &rows,
+		`SELECT il.issue_id, l.id, l.workspace_id, l.project_id, l.name, l.color, l.created_at, l.updated_at
+		 FROM issue_labels il
+		 JOIN labels l ON l.id = il.label_id
+		 WHERE il.issue_id::text = ANY($1)
+		 ORDER BY lower(l.name), l.id`,
+		pq.Array(issueIDs),
+	); err != nil {
+		return nil, fmt.Errorf("load issue labels: %w", err)
+	}
+	out := map[string][]Label{}
+	for _, r := range rows {
+		out[r.IssueID] = append(out[r.IssueID], r.Label)
+	}
+	return out, nil
+}
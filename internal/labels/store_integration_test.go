@@ -0,0 +1,130 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package labels
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestLabels(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	projectID := testpg.SeedProject(t, db, wsID, "LBL")
+	otherProjectID := testpg.SeedProject(t, db, wsID, "LBO")
+
+	var typeID, statusID, issueID string
+	if err := db.GetContext(ctx, &typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert issue type: %v", err)
+	}
+	if err := db.GetContext(ctx, &statusID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	if err := db.GetContext(ctx, &issueID,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, 1, $2, $3, 'Issue', '', 'medium', $4, 0) RETURNING id`,
+		projectID, typeID, statusID, userID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+
+	bug, err := Create(ctx, db, CreateParams{WorkspaceID: wsID, ProjectID: projectID, Name: " Bug ", Color: "#D73A4A"})
+	if err != nil {
+		t.Fatalf("create project label: %v", err)
+	}
+	if bug.Name != "Bug" || bug.Color != "#d73a4a" || bug.ProjectID == nil {
+		t.Fatalf("created label = %+v", bug)
+	}
+	if _, err := Create(ctx, db, CreateParams{WorkspaceID: wsID, ProjectID: projectID, Name: "bug", Color: "#000000"}); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("duplicate project label: error = %v, want ErrDuplicate", err)
+	}
+	shared, err := Create(ctx, db, CreateParams{WorkspaceID: wsID, Name: "Bug", Color: "#0e8a16"})
+	if err != nil {
+		t.Fatalf("workspace label with a project label's name: %v", err)
+	}
+	if _, err := Create(ctx, db, CreateParams{WorkspaceID: wsID, Name: "BUG", Color: "#0e8a16"}); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("duplicate workspace label: error = %v, want ErrDuplicate", err)
+	}
+	foreign, err := Create(ctx, db, CreateParams{WorkspaceID: wsID, ProjectID: otherProjectID, Name: "Other", Color: "#fbca04"})
+	if err != nil {
+		t.Fatalf("create other project label: %v", err)
+	}
+
+	list, err := List(ctx, db, wsID, projectID)
+	if err != nil {
+		t.Fatalf("List project: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != shared.ID || list[1].ID != bug.ID {
+		t.Fatalf("List project = %+v, want workspace then project Bug", list)
+	}
+	wsList, err := List(ctx, db, wsID, "")
+	if err != nil {
+		t.Fatalf("List workspace: %v", err)
+	}
+	if len(wsList) != 1 || wsList[0].ID != shared.ID {
+		t.Fatalf("List workspace = %+v, want only the workspace label", wsList)
+	}
+
+	if _, err := Update(ctx, db, UpdateParams{WorkspaceID: wsID, LabelID: bug.ID, Name: "Defect", Color: "#d73a4a"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("update project label through workspace scope: error = %v, want ErrNotFound", err)
+	}
+	renamed, err := Update(ctx, db, UpdateParams{WorkspaceID: wsID, ProjectID: projectID, LabelID: bug.ID, Name: "Defect", Color: "#d73a4a"})
+	if err != nil || renamed.Name != "Defect" {
+		t.Fatalf("Update() = %+v, %v", renamed, err)
+	}
+
+	set := func(ids ...string) (Change, error) {
+		var change Change
+		err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit set labels", func(tx *sqlx.Tx) error {
+			var err error
+			change, err = Set(ctx, tx, projectID, issueID, ids)
+			return err
+		})
+		return change, err
+	}
+	if _, err := set(bug.ID, foreign.ID); !errors.Is(err, ErrInvalidLabel) {
+		t.Fatalf("Set with another project's label: error = %v, want ErrInvalidLabel", err)
+	}
+	change, err := set(shared.ID, bug.ID, bug.ID)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if want := slices.Sorted(slices.Values([]string{shared.ID, bug.ID})); len(change.From) != 0 || !slices.Equal(change.To, want) {
+		t.Fatalf("Set change = %+v, want from [] to %v", change, want)
+	}
+	loaded, err := Load(ctx, db, []string{issueID})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := loaded[issueID]; len(got) != 2 || got[0].Name != "Bug" || got[1].Name != "Defect" {
+		t.Fatalf("Load = %+v, want Bug and Defect", got)
+	}
+
+	if err := Delete(ctx, db, wsID, "", shared.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := Delete(ctx, db, wsID, "", shared.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete again: error = %v, want ErrNotFound", err)
+	}
+	loaded, err = Load(ctx, db, []string{issueID})
+	if err != nil {
+		t.Fatalf("Load after delete: %v", err)
+	}
+	if got := loaded[issueID]; len(got) != 1 || got[0].ID != bug.ID {
+		t.Fatalf("Load after delete = %+v, want only Defect", got)
+	}
+}
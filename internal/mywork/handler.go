@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package mywork
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("GET /me/issues", handleIssues(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	default:
+		slog.Error("mywork handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// handleIssues needs no membership check: the query only reaches the
+// workspaces the user belongs to.
+func handleIssues(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		params, err := parseParams(r.URL.Query())
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.UserID = authedUserID
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		groups, err := Issues(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, groups)
+	}
+}
+
+func parseParams(q url.Values) (Params, error) {
+	params := Params{
+		Relation:         q.Get("relation"),
+		StatusCategories: q["status_category"],
+		Priorities:       q["priority"],
+	}
+	bounds := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"due_before", &params.DueBefore},
+		{"due_after", &params.DueAfter},
+	}
+	for _, b := range bounds {
+		s := q.Get(b.name)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return Params{}, errors.New(b.name + " must be YYYY-MM-DD")
+		}
+		*b.dst = &t
+	}
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return Params{}, errors.New("limit must be an integer")
+		}
+		params.Limit = n
+	}
+	return params, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package mywork lists the issues assigned to or reported by a user across
+// every workspace and project they can access, grouped by project.
+package mywork
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Relations between the user and an issue.
+const (
+	RelationAssignee = "assignee"
+	RelationReporter = "reporter"
+)
+
+var validRelations = map[string]bool{RelationAssignee: true, RelationReporter: true}
+
+var validStatusCategories = map[string]bool{"todo": true, "doing": true, "done": true}
+
+var validPriorities = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+
+// Issue is an issue of the user with what a client needs to show it
+// without looking up its project or status.
+type Issue struct {
+	ID             string     `db:"id"              json:"id"`
+	ProjectID      string     `db:"project_id"      json:"project_id"`
+	ProjectKey     string     `db:"project_key"     json:"project_key"`
+	Number         int        `db:"number"          json:"number"`
+	Key            string     `db:"-"               json:"key"`
+	Title          string     `db:"title"           json:"title"`
+	Priority       string     `db:"priority"        json:"priority"`
+	StatusID       string     `db:"status_id"       json:"status_id"`
+	StatusName     string     `db:"status_name"     json:"status_name"`
+	StatusCategory string     `db:"status_category" json:"status_category"`
+	AssigneeID     *string    `db:"assignee_id"     json:"assignee_id,omitempty"`
+	ReporterID     string     `db:"reporter_id"     json:"reporter_id"`
+	DueDate        *time.Time `db:"due_date"        json:"due_date,omitempty"`
+	CreatedAt      time.Time  `db:"created_at"      json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"      json:"updated_at"`
+}
+
+// Group holds the issues of one project. Total counts every matching issue
+// of the project; Issues holds at most Params.Limit of them.
+type Group struct {
+	ProjectID     string  `json:"project_id"`
+	ProjectKey    string  `json:"project_key"`
+	ProjectName   string  `json:"project_name"`
+	WorkspaceID   string  `json:"workspace_id"`
+	WorkspaceName string  `json:"workspace_name"`
+	Total         int     `json:"total"`
+	Issues        []Issue `json:"issues"`
+}
+
+// Params selects the issues of UserID. Relation narrows them to those
+// assigned to or reported by the user; both are listed when it is empty.
+// Repeated StatusCategories and Priorities match any of their values. The
+// due date bounds are exclusive. Limit caps the issues of each project.
+type Params struct {
+	UserID           string
+	Relation         string
+	StatusCategories []string
+	Priorities       []string
+	DueBefore        *time.Time
+	DueAfter         *time.Time
+	Limit            int
+}
+
+func (params Params) Validate() error {
+	if params.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	if params.Relation != "" && !validRelations[params.Relation] {
+		return errors.New("relation must be one of assignee, reporter")
+	}
+	for _, c := range params.StatusCategories {
+		if !validStatusCategories[c] {
+			return errors.New("status_category must be one of todo, doing, done")
+		}
+	}
+	for _, p := range params.Priorities {
+		if !validPriorities[p] {
+			return errors.New("priority must be one of low, medium, high, critical")
+		}
+	}
+	if params.Limit < 0 {
+		return errors.New("limit must be >= 0")
+	}
+	if params.Limit > maxLimit {
+		return errors.New("limit must be <= 100")
+	}
+	return nil
+}
+
+// Issues returns the user's active issues in the active projects of the
+// workspaces they belong to, grouped by project and ordered by workspace
+// name then project key. Within a project, issues are listed by due date,
+// those without one last, then by priority, most urgent first. Projects
+// without a matching issue are left out.
+func Issues(ctx context.Context, db *sqlx.DB, params Params) ([]Group, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultLimit
+	}
+	return listIssues(ctx, db, params)
+}
+
+// group splits rows ordered by project into groups, keeping their order.
+func group(rows []row) []Group {
+	groups := []Group{}
+	for _, r := range rows {
+		if len(groups) == 0 || groups[len(groups)-1].ProjectID != r.ProjectID {
+			groups = append(groups, Group{
+				ProjectID:     r.ProjectID,
+				ProjectKey:    r.ProjectKey,
+				ProjectName:   r.ProjectName,
+				WorkspaceID:   r.WorkspaceID,
+				WorkspaceName: r.WorkspaceName,
+				Total:         r.Total,
+				Issues:        []Issue{},
+			})
+		}
+		issue := r.Issue
+		issue.Key = issue.ProjectKey + "-" + strconv.Itoa(issue.Number)
+		g := &groups[len(groups)-1]
+		g.Issues = append(g.Issues, issue)
+	}
+	return groups
+}
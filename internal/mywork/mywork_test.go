@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package mywork
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParams_Validate(t *testing.T) {
+	valid := Params{
+		UserID:           "u",
+		Relation:         RelationAssignee,
+		StatusCategories: []string{"todo", "doing"},
+		Priorities:       []string{"high", "critical"},
+		Limit:            100,
+	}
+
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr bool
+	}{
+		{name: "valid", params: valid, wantErr: false},
+		{name: "defaults", params: Params{UserID: "u"}, wantErr: false},
+		{name: "reporter", params: func() Params { c := valid; c.Relation = RelationReporter; return c }(), wantErr: false},
+		{name: "missing user_id", params: func() Params { c := valid; c.UserID = ""; return c }(), wantErr: true},
+		{name: "bad relation", params: func() Params { c := valid; c.Relation = "watcher"; return c }(), wantErr: true},
+		{name: "bad status category", params: func() Params { c := valid; c.StatusCategories = []string{"blocked"}; return c }(), wantErr: true},
+		{name: "bad priority", params: func() Params { c := valid; c.Priorities = []string{"urgent"}; return c }(), wantErr: true},
+		{name: "negative limit", params: func() Params { c := valid; c.Limit = -1; return c }(), wantErr: true},
+		{name: "limit too high", params: func() Params { c := valid; c.Limit = 101; return c }(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	q, _ := url.ParseQuery("relation=reporter&status_category=todo&status_category=doing&priority=high&due_before=2025-06-09&limit=5")
+	params, err := parseParams(q)
+	if err != nil {
+		t.Fatalf("parseParams: %v", err)
+	}
+	due := time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC)
+	if params.Relation != RelationReporter || len(params.StatusCategories) != 2 || len(params.Priorities) != 1 ||
+		params.DueBefore == nil || !params.DueBefore.Equal(due) || params.DueAfter != nil || params.Limit != 5 {
+		t.Fatalf("parseParams() = %+v", params)
+	}
+
+	for _, raw := range []string{"due_after=soon", "due_before=2025-06-09T00:00:00Z", "limit=ten"} {
+		q, _ := url.ParseQuery(raw)
+		if _, err := parseParams(q); err == nil {
+			t.Errorf("parseParams(%q): expected error", raw)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	groups := group([]row{
+		{Issue: Issue{ID: "1", ProjectID: "a", ProjectKey: "APP", Number: 7}, ProjectName: "App", Total: 3},
+		{Issue: Issue{ID: "2", ProjectID: "a", ProjectKey: "APP", Number: 2}, ProjectName: "App", Total: 3},
+		{Issue: Issue{ID: "3", ProjectID: "b", ProjectKey: "OPS", Number: 1}, ProjectName: "Ops", Total: 1},
+	})
+	if len(groups) != 2 {
+		t.Fatalf("group() = %+v, want two groups", groups)
+	}
+	if g := groups[0]; g.ProjectKey != "APP" || g.Total != 3 || len(g.Issues) != 2 || g.Issues[0].Key != "APP-7" || g.Issues[1].ID != "2" {
+		t.Fatalf("first group = %+v", g)
+	}
+	if g := groups[1]; g.ProjectName != "Ops" || len(g.Issues) != 1 || g.Issues[0].Key != "OPS-1" {
+		t.Fatalf("second group = %+v", g)
+	}
+	if empty := group(nil); empty == nil || len(empty) != 0 {
+		t.Fatalf("group(nil) = %v, want an empty list", empty)
+	}
+}
+
+func TestIssues_NilDB(t *testing.T) {
+	_, err := Issues(context.Background(), nil, Params{UserID: "u"})
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("Issues() error = %v, want %q", err, "db is required")
+	}
+}
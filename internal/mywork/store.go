@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package mywork
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// row is an issue with its project, workspace and project total.
+type row struct {
+	Issue
+	ProjectName   string `db:"project_name"`
+	WorkspaceID   string `db:"workspace_id"`
+	WorkspaceName string `db:"workspace_name"`
+	Total         int    `db:"total"`
+}
+
+const priorityRank = `CASE i.priority WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 ELSE 3 END`
+
+func listIssues(ctx context.Context, db *sqlx.DB, params Params) ([]Group, error) {
+	args := []any{params.UserID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	var where []string
+	switch params.Relation {
+	case RelationAssignee:
+		where = append(where, "i.assignee_id = $1")
+	case RelationReporter:
+		where = append(where, "i.reporter_id = $1")
+	default:
+		where = append(where, "(i.assignee_id = $1 OR i.reporter_id = $1)")
+	}
+	if len(params.StatusCategories) > 0 {
+		where = append(where, "s.category = ANY("+arg(pq.Array(params.StatusCategories))+")")
+	}
+	if len(params.Priorities) > 0 {
+		where = append(where, "i.priority = ANY("+arg(pq.Array(params.Priorities))+")")
+	}
+	if params.DueBefore != nil {
+		where = append(where, "i.due_date < "+arg(*params.DueBefore)+"::date")
+	}
+	if params.DueAfter != nil {
+		where = append(where, "i.due_date > "+arg(*params.DueAfter)+"::date")
+	}
+	limit := arg(params.Limit)
+
+	var rows []row
+	if err := db.SelectContext(ctx, &rows,
+		`WITH mine AS (
+		     SELECT i.id, i.project_id, p.key AS project_key, i.number, i.title, i.priority,
+		            i.status_id, s.name AS status_name, s.category AS status_category,
+		            i.assignee_id, i.reporter_id, i.due_date, i.created_at, i.updated_at,
+		            p.name AS project_name, w.id AS workspace_id, w.name AS workspace_name,
+		            COUNT(*) OVER (PARTITION BY i.project_id) AS total,
+		            ROW_NUMBER() OVER (
+		                PARTITION BY i.project_id
+		                ORDER BY i.due_date NULLS LAST, `+priorityRank+`, i.number
+		            ) AS rn
+		     FROM issues i
+		     JOIN statuses s ON s.id = i.status_id
+		     JOIN projects p ON p.id = i.project_id
+		     JOIN workspaces w ON w.id = p.workspace_id
+		     JOIN workspace_members wm ON wm.workspace_id = w.id
+		     WHERE wm.user_id = $1
+		       AND wm.archived_at IS NULL
+		       AND w.archived_at IS NULL
+		       AND p.archived_at IS NULL
+		       AND i.archived_at IS NULL
+		       AND `+strings.Join(where, " AND ")+`
+		 )
+		 SELECT id, project_id, project_key, number, title, priority,
+		        status_id, status_name, status_category,
+		        assignee_id, reporter_id, due_date, created_at, updated_at,
+		        project_name, workspace_id, workspace_name, total
+		 FROM mine
+		 WHERE rn <= `+limit+`
+		 ORDER BY lower(workspace_name), workspace_id, project_key, project_id, rn`,
+		args...,
+	); err != nil {
+		return nil, fmt.Errorf("list my issues: %w", err)
+	}
+	return group(rows), nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package mywork
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestMyIssues(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	alice := testpg.SeedUser(t, db)
+	bob := testpg.SeedUser(t, db)
+	first := testpg.SeedWorkspace(t, db)
+	second := testpg.SeedWorkspace(t, db)
+	left := testpg.SeedWorkspace(t, db)
+	db.MustExec(`UPDATE workspaces SET name = 'A ' || name WHERE id = $1`, first)
+	db.MustExec(`UPDATE workspaces SET name = 'B ' || name WHERE id = $1`, second)
+	for _, wsID := range []string{first, second, left} {
+		db.MustExec(`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'member')`, wsID, alice)
+	}
+	db.MustExec(`UPDATE workspace_members SET archived_at = now() WHERE workspace_id = $1`, left)
+
+	type project struct{ id, typeID, todoID, doneID string }
+	seed := func(wsID, key string) project {
+		t.Helper()
+		p := project{id: testpg.SeedProject(t, db, wsID, key)}
+		if err := db.GetContext(ctx, &p.typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, p.id); err != nil {
+			t.Fatalf("insert issue_type: %v", err)
+		}
+		if err := db.GetContext(ctx, &p.todoID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'Backlog', 'todo', 0) RETURNING id`, p.id); err != nil {
+			t.Fatalf("insert status: %v", err)
+		}
+		if err := db.GetContext(ctx, &p.doneID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'Shipped', 'done', 1) RETURNING id`, p.id); err != nil {
+			t.Fatalf("insert status: %v", err)
+		}
+		return p
+	}
+	web, api, old := seed(first, "MWB"), seed(first, "MWA"), seed(first, "MWO")
+	ops := seed(second, "MWX")
+	gone := seed(left, "MWL")
+	db.MustExec(`UPDATE projects SET archived_at = now() WHERE id = $1`, old.id)
+
+	due := func(d int) *time.Time { t := time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC); return &t }
+	create := func(p project, statusID, priority, assigneeID, reporterID string, dueDate *time.Time) issues.Issue {
+		t.Helper()
+		issue, err := issues.Create(ctx, db, issues.CreateParams{
+			ProjectID: p.id, IssueTypeID: p.typeID, StatusID: statusID, Title: "Work",
+			Priority: priority, AssigneeID: assigneeID, ReporterID: reporterID, DueDate: dueDate,
+		})
+		if err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+		return issue
+	}
+	apiLow := create(api, api.todoID, "low", alice, bob, due(3))
+	apiCritical := create(api, api.todoID, "critical", alice, bob, due(3))
+	apiUndated := create(api, api.todoID, "critical", alice, bob, nil)
+	webReported := create(web, web.doneID, "medium", bob, alice, due(20))
+	opsAssigned := create(ops, ops.todoID, "high", alice, alice, due(1))
+	create(web, web.todoID, "high", bob, bob, due(2))
+	create(old, old.todoID, "high", alice, alice, nil)
+	create(gone, gone.todoID, "high", alice, alice, nil)
+	archived := create(api, api.todoID, "high", alice, alice, nil)
+	if err := issues.Archive(ctx, db, api.id, archived.ID, alice); err != nil {
+		t.Fatalf("archive issue: %v", err)
+	}
+
+	list := func(params Params) []Group {
+		t.Helper()
+		params.UserID = alice
+		groups, err := Issues(ctx, db, params)
+		if err != nil {
+			t.Fatalf("Issues() error = %v", err)
+		}
+		return groups
+	}
+	ids := func(g Group) []string {
+		out := []string{}
+		for _, issue := range g.Issues {
+			out = append(out, issue.ID)
+		}
+		return out
+	}
+
+	groups := list(Params{})
+	if len(groups) != 3 || groups[0].ProjectKey != "MWA" || groups[1].ProjectKey != "MWB" || groups[2].ProjectKey != "MWX" {
+		t.Fatalf("Issues() = %+v, want MWA, MWB then MWX", groups)
+	}
+	if got, want := ids(groups[0]), []string{apiCritical.ID, apiLow.ID, apiUndated.ID}; len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("MWA issues = %v, want %v", got, want)
+	}
+	issue := groups[1].Issues[0]
+	if issue.ID != webReported.ID || issue.Key != "MWB-1" || issue.StatusName != "Shipped" || issue.StatusCategory != "done" {
+		t.Fatalf("MWB issue = %+v", issue)
+	}
+	if g := groups[2]; g.WorkspaceID != second || g.ProjectName != "Project MWX" || g.Total != 1 || g.Issues[0].ID != opsAssigned.ID {
+		t.Fatalf("MWX group = %+v", g)
+	}
+
+	groups = list(Params{Limit: 1})
+	if len(groups[0].Issues) != 1 || groups[0].Total != 3 {
+		t.Fatalf("limited MWA group = %+v, want one issue of three", groups[0])
+	}
+
+	groups = list(Params{Relation: RelationReporter})
+	if len(groups) != 2 || groups[0].Issues[0].ID != webReported.ID || groups[1].Issues[0].ID != opsAssigned.ID {
+		t.Fatalf("reported issues = %+v", groups)
+	}
+	groups = list(Params{Relation: RelationAssignee, StatusCategories: []string{"todo"}, Priorities: []string{"critical", "high"}})
+	if len(groups) != 2 || groups[0].Total != 2 || groups[1].Total != 1 {
+		t.Fatalf("open urgent issues = %+v", groups)
+	}
+	groups = list(Params{DueAfter: due(1), DueBefore: due(20)})
+	if len(groups) != 1 || groups[0].Total != 2 {
+		t.Fatalf("issues due between 2 and 19 June = %+v, want the two dated MWA issues", groups)
+	}
+
+	other, err := Issues(ctx, db, Params{UserID: testpg.SeedUser(t, db)})
+	if err != nil || len(other) != 0 {
+		t.Fatalf("Issues() for a user without workspaces = %v, %v, want none", other, err)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package notifications keeps issue watchers and turns issue changes into
+// per-user in-app notifications. Producers call AutoWatch and Notify inside
+// the transaction that makes the change; every watcher except the actor who
+// can still see the issue's workspace gets an unread notification.
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+var (
+	ErrNotFound      = errors.New("notification not found")
+	ErrIssueNotFound = errors.New("issue not found")
+)
+
+// Notification tells a user about one change to an issue they watch. Type
+// is the issue event type (created, updated, moved, archived, commented)
+// and Data its details: the changed fields or the comment.
+type Notification struct {
+	ID         string          `db:"id"          json:"id"`
+	IssueID    string          `db:"issue_id"    json:"issue_id"`
+	ProjectID  string          `db:"project_id"  json:"project_id"`
+	IssueKey   string          `db:"issue_key"   json:"issue_key"`
+	IssueTitle string          `db:"issue_title" json:"issue_title"`
+	ActorID    *string         `db:"actor_id"    json:"actor_id"`
+	ActorName  *string         `db:"actor_name"  json:"actor_name"`
+	Type       string          `db:"event_type"  json:"type"`
+	Data       json.RawMessage `db:"data"        json:"data"`
+	ReadAt     *time.Time      `db:"read_at"     json:"read_at"`
+	CreatedAt  time.Time       `db:"created_at"  json:"created_at"`
+}
+
+// Watcher is a user watching an issue.
+type Watcher struct {
+	UserID    string    `db:"user_id"    json:"user_id"`
+	Name      string    `db:"name"       json:"name"`
+	Email     string    `db:"email"      json:"email"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Event is an issue change to notify watchers about. Data is marshalled as
+// the notification's data.
+type Event struct {
+	IssueID string
+	ActorID string
+	Type    string
+	Data    any
+}
+
+// AutoWatch adds users as watchers of an issue inside the caller's
+// transaction. Empty IDs and existing watchers are skipped.
+func AutoWatch(ctx context.Context, tx *sqlx.Tx, issueID string, userIDs ...string) error {
+	if tx == nil {
+		return errors.New("tx is required")
+	}
+	if issueID == "" {
+		return errors.New("issue_id is required")
+	}
+	ids := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return insertWatchers(ctx, tx, issueID, ids)
+}
+
+// Notify records an unread notification of e for every watcher of the
+// issue other than the actor, inside the caller's transaction.
+func Notify(ctx context.Context, tx *sqlx.Tx, e Event) error {
+	if tx == nil {
+		return errors.New("tx is required")
+	}
+	if e.IssueID == "" || e.Type == "" {
+		return errors.New("issue_id and type are required")
+	}
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	if e.Data == nil {
+		data = []byte("{}")
+	}
+	return insertNotifications(ctx, tx, e, data)
+}
+
+// Watch adds userID as a watcher of an active issue of the project.
+// Watching twice is not an error.
+func Watch(ctx context.Context, db *sqlx.DB, projectID, issueID, userID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if projectID == "" || issueID == "" || userID == "" {
+		return errors.New("project_id, issue_id and user_id are required")
+	}
+	return watch(ctx, db, projectID, issueID, userID)
+}
+
+// Unwatch removes userID from the watchers of an issue of the project.
+// Unwatching an issue that is not watched is not an error.
+func Unwatch(ctx context.Context, db *sqlx.DB, projectID, issueID, userID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if projectID == "" || issueID == "" || userID == "" {
+		return errors.New("project_id, issue_id and user_id are required")
+	}
+	return unwatch(ctx, db, projectID, issueID, userID)
+}
+
+// ListWatchers returns the watchers of an issue, in the order they started
+// watching.
+func ListWatchers(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Watcher, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" || issueID == "" {
+		return nil, errors.New("project_id and issue_id are required")
+	}
+	return listWatchers(ctx, db, projectID, issueID)
+}
+
+// ListParams selects a page of a user's notifications.
+type ListParams struct {
+	UserID     string
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+func (params ListParams) Validate() error {
+	if params.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	if params.Limit < 0 || params.Limit > maxLimit {
+		return errors.New("limit must be between 0 and 200")
+	}
+	if params.Offset < 0 {
+		return errors.New("offset must be >= 0")
+	}
+	return nil
+}
+
+// List returns a user's notifications, newest first. Notifications about
+// issues in workspaces the user has left are omitted.
+func List(ctx context.Context, db *sqlx.DB, params ListParams) ([]Notification, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultLimit
+	}
+	return listNotifications(ctx, db, params)
+}
+
+// UnreadCount returns how many of List's notifications are unread.
+func UnreadCount(ctx context.Context, db *sqlx.DB, userID string) (int, error) {
+	if db == nil {
+		return 0, errors.New("db is required")
+	}
+	if userID == "" {
+		return 0, errors.New("user_id is required")
+	}
+	return unreadCount(ctx, db, userID)
+}
+
+// MarkRead marks one of the user's notifications read. Marking a read
+// notification again keeps its original read time.
+func MarkRead(ctx context.Context, db *sqlx.DB, userID, notificationID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if userID == "" || notificationID == "" {
+		return errors.New("user_id and notification_id are required")
+	}
+	return markRead(ctx, db, userID, notificationID)
+}
+
+// MarkAllRead marks every unread notification of the user read.
+func MarkAllRead(ctx context.Context, db *sqlx.DB, userID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if userID == "" {
+		return errors.New("user_id is required")
+	}
+	return markAllRead(ctx, db, userID)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package notifications
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+func TestListParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  ListParams
+		wantErr bool
+	}{
+		{name: "valid", params: ListParams{UserID: "u"}},
+		{name: "unread page", params: ListParams{UserID: "u", UnreadOnly: true, Limit: 200, Offset: 400}},
+		{name: "missing user_id", params: ListParams{}, wantErr: true},
+		{name: "negative limit", params: ListParams{UserID: "u", Limit: -1}, wantErr: true},
+		{name: "limit too large", params: ListParams{UserID: "u", Limit: 201}, wantErr: true},
+		{name: "negative offset", params: ListParams{UserID: "u", Offset: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseListParams(t *testing.T) {
+	params, err := parseListParams(url.Values{"unread": {"true"}, "limit": {"10"}, "offset": {"20"}})
+	if err != nil {
+		t.Fatalf("parseListParams: %v", err)
+	}
+	if !params.UnreadOnly || params.Limit != 10 || params.Offset != 20 {
+		t.Fatalf("params = %+v", params)
+	}
+	for _, q := range []url.Values{{"unread": {"maybe"}}, {"limit": {"ten"}}, {"offset": {"x"}}} {
+		if _, err := parseListParams(q); err == nil {
+			t.Errorf("parseListParams(%v): want error", q)
+		}
+	}
+}
+
+func TestNilDB(t *testing.T) {
+	ctx := context.Background()
+	if err := AutoWatch(ctx, nil, "i", "u"); err == nil {
+		t.Error("AutoWatch: want error for nil tx")
+	}
+	if err := Notify(ctx, nil, Event{IssueID: "i", Type: "updated"}); err == nil {
+		t.Error("Notify: want error for nil tx")
+	}
+	if err := Watch(ctx, nil, "p", "i", "u"); err == nil {
+		t.Error("Watch: want error for nil db")
+	}
+	if err := Unwatch(ctx, nil, "p", "i", "u"); err == nil {
+		t.Error("Unwatch: want error for nil db")
+	}
+	if _, err := ListWatchers(ctx, nil, "p", "i"); err == nil {
+		t.Error("ListWatchers: want error for nil db")
+	}
+	if _, err := List(ctx, nil, ListParams{UserID: "u"}); err == nil {
+		t.Error("List: want error for nil db")
+	}
+	if _, err := UnreadCount(ctx, nil, "u"); err == nil {
+		t.Error("UnreadCount: want error for nil db")
+	}
+	if err := MarkRead(ctx, nil, "u", "n"); err == nil {
+		t.Error("MarkRead: want error for nil db")
+	}
+	if err := MarkAllRead(ctx, nil, "u"); err == nil {
+		t.Error("MarkAllRead: want error for nil db")
+	}
+}
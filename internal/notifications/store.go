@@ -0,0 +1,191 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// visible joins notifications (alias n) to their issue and project and keeps
+// those whose recipient is still an active member of the project's workspace.
+const visible = `JOIN issues i ON i.id = n.issue_id
+	 JOIN projects p ON p.id = i.project_id
+	 JOIN workspace_members wm ON wm.workspace_id = p.workspace_id
+	  AND wm.user_id = n.user_id
+	  AND wm.archived_at IS NULL`
+
+func insertWatchers(ctx context.Context, tx *sqlx.Tx, issueID string, userIDs []string) error {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO issue_watchers (issue_id, user_id)
+		 SELECT $1, unnest($2::uuid[])
+		 ON CONFLICT DO NOTHING`,
+		issueID, pq.Array(userIDs),
+	); err != nil {
+		return fmt.Errorf("insert issue watchers: %w", err)
+	}
+	return nil
+}
+
+func insertNotifications(ctx context.Context, tx *sqlx.Tx, e Event, data []byte) error {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO notifications (user_id, issue_id, actor_id, event_type, data)
+		 SELECT w.user_id, w.issue_id, NULLIF($2, '')::uuid, $3, $4
+		 FROM issue_watchers w
+		 JOIN issues i ON i.id = w.issue_id
+		 JOIN projects p ON p.id = i.project_id
+		 JOIN workspace_members wm ON wm.workspace_id = p.workspace_id
+		  AND wm.user_id = w.user_id
+		  AND wm.archived_at IS NULL
+		 JOIN app_users u ON u.id = w.user_id AND u.archived_at IS NULL
+		 WHERE w.issue_id = $1
+		   AND w.user_id IS DISTINCT FROM NULLIF($2, '')::uuid`,
+		e.IssueID, e.ActorID, e.Type, data,
+	); err != nil {
+		return fmt.Errorf("insert notifications: %w", err)
+	}
+	return nil
+}
+
+func checkIssue(ctx context.Context, db *sqlx.DB, projectID, issueID string) error {
+	var exists bool
+	if err := db.GetContext(ctx, &exists,
+		`SELECT EXISTS(SELECT 1 FROM issues WHERE id = $1 AND project_id = $2 AND archived_at IS NULL)`,
+		issueID, projectID,
+	); err != nil {
+		return fmt.Errorf("check issue: %w", err)
+	}
+	if !exists {
+		return ErrIssueNotFound
+	}
+	return nil
+}
+
+func watch(ctx context.Context, db *sqlx.DB, projectID, issueID, userID string) error {
+	res, err := db.ExecContext(ctx,
+		`INSERT INTO issue_watchers (issue_id, user_id)
+		 SELECT id, $3
+		 FROM issues
+		 WHERE id = $1
+		   AND project_id = $2
+		   AND archived_at IS NULL
+		 ON CONFLICT DO NOTHING`,
+		issueID, projectID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("watch issue: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		// Either already watching or no such issue.
+		return checkIssue(ctx, db, projectID, issueID)
+	}
+	return nil
+}
+
+func unwatch(ctx context.Context, db *sqlx.DB, projectID, issueID, userID string) error {
+	res, err := db.ExecContext(ctx,
+		`DELETE FROM issue_watchers w
+		 USING issues i
+		 WHERE i.id = w.issue_id
+		   AND i.project_id = $2
+		   AND w.issue_id = $1
+		   AND w.user_id = $3`,
+		issueID, projectID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("unwatch issue: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return checkIssue(ctx, db, projectID, issueID)
+	}
+	return nil
+}
+
+func listWatchers(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Watcher, error) {
+	if err := checkIssue(ctx, db, projectID, issueID); err != nil {
+		return nil, err
+	}
+	watchers := []Watcher{}
+	if err := db.SelectContext(ctx, &watchers,
+		`SELECT w.user_id, u.name, u.email, w.created_at
+		 FROM issue_watchers w
+		 JOIN app_users u ON u.id = w.user_id AND u.archived_at IS NULL
+		 WHERE w.issue_id = $1
+		 ORDER BY w.created_at, w.user_id`,
+		issueID,
+	); err != nil {
+		return nil, fmt.Errorf("list watchers: %w", err)
+	}
+	return watchers, nil
+}
+
+func listNotifications(ctx context.Context, db *sqlx.DB, params ListParams) ([]Notification, error) {
+	list := []Notification{}
+	if err := db.SelectContext(ctx, &list,
+		`SELECT n.id, n.issue_id, i.project_id, p.key || '-' || i.number AS issue_key,
+		        i.title AS issue_title, n.actor_id, a.name AS actor_name,
+		        n.event_type, n.data, n.read_at, n.created_at
+		 FROM notifications n
+		 `+visible+`
+		 LEFT JOIN app_users a ON a.id = n.actor_id
+		 WHERE n.user_id = $1
+		   AND (NOT $2 OR n.read_at IS NULL)
+		 ORDER BY n.created_at DESC, n.id DESC
+		 LIMIT $3 OFFSET $4`,
+		params.UserID, params.UnreadOnly, params.Limit, params.Offset,
+	); err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+	return list, nil
+}
+
+func unreadCount(ctx context.Context, db *sqlx.DB, userID string) (int, error) {
+	var n int
+	if err := db.GetContext(ctx, &n,
+		`SELECT COUNT(*)
+		 FROM notifications n
+		 `+visible+`
+		 WHERE n.user_id = $1
+		   AND n.read_at IS NULL`,
+		userID,
+	); err != nil {
+		return 0, fmt.Errorf("count unread notifications: %w", err)
+	}
+	return n, nil
+}
+
+func markRead(ctx context.Context, db *sqlx.DB, userID, notificationID string) error {
+	res, err := db.ExecContext(ctx,
+		`UPDATE notifications
+		 SET read_at = COALESCE(read_at, NOW())
+		 WHERE id = $1
+		   AND user_id = $2`,
+		notificationID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func markAllRead(ctx context.Context, db *sqlx.DB, userID string) error {
+	if _, err := db.ExecContext(ctx,
+		`UPDATE notifications
+		 SET read_at = NOW()
+		 WHERE user_id = $1
+		   AND read_at IS NULL`,
+		userID,
+	); err != nil {
+		return fmt.Errorf("mark notifications read: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestNotifications(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	actorID := testpg.SeedUser(t, db)
+	watcherID := testpg.SeedUser(t, db)
+	leaverID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	projectID := testpg.SeedProject(t, db, wsID, "NTF")
+	for _, id := range []string{actorID, watcherID, leaverID} {
+		if _, err := db.ExecContext(ctx,
+			`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'member')`,
+			wsID, id,
+		); err != nil {
+			t.Fatalf("insert workspace member: %v", err)
+		}
+	}
+
+	var typeID, statusID, issueID string
+	if err := db.GetContext(ctx, &typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert issue type: %v", err)
+	}
+	if err := db.GetContext(ctx, &statusID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, projectID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	if err := db.GetContext(ctx, &issueID,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, 7, $2, $3, 'Issue', '', 'medium', $4, 0) RETURNING id`,
+		projectID, typeID, statusID, actorID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+
+	publish := func(e Event) {
+		t.Helper()
+		if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit", func(tx *sqlx.Tx) error {
+			if err := AutoWatch(ctx, tx, issueID, actorID, "", leaverID); err != nil {
+				return err
+			}
+			return Notify(ctx, tx, e)
+		}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	if err := Watch(ctx, db, projectID, issueID, watcherID); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if err := Watch(ctx, db, projectID, issueID, watcherID); err != nil {
+		t.Fatalf("Watch twice: %v", err)
+	}
+	if err := Watch(ctx, db, projectID, "00000000-0000-0000-0000-000000000000", watcherID); !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("Watch unknown issue: error = %v, want ErrIssueNotFound", err)
+	}
+
+	publish(Event{IssueID: issueID, ActorID: actorID, Type: "updated", Data: map[string]any{"changes": map[string]any{}}})
+	publish(Event{IssueID: issueID, ActorID: actorID, Type: "commented"})
+
+	watchers, err := ListWatchers(ctx, db, projectID, issueID)
+	if err != nil {
+		t.Fatalf("ListWatchers: %v", err)
+	}
+	if len(watchers) != 3 {
+		t.Fatalf("watchers = %+v, want 3", watchers)
+	}
+	if n, err := UnreadCount(ctx, db, actorID); err != nil || n != 0 {
+		t.Fatalf("actor unread = %d, %v; want 0", n, err)
+	}
+
+	list, err := List(ctx, db, ListParams{UserID: watcherID})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || list[0].Type != "commented" || list[1].Type != "updated" {
+		t.Fatalf("List = %+v, want commented then updated", list)
+	}
+	if list[0].IssueKey != "NTF-7" || list[0].ProjectID != projectID || string(list[0].Data) != "{}" {
+		t.Fatalf("notification = %+v", list[0])
+	}
+
+	if err := MarkRead(ctx, db, watcherID, list[1].ID); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if err := MarkRead(ctx, db, actorID, list[0].ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("MarkRead someone else's: error = %v, want ErrNotFound", err)
+	}
+	unread, err := List(ctx, db, ListParams{UserID: watcherID, UnreadOnly: true})
+	if err != nil {
+		t.Fatalf("List unread: %v", err)
+	}
+	if len(unread) != 1 || unread[0].ID != list[0].ID {
+		t.Fatalf("List unread = %+v, want the comment", unread)
+	}
+	if err := MarkAllRead(ctx, db, watcherID); err != nil {
+		t.Fatalf("MarkAllRead: %v", err)
+	}
+	if n, err := UnreadCount(ctx, db, watcherID); err != nil || n != 0 {
+		t.Fatalf("unread after MarkAllRead = %d, %v; want 0", n, err)
+	}
+
+	// Leaving the workspace hides existing notifications and stops new ones.
+	if n, err := UnreadCount(ctx, db, leaverID); err != nil || n != 2 {
+		t.Fatalf("leaver unread = %d, %v; want 2", n, err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE workspace_members SET archived_at = NOW() WHERE workspace_id = $1 AND user_id = $2`, wsID, leaverID); err != nil {
+		t.Fatalf("archive membership: %v", err)
+	}
+	publish(Event{IssueID: issueID, ActorID: actorID, Type: "moved"})
+	if n, err := UnreadCount(ctx, db, leaverID); err != nil || n != 0 {
+		t.Fatalf("leaver unread after leaving = %d, %v; want 0", n, err)
+	}
+
+	if err := Unwatch(ctx, db, projectID, issueID, watcherID); err != nil {
+		t.Fatalf("Unwatch: %v", err)
+	}
+	if err := Unwatch(ctx, db, projectID, issueID, watcherID); err != nil {
+		t.Fatalf("Unwatch twice: %v", err)
+	}
+	publish(Event{IssueID: issueID, ActorID: actorID, Type: "archived"})
+	if n, err := UnreadCount(ctx, db, watcherID); err != nil || n != 0 {
+		t.Fatalf("unwatched unread = %d, %v; want 0", n, err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/audit"
 	"github.com/start-codex/tookly/internal/pgutil"
 )
 
@@ -23,17 +24,23 @@ func createProvider(ctx context.Context, db *sqlx.DB, p CreateProviderParams) (P
 		scopes = "openid email profile"
 	}
 	var prov Provider
-	err := db.QueryRowxContext(ctx,
-		`INSERT INTO oidc_providers (name, slug, issuer_url, client_id, client_secret, redirect_uri, scopes, auto_register, enabled)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		 RETURNING `+providerCols,
-		p.Name, p.Slug, p.IssuerURL, p.ClientID, p.ClientSecret, p.RedirectURI, scopes, p.AutoRegister, p.Enabled,
-	).StructScan(&prov)
-	if err != nil {
-		if pgutil.IsUniqueViolation(err) {
-			return Provider{}, ErrDuplicateSlug
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create oidc provider", func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx,
+			`INSERT INTO oidc_providers (name, slug, issuer_url, client_id, client_secret, redirect_uri, scopes, auto_register, enabled)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			 RETURNING `+providerCols,
+			p.Name, p.Slug, p.IssuerURL, p.ClientID, p.ClientSecret, p.RedirectURI, scopes, p.AutoRegister, p.Enabled,
+		).StructScan(&prov)
+		if err != nil {
+			if pgutil.IsUniqueViolation(err) {
+				return ErrDuplicateSlug
+			}
+			return fmt.Errorf("insert oidc provider: %w", err)
 		}
-		return Provider{}, fmt.Errorf("insert oidc provider: %w", err)
+		return recordProviderChange(ctx, tx, audit.ActionOIDCProviderCreated, prov.ID, nil, prov)
+	})
+	if err != nil {
+		return Provider{}, err
 	}
 	return prov, nil
 }
@@ -41,50 +48,67 @@ func createProvider(ctx context.Context, db *sqlx.DB, p CreateProviderParams) (P
 const maskedSecret = "********"
 
 func updateProvider(ctx context.Context, db *sqlx.DB, id string, p UpdateProviderParams) (Provider, error) {
-	secret := p.ClientSecret
-	if secret == maskedSecret || secret == "" {
-		var existing string
-		if err := db.GetContext(ctx, &existing,
-			`SELECT client_secret FROM oidc_providers WHERE id = $1`, id); err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return Provider{}, ErrProviderNotFound
-			}
-			return Provider{}, fmt.Errorf("get existing secret: %w", err)
-		}
-		secret = existing
-	}
 	scopes := p.Scopes
 	if scopes == "" {
 		scopes = "openid email profile"
 	}
 	var prov Provider
-	err := db.QueryRowxContext(ctx,
-		`UPDATE oidc_providers
-		 SET name = $2, issuer_url = $3, client_id = $4, client_secret = $5, redirect_uri = $6,
-		     scopes = $7, auto_register = $8, enabled = $9, updated_at = NOW()
-		 WHERE id = $1
-		 RETURNING `+providerCols,
-		id, p.Name, p.IssuerURL, p.ClientID, secret, p.RedirectURI, scopes, p.AutoRegister, p.Enabled,
-	).StructScan(&prov)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return Provider{}, ErrProviderNotFound
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update oidc provider", func(tx *sqlx.Tx) error {
+		var existing Provider
+		if err := tx.GetContext(ctx, &existing,
+			`SELECT `+providerCols+` FROM oidc_providers WHERE id = $1 FOR UPDATE`, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrProviderNotFound
+			}
+			return fmt.Errorf("get existing provider: %w", err)
 		}
-		return Provider{}, fmt.Errorf("update oidc provider: %w", err)
+		secret := p.ClientSecret
+		if secret == maskedSecret || secret == "" {
+			secret = existing.ClientSecret
+		}
+		err := tx.QueryRowxContext(ctx,
+			`UPDATE oidc_providers
+			 SET name = $2, issuer_url = $3, client_id = $4, client_secret = $5, redirect_uri = $6,
+			     scopes = $7, auto_register = $8, enabled = $9, updated_at = NOW()
+			 WHERE id = $1
+			 RETURNING `+providerCols,
+			id, p.Name, p.IssuerURL, p.ClientID, secret, p.RedirectURI, scopes, p.AutoRegister, p.Enabled,
+		).StructScan(&prov)
+		if err != nil {
+			return fmt.Errorf("update oidc provider: %w", err)
+		}
+		return recordProviderChange(ctx, tx, audit.ActionOIDCProviderUpdated, id, existing, prov)
+	})
+	if err != nil {
+		return Provider{}, err
 	}
 	return prov, nil
 }
 
 func deleteProvider(ctx context.Context, db *sqlx.DB, id string) error {
-	result, err := db.ExecContext(ctx, `DELETE FROM oidc_providers WHERE id = $1`, id)
-	if err != nil {
-		return fmt.Errorf("delete oidc provider: %w", err)
-	}
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
-		return ErrProviderNotFound
-	}
-	return nil
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit delete oidc provider", func(tx *sqlx.Tx) error {
+		var prov Provider
+		if err := tx.GetContext(ctx, &prov,
+			`DELETE FROM oidc_providers WHERE id = $1 RETURNING `+providerCols, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrProviderNotFound
+			}
+			return fmt.Errorf("delete oidc provider: %w", err)
+		}
+		return recordProviderChange(ctx, tx, audit.ActionOIDCProviderDeleted, id, prov, nil)
+	})
+}
+
+// recordProviderChange audits a provider change. Provider never serialises
+// its client secret.
+func recordProviderChange(ctx context.Context, tx *sqlx.Tx, action, id string, before, after any) error {
+	return audit.Record(ctx, tx, audit.Change{
+		Action:     action,
+		TargetType: audit.TargetOIDCProvider,
+		TargetID:   id,
+		Before:     before,
+		After:      after,
+	})
 }
 
 func getProvider(ctx context.Context, db *sqlx.DB, id string) (Provider, error) {
@@ -21,6 +21,20 @@ func IsUniqueViolation(err error) bool {
 	return errors.As(err, &pqErr) && pqErr.Code == "23505"
 }
 
+// IsUniqueViolationOf reports whether err is a unique violation of the
+// named constraint or unique index.
+func IsUniqueViolationOf(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
+
+// HasCode reports whether err is a PostgreSQL error with the given SQLSTATE,
+// including custom codes raised by triggers.
+func HasCode(err error, code string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && string(pqErr.Code) == code
+}
+
 // WithTx begins a transaction, runs fn, and commits on success.
 // defer tx.Rollback() is registered immediately after Begin so it fires on any return path.
 // Begin errors are wrapped with beginLabel; Commit errors are wrapped with commitLabel.
@@ -12,7 +12,9 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/audit"
 	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/webhooks"
 )
 
 const selectCols = `id, workspace_id, name, key, description, created_at, updated_at, archived_at`
@@ -157,61 +159,114 @@ func listProjects(ctx context.Context, db *sqlx.DB, workspaceID string) ([]Proje
 }
 
 func archiveProject(ctx context.Context, db *sqlx.DB, id string) error {
-	res, err := db.ExecContext(
-		ctx,
-		`UPDATE projects
-		 SET archived_at = NOW()
-		 WHERE id = $1
-		   AND archived_at IS NULL`,
-		id,
-	)
-	if err != nil {
-		return fmt.Errorf("archive project: %w", err)
-	}
-	n, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("archive project rows affected: %w", err)
-	}
-	if n == 0 {
-		return ErrNotFound
-	}
-	return nil
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit archive project", func(tx *sqlx.Tx) error {
+		var project Project
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE projects
+			 SET archived_at = NOW()
+			 WHERE id = $1
+			   AND archived_at IS NULL
+			 RETURNING `+selectCols,
+			id,
+		).StructScan(&project); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("archive project: %w", err)
+		}
+		before := project
+		before.ArchivedAt = nil
+		return audit.Record(ctx, tx, audit.Change{
+			WorkspaceID: project.WorkspaceID,
+			Action:      audit.ActionProjectArchived,
+			TargetType:  audit.TargetProject,
+			TargetID:    project.ID,
+			Before:      before,
+			After:       project,
+		})
+	})
 }
 
 func addMember(ctx context.Context, db *sqlx.DB, params AddMemberParams) (Member, error) {
 	var member Member
-	err := db.QueryRowxContext(ctx,
-		`INSERT INTO project_members (project_id, user_id, role)
-		 VALUES ($1, $2, $3)
-		 ON CONFLICT (project_id, user_id)
-		 DO UPDATE SET role = excluded.role, archived_at = NULL
-		 RETURNING `+memberCols,
-		params.ProjectID, params.UserID, params.Role,
-	).StructScan(&member)
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit add project member", func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx,
+			`INSERT INTO project_members (project_id, user_id, role)
+			 VALUES ($1, $2, $3)
+			 ON CONFLICT (project_id, user_id) DO NOTHING
+			 RETURNING `+memberCols,
+			params.ProjectID, params.UserID, params.Role,
+		).StructScan(&member)
+		if err == nil {
+			return enqueueMemberWebhook(ctx, tx, webhooks.EventMemberAdded, member)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("add project member: %w", err)
+		}
+		// The user already has a membership row: update it in place without
+		// announcing a new member.
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE project_members
+			 SET role = $3, archived_at = NULL
+			 WHERE project_id = $1 AND user_id = $2
+			 RETURNING `+memberCols,
+			params.ProjectID, params.UserID, params.Role,
+		).StructScan(&member); err != nil {
+			return fmt.Errorf("update project member: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return Member{}, fmt.Errorf("add project member: %w", err)
+		return Member{}, err
 	}
 	return member, nil
 }
 
 func removeMember(ctx context.Context, db *sqlx.DB, projectID, userID string) error {
-	res, err := db.ExecContext(ctx,
-		`UPDATE project_members
-		 SET archived_at = NOW()
-		 WHERE project_id = $1 AND user_id = $2 AND archived_at IS NULL`,
-		projectID, userID,
-	)
-	if err != nil {
-		return fmt.Errorf("remove project member: %w", err)
-	}
-	n, err := res.RowsAffected()
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit remove project member", func(tx *sqlx.Tx) error {
+		var member Member
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE project_members
+			 SET archived_at = NOW()
+			 WHERE project_id = $1 AND user_id = $2 AND archived_at IS NULL
+			 RETURNING `+memberCols,
+			projectID, userID,
+		).StructScan(&member); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrMemberNotFound
+			}
+			return fmt.Errorf("remove project member: %w", err)
+		}
+		return enqueueMemberWebhook(ctx, tx, webhooks.EventMemberRemoved, member)
+	})
+}
+
+func enqueueMemberWebhook(ctx context.Context, tx *sqlx.Tx, eventType string, member Member) error {
+	workspaceID, err := projectWorkspaceID(ctx, tx, member.ProjectID)
 	if err != nil {
-		return fmt.Errorf("remove project member rows affected: %w", err)
+		return err
 	}
-	if n == 0 {
-		return ErrMemberNotFound
+	return webhooks.Enqueue(ctx, tx, webhooks.Event{
+		WorkspaceID: workspaceID,
+		Type:        eventType,
+		Data: webhooks.MemberData{
+			WorkspaceID: workspaceID,
+			ProjectID:   member.ProjectID,
+			UserID:      member.UserID,
+			Role:        member.Role,
+		},
+	})
+}
+
+func projectWorkspaceID(ctx context.Context, tx *sqlx.Tx, projectID string) (string, error) {
+	var workspaceID string
+	if err := tx.GetContext(ctx, &workspaceID,
+		`SELECT workspace_id FROM projects WHERE id = $1`,
+		projectID,
+	); err != nil {
+		return "", fmt.Errorf("load project workspace: %w", err)
 	}
-	return nil
+	return workspaceID, nil
 }
 
 func listMembers(ctx context.Context, db *sqlx.DB, projectID string) ([]Member, error) {
@@ -231,18 +286,46 @@ func listMembers(ctx context.Context, db *sqlx.DB, projectID string) ([]Member,
 
 func updateMemberRole(ctx context.Context, db *sqlx.DB, params UpdateMemberRoleParams) (Member, error) {
 	var member Member
-	err := db.QueryRowxContext(ctx,
-		`UPDATE project_members
-		 SET role = $1
-		 WHERE project_id = $2 AND user_id = $3 AND archived_at IS NULL
-		 RETURNING `+memberCols,
-		params.Role, params.ProjectID, params.UserID,
-	).StructScan(&member)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return Member{}, ErrMemberNotFound
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update project member role", func(tx *sqlx.Tx) error {
+		var oldRole string
+		if err := tx.GetContext(ctx, &oldRole,
+			`SELECT role FROM project_members
+			 WHERE project_id = $1 AND user_id = $2 AND archived_at IS NULL
+			 FOR UPDATE`,
+			params.ProjectID, params.UserID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrMemberNotFound
+			}
+			return fmt.Errorf("lock project member: %w", err)
+		}
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE project_members
+			 SET role = $1
+			 WHERE project_id = $2 AND user_id = $3
+			 RETURNING `+memberCols,
+			params.Role, params.ProjectID, params.UserID,
+		).StructScan(&member); err != nil {
+			return fmt.Errorf("update project member role: %w", err)
 		}
-		return Member{}, fmt.Errorf("update project member role: %w", err)
+		workspaceID, err := projectWorkspaceID(ctx, tx, member.ProjectID)
+		if err != nil {
+			return err
+		}
+		if err := audit.Record(ctx, tx, audit.Change{
+			WorkspaceID: workspaceID,
+			Action:      audit.ActionProjectMemberRoleChanged,
+			TargetType:  audit.TargetProjectMember,
+			TargetID:    member.UserID,
+			Before:      map[string]string{"project_id": member.ProjectID, "role": oldRole},
+			After:       map[string]string{"project_id": member.ProjectID, "role": member.Role},
+		}); err != nil {
+			return err
+		}
+		return enqueueMemberWebhook(ctx, tx, webhooks.EventMemberRoleChanged, member)
+	})
+	if err != nil {
+		return Member{}, err
 	}
 	return member, nil
 }
@@ -12,10 +12,12 @@ import (
 )
 
 type envelope struct {
-	Status  int    `json:"status"`
-	Data    any    `json:"data,omitempty"`
-	Error   string `json:"error,omitempty"`
-	Message string `json:"message,omitempty"`
+	Status     int    `json:"status"`
+	Data       any    `json:"data,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    *bool  `json:"has_more,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Message    string `json:"message,omitempty"`
 }
 
 func JSON(w http.ResponseWriter, status int, v any) {
@@ -26,12 +28,30 @@ func JSON(w http.ResponseWriter, status int, v any) {
 	}
 }
 
+// Page writes one page of a cursor-paginated list. has_more is always
+// present; next_cursor is omitted on the last page.
+func Page(w http.ResponseWriter, status int, v any, nextCursor string, hasMore bool) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(envelope{Status: status, Data: v, NextCursor: nextCursor, HasMore: &hasMore}); err != nil {
+		slog.Error("respond.Page: encode failed", "error", err)
+	}
+}
+
 func Error(w http.ResponseWriter, status int, msg string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(envelope{Status: status, Error: msg})
 }
 
+// ErrorWithData writes an error that carries a payload, such as the current
+// state of a resource a conditional request failed against.
+func ErrorWithData(w http.ResponseWriter, status int, msg string, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(envelope{Status: status, Data: v, Error: msg})
+}
+
 func Decode(r *http.Request, v any) error {
 	return json.NewDecoder(r.Body).Decode(v)
 }
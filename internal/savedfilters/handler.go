@@ -0,0 +1,243 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package savedfilters
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("POST /workspaces/{workspaceID}/filters", handleCreate(db))
+	mux.HandleFunc("GET /workspaces/{workspaceID}/filters", handleList(db))
+	mux.HandleFunc("GET /filters/{filterID}", handleGet(db))
+	mux.HandleFunc("PUT /filters/{filterID}", handleUpdate(db))
+	mux.HandleFunc("DELETE /filters/{filterID}", handleDelete(db))
+	mux.HandleFunc("GET /filters/{filterID}/issues", handleIssues(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInvalidQuery),
+		errors.Is(err, ErrProjectNotFound),
+		errors.Is(err, issues.ErrInvalidCursor):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		slog.Error("savedfilters handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// CheckProject is the ProjectCheck handlers pass to Scope: the user must be
+// allowed to read the project under authz.
+func CheckProject(db *sqlx.DB) ProjectCheck {
+	return func(ctx context.Context, projectID string) error {
+		_, err := authz.RequireProjectMembership(ctx, db, projectID)
+		return err
+	}
+}
+
+type filterBody struct {
+	Name       string   `json:"name"`
+	Query      string   `json:"query"`
+	ProjectIDs []string `json:"project_ids"`
+	Shared     bool     `json:"shared"`
+}
+
+func handleCreate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID := r.PathValue("workspaceID")
+		if err := authz.RequireWorkspaceMembership(r.Context(), db, wsID); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body filterBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := CreateParams{
+			WorkspaceID: wsID,
+			OwnerID:     authedUserID,
+			Name:        body.Name,
+			Query:       body.Query,
+			ProjectIDs:  body.ProjectIDs,
+			Shared:      body.Shared,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		f, err := Create(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusCreated, f)
+	}
+}
+
+func handleList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wsID := r.PathValue("workspaceID")
+		if err := authz.RequireWorkspaceMembership(r.Context(), db, wsID); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		filters, err := List(r.Context(), db, wsID, authedUserID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, filters)
+	}
+}
+
+// requireFilter loads a filter the user can see in a workspace they still
+// belong to. With owner set, only the owner passes.
+func requireFilter(r *http.Request, db *sqlx.DB, owner bool) (Filter, string, error) {
+	authedUserID, err := authz.UserIDFromContext(r.Context())
+	if err != nil {
+		return Filter{}, "", err
+	}
+	f, err := Get(r.Context(), db, r.PathValue("filterID"), authedUserID)
+	if err != nil {
+		return Filter{}, "", err
+	}
+	if err := authz.RequireWorkspaceMembership(r.Context(), db, f.WorkspaceID); err != nil {
+		return Filter{}, "", err
+	}
+	if owner && f.OwnerID != authedUserID {
+		return Filter{}, "", authz.ErrForbidden
+	}
+	return f, authedUserID, nil
+}
+
+func handleGet(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, _, err := requireFilter(r, db, false)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, f)
+	}
+}
+
+func handleUpdate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, _, err := requireFilter(r, db, true)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body filterBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := UpdateParams{
+			FilterID:   f.ID,
+			Name:       body.Name,
+			Query:      body.Query,
+			ProjectIDs: body.ProjectIDs,
+			Shared:     body.Shared,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		updated, err := Update(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, updated)
+	}
+}
+
+func handleDelete(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, _, err := requireFilter(r, db, true)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if err := Delete(r.Context(), db, f.ID); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleIssues runs a filter over the projects the user may read, taking
+// limit and cursor like the project issue list.
+func handleIssues(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, authedUserID, err := requireFilter(r, db, false)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		q := r.URL.Query()
+		limit := 0
+		if s := q.Get("limit"); s != "" {
+			limit, err = strconv.Atoi(s)
+			if err != nil {
+				respond.Error(w, http.StatusUnprocessableEntity, "limit must be an integer")
+				return
+			}
+		}
+		params, ok, err := Scope(r.Context(), db, f, authedUserID, CheckProject(db))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if !ok {
+			respond.Page(w, http.StatusOK, []issues.Issue{}, "", false)
+			return
+		}
+		params.Limit = limit
+		params.Cursor = q.Get("cursor")
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		page, err := issues.List(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.Page(w, http.StatusOK, page.Issues, page.NextCursor, page.HasMore)
+	}
+}
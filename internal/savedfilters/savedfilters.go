@@ -0,0 +1,293 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package savedfilters stores issue list filters under a name so they can
+// be run again across the projects of a workspace. A filter belongs to the
+// user who saved it and may be shared with every member of its workspace.
+package savedfilters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/issues"
+)
+
+var (
+	ErrNotFound        = errors.New("saved filter not found")
+	ErrInvalidQuery    = errors.New("invalid filter query")
+	ErrProjectNotFound = errors.New("project not found in workspace")
+)
+
+const (
+	maxNameLen     = 200
+	maxQueryLen    = 4000
+	maxProjectIDs  = 100
+	meValue        = "me"
+	customFieldPfx = "cf."
+)
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// queryParams are the issue list parameters a filter may hold, besides
+// cf.<key>. The page parameters cursor and limit are left to the caller.
+var queryParams = map[string]bool{
+	"status_id": true, "status_category": true, "assignee_id": true, "reporter_id": true,
+	"issue_type_id": true, "parent_issue_id": true, "priority": true,
+	"due_before": true, "due_after": true, "created_before": true, "created_after": true,
+	"updated_before": true, "updated_after": true, "include_archived": true,
+	"label": true, "sort": true,
+}
+
+// Filter is a saved filter. Query holds the parameters of
+// GET /projects/{projectID}/issues as a URL query string, such as
+// "assignee_id=me&priority=critical&status_category=todo&status_category=doing";
+// "me" in assignee_id or reporter_id stands for the user running the
+// filter. ProjectIDs limits the filter to some projects of the workspace;
+// it runs on all of them when empty.
+type Filter struct {
+	ID          string         `db:"id"           json:"id"`
+	WorkspaceID string         `db:"workspace_id" json:"workspace_id"`
+	OwnerID     string         `db:"owner_id"     json:"owner_id"`
+	Name        string         `db:"name"         json:"name"`
+	Query       string         `db:"query"        json:"query"`
+	ProjectIDs  pq.StringArray `db:"project_ids"  json:"project_ids"`
+	Shared      bool           `db:"shared"       json:"shared"`
+	CreatedAt   time.Time      `db:"created_at"   json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"   json:"updated_at"`
+}
+
+type CreateParams struct {
+	WorkspaceID string
+	OwnerID     string
+	Name        string
+	Query       string
+	ProjectIDs  []string
+	Shared      bool
+}
+
+func (params CreateParams) Validate() error {
+	if params.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if params.OwnerID == "" {
+		return errors.New("owner_id is required")
+	}
+	return validate(params.Name, params.Query, params.ProjectIDs)
+}
+
+// UpdateParams replaces the name, query, projects and sharing of a filter.
+type UpdateParams struct {
+	FilterID   string
+	Name       string
+	Query      string
+	ProjectIDs []string
+	Shared     bool
+}
+
+func (params UpdateParams) Validate() error {
+	if params.FilterID == "" {
+		return errors.New("filter_id is required")
+	}
+	return validate(params.Name, params.Query, params.ProjectIDs)
+}
+
+func validate(name, query string, projectIDs []string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > maxNameLen {
+		return errors.New("name must be at most 200 characters")
+	}
+	if len(projectIDs) > maxProjectIDs {
+		return errors.New("project_ids must hold at most 100 projects")
+	}
+	for _, id := range projectIDs {
+		if !uuidRe.MatchString(id) {
+			return errors.New("project_ids must be project IDs")
+		}
+	}
+	_, err := parseQuery(query)
+	return err
+}
+
+// parseQuery reads a filter query into issue list parameters, leaving the
+// projects unset and "me" unresolved.
+func parseQuery(query string) (issues.ListParams, error) {
+	if len(query) > maxQueryLen {
+		return issues.ListParams{}, fmt.Errorf("%w: at most 4000 characters", ErrInvalidQuery)
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return issues.ListParams{}, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	for name := range values {
+		if !queryParams[name] && !strings.HasPrefix(name, customFieldPfx) {
+			return issues.ListParams{}, fmt.Errorf("%w: unknown parameter %q", ErrInvalidQuery, name)
+		}
+	}
+	params, err := issues.ParseListQuery(values)
+	if err != nil {
+		return issues.ListParams{}, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	ids := []struct {
+		name  string
+		value string
+		me    bool
+	}{
+		{"status_id", params.StatusID, false},
+		{"assignee_id", params.AssigneeID, true},
+		{"reporter_id", params.ReporterID, true},
+		{"issue_type_id", params.IssueTypeID, false},
+		{"parent_issue_id", params.ParentIssueID, false},
+	}
+	for _, id := range ids {
+		if id.value == "" || uuidRe.MatchString(id.value) || (id.me && id.value == meValue) {
+			continue
+		}
+		if id.me {
+			return issues.ListParams{}, fmt.Errorf("%w: %s must be an ID or 'me'", ErrInvalidQuery, id.name)
+		}
+		return issues.ListParams{}, fmt.Errorf("%w: %s must be an ID", ErrInvalidQuery, id.name)
+	}
+	if err := params.ValidateFilter(); err != nil {
+		return issues.ListParams{}, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	return params, nil
+}
+
+// ProjectCheck returns an error when the viewer may not read the issues of
+// a project; handlers pass a closure over authz.RequireProjectMembership.
+type ProjectCheck func(ctx context.Context, projectID string) error
+
+// Scope resolves a filter for viewerID: list parameters with "me" replaced
+// by viewerID, on the active projects of the filter's workspace that it
+// covers and that check lets through. Projects check rejects with an authz
+// error are left out; ok is false when no project is left, in which case
+// the filter matches nothing.
+func Scope(ctx context.Context, db *sqlx.DB, f Filter, viewerID string, check ProjectCheck) (params issues.ListParams, ok bool, err error) {
+	if db == nil {
+		return issues.ListParams{}, false, errors.New("db is required")
+	}
+	if viewerID == "" {
+		return issues.ListParams{}, false, errors.New("viewer_id is required")
+	}
+	params, err = parseQuery(f.Query)
+	if err != nil {
+		return issues.ListParams{}, false, err
+	}
+	if params.AssigneeID == meValue {
+		params.AssigneeID = viewerID
+	}
+	if params.ReporterID == meValue {
+		params.ReporterID = viewerID
+	}
+	projectIDs, err := activeProjects(ctx, db, f.WorkspaceID, f.ProjectIDs)
+	if err != nil {
+		return issues.ListParams{}, false, err
+	}
+	for _, id := range projectIDs {
+		if check != nil {
+			err := check(ctx, id)
+			if errors.Is(err, authz.ErrForbidden) || errors.Is(err, authz.ErrProjectNotFound) {
+				continue
+			}
+			if err != nil {
+				return issues.ListParams{}, false, err
+			}
+		}
+		params.ProjectIDs = append(params.ProjectIDs, id)
+	}
+	return params, len(params.ProjectIDs) > 0, nil
+}
+
+// Create saves a filter. Every project in ProjectIDs must belong to the
+// workspace.
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Filter, error) {
+	if db == nil {
+		return Filter{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Filter{}, err
+	}
+	params.Name = strings.TrimSpace(params.Name)
+	params.ProjectIDs = dedupe(params.ProjectIDs)
+	return createFilter(ctx, db, params)
+}
+
+// Get returns a filter the user owns or that is shared with its workspace.
+// Callers are responsible for checking workspace membership.
+func Get(ctx context.Context, db *sqlx.DB, filterID, userID string) (Filter, error) {
+	if db == nil {
+		return Filter{}, errors.New("db is required")
+	}
+	if filterID == "" {
+		return Filter{}, errors.New("filter_id is required")
+	}
+	if userID == "" {
+		return Filter{}, errors.New("user_id is required")
+	}
+	return getFilter(ctx, db, filterID, userID)
+}
+
+// List returns the user's own filters of a workspace and those shared with
+// it, by name.
+func List(ctx context.Context, db *sqlx.DB, workspaceID, userID string) ([]Filter, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if workspaceID == "" {
+		return nil, errors.New("workspace_id is required")
+	}
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	return listFilters(ctx, db, workspaceID, userID)
+}
+
+// Update replaces a filter. Callers are responsible for checking that the
+// user owns it.
+func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Filter, error) {
+	if db == nil {
+		return Filter{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Filter{}, err
+	}
+	params.Name = strings.TrimSpace(params.Name)
+	params.ProjectIDs = dedupe(params.ProjectIDs)
+	return updateFilter(ctx, db, params)
+}
+
+// Delete removes a filter together with the dashboard widgets that run it.
+func Delete(ctx context.Context, db *sqlx.DB, filterID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if filterID == "" {
+		return errors.New("filter_id is required")
+	}
+	return deleteFilter(ctx, db, filterID)
+}
+
+func dedupe(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.ToLower(id)
+		if !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package savedfilters
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const projectID = "7d1c4a52-0f3e-4b8a-9c6d-2e5f8a1b3c4d"
+
+func TestCreateFilterParams_Validate(t *testing.T) {
+	valid := CreateParams{
+		WorkspaceID: "w",
+		OwnerID:     "u",
+		Name:        "My open criticals",
+		Query:       "assignee_id=me&priority=critical&status_category=todo&status_category=doing",
+		ProjectIDs:  []string{projectID},
+	}
+
+	tests := []struct {
+		name      string
+		params    CreateParams
+		wantErr   bool
+		wantQuery bool
+	}{
+		{name: "valid", params: valid},
+		{name: "empty query", params: func() CreateParams { c := valid; c.Query = ""; return c }()},
+		{name: "all projects", params: func() CreateParams { c := valid; c.ProjectIDs = nil; return c }()},
+		{name: "custom field", params: func() CreateParams { c := valid; c.Query = "cf.severity=high&sort=-priority"; return c }()},
+		{name: "reporter me", params: func() CreateParams { c := valid; c.Query = "reporter_id=me&label=bug"; return c }()},
+		{name: "missing workspace_id", params: func() CreateParams { c := valid; c.WorkspaceID = ""; return c }(), wantErr: true},
+		{name: "missing owner_id", params: func() CreateParams { c := valid; c.OwnerID = ""; return c }(), wantErr: true},
+		{name: "blank name", params: func() CreateParams { c := valid; c.Name = "  "; return c }(), wantErr: true},
+		{name: "long name", params: func() CreateParams { c := valid; c.Name = strings.Repeat("n", 201); return c }(), wantErr: true},
+		{name: "bad project id", params: func() CreateParams { c := valid; c.ProjectIDs = []string{"p"}; return c }(), wantErr: true},
+		{name: "unknown parameter", params: func() CreateParams { c := valid; c.Query = "project_id=" + projectID; return c }(), wantErr: true, wantQuery: true},
+		{name: "page parameter", params: func() CreateParams { c := valid; c.Query = "limit=5"; return c }(), wantErr: true, wantQuery: true},
+		{name: "bad status id", params: func() CreateParams { c := valid; c.Query = "status_id=me"; return c }(), wantErr: true, wantQuery: true},
+		{name: "bad assignee", params: func() CreateParams { c := valid; c.Query = "assignee_id=ada"; return c }(), wantErr: true, wantQuery: true},
+		{name: "bad priority", params: func() CreateParams { c := valid; c.Query = "priority=urgent"; return c }(), wantErr: true, wantQuery: true},
+		{name: "bad status category", params: func() CreateParams { c := valid; c.Query = "status_category=blocked"; return c }(), wantErr: true, wantQuery: true},
+		{name: "bad date", params: func() CreateParams { c := valid; c.Query = "due_before=soon"; return c }(), wantErr: true, wantQuery: true},
+		{name: "malformed query", params: func() CreateParams { c := valid; c.Query = "priority=%zz"; return c }(), wantErr: true, wantQuery: true},
+		{name: "long query", params: func() CreateParams { c := valid; c.Query = "label=" + strings.Repeat("x", 4000); return c }(), wantErr: true, wantQuery: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantQuery && !errors.Is(err, ErrInvalidQuery) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidQuery", err)
+			}
+		})
+	}
+}
+
+func TestUpdateFilterParams_Validate(t *testing.T) {
+	valid := UpdateParams{FilterID: "f", Name: "Mine", Query: "assignee_id=me"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	missing := valid
+	missing.FilterID = ""
+	if err := missing.Validate(); err == nil {
+		t.Fatal("Validate() without filter_id: expected error")
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	params, err := parseQuery("assignee_id=me&status_category=todo&status_category=doing&label=bug")
+	if err != nil {
+		t.Fatalf("parseQuery: %v", err)
+	}
+	if params.AssigneeID != "me" || len(params.StatusCategories) != 2 || len(params.Labels) != 1 {
+		t.Fatalf("parseQuery() = %+v", params)
+	}
+	if params.ProjectID != "" || params.ProjectIDs != nil {
+		t.Fatalf("parseQuery() set projects: %+v", params)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	upper := strings.ToUpper(projectID)
+	got := dedupe([]string{projectID, upper})
+	if len(got) != 1 || got[0] != projectID {
+		t.Fatalf("dedupe() = %v, want [%s]", got, projectID)
+	}
+}
+
+func TestCreateFilter_NilDB(t *testing.T) {
+	_, err := Create(context.Background(), nil, CreateParams{WorkspaceID: "w", OwnerID: "u", Name: "n"})
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("Create() error = %v, want %q", err, "db is required")
+	}
+}
+
+func TestScope_NilDB(t *testing.T) {
+	_, _, err := Scope(context.Background(), nil, Filter{}, "u", nil)
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("Scope() error = %v, want %q", err, "db is required")
+	}
+}
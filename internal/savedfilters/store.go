@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package savedfilters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+const filterCols = `id, workspace_id, owner_id, name, query, project_ids, shared, created_at, updated_at`
+
+func createFilter(ctx context.Context, db *sqlx.DB, params CreateParams) (Filter, error) {
+	var f Filter
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create saved filter", func(tx *sqlx.Tx) error {
+		if err := checkProjects(ctx, tx, params.WorkspaceID, params.ProjectIDs); err != nil {
+			return err
+		}
+		if err := tx.QueryRowxContext(ctx,
+			`INSERT INTO saved_filters (workspace_id, owner_id, name, query, project_ids, shared)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 RETURNING `+filterCols,
+			params.WorkspaceID, params.OwnerID, params.Name, params.Query, pq.Array(params.ProjectIDs), params.Shared,
+		).StructScan(&f); err != nil {
+			return fmt.Errorf("insert saved filter: %w", err)
+		}
+		return nil
+	})
+	return f, err
+}
+
+func getFilter(ctx context.Context, db *sqlx.DB, filterID, userID string) (Filter, error) {
+	var f Filter
+	if err := db.GetContext(ctx, &f,
+		`SELECT `+filterCols+`
+		 FROM saved_filters
+		 WHERE id = $1
+		   AND (owner_id = $2 OR shared)`,
+		filterID, userID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Filter{}, ErrNotFound
+		}
+		return Filter{}, fmt.Errorf("get saved filter: %w", err)
+	}
+	return f, nil
+}
+
+func listFilters(ctx context.Context, db *sqlx.DB, workspaceID, userID string) ([]Filter, error) {
+	filters := []Filter{}
+	if err := db.SelectContext(ctx, &filters,
+		`SELECT `+filterCols+`
+		 FROM saved_filters
+		 WHERE workspace_id = $1
+		   AND (owner_id = $2 OR shared)
+		 ORDER BY lower(name), created_at, id`,
+		workspaceID, userID,
+	); err != nil {
+		return nil, fmt.Errorf("list saved filters: %w", err)
+	}
+	return filters, nil
+}
+
+func updateFilter(ctx context.Context, db *sqlx.DB, params UpdateParams) (Filter, error) {
+	var f Filter
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update saved filter", func(tx *sqlx.Tx) error {
+		var workspaceID string
+		if err := tx.GetContext(ctx, &workspaceID,
+			`SELECT workspace_id FROM saved_filters WHERE id = $1 FOR UPDATE`,
+			params.FilterID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("lock saved filter: %w", err)
+		}
+		if err := checkProjects(ctx, tx, workspaceID, params.ProjectIDs); err != nil {
+			return err
+		}
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE saved_filters
+			 SET name        = $1,
+			     query       = $2,
+			     project_ids = $3,
+			     shared      = $4
+			 WHERE id = $5
+			 RETURNING `+filterCols,
+			params.Name, params.Query, pq.Array(params.ProjectIDs), params.Shared, params.FilterID,
+		).StructScan(&f); err != nil {
+			return fmt.Errorf("update saved filter: %w", err)
+		}
+		return nil
+	})
+	return f, err
+}
+
+func deleteFilter(ctx context.Context, db *sqlx.DB, filterID string) error {
+	res, err := db.ExecContext(ctx, `DELETE FROM saved_filters WHERE id = $1`, filterID)
+	if err != nil {
+		return fmt.Errorf("delete saved filter: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete saved filter rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// checkProjects fails with ErrProjectNotFound unless every project belongs
+// to the workspace. Archived projects are accepted; they are skipped when
+// the filter runs.
+func checkProjects(ctx context.Context, tx *sqlx.Tx, workspaceID string, projectIDs []string) error {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+	var n int
+	if err := tx.GetContext(ctx, &n,
+		`SELECT COUNT(*) FROM projects WHERE workspace_id = $1 AND id = ANY($2)`,
+		workspaceID, pq.Array(projectIDs),
+	); err != nil {
+		return fmt.Errorf("check saved filter projects: %w", err)
+	}
+	if n != len(projectIDs) {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+// activeProjects returns the active projects of a workspace, or of those in
+// projectIDs when it is not empty.
+func activeProjects(ctx context.Context, db *sqlx.DB, workspaceID string, projectIDs []string) ([]string, error) {
+	ids := []string{}
+	if err := db.SelectContext(ctx, &ids,
+		`SELECT id
+		 FROM projects
+		 WHERE workspace_id = $1
+		   AND archived_at IS NULL
+		   AND (COALESCE(cardinality($2::uuid[]), 0) = 0 OR id = ANY($2::uuid[]))
+		 ORDER BY key`,
+		workspaceID, pq.Array(projectIDs),
+	); err != nil {
+		return nil, fmt.Errorf("list saved filter projects: %w", err)
+	}
+	return ids, nil
+}
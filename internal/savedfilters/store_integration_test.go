@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package savedfilters
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+type projectSeed struct {
+	id       string
+	typeID   string
+	statuses map[string]string // category → status ID
+}
+
+func seedProject(t *testing.T, db *sqlx.DB, wsID, key string) projectSeed {
+	t.Helper()
+	p := projectSeed{id: testpg.SeedProject(t, db, wsID, key), statuses: map[string]string{}}
+	if err := db.Get(&p.typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, p.id); err != nil {
+		t.Fatalf("insert issue_type: %v", err)
+	}
+	for i, category := range []string{"todo", "doing", "done"} {
+		var id string
+		if err := db.Get(&id, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, $2, $2, $3) RETURNING id`, p.id, category, i); err != nil {
+			t.Fatalf("insert status: %v", err)
+		}
+		p.statuses[category] = id
+	}
+	return p
+}
+
+func TestSavedFilters(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	alice := testpg.SeedUser(t, db)
+	bob := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	first := seedProject(t, db, wsID, "SFA")
+	second := seedProject(t, db, wsID, "SFB")
+	archived := seedProject(t, db, wsID, "SFC")
+	db.MustExec(`UPDATE projects SET archived_at = now() WHERE id = $1`, archived.id)
+
+	create := func(p projectSeed, category, priority, assigneeID string) issues.Issue {
+		t.Helper()
+		issue, err := issues.Create(ctx, db, issues.CreateParams{
+			ProjectID: p.id, IssueTypeID: p.typeID, StatusID: p.statuses[category],
+			Title: category + " " + priority, Priority: priority, AssigneeID: assigneeID, ReporterID: alice,
+		})
+		if err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+		return issue
+	}
+	mineTodo := create(first, "todo", "critical", alice)
+	mineDoing := create(second, "doing", "critical", alice)
+	create(first, "done", "critical", alice)
+	create(first, "todo", "low", alice)
+	create(second, "todo", "critical", bob)
+	bobsTodo := create(first, "todo", "critical", bob)
+
+	f, err := Create(ctx, db, CreateParams{
+		WorkspaceID: wsID,
+		OwnerID:     alice,
+		Name:        " My open criticals ",
+		Query:       "assignee_id=me&priority=critical&status_category=todo&status_category=doing",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if f.Name != "My open criticals" || f.Shared || len(f.ProjectIDs) != 0 {
+		t.Fatalf("Create() = %+v", f)
+	}
+
+	run := func(viewerID string, check ProjectCheck) []string {
+		t.Helper()
+		params, ok, err := Scope(ctx, db, f, viewerID, check)
+		if err != nil {
+			t.Fatalf("Scope() error = %v", err)
+		}
+		if !ok {
+			return nil
+		}
+		page, err := issues.List(ctx, db, params)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		ids := []string{}
+		for _, issue := range page.Issues {
+			ids = append(ids, issue.ID)
+		}
+		return ids
+	}
+	assertIDs := func(name string, got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %v, want %v", name, got, want)
+		}
+		for _, id := range want {
+			found := false
+			for _, g := range got {
+				found = found || g == id
+			}
+			if !found {
+				t.Fatalf("%s: got %v, want %v", name, got, want)
+			}
+		}
+	}
+
+	assertIDs("all projects", run(alice, nil), mineTodo.ID, mineDoing.ID)
+	// "me" is whoever runs the filter.
+	assertIDs("run by bob", run(bob, nil), bobsTodo.ID)
+	forbidSecond := func(_ context.Context, projectID string) error {
+		if projectID == second.id {
+			return authz.ErrForbidden
+		}
+		return nil
+	}
+	assertIDs("second project forbidden", run(alice, forbidSecond), mineTodo.ID)
+	if got := run(alice, func(context.Context, string) error { return authz.ErrForbidden }); got != nil {
+		t.Fatalf("every project forbidden: got %v, want no scope", got)
+	}
+	boom := errors.New("boom")
+	if _, _, err := Scope(ctx, db, f, alice, func(context.Context, string) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("Scope() error = %v, want the check error", err)
+	}
+
+	// Filters hidden from bob until shared.
+	if _, err := Get(ctx, db, f.ID, bob); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() unshared error = %v, want ErrNotFound", err)
+	}
+	if list, err := List(ctx, db, wsID, bob); err != nil || len(list) != 0 {
+		t.Fatalf("List() for bob = %v, %v, want none", list, err)
+	}
+	f, err = Update(ctx, db, UpdateParams{
+		FilterID:   f.ID,
+		Name:       f.Name,
+		Query:      f.Query,
+		ProjectIDs: []string{second.id, second.id, archived.id},
+		Shared:     true,
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(f.ProjectIDs) != 2 || !f.Shared {
+		t.Fatalf("Update() = %+v, want two projects and shared", f)
+	}
+	if _, err := Get(ctx, db, f.ID, bob); err != nil {
+		t.Fatalf("Get() shared error = %v", err)
+	}
+	if list, err := List(ctx, db, wsID, bob); err != nil || len(list) != 1 {
+		t.Fatalf("List() for bob = %v, %v, want the shared filter", list, err)
+	}
+	// The archived project is skipped.
+	assertIDs("second project only", run(alice, nil), mineDoing.ID)
+
+	otherWS := testpg.SeedWorkspace(t, db)
+	foreign := testpg.SeedProject(t, db, otherWS, "SFX")
+	if _, err := Update(ctx, db, UpdateParams{FilterID: f.ID, Name: f.Name, ProjectIDs: []string{foreign}}); !errors.Is(err, ErrProjectNotFound) {
+		t.Fatalf("Update() with a foreign project error = %v, want ErrProjectNotFound", err)
+	}
+
+	if err := Delete(ctx, db, f.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := Delete(ctx, db, f.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete() twice error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package search implements workspace-wide issue search backed by the
+// issues.search_vector column, plus direct lookup by issue key.
+package search
+
+import (
+	"context"
+	"errors"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	maxQueryLen  = 200
+)
+
+// Highlight markers wrapped around matched words in Snippet and
+// TitleHighlight. Everything else in those fields is HTML-escaped.
+const (
+	markStart = "<mark>"
+	markStop  = "</mark>"
+)
+
+var issueKeyRe = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]{1,9})-([1-9][0-9]*)$`)
+
+// Result is a single matching issue. Key matches have Rank 1 and are always
+// listed first.
+type Result struct {
+	IssueID        string  `db:"issue_id"        json:"issue_id"`
+	ProjectID      string  `db:"project_id"      json:"project_id"`
+	ProjectKey     string  `db:"project_key"     json:"project_key"`
+	Number         int     `db:"number"          json:"number"`
+	Key            string  `db:"-"               json:"key"`
+	StatusID       string  `db:"status_id"       json:"status_id"`
+	Title          string  `db:"title"           json:"title"`
+	TitleHighlight string  `db:"title_highlight" json:"title_highlight"`
+	Snippet        string  `db:"snippet"         json:"snippet"`
+	Rank           float64 `db:"rank"            json:"rank"`
+}
+
+type Params struct {
+	WorkspaceID string
+	Query       string
+	Limit       int
+}
+
+func (params Params) Validate() error {
+	if params.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		return errors.New("q is required")
+	}
+	if len(params.Query) > maxQueryLen {
+		return errors.New("q must be at most 200 characters")
+	}
+	if params.Limit < 0 {
+		return errors.New("limit must be >= 0")
+	}
+	if params.Limit > maxLimit {
+		return errors.New("limit must be <= 100")
+	}
+	return nil
+}
+
+// Issues searches the active issues of every active project in a workspace.
+// A query shaped like an issue key (PROJ-123) also returns that issue first.
+// Callers are responsible for checking workspace membership.
+func Issues(ctx context.Context, db *sqlx.DB, params Params) ([]Result, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultLimit
+	}
+	params.Query = strings.TrimSpace(params.Query)
+	return searchIssues(ctx, db, params)
+}
+
+// parseIssueKey splits an issue key such as "proj-12" into its upper-cased
+// project key and number.
+func parseIssueKey(q string) (string, int, bool) {
+	m := issueKeyRe.FindStringSubmatch(q)
+	if m == nil {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return strings.ToUpper(m[1]), n, true
+}
+
+// escapeHighlight HTML-escapes a ts_headline result while keeping the
+// highlight markers.
+func escapeHighlight(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, html.EscapeString(markStart), markStart)
+	return strings.ReplaceAll(s, html.EscapeString(markStop), markStop)
+}
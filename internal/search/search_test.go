@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParams_Validate(t *testing.T) {
+	valid := Params{WorkspaceID: "ws-1", Query: "login bug", Limit: 10}
+	tests := []struct {
+		name    string
+		modify  func(p *Params)
+		wantErr bool
+	}{
+		{"valid", func(p *Params) {}, false},
+		{"default limit", func(p *Params) { p.Limit = 0 }, false},
+		{"missing workspace_id", func(p *Params) { p.WorkspaceID = "" }, true},
+		{"blank query", func(p *Params) { p.Query = "   " }, true},
+		{"query too long", func(p *Params) { p.Query = strings.Repeat("a", 201) }, true},
+		{"negative limit", func(p *Params) { p.Limit = -1 }, true},
+		{"limit too large", func(p *Params) { p.Limit = 101 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIssues_NilDB(t *testing.T) {
+	if _, err := Issues(context.Background(), nil, Params{WorkspaceID: "ws-1", Query: "x"}); err == nil {
+		t.Error("expected error for nil db")
+	}
+}
+
+func TestParseIssueKey(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantKey string
+		wantNum int
+		wantOK  bool
+	}{
+		{"PROJ-123", "PROJ", 123, true},
+		{"proj-7", "PROJ", 7, true},
+		{"AB2-1", "AB2", 1, true},
+		{"PROJ-0", "", 0, false},
+		{"P-1", "", 0, false},
+		{"PROJ-", "", 0, false},
+		{"PROJ 12", "", 0, false},
+		{"login bug", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			key, num, ok := parseIssueKey(tt.in)
+			if key != tt.wantKey || num != tt.wantNum || ok != tt.wantOK {
+				t.Errorf("parseIssueKey(%q) = (%q, %d, %v), want (%q, %d, %v)", tt.in, key, num, ok, tt.wantKey, tt.wantNum, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEscapeHighlight(t *testing.T) {
+	got := escapeHighlight(`<script>x</script> the <mark>login</mark> & "page"`)
+	want := `&lt;script&gt;x&lt;/script&gt; the <mark>login</mark> &amp; &#34;page&#34;`
+	if got != want {
+		t.Errorf("escapeHighlight() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package search
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	titleHeadlineOpts   = `HighlightAll=true, StartSel="<mark>", StopSel="</mark>"`
+	snippetHeadlineOpts = `StartSel="<mark>", StopSel="</mark>", MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" ... "`
+)
+
+func searchIssues(ctx context.Context, db *sqlx.DB, params Params) ([]Result, error) {
+	results := []Result{}
+	seen := map[string]bool{}
+
+	if projectKey, number, ok := parseIssueKey(params.Query); ok {
+		var byKey []Result
+		if err := db.SelectContext(ctx, &byKey,
+			`SELECT i.id AS issue_id, i.project_id, p.key AS project_key, i.number, i.status_id,
+			        i.title, i.title AS title_highlight, LEFT(i.description, 200) AS snippet,
+			        1.0::float8 AS rank
+			 FROM issues i
+			 JOIN projects p ON p.id = i.project_id
+			 WHERE p.workspace_id = $1
+			   AND p.key = $2
+			   AND i.number = $3
+			   AND p.archived_at IS NULL
+			   AND i.archived_at IS NULL`,
+			params.WorkspaceID, projectKey, number,
+		); err != nil {
+			return nil, fmt.Errorf("search issue by key: %w", err)
+		}
+		for _, r := range byKey {
+			r.TitleHighlight = escapeHighlight(r.TitleHighlight)
+			r.Snippet = escapeHighlight(r.Snippet)
+			seen[r.IssueID] = true
+			results = append(results, r)
+		}
+	}
+
+	var matches []Result
+	if err := db.SelectContext(ctx, &matches,
+		`WITH q AS (SELECT websearch_to_tsquery('english', $2) AS query)
+		 SELECT i.id AS issue_id, i.project_id, p.key AS project_key, i.number, i.status_id, i.title,
+		        ts_headline('english', i.title, q.query, '`+titleHeadlineOpts+`') AS title_highlight,
+		        ts_headline('english', i.description, q.query, '`+snippetHeadlineOpts+`') AS snippet,
+		        ts_rank_cd(i.search_vector, q.query)::float8 AS rank
+		 FROM issues i
+		 JOIN projects p ON p.id = i.project_id
+		 CROSS JOIN q
+		 WHERE p.workspace_id = $1
+		   AND p.archived_at IS NULL
+		   AND i.archived_at IS NULL
+		   AND i.search_vector @@ q.query
+		 ORDER BY rank DESC, i.updated_at DESC, i.id
+		 LIMIT $3`,
+		params.WorkspaceID, params.Query, params.Limit,
+	); err != nil {
+		return nil, fmt.Errorf("search issues: %w", err)
+	}
+	for _, r := range matches {
+		if seen[r.IssueID] {
+			continue
+		}
+		r.TitleHighlight = escapeHighlight(r.TitleHighlight)
+		r.Snippet = escapeHighlight(r.Snippet)
+		results = append(results, r)
+	}
+
+	if len(results) > params.Limit {
+		results = results[:params.Limit]
+	}
+	for i := range results {
+		results[i].Key = results[i].ProjectKey + "-" + strconv.Itoa(results[i].Number)
+	}
+	return results, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func seedSearchIssue(t *testing.T, db *sqlx.DB, projectID, reporterID string, number int, title, description string) string {
+	t.Helper()
+	ctx := context.Background()
+	var typeID, statusID string
+	if err := db.GetContext(ctx, &typeID,
+		`INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1)
+		 ON CONFLICT (project_id, name) DO UPDATE SET name = EXCLUDED.name
+		 RETURNING id`, projectID,
+	); err != nil {
+		t.Fatalf("insert issue_type: %v", err)
+	}
+	if err := db.GetContext(ctx, &statusID,
+		`INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0)
+		 ON CONFLICT (project_id, name) DO UPDATE SET name = EXCLUDED.name
+		 RETURNING id`, projectID,
+	); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	var id string
+	if err := db.GetContext(ctx, &id,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, $2, $3, $4, $5, $6, 'medium', $7, $2) RETURNING id`,
+		projectID, number, typeID, statusID, title, description, reporterID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+	return id
+}
+
+func TestSearchIssues(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	otherWS := testpg.SeedWorkspace(t, db)
+	web := testpg.SeedProject(t, db, wsID, "WEB")
+	api := testpg.SeedProject(t, db, wsID, "API")
+	foreign := testpg.SeedProject(t, db, otherWS, "WEB")
+
+	titleHit := seedSearchIssue(t, db, web, userID, 1, "Login page crashes", "Steps to reproduce on Safari")
+	descHit := seedSearchIssue(t, db, api, userID, 1, "Token refresh", "The <b>login</b> endpoint returns 500 after refresh")
+	seedSearchIssue(t, db, api, userID, 2, "Unrelated", "Nothing to see")
+	seedSearchIssue(t, db, foreign, userID, 1, "Login page crashes", "Other workspace")
+
+	results, err := Issues(ctx, db, Params{WorkspaceID: wsID, Query: "login"})
+	if err != nil {
+		t.Fatalf("Issues() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Issues() = %d results, want 2: %+v", len(results), results)
+	}
+	if results[0].IssueID != titleHit || results[1].IssueID != descHit {
+		t.Fatalf("ranking = [%s %s], want title match first", results[0].Key, results[1].Key)
+	}
+	if results[0].Key != "WEB-1" || !strings.Contains(results[0].TitleHighlight, "<mark>Login</mark>") {
+		t.Fatalf("title result = %+v", results[0])
+	}
+	if !strings.Contains(results[1].Snippet, "<mark>login</mark>") || strings.Contains(results[1].Snippet, "<b>") {
+		t.Fatalf("snippet = %q, want escaped HTML with highlight", results[1].Snippet)
+	}
+
+	results, err = Issues(ctx, db, Params{WorkspaceID: wsID, Query: "api-2"})
+	if err != nil {
+		t.Fatalf("Issues() by key error = %v", err)
+	}
+	if len(results) != 1 || results[0].Key != "API-2" {
+		t.Fatalf("key lookup = %+v, want API-2", results)
+	}
+
+	if _, err := db.ExecContext(ctx, `UPDATE issues SET archived_at = NOW() WHERE id = $1`, titleHit); err != nil {
+		t.Fatalf("archive issue: %v", err)
+	}
+	results, err = Issues(ctx, db, Params{WorkspaceID: wsID, Query: "WEB-1"})
+	if err != nil {
+		t.Fatalf("Issues() archived key error = %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("archived key lookup = %+v, want none", results)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package sprints
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+func parseDate(field string, s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", *s)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be YYYY-MM-DD format", field)
+	}
+	return &t, nil
+}
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("POST /boards/{boardID}/sprints", handleCreate(db))
+	mux.HandleFunc("GET /boards/{boardID}/sprints", handleList(db))
+	mux.HandleFunc("GET /boards/{boardID}/sprints/{sprintID}", handleGet(db))
+	mux.HandleFunc("PUT /boards/{boardID}/sprints/{sprintID}", handleUpdate(db))
+	mux.HandleFunc("DELETE /boards/{boardID}/sprints/{sprintID}", handleDelete(db))
+	mux.HandleFunc("POST /boards/{boardID}/sprints/{sprintID}/start", handleStart(db))
+	mux.HandleFunc("POST /boards/{boardID}/sprints/{sprintID}/close", handleClose(db))
+	mux.HandleFunc("GET /boards/{boardID}/sprints/{sprintID}/issues", handleListIssues(db))
+	mux.HandleFunc("POST /boards/{boardID}/sprints/{sprintID}/issues", handleSetIssues(db))
+	mux.HandleFunc("POST /boards/{boardID}/backlog/issues", handleSetIssues(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound),
+		errors.Is(err, authz.ErrProjectNotFound),
+		errors.Is(err, authz.ErrBoardNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound),
+		errors.Is(err, ErrBoardNotFound),
+		errors.Is(err, ErrIssueNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrDuplicateName),
+		errors.Is(err, ErrActiveSprint),
+		errors.Is(err, ErrNotPlanned),
+		errors.Is(err, ErrNotActive),
+		errors.Is(err, ErrClosed):
+		respond.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrNotScrumBoard),
+		errors.Is(err, ErrIssueNotInProject):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		slog.Error("sprints handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+type sprintBody struct {
+	Name      string  `json:"name"`
+	Goal      string  `json:"goal"`
+	StartDate *string `json:"start_date"`
+	EndDate   *string `json:"end_date"`
+}
+
+func (body sprintBody) dates() (*time.Time, *time.Time, error) {
+	start, err := parseDate("start_date", body.StartDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := parseDate("end_date", body.EndDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	return start, end, nil
+}
+
+func handleCreate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardRole(r.Context(), db, boardID, authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		var body sprintBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		start, end, err := body.dates()
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		params := CreateParams{
+			BoardID:   boardID,
+			Name:      body.Name,
+			Goal:      body.Goal,
+			StartDate: start,
+			EndDate:   end,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		sprint, err := Create(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusCreated, sprint)
+	}
+}
+
+func handleList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardAccess(r.Context(), db, boardID); err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := List(r.Context(), db, boardID)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+func handleGet(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardAccess(r.Context(), db, boardID); err != nil {
+			fail(w, err)
+			return
+		}
+		sprint, err := Get(r.Context(), db, boardID, r.PathValue("sprintID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, sprint)
+	}
+}
+
+func handleUpdate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardRole(r.Context(), db, boardID, authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		var body sprintBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		start, end, err := body.dates()
+		if err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		params := UpdateParams{
+			BoardID:   boardID,
+			SprintID:  r.PathValue("sprintID"),
+			Name:      body.Name,
+			Goal:      body.Goal,
+			StartDate: start,
+			EndDate:   end,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		sprint, err := Update(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, sprint)
+	}
+}
+
+func handleDelete(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardRole(r.Context(), db, boardID, authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		if err := Delete(r.Context(), db, boardID, r.PathValue("sprintID")); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func handleStart(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardRole(r.Context(), db, boardID, authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		sprint, err := Start(r.Context(), db, boardID, r.PathValue("sprintID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, sprint)
+	}
+}
+
+func handleClose(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardRole(r.Context(), db, boardID, authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		result, err := Close(r.Context(), db, CloseParams{
+			BoardID:  boardID,
+			SprintID: r.PathValue("sprintID"),
+			ActorID:  authedUserID,
+		})
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, result)
+	}
+}
+
+func handleListIssues(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardAccess(r.Context(), db, boardID); err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := ListIssues(r.Context(), db, boardID, r.PathValue("sprintID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+// handleSetIssues serves both sprint membership and the backlog route; the
+// latter has no sprintID path value, which moves the issues to the backlog.
+func handleSetIssues(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.PathValue("boardID")
+		if _, _, err := authz.RequireBoardRole(r.Context(), db, boardID, authz.RoleMember); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body struct {
+			IssueIDs []string `json:"issue_ids"`
+		}
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := SetIssuesParams{
+			BoardID:  boardID,
+			SprintID: r.PathValue("sprintID"),
+			IssueIDs: body.IssueIDs,
+			ActorID:  authedUserID,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if err := SetIssues(r.Context(), db, params); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
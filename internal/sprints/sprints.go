@@ -0,0 +1,267 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package sprints
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/issues"
+)
+
+const (
+	StatePlanned = "planned"
+	StateActive  = "active"
+	StateClosed  = "closed"
+)
+
+var (
+	ErrNotFound          = errors.New("sprint not found")
+	ErrBoardNotFound     = errors.New("board not found")
+	ErrNotScrumBoard     = errors.New("sprints are only available on scrum boards")
+	ErrDuplicateName     = errors.New("sprint name already exists in board")
+	ErrActiveSprint      = errors.New("board already has an active sprint")
+	ErrNotPlanned        = errors.New("sprint is not planned")
+	ErrNotActive         = errors.New("sprint is not active")
+	ErrClosed            = errors.New("sprint is closed")
+	ErrIssueNotFound     = errors.New("issue not found")
+	ErrIssueNotInProject = errors.New("issue does not belong to the board's project")
+)
+
+type Sprint struct {
+	ID        string     `db:"id"         json:"id"`
+	BoardID   string     `db:"board_id"   json:"board_id"`
+	Name      string     `db:"name"       json:"name"`
+	Goal      string     `db:"goal"       json:"goal"`
+	State     string     `db:"state"      json:"state"`
+	StartDate *time.Time `db:"start_date" json:"start_date,omitempty"`
+	EndDate   *time.Time `db:"end_date"   json:"end_date,omitempty"`
+	StartedAt *time.Time `db:"started_at" json:"started_at,omitempty"`
+	ClosedAt  *time.Time `db:"closed_at"  json:"closed_at,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// CloseResult reports where the unfinished issues of a closed sprint went.
+// A nil NextSprintID means they were moved back to the backlog.
+type CloseResult struct {
+	Sprint         Sprint   `json:"sprint"`
+	NextSprintID   *string  `json:"next_sprint_id"`
+	MovedIssueIDs  []string `json:"moved_issue_ids"`
+	CompletedCount int      `json:"completed_count"`
+}
+
+type CreateParams struct {
+	BoardID   string
+	Name      string
+	Goal      string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+func (params CreateParams) Validate() error {
+	if params.BoardID == "" {
+		return errors.New("board_id is required")
+	}
+	if strings.TrimSpace(params.Name) == "" {
+		return errors.New("name is required")
+	}
+	return validateDates(params.StartDate, params.EndDate)
+}
+
+type UpdateParams struct {
+	BoardID   string
+	SprintID  string
+	Name      string
+	Goal      string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+func (params UpdateParams) Validate() error {
+	if params.BoardID == "" {
+		return errors.New("board_id is required")
+	}
+	if params.SprintID == "" {
+		return errors.New("sprint_id is required")
+	}
+	if strings.TrimSpace(params.Name) == "" {
+		return errors.New("name is required")
+	}
+	return validateDates(params.StartDate, params.EndDate)
+}
+
+type CloseParams struct {
+	BoardID  string
+	SprintID string
+	ActorID  string
+}
+
+func (params CloseParams) Validate() error {
+	if params.BoardID == "" {
+		return errors.New("board_id is required")
+	}
+	if params.SprintID == "" {
+		return errors.New("sprint_id is required")
+	}
+	if params.ActorID == "" {
+		return errors.New("actor_id is required")
+	}
+	return nil
+}
+
+// SetIssuesParams moves issues into a sprint, or to the backlog when
+// SprintID is empty.
+type SetIssuesParams struct {
+	BoardID  string
+	SprintID string
+	IssueIDs []string
+	ActorID  string
+}
+
+func (params SetIssuesParams) Validate() error {
+	if params.BoardID == "" {
+		return errors.New("board_id is required")
+	}
+	if len(params.IssueIDs) == 0 {
+		return errors.New("issue_ids is required")
+	}
+	for _, id := range params.IssueIDs {
+		if id == "" {
+			return errors.New("issue_ids must not contain empty values")
+		}
+	}
+	if params.ActorID == "" {
+		return errors.New("actor_id is required")
+	}
+	return nil
+}
+
+func validateDates(start, end *time.Time) error {
+	if start != nil && end != nil && end.Before(*start) {
+		return errors.New("end_date must not be before start_date")
+	}
+	return nil
+}
+
+// Create adds a planned sprint to a scrum board.
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Sprint, error) {
+	if db == nil {
+		return Sprint{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Sprint{}, err
+	}
+	return createSprint(ctx, db, params)
+}
+
+func Get(ctx context.Context, db *sqlx.DB, boardID, sprintID string) (Sprint, error) {
+	if db == nil {
+		return Sprint{}, errors.New("db is required")
+	}
+	if boardID == "" {
+		return Sprint{}, errors.New("board_id is required")
+	}
+	if sprintID == "" {
+		return Sprint{}, errors.New("sprint_id is required")
+	}
+	return getSprint(ctx, db, boardID, sprintID)
+}
+
+// List returns the sprints of a board: active first, then planned in
+// schedule order, then closed with the most recent first.
+func List(ctx context.Context, db *sqlx.DB, boardID string) ([]Sprint, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if boardID == "" {
+		return nil, errors.New("board_id is required")
+	}
+	return listSprints(ctx, db, boardID)
+}
+
+// Update changes the name, goal and dates of a planned or active sprint.
+func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Sprint, error) {
+	if db == nil {
+		return Sprint{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Sprint{}, err
+	}
+	return updateSprint(ctx, db, params)
+}
+
+// Delete removes a planned sprint. Its issues return to the backlog.
+func Delete(ctx context.Context, db *sqlx.DB, boardID, sprintID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if boardID == "" {
+		return errors.New("board_id is required")
+	}
+	if sprintID == "" {
+		return errors.New("sprint_id is required")
+	}
+	return deleteSprint(ctx, db, boardID, sprintID)
+}
+
+// Start activates a planned sprint. The board row is locked so that two
+// concurrent starts cannot both succeed.
+func Start(ctx context.Context, db *sqlx.DB, boardID, sprintID string) (Sprint, error) {
+	if db == nil {
+		return Sprint{}, errors.New("db is required")
+	}
+	if boardID == "" {
+		return Sprint{}, errors.New("board_id is required")
+	}
+	if sprintID == "" {
+		return Sprint{}, errors.New("sprint_id is required")
+	}
+	return startSprint(ctx, db, boardID, sprintID)
+}
+
+// Close closes the active sprint. Issues whose status category is not 'done'
+// move to the next planned sprint of the board, or to the backlog if there is
+// none.
+func Close(ctx context.Context, db *sqlx.DB, params CloseParams) (CloseResult, error) {
+	if db == nil {
+		return CloseResult{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return CloseResult{}, err
+	}
+	return closeSprint(ctx, db, params)
+}
+
+// SetIssues assigns issues to a planned or active sprint, or moves them to
+// the backlog when params.SprintID is empty.
+func SetIssues(ctx context.Context, db *sqlx.DB, params SetIssuesParams) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	return setSprintIssues(ctx, db, params)
+}
+
+// ListIssues returns the active issues of a sprint ordered by status and
+// position.
+func ListIssues(ctx context.Context, db *sqlx.DB, boardID, sprintID string) ([]issues.Issue, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if boardID == "" {
+		return nil, errors.New("board_id is required")
+	}
+	if sprintID == "" {
+		return nil, errors.New("sprint_id is required")
+	}
+	return listSprintIssues(ctx, db, boardID, sprintID)
+}
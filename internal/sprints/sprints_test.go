@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package sprints
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func date(s string) *time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return &t
+}
+
+func TestCreateParams_Validate(t *testing.T) {
+	valid := CreateParams{BoardID: "b1", Name: "Sprint 1", StartDate: date("2025-07-01"), EndDate: date("2025-07-14")}
+	tests := []struct {
+		name    string
+		modify  func(p *CreateParams)
+		wantErr bool
+	}{
+		{"valid", func(p *CreateParams) {}, false},
+		{"no dates", func(p *CreateParams) { p.StartDate, p.EndDate = nil, nil }, false},
+		{"same day", func(p *CreateParams) { p.EndDate = p.StartDate }, false},
+		{"missing board_id", func(p *CreateParams) { p.BoardID = "" }, true},
+		{"missing name", func(p *CreateParams) { p.Name = "  " }, true},
+		{"end before start", func(p *CreateParams) { p.EndDate = date("2025-06-30") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateParams_Validate(t *testing.T) {
+	valid := UpdateParams{BoardID: "b1", SprintID: "s1", Name: "Sprint 1"}
+	tests := []struct {
+		name    string
+		modify  func(p *UpdateParams)
+		wantErr bool
+	}{
+		{"valid", func(p *UpdateParams) {}, false},
+		{"missing board_id", func(p *UpdateParams) { p.BoardID = "" }, true},
+		{"missing sprint_id", func(p *UpdateParams) { p.SprintID = "" }, true},
+		{"missing name", func(p *UpdateParams) { p.Name = "" }, true},
+		{"end before start", func(p *UpdateParams) { p.StartDate, p.EndDate = date("2025-07-02"), date("2025-07-01") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCloseParams_Validate(t *testing.T) {
+	valid := CloseParams{BoardID: "b1", SprintID: "s1", ActorID: "u1"}
+	tests := []struct {
+		name    string
+		modify  func(p *CloseParams)
+		wantErr bool
+	}{
+		{"valid", func(p *CloseParams) {}, false},
+		{"missing board_id", func(p *CloseParams) { p.BoardID = "" }, true},
+		{"missing sprint_id", func(p *CloseParams) { p.SprintID = "" }, true},
+		{"missing actor_id", func(p *CloseParams) { p.ActorID = "" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetIssuesParams_Validate(t *testing.T) {
+	valid := SetIssuesParams{BoardID: "b1", SprintID: "s1", IssueIDs: []string{"i1", "i2"}, ActorID: "u1"}
+	tests := []struct {
+		name    string
+		modify  func(p *SetIssuesParams)
+		wantErr bool
+	}{
+		{"valid", func(p *SetIssuesParams) {}, false},
+		{"backlog", func(p *SetIssuesParams) { p.SprintID = "" }, false},
+		{"missing board_id", func(p *SetIssuesParams) { p.BoardID = "" }, true},
+		{"no issues", func(p *SetIssuesParams) { p.IssueIDs = nil }, true},
+		{"empty issue id", func(p *SetIssuesParams) { p.IssueIDs = []string{"i1", ""} }, true},
+		{"missing actor_id", func(p *SetIssuesParams) { p.ActorID = "" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSprints_NilDB(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Create(ctx, nil, CreateParams{BoardID: "b1", Name: "S"}); err == nil {
+		t.Error("Create: expected error for nil db")
+	}
+	if _, err := Get(ctx, nil, "b1", "s1"); err == nil {
+		t.Error("Get: expected error for nil db")
+	}
+	if _, err := List(ctx, nil, "b1"); err == nil {
+		t.Error("List: expected error for nil db")
+	}
+	if _, err := Start(ctx, nil, "b1", "s1"); err == nil {
+		t.Error("Start: expected error for nil db")
+	}
+	if _, err := Close(ctx, nil, CloseParams{BoardID: "b1", SprintID: "s1", ActorID: "u1"}); err == nil {
+		t.Error("Close: expected error for nil db")
+	}
+	if err := SetIssues(ctx, nil, SetIssuesParams{BoardID: "b1", IssueIDs: []string{"i1"}, ActorID: "u1"}); err == nil {
+		t.Error("SetIssues: expected error for nil db")
+	}
+	if err := Delete(ctx, nil, "b1", "s1"); err == nil {
+		t.Error("Delete: expected error for nil db")
+	}
+}
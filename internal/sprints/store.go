@@ -0,0 +1,419 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package sprints
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+const sprintCols = `id, board_id, name, goal, state, start_date, end_date,
+	started_at, closed_at, created_at, updated_at`
+
+const sprintIssueCols = `i.id, i.project_id, i.number, i.issue_type_id, i.status_id, i.parent_issue_id, i.sprint_id,
+	i.title, i.description, i.priority, i.assignee_id, i.reporter_id, i.due_date,
+	i.original_estimate_minutes, i.remaining_estimate_minutes, i.external_ref,
+	i.status_position, i.version, i.created_at, i.updated_at, i.archived_at`
+
+// lockBoard locks an active scrum board and returns its project ID. Start and
+// Close take the lock FOR UPDATE so only one of them runs per board at a time.
+func lockBoard(ctx context.Context, tx *sqlx.Tx, boardID, lockMode string) (string, error) {
+	var board struct {
+		ProjectID string `db:"project_id"`
+		Type      string `db:"type"`
+	}
+	if err := tx.GetContext(ctx, &board,
+		`SELECT project_id, type
+		 FROM boards
+		 WHERE id = $1
+		   AND archived_at IS NULL
+		 FOR `+lockMode,
+		boardID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrBoardNotFound
+		}
+		return "", fmt.Errorf("lock board: %w", err)
+	}
+	if board.Type != "scrum" {
+		return "", ErrNotScrumBoard
+	}
+	return board.ProjectID, nil
+}
+
+func getSprintForUpdate(ctx context.Context, tx *sqlx.Tx, boardID, sprintID string) (Sprint, error) {
+	var sprint Sprint
+	if err := tx.GetContext(ctx, &sprint,
+		`SELECT `+sprintCols+`
+		 FROM sprints
+		 WHERE id = $1
+		   AND board_id = $2
+		 FOR UPDATE`,
+		sprintID, boardID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Sprint{}, ErrNotFound
+		}
+		return Sprint{}, fmt.Errorf("lock sprint: %w", err)
+	}
+	return sprint, nil
+}
+
+func createSprint(ctx context.Context, db *sqlx.DB, params CreateParams) (Sprint, error) {
+	var sprint Sprint
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create sprint", func(tx *sqlx.Tx) error {
+		if _, err := lockBoard(ctx, tx, params.BoardID, "SHARE"); err != nil {
+			return err
+		}
+		if err := tx.GetContext(ctx, &sprint,
+			`INSERT INTO sprints (board_id, name, goal, start_date, end_date)
+			 VALUES ($1, $2, $3, $4, $5)
+			 RETURNING `+sprintCols,
+			params.BoardID, params.Name, params.Goal, params.StartDate, params.EndDate,
+		); err != nil {
+			if pgutil.IsUniqueViolation(err) {
+				return ErrDuplicateName
+			}
+			return fmt.Errorf("insert sprint: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return Sprint{}, err
+	}
+	return sprint, nil
+}
+
+func getSprint(ctx context.Context, db *sqlx.DB, boardID, sprintID string) (Sprint, error) {
+	var sprint Sprint
+	err := db.GetContext(ctx, &sprint,
+		`SELECT `+sprintCols+`
+		 FROM sprints
+		 WHERE id = $1
+		   AND board_id = $2`,
+		sprintID, boardID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Sprint{}, ErrNotFound
+		}
+		return Sprint{}, fmt.Errorf("get sprint: %w", err)
+	}
+	return sprint, nil
+}
+
+func listSprints(ctx context.Context, db *sqlx.DB, boardID string) ([]Sprint, error) {
+	list := []Sprint{}
+	if err := db.SelectContext(ctx, &list,
+		`SELECT `+sprintCols+`
+		 FROM sprints
+		 WHERE board_id = $1
+		 ORDER BY CASE state WHEN 'active' THEN 0 WHEN 'planned' THEN 1 ELSE 2 END,
+		          CASE WHEN state = 'closed' THEN closed_at END DESC,
+		          start_date NULLS LAST, created_at, id`,
+		boardID,
+	); err != nil {
+		return nil, fmt.Errorf("list sprints: %w", err)
+	}
+	return list, nil
+}
+
+func updateSprint(ctx context.Context, db *sqlx.DB, params UpdateParams) (Sprint, error) {
+	var sprint Sprint
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update sprint", func(tx *sqlx.Tx) error {
+		current, err := getSprintForUpdate(ctx, tx, params.BoardID, params.SprintID)
+		if err != nil {
+			return err
+		}
+		if current.State == StateClosed {
+			return ErrClosed
+		}
+		if err := tx.GetContext(ctx, &sprint,
+			`UPDATE sprints
+			 SET name = $1, goal = $2, start_date = $3, end_date = $4
+			 WHERE id = $5
+			 RETURNING `+sprintCols,
+			params.Name, params.Goal, params.StartDate, params.EndDate, params.SprintID,
+		); err != nil {
+			if pgutil.IsUniqueViolation(err) {
+				return ErrDuplicateName
+			}
+			return fmt.Errorf("update sprint: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return Sprint{}, err
+	}
+	return sprint, nil
+}
+
+func deleteSprint(ctx context.Context, db *sqlx.DB, boardID, sprintID string) error {
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit delete sprint", func(tx *sqlx.Tx) error {
+		current, err := getSprintForUpdate(ctx, tx, boardID, sprintID)
+		if err != nil {
+			return err
+		}
+		if current.State != StatePlanned {
+			return ErrNotPlanned
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM sprints WHERE id = $1`, sprintID); err != nil {
+			return fmt.Errorf("delete sprint: %w", err)
+		}
+		return nil
+	})
+}
+
+func startSprint(ctx context.Context, db *sqlx.DB, boardID, sprintID string) (Sprint, error) {
+	var sprint Sprint
+	opts := &sql.TxOptions{Isolation: sql.LevelReadCommitted}
+	if err := pgutil.WithTx(ctx, db, opts, "begin tx", "commit start sprint", func(tx *sqlx.Tx) error {
+		if _, err := lockBoard(ctx, tx, boardID, "UPDATE"); err != nil {
+			return err
+		}
+		current, err := getSprintForUpdate(ctx, tx, boardID, sprintID)
+		if err != nil {
+			return err
+		}
+		if current.State != StatePlanned {
+			return ErrNotPlanned
+		}
+
+		var active bool
+		if err := tx.GetContext(ctx, &active,
+			`SELECT EXISTS(SELECT 1 FROM sprints WHERE board_id = $1 AND state = 'active')`,
+			boardID,
+		); err != nil {
+			return fmt.Errorf("check active sprint: %w", err)
+		}
+		if active {
+			return ErrActiveSprint
+		}
+
+		if err := tx.GetContext(ctx, &sprint,
+			`UPDATE sprints
+			 SET state = 'active',
+			     started_at = NOW(),
+			     start_date = COALESCE(start_date, CURRENT_DATE)
+			 WHERE id = $1
+			 RETURNING `+sprintCols,
+			sprintID,
+		); err != nil {
+			if pgutil.IsUniqueViolation(err) {
+				return ErrActiveSprint
+			}
+			return fmt.Errorf("start sprint: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return Sprint{}, err
+	}
+	return sprint, nil
+}
+
+func closeSprint(ctx context.Context, db *sqlx.DB, params CloseParams) (CloseResult, error) {
+	result := CloseResult{MovedIssueIDs: []string{}}
+	opts := &sql.TxOptions{Isolation: sql.LevelReadCommitted}
+	if err := pgutil.WithTx(ctx, db, opts, "begin tx", "commit close sprint", func(tx *sqlx.Tx) error {
+		if _, err := lockBoard(ctx, tx, params.BoardID, "UPDATE"); err != nil {
+			return err
+		}
+		current, err := getSprintForUpdate(ctx, tx, params.BoardID, params.SprintID)
+		if err != nil {
+			return err
+		}
+		if current.State != StateActive {
+			return ErrNotActive
+		}
+
+		var next []string
+		if err := tx.SelectContext(ctx, &next,
+			`SELECT id
+			 FROM sprints
+			 WHERE board_id = $1
+			   AND state = 'planned'
+			 ORDER BY start_date NULLS LAST, created_at, id
+			 LIMIT 1`,
+			params.BoardID,
+		); err != nil {
+			return fmt.Errorf("find next sprint: %w", err)
+		}
+		if len(next) > 0 {
+			result.NextSprintID = &next[0]
+		}
+
+		if err := tx.SelectContext(ctx, &result.MovedIssueIDs,
+			`SELECT i.id
+			 FROM issues i
+			 JOIN statuses s ON s.id = i.status_id
+			 WHERE i.sprint_id = $1
+			   AND i.archived_at IS NULL
+			   AND s.category <> 'done'
+			 ORDER BY i.id
+			 FOR UPDATE OF i`,
+			params.SprintID,
+		); err != nil {
+			return fmt.Errorf("lock unfinished issues: %w", err)
+		}
+
+		if len(result.MovedIssueIDs) > 0 {
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE issues SET sprint_id = $1 WHERE id = ANY($2)`,
+				result.NextSprintID, pq.Array(result.MovedIssueIDs),
+			); err != nil {
+				return fmt.Errorf("move unfinished issues: %w", err)
+			}
+			if err := insertSprintEvents(ctx, tx, result.MovedIssueIDs, params.ActorID, &params.SprintID, result.NextSprintID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.GetContext(ctx, &result.CompletedCount,
+			`SELECT COUNT(*)
+			 FROM issues i
+			 JOIN statuses s ON s.id = i.status_id
+			 WHERE i.sprint_id = $1
+			   AND i.archived_at IS NULL
+			   AND s.category = 'done'`,
+			params.SprintID,
+		); err != nil {
+			return fmt.Errorf("count completed issues: %w", err)
+		}
+
+		if err := tx.GetContext(ctx, &result.Sprint,
+			`UPDATE sprints
+			 SET state = 'closed', closed_at = NOW()
+			 WHERE id = $1
+			 RETURNING `+sprintCols,
+			params.SprintID,
+		); err != nil {
+			return fmt.Errorf("close sprint: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return CloseResult{}, err
+	}
+	return result, nil
+}
+
+func setSprintIssues(ctx context.Context, db *sqlx.DB, params SetIssuesParams) error {
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit set sprint issues", func(tx *sqlx.Tx) error {
+		projectID, err := lockBoard(ctx, tx, params.BoardID, "SHARE")
+		if err != nil {
+			return err
+		}
+
+		var target *string
+		if params.SprintID != "" {
+			var state string
+			if err := tx.GetContext(ctx, &state,
+				`SELECT state FROM sprints WHERE id = $1 AND board_id = $2 FOR SHARE`,
+				params.SprintID, params.BoardID,
+			); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return ErrNotFound
+				}
+				return fmt.Errorf("lock sprint: %w", err)
+			}
+			if state == StateClosed {
+				return ErrClosed
+			}
+			target = &params.SprintID
+		}
+
+		var rows []struct {
+			ID        string  `db:"id"`
+			ProjectID string  `db:"project_id"`
+			SprintID  *string `db:"sprint_id"`
+		}
+		if err := tx.SelectContext(ctx, &rows,
+			`SELECT id, project_id, sprint_id
+			 FROM issues
+			 WHERE id = ANY($1)
+			   AND archived_at IS NULL
+			 ORDER BY id
+			 FOR UPDATE`,
+			pq.Array(params.IssueIDs),
+		); err != nil {
+			return fmt.Errorf("lock issues: %w", err)
+		}
+		found := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			found[row.ID] = true
+		}
+		for _, id := range params.IssueIDs {
+			if !found[id] {
+				return ErrIssueNotFound
+			}
+		}
+
+		for _, row := range rows {
+			if row.ProjectID != projectID {
+				return ErrIssueNotInProject
+			}
+			if sameSprint(row.SprintID, target) {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE issues SET sprint_id = $1 WHERE id = $2`,
+				target, row.ID,
+			); err != nil {
+				return fmt.Errorf("set issue sprint: %w", err)
+			}
+			if err := insertSprintEvents(ctx, tx, []string{row.ID}, params.ActorID, row.SprintID, target); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func listSprintIssues(ctx context.Context, db *sqlx.DB, boardID, sprintID string) ([]issues.Issue, error) {
+	if _, err := getSprint(ctx, db, boardID, sprintID); err != nil {
+		return nil, err
+	}
+	list := []issues.Issue{}
+	if err := db.SelectContext(ctx, &list,
+		`SELECT `+sprintIssueCols+`
+		 FROM issues i
+		 JOIN statuses s ON s.id = i.status_id
+		 WHERE i.sprint_id = $1
+		   AND i.archived_at IS NULL
+		 ORDER BY s.position, i.status_position`,
+		sprintID,
+	); err != nil {
+		return nil, fmt.Errorf("list sprint issues: %w", err)
+	}
+	if err := issues.LoadDetails(ctx, db, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// insertSprintEvents records an "updated" issue event with the sprint_id
+// change for each issue through the issues package, so webhooks, watcher
+// notifications and board streams see it like any other issue update.
+func insertSprintEvents(ctx context.Context, tx *sqlx.Tx, issueIDs []string, actorID string, from, to *string) error {
+	changes := map[string]issues.FieldChange{"sprint_id": {From: from, To: to}}
+	for _, id := range issueIDs {
+		if err := issues.RecordEventTx(ctx, tx, id, actorID, issues.EventUpdated, changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameSprint(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
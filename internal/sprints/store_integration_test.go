@@ -0,0 +1,248 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package sprints
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+type boardFixture struct {
+	projectID  string
+	boardID    string
+	userID     string
+	typeID     string
+	todoID     string
+	doneID     string
+	nextNumber int
+}
+
+func seedScrumBoard(t *testing.T, db *sqlx.DB) *boardFixture {
+	t.Helper()
+	ctx := context.Background()
+	fx := &boardFixture{userID: testpg.SeedUser(t, db)}
+	wsID := testpg.SeedWorkspace(t, db)
+	fx.projectID = testpg.SeedProject(t, db, wsID, "SPR")
+
+	if err := db.GetContext(ctx, &fx.typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, fx.projectID); err != nil {
+		t.Fatalf("insert issue_type: %v", err)
+	}
+	if err := db.GetContext(ctx, &fx.todoID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, fx.projectID); err != nil {
+		t.Fatalf("insert todo status: %v", err)
+	}
+	if err := db.GetContext(ctx, &fx.doneID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'Done', 'done', 1) RETURNING id`, fx.projectID); err != nil {
+		t.Fatalf("insert done status: %v", err)
+	}
+	if err := db.GetContext(ctx, &fx.boardID, `INSERT INTO boards (project_id, name, type) VALUES ($1, 'Scrum', 'scrum') RETURNING id`, fx.projectID); err != nil {
+		t.Fatalf("insert board: %v", err)
+	}
+	return fx
+}
+
+func (fx *boardFixture) issue(t *testing.T, db *sqlx.DB, statusID string) string {
+	t.Helper()
+	fx.nextNumber++
+	var id string
+	if err := db.GetContext(context.Background(), &id,
+		`INSERT INTO issues (project_id, number, issue_type_id, status_id, title, description, priority, reporter_id, status_position)
+		 VALUES ($1, $2, $3, $4, 'Issue', '', 'medium', $5, $2) RETURNING id`,
+		fx.projectID, fx.nextNumber, fx.typeID, statusID, fx.userID,
+	); err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+	return id
+}
+
+func issueSprint(t *testing.T, db *sqlx.DB, issueID string) *string {
+	t.Helper()
+	var sprintID *string
+	if err := db.GetContext(context.Background(), &sprintID, `SELECT sprint_id FROM issues WHERE id = $1`, issueID); err != nil {
+		t.Fatalf("load issue sprint: %v", err)
+	}
+	return sprintID
+}
+
+func TestSprintLifecycle(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	fx := seedScrumBoard(t, db)
+
+	s1, err := Create(ctx, db, CreateParams{BoardID: fx.boardID, Name: "Sprint 1", Goal: "Ship login"})
+	if err != nil {
+		t.Fatalf("create sprint 1: %v", err)
+	}
+	s2, err := Create(ctx, db, CreateParams{BoardID: fx.boardID, Name: "Sprint 2"})
+	if err != nil {
+		t.Fatalf("create sprint 2: %v", err)
+	}
+	if _, err := Create(ctx, db, CreateParams{BoardID: fx.boardID, Name: "Sprint 1"}); !errors.Is(err, ErrDuplicateName) {
+		t.Fatalf("duplicate name: error = %v, want ErrDuplicateName", err)
+	}
+
+	open := fx.issue(t, db, fx.todoID)
+	done := fx.issue(t, db, fx.doneID)
+	if err := SetIssues(ctx, db, SetIssuesParams{BoardID: fx.boardID, SprintID: s1.ID, IssueIDs: []string{open, done}, ActorID: fx.userID}); err != nil {
+		t.Fatalf("SetIssues() error = %v", err)
+	}
+
+	if _, err := Close(ctx, db, CloseParams{BoardID: fx.boardID, SprintID: s1.ID, ActorID: fx.userID}); !errors.Is(err, ErrNotActive) {
+		t.Fatalf("close planned sprint: error = %v, want ErrNotActive", err)
+	}
+
+	started, err := Start(ctx, db, fx.boardID, s1.ID)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if started.State != StateActive || started.StartDate == nil || started.StartedAt == nil {
+		t.Fatalf("started sprint = %+v", started)
+	}
+	if _, err := Start(ctx, db, fx.boardID, s2.ID); !errors.Is(err, ErrActiveSprint) {
+		t.Fatalf("second start: error = %v, want ErrActiveSprint", err)
+	}
+
+	result, err := Close(ctx, db, CloseParams{BoardID: fx.boardID, SprintID: s1.ID, ActorID: fx.userID})
+	if err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if result.Sprint.State != StateClosed || result.CompletedCount != 1 {
+		t.Fatalf("close result = %+v", result)
+	}
+	if result.NextSprintID == nil || *result.NextSprintID != s2.ID {
+		t.Fatalf("next sprint = %v, want %s", result.NextSprintID, s2.ID)
+	}
+	if len(result.MovedIssueIDs) != 1 || result.MovedIssueIDs[0] != open {
+		t.Fatalf("moved issues = %v, want [%s]", result.MovedIssueIDs, open)
+	}
+	if got := issueSprint(t, db, open); got == nil || *got != s2.ID {
+		t.Fatalf("open issue sprint = %v, want %s", got, s2.ID)
+	}
+	if got := issueSprint(t, db, done); got == nil || *got != s1.ID {
+		t.Fatalf("done issue sprint = %v, want %s", got, s1.ID)
+	}
+
+	if _, err := Update(ctx, db, UpdateParams{BoardID: fx.boardID, SprintID: s1.ID, Name: "Renamed"}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("update closed sprint: error = %v, want ErrClosed", err)
+	}
+	if err := SetIssues(ctx, db, SetIssuesParams{BoardID: fx.boardID, SprintID: s1.ID, IssueIDs: []string{open}, ActorID: fx.userID}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("add to closed sprint: error = %v, want ErrClosed", err)
+	}
+
+	// With no planned sprint left, unfinished issues return to the backlog.
+	if _, err := Start(ctx, db, fx.boardID, s2.ID); err != nil {
+		t.Fatalf("start sprint 2: %v", err)
+	}
+	result, err = Close(ctx, db, CloseParams{BoardID: fx.boardID, SprintID: s2.ID, ActorID: fx.userID})
+	if err != nil {
+		t.Fatalf("close sprint 2: %v", err)
+	}
+	if result.NextSprintID != nil {
+		t.Fatalf("next sprint = %v, want backlog", *result.NextSprintID)
+	}
+	if got := issueSprint(t, db, open); got != nil {
+		t.Fatalf("open issue sprint = %v, want backlog", *got)
+	}
+
+	var sprintEvents int
+	if err := db.GetContext(ctx, &sprintEvents,
+		`SELECT COUNT(*) FROM issue_events WHERE issue_id = $1 AND payload_json->'changes' ? 'sprint_id'`, open,
+	); err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	if sprintEvents != 3 {
+		t.Fatalf("sprint events = %d, want 3", sprintEvents)
+	}
+	var boardEvents int
+	if err := db.GetContext(ctx, &boardEvents,
+		`SELECT COUNT(*) FROM board_events WHERE issue_id = $1 AND payload->'changes' ? 'sprint_id'`, open,
+	); err != nil {
+		t.Fatalf("count board events: %v", err)
+	}
+	if boardEvents != 3 {
+		t.Fatalf("sprint board events = %d, want 3", boardEvents)
+	}
+
+	list, err := List(ctx, db, fx.boardID)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("List() = %d sprints, want 2", len(list))
+	}
+}
+
+func TestSprintStart_Concurrent(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	fx := seedScrumBoard(t, db)
+
+	const n = 5
+	ids := make([]string, n)
+	for i := range ids {
+		s, err := Create(ctx, db, CreateParams{BoardID: fx.boardID, Name: "Sprint " + string(rune('A'+i))})
+		if err != nil {
+			t.Fatalf("create sprint: %v", err)
+		}
+		ids[i] = s.ID
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = Start(ctx, db, fx.boardID, id)
+		}()
+	}
+	wg.Wait()
+
+	started := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			started++
+		case errors.Is(err, ErrActiveSprint):
+		default:
+			t.Fatalf("Start() unexpected error = %v", err)
+		}
+	}
+	if started != 1 {
+		t.Fatalf("started = %d, want exactly 1", started)
+	}
+}
+
+func TestSprints_RejectKanbanAndForeignIssues(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+	fx := seedScrumBoard(t, db)
+
+	var kanbanID string
+	if err := db.GetContext(ctx, &kanbanID, `INSERT INTO boards (project_id, name, type) VALUES ($1, 'Kanban', 'kanban') RETURNING id`, fx.projectID); err != nil {
+		t.Fatalf("insert kanban board: %v", err)
+	}
+	if _, err := Create(ctx, db, CreateParams{BoardID: kanbanID, Name: "Sprint 1"}); !errors.Is(err, ErrNotScrumBoard) {
+		t.Fatalf("kanban sprint: error = %v, want ErrNotScrumBoard", err)
+	}
+
+	other := seedScrumBoard(t, db)
+	foreign := other.issue(t, db, other.todoID)
+	s, err := Create(ctx, db, CreateParams{BoardID: fx.boardID, Name: "Sprint 1"})
+	if err != nil {
+		t.Fatalf("create sprint: %v", err)
+	}
+	if err := SetIssues(ctx, db, SetIssuesParams{BoardID: fx.boardID, SprintID: s.ID, IssueIDs: []string{foreign}, ActorID: fx.userID}); !errors.Is(err, ErrIssueNotInProject) {
+		t.Fatalf("foreign issue: error = %v, want ErrIssueNotInProject", err)
+	}
+}
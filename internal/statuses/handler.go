@@ -20,6 +20,8 @@ func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
 	mux.HandleFunc("GET /projects/{projectID}/statuses", handleList(db))
 	mux.HandleFunc("PUT /projects/{projectID}/statuses/{statusID}", handleUpdate(db))
 	mux.HandleFunc("DELETE /projects/{projectID}/statuses/{statusID}", handleArchive(db))
+	mux.HandleFunc("GET /projects/{projectID}/transitions", handleListTransitions(db))
+	mux.HandleFunc("PUT /projects/{projectID}/transitions", handleReplaceTransitions(db))
 }
 
 func fail(w http.ResponseWriter, err error) {
@@ -35,6 +37,9 @@ func fail(w http.ResponseWriter, err error) {
 		respond.Error(w, http.StatusNotFound, err.Error())
 	case errors.Is(err, ErrDuplicate):
 		respond.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidTransition),
+		errors.Is(err, ErrInvalidIssueType):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
 	default:
 		slog.Error("statuses handler error", "error", err)
 		respond.Error(w, http.StatusInternalServerError, "internal server error")
@@ -44,12 +49,7 @@ func fail(w http.ResponseWriter, err error) {
 func handleCreate(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		projID := r.PathValue("projectID")
-		wsID, err := authz.RequireProjectMembership(r.Context(), db, projID)
-		if err != nil {
-			fail(w, err)
-			return
-		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -98,12 +98,7 @@ func handleList(db *sqlx.DB) http.HandlerFunc {
 func handleUpdate(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		projID := r.PathValue("projectID")
-		wsID, err := authz.RequireProjectMembership(r.Context(), db, projID)
-		if err != nil {
-			fail(w, err)
-			return
-		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
@@ -137,19 +132,72 @@ func handleUpdate(db *sqlx.DB) http.HandlerFunc {
 func handleArchive(db *sqlx.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		projID := r.PathValue("projectID")
-		wsID, err := authz.RequireProjectMembership(r.Context(), db, projID)
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
+			fail(w, err)
+			return
+		}
+		if err := Archive(r.Context(), db, projID, r.PathValue("statusID")); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func handleListTransitions(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projID := r.PathValue("projectID")
+		if _, err := authz.RequireProjectMembership(r.Context(), db, projID); err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := ListTransitions(r.Context(), db, projID, r.URL.Query().Get("issue_type_id"))
 		if err != nil {
 			fail(w, err)
 			return
 		}
-		if err := authz.RequireWorkspaceAdmin(r.Context(), db, wsID); err != nil {
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+func handleReplaceTransitions(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projID := r.PathValue("projectID")
+		if _, err := authz.RequireProjectRole(r.Context(), db, projID, authz.RoleAdmin); err != nil {
 			fail(w, err)
 			return
 		}
-		if err := Archive(r.Context(), db, projID, r.PathValue("statusID")); err != nil {
+		var body struct {
+			IssueTypeID string `json:"issue_type_id"`
+			Transitions []struct {
+				FromStatusID    string `json:"from_status_id"`
+				ToStatusID      string `json:"to_status_id"`
+				RequireAssignee bool   `json:"require_assignee"`
+				AdminOnly       bool   `json:"admin_only"`
+			} `json:"transitions"`
+		}
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := ReplaceTransitionsParams{ProjectID: projID, IssueTypeID: body.IssueTypeID}
+		for _, t := range body.Transitions {
+			params.Transitions = append(params.Transitions, TransitionRule{
+				FromStatusID:    t.FromStatusID,
+				ToStatusID:      t.ToStatusID,
+				RequireAssignee: t.RequireAssignee,
+				AdminOnly:       t.AdminOnly,
+			})
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		list, err := ReplaceTransitions(r.Context(), db, params)
+		if err != nil {
 			fail(w, err)
 			return
 		}
-		w.WriteHeader(http.StatusNoContent)
+		respond.JSON(w, http.StatusOK, list)
 	}
 }
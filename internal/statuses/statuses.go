@@ -83,6 +83,17 @@ func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Status, erro
 	return createStatus(ctx, db, params)
 }
 
+// CreateTx creates a status inside the caller's transaction.
+func CreateTx(ctx context.Context, tx *sqlx.Tx, params CreateParams) (Status, error) {
+	if tx == nil {
+		return Status{}, errors.New("tx is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Status{}, err
+	}
+	return createStatus(ctx, tx, params)
+}
+
 func List(ctx context.Context, db *sqlx.DB, projectID string) ([]Status, error) {
 	if db == nil {
 		return nil, errors.New("db is required")
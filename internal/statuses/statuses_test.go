@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package statuses
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestReplaceTransitionsParams_Validate(t *testing.T) {
+	rule := TransitionRule{FromStatusID: "a", ToStatusID: "b"}
+	tests := []struct {
+		name    string
+		params  ReplaceTransitionsParams
+		wantErr bool
+	}{
+		{name: "valid", params: ReplaceTransitionsParams{ProjectID: "p", Transitions: []TransitionRule{rule}}},
+		{name: "empty graph", params: ReplaceTransitionsParams{ProjectID: "p"}},
+		{name: "missing project_id", params: ReplaceTransitionsParams{Transitions: []TransitionRule{rule}}, wantErr: true},
+		{name: "missing status", params: ReplaceTransitionsParams{ProjectID: "p", Transitions: []TransitionRule{{FromStatusID: "a"}}}, wantErr: true},
+		{name: "self transition", params: ReplaceTransitionsParams{ProjectID: "p", Transitions: []TransitionRule{{FromStatusID: "a", ToStatusID: "a"}}}, wantErr: true},
+		{name: "duplicate edge", params: ReplaceTransitionsParams{ProjectID: "p", Transitions: []TransitionRule{rule, rule}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rules := []outgoing{
+		{ToStatusID: "doing", ToName: "Doing"},
+		{ToStatusID: "review", ToName: "Review", RequireAssignee: true},
+		{ToStatusID: "done", ToName: "Done", AdminOnly: true},
+	}
+	tests := []struct {
+		name        string
+		check       TransitionCheck
+		wantReason  string
+		wantAllowed []string
+	}{
+		{name: "plain rule", check: TransitionCheck{ToStatusID: "doing"}},
+		{name: "missing rule", check: TransitionCheck{ToStatusID: "todo"}, wantReason: "is not allowed by the workflow", wantAllowed: []string{"Doing"}},
+		{name: "needs assignee", check: TransitionCheck{ToStatusID: "review"}, wantReason: "requires an assignee", wantAllowed: []string{"Doing"}},
+		{name: "has assignee", check: TransitionCheck{ToStatusID: "review", HasAssignee: true}},
+		{name: "needs admin", check: TransitionCheck{ToStatusID: "done", HasAssignee: true}, wantReason: "is restricted to project admins", wantAllowed: []string{"Doing", "Review"}},
+		{name: "admin", check: TransitionCheck{ToStatusID: "done", ActorIsAdmin: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, allowed := evaluate(rules, tt.check)
+			if reason != tt.wantReason {
+				t.Fatalf("reason = %q, want %q", reason, tt.wantReason)
+			}
+			if tt.wantReason != "" && !slices.Equal(allowed, tt.wantAllowed) {
+				t.Fatalf("allowed = %v, want %v", allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestTransitionError(t *testing.T) {
+	err := error(&TransitionError{From: "To do", To: "Done", Reason: "is not allowed by the workflow", Allowed: []string{"Doing"}})
+	if !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Fatal("TransitionError does not match ErrTransitionNotAllowed")
+	}
+	want := `moving from "To do" to "Done" is not allowed by the workflow; allowed targets: "Doing"`
+	if err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+	none := &TransitionError{From: "Done", To: "To do", Reason: "is not allowed by the workflow"}
+	if got := none.Error(); got != `moving from "Done" to "To do" is not allowed by the workflow; allowed targets: none` {
+		t.Fatalf("Error() = %q", got)
+	}
+}
@@ -12,14 +12,15 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/start-codex/tookly/internal/pgutil"
 )
 
 const statusCols = `id, project_id, name, category, position, created_at, updated_at, archived_at`
 
-func createStatus(ctx context.Context, db *sqlx.DB, params CreateParams) (Status, error) {
+func createStatus(ctx context.Context, q sqlx.QueryerContext, params CreateParams) (Status, error) {
 	var status Status
-	err := db.QueryRowxContext(ctx,
+	err := q.QueryRowxContext(ctx,
 		`INSERT INTO statuses (project_id, name, category, position)
 		 VALUES ($1, $2, $3,
 		   COALESCE(
@@ -99,3 +100,161 @@ func archiveStatus(ctx context.Context, db *sqlx.DB, projectID, statusID string)
 	}
 	return nil
 }
+
+const transitionCols = `id, project_id, issue_type_id, from_status_id, to_status_id,
+	require_assignee, admin_only, created_at`
+
+func listTransitions(ctx context.Context, q sqlx.QueryerContext, projectID, issueTypeID string) ([]Transition, error) {
+	var scope *string
+	if issueTypeID != "" {
+		scope = &issueTypeID
+	}
+	transitions := []Transition{}
+	if err := sqlx.SelectContext(ctx, q, &transitions,
+		`SELECT `+transitionCols+`
+		 FROM status_transitions
+		 WHERE project_id = $1
+		   AND issue_type_id::text IS NOT DISTINCT FROM $2
+		 ORDER BY created_at ASC, id ASC`,
+		projectID, scope,
+	); err != nil {
+		return nil, fmt.Errorf("list transitions: %w", err)
+	}
+	return transitions, nil
+}
+
+func replaceTransitions(ctx context.Context, db *sqlx.DB, params ReplaceTransitionsParams) ([]Transition, error) {
+	var scope *string
+	if params.IssueTypeID != "" {
+		scope = &params.IssueTypeID
+	}
+	var out []Transition
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit replace transitions", func(tx *sqlx.Tx) error {
+		if scope != nil {
+			var ok bool
+			if err := tx.GetContext(ctx, &ok,
+				`SELECT EXISTS(
+					SELECT 1 FROM issue_types
+					WHERE id::text = $1 AND project_id = $2 AND archived_at IS NULL
+				)`,
+				params.IssueTypeID, params.ProjectID,
+			); err != nil {
+				return fmt.Errorf("check issue type: %w", err)
+			}
+			if !ok {
+				return ErrInvalidIssueType
+			}
+		}
+
+		ids := map[string]bool{}
+		for _, t := range params.Transitions {
+			ids[t.FromStatusID] = true
+			ids[t.ToStatusID] = true
+		}
+		statusIDs := make([]string, 0, len(ids))
+		for id := range ids {
+			statusIDs = append(statusIDs, id)
+		}
+		var active int
+		if err := tx.GetContext(ctx, &active,
+			`SELECT COUNT(*) FROM statuses
+			 WHERE project_id = $1 AND archived_at IS NULL AND id::text = ANY($2)`,
+			params.ProjectID, pq.Array(statusIDs),
+		); err != nil {
+			return fmt.Errorf("check transition statuses: %w", err)
+		}
+		if active != len(statusIDs) {
+			return ErrInvalidTransition
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM status_transitions
+			 WHERE project_id = $1
+			   AND issue_type_id::text IS NOT DISTINCT FROM $2`,
+			params.ProjectID, scope,
+		); err != nil {
+			return fmt.Errorf("clear transitions: %w", err)
+		}
+		for _, t := range params.Transitions {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO status_transitions
+				   (project_id, issue_type_id, from_status_id, to_status_id, require_assignee, admin_only)
+				 VALUES ($1, $2, $3, $4, $5, $6)`,
+				params.ProjectID, scope, t.FromStatusID, t.ToStatusID, t.RequireAssignee, t.AdminOnly,
+			); err != nil {
+				return fmt.Errorf("insert transition: %w", err)
+			}
+		}
+
+		var err error
+		out, err = listTransitions(ctx, tx, params.ProjectID, params.IssueTypeID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func checkTransition(ctx context.Context, tx *sqlx.Tx, params TransitionCheck) error {
+	// The issue type's own graph wins; otherwise the project graph applies.
+	var scope sql.NullString
+	var hasGraph bool
+	if err := tx.QueryRowxContext(ctx,
+		`SELECT
+		   CASE WHEN EXISTS (SELECT 1 FROM status_transitions WHERE project_id = $1 AND issue_type_id = $2)
+		        THEN $2 END,
+		   EXISTS (SELECT 1 FROM status_transitions
+		           WHERE project_id = $1 AND (issue_type_id = $2 OR issue_type_id IS NULL))`,
+		params.ProjectID, params.IssueTypeID,
+	).Scan(&scope, &hasGraph); err != nil {
+		return fmt.Errorf("resolve workflow: %w", err)
+	}
+	if !hasGraph {
+		return nil
+	}
+
+	rules := []outgoing{}
+	if err := tx.SelectContext(ctx, &rules,
+		`SELECT t.to_status_id, s.name AS to_name, t.require_assignee, t.admin_only
+		 FROM status_transitions t
+		 JOIN statuses s ON s.id = t.to_status_id AND s.archived_at IS NULL
+		 WHERE t.project_id = $1
+		   AND t.from_status_id = $2
+		   AND t.issue_type_id::text IS NOT DISTINCT FROM $3
+		 ORDER BY s.position ASC`,
+		params.ProjectID, params.FromStatusID, scope,
+	); err != nil {
+		return fmt.Errorf("load transitions: %w", err)
+	}
+	reason, allowed := evaluate(rules, params)
+	if reason == "" {
+		return nil
+	}
+
+	names := map[string]string{}
+	rows, err := tx.QueryxContext(ctx,
+		`SELECT id, name FROM statuses WHERE id = $1 OR id = $2`,
+		params.FromStatusID, params.ToStatusID,
+	)
+	if err != nil {
+		return fmt.Errorf("load status names: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return fmt.Errorf("scan status name: %w", err)
+		}
+		names[id] = name
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate status names: %w", err)
+	}
+	return &TransitionError{
+		From:    names[params.FromStatusID],
+		To:      names[params.ToStatusID],
+		Reason:  reason,
+		Allowed: allowed,
+	}
+}
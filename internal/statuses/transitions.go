@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package statuses
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrInvalidTransition    = errors.New("transitions must connect two different active statuses of the project, at most once each")
+	ErrInvalidIssueType     = errors.New("issue_type_id must reference an active issue type of the project")
+	ErrTransitionNotAllowed = errors.New("status transition not allowed")
+)
+
+// Transition allows issues to move from one status to another. A nil
+// IssueTypeID marks a rule of the project graph; otherwise the rule belongs
+// to the graph of that issue type, which replaces the project graph for
+// issues of the type.
+type Transition struct {
+	ID              string    `db:"id"               json:"id"`
+	ProjectID       string    `db:"project_id"       json:"project_id"`
+	IssueTypeID     *string   `db:"issue_type_id"    json:"issue_type_id"`
+	FromStatusID    string    `db:"from_status_id"   json:"from_status_id"`
+	ToStatusID      string    `db:"to_status_id"     json:"to_status_id"`
+	RequireAssignee bool      `db:"require_assignee" json:"require_assignee"`
+	AdminOnly       bool      `db:"admin_only"       json:"admin_only"`
+	CreatedAt       time.Time `db:"created_at"       json:"created_at"`
+}
+
+// TransitionRule is one edge of a graph being replaced.
+type TransitionRule struct {
+	FromStatusID    string
+	ToStatusID      string
+	RequireAssignee bool
+	AdminOnly       bool
+}
+
+// ReplaceTransitionsParams replaces the project graph, or the graph of one
+// issue type when IssueTypeID is set. An empty Transitions removes the
+// graph: issues fall back to the project graph, or move freely.
+type ReplaceTransitionsParams struct {
+	ProjectID   string
+	IssueTypeID string
+	Transitions []TransitionRule
+}
+
+func (params ReplaceTransitionsParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	seen := map[[2]string]bool{}
+	for _, t := range params.Transitions {
+		edge := [2]string{t.FromStatusID, t.ToStatusID}
+		if t.FromStatusID == "" || t.ToStatusID == "" || t.FromStatusID == t.ToStatusID || seen[edge] {
+			return ErrInvalidTransition
+		}
+		seen[edge] = true
+	}
+	return nil
+}
+
+// TransitionCheck describes a status change about to be made to an issue.
+type TransitionCheck struct {
+	ProjectID    string
+	IssueTypeID  string
+	FromStatusID string
+	ToStatusID   string
+	HasAssignee  bool
+	ActorIsAdmin bool
+}
+
+// TransitionError rejects a move that the workflow does not allow. Allowed
+// lists the names of the statuses the issue could move to instead.
+type TransitionError struct {
+	From    string
+	To      string
+	Reason  string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	allowed := "none"
+	if len(e.Allowed) > 0 {
+		quoted := make([]string, len(e.Allowed))
+		for i, name := range e.Allowed {
+			quoted[i] = strconv.Quote(name)
+		}
+		allowed = strings.Join(quoted, ", ")
+	}
+	return "moving from " + strconv.Quote(e.From) + " to " + strconv.Quote(e.To) + " " + e.Reason + "; allowed targets: " + allowed
+}
+
+func (e *TransitionError) Unwrap() error { return ErrTransitionNotAllowed }
+
+// ListTransitions returns the project graph, or the graph of issueTypeID when
+// it is non-empty.
+func ListTransitions(ctx context.Context, db *sqlx.DB, projectID, issueTypeID string) ([]Transition, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" {
+		return nil, errors.New("project_id is required")
+	}
+	return listTransitions(ctx, db, projectID, issueTypeID)
+}
+
+func ReplaceTransitions(ctx context.Context, db *sqlx.DB, params ReplaceTransitionsParams) ([]Transition, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return replaceTransitions(ctx, db, params)
+}
+
+// CheckTransition returns a *TransitionError when the workflow of the issue
+// type forbids the move, inside the caller's transaction. Moves within a
+// status and moves in projects without a graph are always allowed.
+func CheckTransition(ctx context.Context, tx *sqlx.Tx, params TransitionCheck) error {
+	if tx == nil {
+		return errors.New("tx is required")
+	}
+	if params.ProjectID == "" || params.IssueTypeID == "" || params.FromStatusID == "" || params.ToStatusID == "" {
+		return errors.New("project_id, issue_type_id and both status ids are required")
+	}
+	if params.FromStatusID == params.ToStatusID {
+		return nil
+	}
+	return checkTransition(ctx, tx, params)
+}
+
+// outgoing is a transition out of the issue's current status.
+type outgoing struct {
+	ToStatusID      string `db:"to_status_id"`
+	ToName          string `db:"to_name"`
+	RequireAssignee bool   `db:"require_assignee"`
+	AdminOnly       bool   `db:"admin_only"`
+}
+
+// evaluate returns why the move in params is rejected, or "" if it is
+// allowed, along with the targets the move could use instead.
+func evaluate(rules []outgoing, params TransitionCheck) (string, []string) {
+	reason := "is not allowed by the workflow"
+	allowed := []string{}
+	for _, r := range rules {
+		var why string
+		switch {
+		case r.AdminOnly && !params.ActorIsAdmin:
+			why = "is restricted to project admins"
+		case r.RequireAssignee && !params.HasAssignee:
+			why = "requires an assignee"
+		}
+		if r.ToStatusID == params.ToStatusID {
+			if why == "" {
+				return "", nil
+			}
+			reason = why
+		} else if why == "" {
+			allowed = append(allowed, r.ToName)
+		}
+	}
+	return reason, allowed
+}
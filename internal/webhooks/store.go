@@ -0,0 +1,203 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// webhookCols leaves the secret out; only Create returns it.
+const webhookCols = `id, workspace_id, url, '' AS secret, events, active, created_by, created_at, updated_at`
+
+const deliveryCols = `id, webhook_id, event_type, payload, status, attempts, next_attempt_at,
+	last_attempt_at, response_status, last_error, created_at, delivered_at`
+
+func createWebhook(ctx context.Context, db *sqlx.DB, params CreateParams) (Webhook, error) {
+	var hook Webhook
+	if err := db.QueryRowxContext(ctx,
+		`INSERT INTO webhooks (workspace_id, url, secret, events, active, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, workspace_id, url, secret, events, active, created_by, created_at, updated_at`,
+		params.WorkspaceID, params.URL, params.Secret, pq.Array(nonNil(params.Events)), params.Active, params.CreatedBy,
+	).StructScan(&hook); err != nil {
+		return Webhook{}, fmt.Errorf("insert webhook: %w", err)
+	}
+	return hook, nil
+}
+
+func listWebhooks(ctx context.Context, db *sqlx.DB, workspaceID string) ([]Webhook, error) {
+	hooks := []Webhook{}
+	if err := db.SelectContext(ctx, &hooks,
+		`SELECT `+webhookCols+`
+		 FROM webhooks
+		 WHERE workspace_id = $1
+		 ORDER BY created_at ASC`,
+		workspaceID,
+	); err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+func updateWebhook(ctx context.Context, db *sqlx.DB, params UpdateParams) (Webhook, error) {
+	var hook Webhook
+	err := db.QueryRowxContext(ctx,
+		`UPDATE webhooks
+		 SET url    = $1,
+		     events = $2,
+		     active = $3,
+		     secret = COALESCE(NULLIF($4, ''), secret)
+		 WHERE id = $5
+		   AND workspace_id = $6
+		 RETURNING `+webhookCols,
+		params.URL, pq.Array(nonNil(params.Events)), params.Active, params.Secret,
+		params.WebhookID, params.WorkspaceID,
+	).StructScan(&hook)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Webhook{}, ErrNotFound
+		}
+		return Webhook{}, fmt.Errorf("update webhook: %w", err)
+	}
+	return hook, nil
+}
+
+func deleteWebhook(ctx context.Context, db *sqlx.DB, workspaceID, webhookID string) error {
+	res, err := db.ExecContext(ctx,
+		`DELETE FROM webhooks WHERE id = $1 AND workspace_id = $2`,
+		webhookID, workspaceID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete webhook rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func listDeliveries(ctx context.Context, db *sqlx.DB, params ListDeliveriesParams) ([]Delivery, error) {
+	var exists bool
+	if err := db.GetContext(ctx, &exists,
+		`SELECT EXISTS(SELECT 1 FROM webhooks WHERE id = $1 AND workspace_id = $2)`,
+		params.WebhookID, params.WorkspaceID,
+	); err != nil {
+		return nil, fmt.Errorf("check webhook: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	deliveries := []Delivery{}
+	if err := db.SelectContext(ctx, &deliveries,
+		`SELECT `+deliveryCols+`
+		 FROM webhook_deliveries
+		 WHERE webhook_id = $1
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT $2 OFFSET $3`,
+		params.WebhookID, params.Limit, params.Offset,
+	); err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func enqueue(ctx context.Context, tx *sqlx.Tx, workspaceID, eventType string, body []byte) error {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+		 SELECT id, $2, $3
+		 FROM webhooks
+		 WHERE workspace_id = $1
+		   AND active
+		   AND (cardinality(events) = 0 OR $2 = ANY(events))`,
+		workspaceID, eventType, body,
+	); err != nil {
+		return fmt.Errorf("enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// pendingDelivery is a claimed outbox row with what is needed to send it.
+type pendingDelivery struct {
+	ID        string `db:"id"`
+	EventType string `db:"event_type"`
+	Payload   []byte `db:"payload"`
+	Attempts  int    `db:"attempts"`
+	URL       string `db:"url"`
+	Secret    string `db:"secret"`
+}
+
+// claimDeliveries leases up to limit due deliveries by pushing their next
+// attempt past lease, so concurrent workers skip them while they are sent.
+func claimDeliveries(ctx context.Context, db *sqlx.DB, limit int, lease time.Duration) ([]pendingDelivery, error) {
+	claimed := []pendingDelivery{}
+	if err := db.SelectContext(ctx, &claimed,
+		`WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			WHERE d.status = 'pending'
+			  AND d.next_attempt_at <= NOW()
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due, webhooks w
+		WHERE d.id = due.id
+		  AND w.id = d.webhook_id
+		RETURNING d.id, d.event_type, d.payload, d.attempts, w.url, w.secret`,
+		limit, lease.Seconds(),
+	); err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	return claimed, nil
+}
+
+// recordAttempt stores the outcome of one delivery attempt. A nil retryAt
+// marks the delivery as delivered when failure is empty, or as failed for
+// good otherwise.
+func recordAttempt(ctx context.Context, db *sqlx.DB, id string, responseStatus *int, failure string, retryAt *time.Time) error {
+	status := StatusPending
+	switch {
+	case failure == "":
+		status = StatusDelivered
+	case retryAt == nil:
+		status = StatusFailed
+	}
+	if _, err := db.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		 SET status          = $2,
+		     attempts        = attempts + 1,
+		     last_attempt_at = NOW(),
+		     response_status = $3,
+		     last_error      = $4,
+		     next_attempt_at = COALESCE($5, next_attempt_at),
+		     delivered_at    = CASE WHEN $2 = 'delivered' THEN NOW() END
+		 WHERE id = $1`,
+		id, status, responseStatus, failure, retryAt,
+	); err != nil {
+		return fmt.Errorf("record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
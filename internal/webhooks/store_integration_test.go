@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func enqueueEvent(t *testing.T, db *sqlx.DB, e Event) {
+	t.Helper()
+	err := pgutil.WithTx(context.Background(), db, nil, "begin tx", "commit enqueue", func(tx *sqlx.Tx) error {
+		return Enqueue(context.Background(), tx, e)
+	})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+
+	var received atomic.Int32
+	var lastSig, lastBody atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lastBody.Store(body)
+		lastSig.Store(r.Header.Get(HeaderSignature))
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	all, err := Create(ctx, db, CreateParams{WorkspaceID: wsID, URL: hookURL, Active: true, CreatedBy: userID})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if all.Secret == "" {
+		t.Fatal("Create did not return a generated secret")
+	}
+	filtered, err := Create(ctx, db, CreateParams{WorkspaceID: wsID, URL: hookURL, Secret: "s", Events: []string{EventBoardCreated}, Active: true, CreatedBy: userID})
+	if err != nil {
+		t.Fatalf("Create filtered: %v", err)
+	}
+
+	hooks, err := List(ctx, db, wsID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(hooks) != 2 || hooks[0].Secret != "" {
+		t.Fatalf("List = %+v, want 2 webhooks without secrets", hooks)
+	}
+
+	enqueueEvent(t, db, Event{WorkspaceID: wsID, Type: EventIssueCreated, ActorID: userID, Data: map[string]string{"key": "ABC-1"}})
+
+	w := &Worker{DB: db, Client: testClient(srv)}
+	n, err := w.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if n != 1 || received.Load() != 1 {
+		t.Fatalf("RunOnce sent %d, receiver got %d, want 1 (filtered webhook skipped)", n, received.Load())
+	}
+	if !Verify(all.Secret, lastBody.Load().([]byte), lastSig.Load().(string)) {
+		t.Fatal("delivery signature does not verify")
+	}
+
+	deliveries, err := ListDeliveries(ctx, db, ListDeliveriesParams{WorkspaceID: wsID, WebhookID: all.ID})
+	if err != nil {
+		t.Fatalf("ListDeliveries: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != StatusDelivered || deliveries[0].Attempts != 1 {
+		t.Fatalf("deliveries = %+v, want one delivered", deliveries)
+	}
+	if n, _ := w.RunOnce(ctx); n != 0 {
+		t.Fatalf("second RunOnce sent %d, want 0", n)
+	}
+
+	if _, err := ListDeliveries(ctx, db, ListDeliveriesParams{WorkspaceID: testpg.SeedWorkspace(t, db), WebhookID: filtered.ID}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("ListDeliveries other workspace = %v, want ErrNotFound", err)
+	}
+	if err := Delete(ctx, db, wsID, filtered.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := Delete(ctx, db, wsID, filtered.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete twice = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	userID := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	hook, err := Create(ctx, db, CreateParams{WorkspaceID: wsID, URL: hookURL, Active: true, CreatedBy: userID})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	enqueueEvent(t, db, Event{WorkspaceID: wsID, Type: EventMemberAdded, ActorID: userID})
+
+	// Retry immediately so the test can drive every attempt.
+	w := &Worker{DB: db, Client: testClient(srv), MaxAttempts: 2, Backoff: func(int) time.Duration { return 0 }}
+	for range 3 {
+		if _, err := w.RunOnce(ctx); err != nil {
+			t.Fatalf("RunOnce: %v", err)
+		}
+	}
+
+	deliveries, err := ListDeliveries(ctx, db, ListDeliveriesParams{WorkspaceID: wsID, WebhookID: hook.ID})
+	if err != nil {
+		t.Fatalf("ListDeliveries: %v", err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(deliveries))
+	}
+	d := deliveries[0]
+	if d.Status != StatusFailed || d.Attempts != 2 {
+		t.Fatalf("delivery status = %s after %d attempts, want failed after 2", d.Status, d.Attempts)
+	}
+	if d.ResponseStatus == nil || *d.ResponseStatus != http.StatusServiceUnavailable || d.LastError == "" {
+		t.Fatalf("delivery = %+v, want 503 with error", d)
+	}
+}
@@ -0,0 +1,363 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package webhooks delivers workspace events to subscribed HTTP endpoints.
+// Producers call Enqueue inside their own transaction, which writes one
+// outbox row per matching subscription; a Worker drains the outbox, signing
+// each request body with HMAC-SHA256 and retrying failures with exponential
+// backoff.
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/authz"
+)
+
+var (
+	ErrNotFound     = errors.New("webhook not found")
+	ErrInvalidURL   = errors.New("url must be an absolute http or https URL")
+	ErrBlockedHost  = errors.New("url must not point to a loopback, link-local or private address")
+	ErrInvalidEvent = errors.New("events must be known event types")
+)
+
+// Event types.
+const (
+	EventIssueCreated      = "issue.created"
+	EventIssueUpdated      = "issue.updated"
+	EventIssueMoved        = "issue.moved"
+	EventIssueArchived     = "issue.archived"
+	EventIssueCommented    = "issue.commented"
+	EventBoardCreated      = "board.created"
+	EventBoardArchived     = "board.archived"
+	EventMemberAdded       = "member.added"
+	EventMemberRemoved     = "member.removed"
+	EventMemberRoleChanged = "member.role_changed"
+)
+
+var validEvents = map[string]bool{
+	EventIssueCreated: true, EventIssueUpdated: true, EventIssueMoved: true,
+	EventIssueArchived: true, EventIssueCommented: true,
+	EventBoardCreated: true, EventBoardArchived: true,
+	EventMemberAdded: true, EventMemberRemoved: true, EventMemberRoleChanged: true,
+}
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Request headers set on every delivery.
+const (
+	HeaderEvent     = "X-Tookly-Event"
+	HeaderDelivery  = "X-Tookly-Delivery"
+	HeaderSignature = "X-Tookly-Signature"
+)
+
+// Webhook is a workspace subscription. Secret is only populated in the
+// response to Create.
+type Webhook struct {
+	ID          string         `db:"id"           json:"id"`
+	WorkspaceID string         `db:"workspace_id" json:"workspace_id"`
+	URL         string         `db:"url"          json:"url"`
+	Secret      string         `db:"secret"       json:"secret,omitempty"`
+	Events      pq.StringArray `db:"events"       json:"events"`
+	Active      bool           `db:"active"       json:"active"`
+	CreatedBy   *string        `db:"created_by"   json:"created_by"`
+	CreatedAt   time.Time      `db:"created_at"   json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"   json:"updated_at"`
+}
+
+// Delivery is one attempt record of the delivery log.
+type Delivery struct {
+	ID             string          `db:"id"              json:"id"`
+	WebhookID      string          `db:"webhook_id"      json:"webhook_id"`
+	EventType      string          `db:"event_type"      json:"event_type"`
+	Payload        json.RawMessage `db:"payload"         json:"payload"`
+	Status         string          `db:"status"          json:"status"`
+	Attempts       int             `db:"attempts"        json:"attempts"`
+	NextAttemptAt  time.Time       `db:"next_attempt_at" json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `db:"last_attempt_at" json:"last_attempt_at"`
+	ResponseStatus *int            `db:"response_status" json:"response_status"`
+	LastError      string          `db:"last_error"      json:"last_error"`
+	CreatedAt      time.Time       `db:"created_at"      json:"created_at"`
+	DeliveredAt    *time.Time      `db:"delivered_at"    json:"delivered_at"`
+}
+
+// Event is a change to announce. Data is marshalled as the "data" member of
+// the delivered JSON body.
+type Event struct {
+	WorkspaceID string
+	Type        string
+	ActorID     string
+	Data        any
+}
+
+// MemberData is the data of member.* events. ProjectID is set for project
+// memberships and empty for workspace memberships.
+type MemberData struct {
+	WorkspaceID string `json:"workspace_id"`
+	ProjectID   string `json:"project_id,omitempty"`
+	UserID      string `json:"user_id"`
+	Role        string `json:"role"`
+}
+
+// payload is the JSON body of a delivery.
+type payload struct {
+	Type        string    `json:"type"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	WorkspaceID string    `json:"workspace_id"`
+	ActorID     string    `json:"actor_id,omitempty"`
+	Data        any       `json:"data"`
+}
+
+type CreateParams struct {
+	WorkspaceID string
+	URL         string
+	Secret      string
+	Events      []string
+	Active      bool
+	CreatedBy   string
+}
+
+func (params CreateParams) Validate() error {
+	if params.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if params.CreatedBy == "" {
+		return errors.New("created_by is required")
+	}
+	return validate(params.URL, params.Events)
+}
+
+// UpdateParams replaces the URL, event filter and active flag. A non-empty
+// Secret rotates the signing secret.
+type UpdateParams struct {
+	WorkspaceID string
+	WebhookID   string
+	URL         string
+	Secret      string
+	Events      []string
+	Active      bool
+}
+
+func (params UpdateParams) Validate() error {
+	if params.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if params.WebhookID == "" {
+		return errors.New("webhook_id is required")
+	}
+	return validate(params.URL, params.Events)
+}
+
+type ListDeliveriesParams struct {
+	WorkspaceID string
+	WebhookID   string
+	Limit       int
+	Offset      int
+}
+
+func (params ListDeliveriesParams) Validate() error {
+	if params.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if params.WebhookID == "" {
+		return errors.New("webhook_id is required")
+	}
+	if params.Limit < 0 || params.Limit > 100 {
+		return errors.New("limit must be between 0 and 100")
+	}
+	if params.Offset < 0 {
+		return errors.New("offset must be >= 0")
+	}
+	return nil
+}
+
+func validate(rawURL string, events []string) error {
+	if err := checkURL(rawURL); err != nil {
+		return err
+	}
+	for _, e := range events {
+		if !validEvents[e] {
+			return ErrInvalidEvent
+		}
+	}
+	return nil
+}
+
+// checkURL rejects URLs that are not absolute http(s) URLs and those whose
+// host is a blocked IP address or a localhost name. Host names are checked
+// again at delivery, against the addresses they resolve to.
+func checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrBlockedHost
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && blockedAddr(addr) {
+		return ErrBlockedHost
+	}
+	return nil
+}
+
+// sharedPrefixes are ranges that are neither public nor covered by the
+// netip predicates: "this network" and carrier-grade NAT.
+var sharedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// blockedAddr reports whether webhooks may not be delivered to addr, so that
+// they cannot reach the server itself or services on its network, such as a
+// cloud metadata endpoint at 169.254.169.254.
+func blockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, p := range sharedPrefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Create registers a webhook. When params.Secret is empty a random secret is
+// generated; either way it is returned once on the created webhook.
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Webhook, error) {
+	if db == nil {
+		return Webhook{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Webhook{}, err
+	}
+	if params.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return Webhook{}, err
+		}
+		params.Secret = secret
+	}
+	return createWebhook(ctx, db, params)
+}
+
+func List(ctx context.Context, db *sqlx.DB, workspaceID string) ([]Webhook, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if workspaceID == "" {
+		return nil, errors.New("workspace_id is required")
+	}
+	return listWebhooks(ctx, db, workspaceID)
+}
+
+func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Webhook, error) {
+	if db == nil {
+		return Webhook{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Webhook{}, err
+	}
+	return updateWebhook(ctx, db, params)
+}
+
+// Delete removes a webhook together with its delivery log.
+func Delete(ctx context.Context, db *sqlx.DB, workspaceID, webhookID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if workspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if webhookID == "" {
+		return errors.New("webhook_id is required")
+	}
+	return deleteWebhook(ctx, db, workspaceID, webhookID)
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first.
+func ListDeliveries(ctx context.Context, db *sqlx.DB, params ListDeliveriesParams) ([]Delivery, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.Limit == 0 {
+		params.Limit = 50
+	}
+	return listDeliveries(ctx, db, params)
+}
+
+// Enqueue writes e to the outbox of every active webhook of the workspace
+// subscribed to its type, inside the caller's transaction. Without an
+// explicit ActorID the authenticated user of ctx, if any, is recorded.
+func Enqueue(ctx context.Context, tx *sqlx.Tx, e Event) error {
+	if tx == nil {
+		return errors.New("tx is required")
+	}
+	if e.WorkspaceID == "" {
+		return errors.New("workspace_id is required")
+	}
+	if !validEvents[e.Type] {
+		return ErrInvalidEvent
+	}
+	if e.ActorID == "" {
+		e.ActorID, _ = authz.UserIDFromContext(ctx)
+	}
+	body, err := json.Marshal(payload{
+		Type:        e.Type,
+		OccurredAt:  time.Now().UTC(),
+		WorkspaceID: e.WorkspaceID,
+		ActorID:     e.ActorID,
+		Data:        e.Data,
+	})
+	if err != nil {
+		return err
+	}
+	return enqueue(ctx, tx, e.WorkspaceID, e.Type, body)
+}
+
+// Sign returns the X-Tookly-Signature value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body under secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the valid Sign value of body.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// hookURL is the public URL tests register; testClient sends its requests
+// to a local test server, which a webhook may not point at directly.
+const hookURL = "http://hooks.example.com/hook"
+
+func testClient(srv *httptest.Server) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+	}}
+}
+
+func TestCreateParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  CreateParams
+		wantErr bool
+	}{
+		{name: "valid", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "https://example.com/hook"}},
+		{name: "valid events", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "http://example.com", Events: []string{EventIssueCreated, EventMemberAdded}}},
+		{name: "missing workspace_id", params: CreateParams{CreatedBy: "u", URL: "https://example.com"}, wantErr: true},
+		{name: "missing created_by", params: CreateParams{WorkspaceID: "w", URL: "https://example.com"}, wantErr: true},
+		{name: "relative url", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "/hook"}, wantErr: true},
+		{name: "ftp url", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "ftp://example.com"}, wantErr: true},
+		{name: "unknown event", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "https://example.com", Events: []string{"issue.deleted"}}, wantErr: true},
+		{name: "public ip", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "https://93.184.215.14/hook"}},
+		{name: "loopback", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "http://127.0.0.1:8080/hook"}, wantErr: true},
+		{name: "localhost", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "http://LocalHost./hook"}, wantErr: true},
+		{name: "metadata", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "http://169.254.169.254/latest/meta-data"}, wantErr: true},
+		{name: "private", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "https://10.0.0.5/hook"}, wantErr: true},
+		{name: "ipv6 loopback", params: CreateParams{WorkspaceID: "w", CreatedBy: "u", URL: "http://[::1]/hook"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListDeliveriesParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  ListDeliveriesParams
+		wantErr bool
+	}{
+		{name: "valid", params: ListDeliveriesParams{WorkspaceID: "w", WebhookID: "h"}},
+		{name: "missing webhook_id", params: ListDeliveriesParams{WorkspaceID: "w"}, wantErr: true},
+		{name: "limit too large", params: ListDeliveriesParams{WorkspaceID: "w", WebhookID: "h", Limit: 101}, wantErr: true},
+		{name: "negative offset", params: ListDeliveriesParams{WorkspaceID: "w", WebhookID: "h", Offset: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"issue.created"}`)
+	sig := Sign("secret", body)
+	if len(sig) != len("sha256=")+64 || sig[:7] != "sha256=" {
+		t.Fatalf("Sign() = %q", sig)
+	}
+	if !Verify("secret", body, sig) {
+		t.Fatal("Verify() rejected a valid signature")
+	}
+	if Verify("other", body, sig) {
+		t.Fatal("Verify() accepted a signature under another secret")
+	}
+	if Verify("secret", []byte(`{}`), sig) {
+		t.Fatal("Verify() accepted a signature of another body")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		n    int
+		want time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, 512 * 30 * time.Second},
+		{20, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.n); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	var gotHeader http.Header
+	var gotBody []byte
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	d := pendingDelivery{ID: "d1", EventType: EventIssueMoved, Payload: []byte(`{"type":"issue.moved"}`), URL: hookURL, Secret: "s3cret"}
+	code, failure := send(context.Background(), testClient(srv), d)
+	if failure != "" || code == nil || *code != http.StatusNoContent {
+		t.Fatalf("send() = %v, %q", code, failure)
+	}
+	if string(gotBody) != string(d.Payload) {
+		t.Fatalf("body = %s", gotBody)
+	}
+	if gotHeader.Get(HeaderEvent) != EventIssueMoved || gotHeader.Get(HeaderDelivery) != "d1" {
+		t.Fatalf("headers = %v", gotHeader)
+	}
+	if !Verify("s3cret", gotBody, gotHeader.Get(HeaderSignature)) {
+		t.Fatalf("invalid signature %q", gotHeader.Get(HeaderSignature))
+	}
+
+	status = http.StatusInternalServerError
+	code, failure = send(context.Background(), testClient(srv), d)
+	if failure == "" || code == nil || *code != http.StatusInternalServerError {
+		t.Fatalf("send() = %v, %q, want failure with 500", code, failure)
+	}
+
+	// A blocked URL saved before it was checked is not requested.
+	status = http.StatusOK
+	d.URL = srv.URL
+	gotBody = nil
+	code, failure = send(context.Background(), testClient(srv), d)
+	if code != nil || failure != ErrBlockedHost.Error() || gotBody != nil {
+		t.Fatalf("send() to %s = %v, %q, want it refused", d.URL, code, failure)
+	}
+}
+
+func TestBlockedAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1":        true,
+		"10.1.2.3":         true,
+		"172.16.0.1":       true,
+		"192.168.1.1":      true,
+		"169.254.169.254":  true,
+		"100.64.0.1":       true,
+		"0.0.0.0":          true,
+		"224.0.0.1":        true,
+		"::1":              true,
+		"fe80::1":          true,
+		"fd00::1":          true,
+		"::ffff:127.0.0.1": true,
+		"93.184.215.14":    false,
+		"8.8.8.8":          false,
+		"2606:4700::1111":  false,
+	} {
+		if got := blockedAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("blockedAddr(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+// TestNewClient checks that the default client refuses to connect to a
+// blocked address; the URL check in send is bypassed on purpose.
+func TestNewClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer srv.Close()
+
+	if _, err := newClient().Get(srv.URL); !errors.Is(err, ErrBlockedHost) {
+		t.Fatalf("Get() error = %v, want ErrBlockedHost", err)
+	}
+	if err := dialControl("tcp", "93.184.215.14:443", nil); err != nil {
+		t.Fatalf("dialControl() public address error = %v", err)
+	}
+}
+
+func TestNilDB(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Create(ctx, nil, CreateParams{}); err == nil {
+		t.Fatal("Create with nil db should fail")
+	}
+	if _, err := List(ctx, nil, "w"); err == nil {
+		t.Fatal("List with nil db should fail")
+	}
+	if err := Delete(ctx, nil, "w", "h"); err == nil {
+		t.Fatal("Delete with nil db should fail")
+	}
+	if err := Enqueue(ctx, nil, Event{}); err == nil {
+		t.Fatal("Enqueue with nil tx should fail")
+	}
+	if _, err := (&Worker{}).RunOnce(ctx); err == nil {
+		t.Fatal("RunOnce with nil db should fail")
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 20
+	defaultMaxAttempts  = 8
+	defaultTimeout      = 10 * time.Second
+	baseBackoff         = 30 * time.Second
+	maxBackoff          = 6 * time.Hour
+	maxErrorLen         = 500
+)
+
+// Worker drains the webhook outbox. The zero value of each field other than
+// DB selects a default.
+type Worker struct {
+	DB *sqlx.DB
+	// Client defaults to one that refuses to connect to blocked addresses,
+	// whatever a webhook's host name resolves to, and uses no proxy.
+	Client       *http.Client
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	// Backoff returns the wait before retry n (1-based). Defaults to
+	// exponential backoff from 30s, capped at 6h.
+	Backoff func(n int) time.Duration
+}
+
+// Run drains the outbox every PollInterval until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval())
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := w.RunOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Error("webhook worker error", "error", err)
+				}
+				break
+			}
+			if n < w.batchSize() {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends one batch of due deliveries and returns how many it sent.
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	if w.DB == nil {
+		return 0, errors.New("db is required")
+	}
+	client := w.Client
+	if client == nil {
+		client = newClient()
+	}
+	// Lease claimed rows for longer than a request can take.
+	lease := client.Timeout + time.Minute
+	batch, err := claimDeliveries(ctx, w.DB, w.batchSize(), lease)
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range batch {
+		status, failure := send(ctx, client, d)
+		var retryAt *time.Time
+		if failure != "" && d.Attempts+1 < w.maxAttempts() {
+			t := time.Now().Add(w.backoff(d.Attempts + 1))
+			retryAt = &t
+		}
+		if err := recordAttempt(ctx, w.DB, d.ID, status, failure, retryAt); err != nil {
+			return 0, err
+		}
+	}
+	return len(batch), nil
+}
+
+// send posts one delivery. It returns the response status, if any, and a
+// failure description, empty on a 2xx response.
+func send(ctx context.Context, client *http.Client, d pendingDelivery) (*int, string) {
+	// Webhooks saved before their URL was checked are refused here too.
+	if err := checkURL(d.URL); err != nil {
+		return nil, err.Error()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, truncate(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Tookly-Webhooks/1")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderSignature, Sign(d.Secret, d.Payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, truncate(err.Error())
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	status := resp.StatusCode
+	if status < 200 || status > 299 {
+		return &status, fmt.Sprintf("unexpected status %d", status)
+	}
+	return &status, ""
+}
+
+// newClient returns the default delivery client. Checking the address in
+// the dialer, after resolution, covers redirects and host names that
+// resolve to blocked addresses.
+func newClient() *http.Client {
+	dialer := &net.Dialer{Timeout: defaultTimeout, Control: dialControl}
+	return &http.Client{
+		Timeout:   defaultTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+	}
+}
+
+func dialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("parse dial address: %w", err)
+	}
+	if blockedAddr(addrPort.Addr()) {
+		return ErrBlockedHost
+	}
+	return nil
+}
+
+// Backoff doubles from 30s per attempt, capped at 6h.
+func Backoff(n int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < n; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+func (w *Worker) pollInterval() time.Duration {
+	if w.PollInterval > 0 {
+		return w.PollInterval
+	}
+	return defaultPollInterval
+}
+
+func (w *Worker) batchSize() int {
+	if w.BatchSize > 0 {
+		return w.BatchSize
+	}
+	return defaultBatchSize
+}
+
+func (w *Worker) maxAttempts() int {
+	if w.MaxAttempts > 0 {
+		return w.MaxAttempts
+	}
+	return defaultMaxAttempts
+}
+
+func (w *Worker) backoff(n int) time.Duration {
+	if w.Backoff != nil {
+		return w.Backoff(n)
+	}
+	return Backoff(n)
+}
+
+func truncate(s string) string {
+	if len(s) > maxErrorLen {
+		return s[:maxErrorLen]
+	}
+	return s
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package worklogs
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/authz"
+	"github.com/start-codex/tookly/internal/respond"
+)
+
+func RegisterRoutes(mux *http.ServeMux, db *sqlx.DB) {
+	mux.HandleFunc("POST /projects/{projectID}/issues/{issueID}/worklogs", handleCreate(db))
+	mux.HandleFunc("GET /projects/{projectID}/issues/{issueID}/worklogs", handleList(db))
+	mux.HandleFunc("PUT /projects/{projectID}/issues/{issueID}/worklogs/{worklogID}", handleUpdate(db))
+	mux.HandleFunc("DELETE /projects/{projectID}/issues/{issueID}/worklogs/{worklogID}", handleDelete(db))
+	mux.HandleFunc("GET /projects/{projectID}/timesheet", handleTimesheet(db))
+}
+
+func fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authz.ErrUnauthenticated):
+		respond.Error(w, http.StatusUnauthorized, "authentication required")
+	case errors.Is(err, authz.ErrForbidden):
+		respond.Error(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, authz.ErrWorkspaceNotFound),
+		errors.Is(err, authz.ErrProjectNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFound),
+		errors.Is(err, ErrIssueNotFound):
+		respond.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInvalidRange):
+		respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		slog.Error("worklogs handler error", "error", err)
+		respond.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// entryBody is the request body of create and update. Without started_at,
+// a new worklog starts at the time of the request and an updated one keeps
+// its start.
+type entryBody struct {
+	Minutes   int        `json:"minutes"`
+	StartedAt *time.Time `json:"started_at"`
+	Comment   string     `json:"comment"`
+}
+
+func (b entryBody) startedAt() time.Time {
+	if b.StartedAt == nil {
+		return time.Now()
+	}
+	return *b.StartedAt
+}
+
+func handleCreate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectRole(r.Context(), db, r.PathValue("projectID"), authz.RoleMember); err != nil {
+			fail(w, err)
+			return
+		}
+		authedUserID, err := authz.UserIDFromContext(r.Context())
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body entryBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		params := CreateParams{
+			ProjectID: r.PathValue("projectID"),
+			IssueID:   r.PathValue("issueID"),
+			UserID:    authedUserID,
+			Minutes:   body.Minutes,
+			StartedAt: body.startedAt(),
+			Comment:   body.Comment,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		worklog, err := Create(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusCreated, worklog)
+	}
+}
+
+func handleList(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+			fail(w, err)
+			return
+		}
+		list, err := List(r.Context(), db, r.PathValue("projectID"), r.PathValue("issueID"))
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, list)
+	}
+}
+
+// requireOwnerOrAdmin lets the user who logged the work, or a project admin,
+// change or remove a worklog.
+func requireOwnerOrAdmin(r *http.Request, db *sqlx.DB) (Worklog, error) {
+	projID := r.PathValue("projectID")
+	_, role, err := authz.ProjectRole(r.Context(), db, projID)
+	if err == nil && !authz.HasRole(role, authz.RoleMember) {
+		err = authz.ErrForbidden
+	}
+	if err != nil {
+		return Worklog{}, err
+	}
+	authedUserID, err := authz.UserIDFromContext(r.Context())
+	if err != nil {
+		return Worklog{}, err
+	}
+	worklog, err := Get(r.Context(), db, projID, r.PathValue("issueID"), r.PathValue("worklogID"))
+	if err != nil {
+		return Worklog{}, err
+	}
+	if worklog.UserID != authedUserID && role != authz.RoleAdmin {
+		return Worklog{}, authz.ErrForbidden
+	}
+	return worklog, nil
+}
+
+func handleUpdate(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		existing, err := requireOwnerOrAdmin(r, db)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		var body entryBody
+		if err := respond.Decode(r, &body); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if body.StartedAt == nil {
+			body.StartedAt = &existing.StartedAt
+		}
+		params := UpdateParams{
+			ProjectID: r.PathValue("projectID"),
+			IssueID:   existing.IssueID,
+			WorklogID: existing.ID,
+			Minutes:   body.Minutes,
+			StartedAt: body.startedAt(),
+			Comment:   body.Comment,
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		worklog, err := Update(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, worklog)
+	}
+}
+
+func handleDelete(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		existing, err := requireOwnerOrAdmin(r, db)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if err := Delete(r.Context(), db, r.PathValue("projectID"), existing.IssueID, existing.ID); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleTimesheet answers JSON, or CSV with format=csv. from and to are
+// inclusive YYYY-MM-DD dates; user_id limits the report to one user.
+func handleTimesheet(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := authz.RequireProjectMembership(r.Context(), db, r.PathValue("projectID")); err != nil {
+			fail(w, err)
+			return
+		}
+		q := r.URL.Query()
+		params := TimesheetParams{
+			ProjectID: r.PathValue("projectID"),
+			UserID:    q.Get("user_id"),
+		}
+		for _, b := range []struct {
+			name string
+			dst  **time.Time
+		}{{"from", &params.From}, {"to", &params.To}} {
+			s := q.Get(b.name)
+			if s == "" {
+				continue
+			}
+			t, err := time.Parse("2006-01-02", s)
+			if err != nil {
+				respond.Error(w, http.StatusUnprocessableEntity, b.name+" must be YYYY-MM-DD format")
+				return
+			}
+			*b.dst = &t
+		}
+		format := q.Get("format")
+		if format != "" && format != "json" && format != "csv" {
+			respond.Error(w, http.StatusUnprocessableEntity, "format must be 'json' or 'csv'")
+			return
+		}
+		if err := params.Validate(); err != nil {
+			respond.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		ts, err := GetTimesheet(r.Context(), db, params)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		if format != "csv" {
+			respond.JSON(w, http.StatusOK, ts)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="timesheet.csv"`)
+		w.WriteHeader(http.StatusOK)
+		if err := WriteCSV(w, ts); err != nil {
+			slog.Warn("write timesheet csv", "error", err)
+		}
+	}
+}
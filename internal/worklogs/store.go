@@ -0,0 +1,199 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package worklogs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/pgutil"
+)
+
+// worklogCols selects a worklog (alias w) joined with its user (alias u).
+const worklogCols = `w.id, w.issue_id, w.user_id, u.name AS user_name, w.minutes, w.started_at,
+	w.comment, w.created_at, w.updated_at`
+
+// projectIssue joins worklogs (alias w) to their issue when it belongs to
+// the project in $1.
+const projectIssue = `JOIN issues i ON i.id = w.issue_id AND i.project_id = $1
+	JOIN app_users u ON u.id = w.user_id`
+
+func createWorklog(ctx context.Context, db *sqlx.DB, params CreateParams) (Worklog, error) {
+	var worklog Worklog
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit create worklog", func(tx *sqlx.Tx) error {
+		var issueID string
+		if err := tx.GetContext(ctx, &issueID,
+			`SELECT id
+			 FROM issues
+			 WHERE id = $1
+			   AND project_id = $2
+			   AND archived_at IS NULL
+			 FOR SHARE`,
+			params.IssueID, params.ProjectID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrIssueNotFound
+			}
+			return fmt.Errorf("lock issue: %w", err)
+		}
+		var id string
+		if err := tx.GetContext(ctx, &id,
+			`INSERT INTO worklogs (issue_id, user_id, minutes, started_at, comment)
+			 VALUES ($1, $2, $3, $4, $5)
+			 RETURNING id`,
+			params.IssueID, params.UserID, params.Minutes, params.StartedAt, params.Comment,
+		); err != nil {
+			return fmt.Errorf("insert worklog: %w", err)
+		}
+		if err := tx.GetContext(ctx, &worklog,
+			`SELECT `+worklogCols+`
+			 FROM worklogs w
+			 `+projectIssue+`
+			 WHERE w.id = $2`,
+			params.ProjectID, id,
+		); err != nil {
+			return fmt.Errorf("load created worklog: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return Worklog{}, err
+	}
+	return worklog, nil
+}
+
+func getWorklog(ctx context.Context, db *sqlx.DB, projectID, issueID, worklogID string) (Worklog, error) {
+	var worklog Worklog
+	err := db.GetContext(ctx, &worklog,
+		`SELECT `+worklogCols+`
+		 FROM worklogs w
+		 `+projectIssue+`
+		 WHERE w.id = $2
+		   AND w.issue_id = $3`,
+		projectID, worklogID, issueID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Worklog{}, ErrNotFound
+		}
+		return Worklog{}, fmt.Errorf("get worklog: %w", err)
+	}
+	return worklog, nil
+}
+
+func listWorklogs(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Worklog, error) {
+	var exists bool
+	if err := db.GetContext(ctx, &exists,
+		`SELECT EXISTS(SELECT 1 FROM issues WHERE id = $1 AND project_id = $2)`,
+		issueID, projectID,
+	); err != nil {
+		return nil, fmt.Errorf("check issue: %w", err)
+	}
+	if !exists {
+		return nil, ErrIssueNotFound
+	}
+
+	list := []Worklog{}
+	if err := db.SelectContext(ctx, &list,
+		`SELECT `+worklogCols+`
+		 FROM worklogs w
+		 `+projectIssue+`
+		 WHERE w.issue_id = $2
+		 ORDER BY w.started_at ASC, w.id ASC`,
+		projectID, issueID,
+	); err != nil {
+		return nil, fmt.Errorf("list worklogs: %w", err)
+	}
+	return list, nil
+}
+
+func updateWorklog(ctx context.Context, db *sqlx.DB, params UpdateParams) (Worklog, error) {
+	var worklog Worklog
+	if err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update worklog", func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE worklogs w
+			 SET minutes = $1, started_at = $2, comment = $3
+			 FROM issues i
+			 WHERE w.id = $4
+			   AND w.issue_id = $5
+			   AND i.id = w.issue_id
+			   AND i.project_id = $6`,
+			params.Minutes, params.StartedAt, params.Comment,
+			params.WorklogID, params.IssueID, params.ProjectID,
+		)
+		if err != nil {
+			return fmt.Errorf("update worklog: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrNotFound
+		}
+		if err := tx.GetContext(ctx, &worklog,
+			`SELECT `+worklogCols+`
+			 FROM worklogs w
+			 `+projectIssue+`
+			 WHERE w.id = $2`,
+			params.ProjectID, params.WorklogID,
+		); err != nil {
+			return fmt.Errorf("load updated worklog: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return Worklog{}, err
+	}
+	return worklog, nil
+}
+
+func deleteWorklog(ctx context.Context, db *sqlx.DB, projectID, issueID, worklogID string) error {
+	res, err := db.ExecContext(ctx,
+		`DELETE FROM worklogs w
+		 USING issues i
+		 WHERE w.id = $1
+		   AND w.issue_id = $2
+		   AND i.id = w.issue_id
+		   AND i.project_id = $3`,
+		worklogID, issueID, projectID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete worklog: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func listTimesheetEntries(ctx context.Context, db *sqlx.DB, params TimesheetParams) ([]TimesheetEntry, error) {
+	query := `SELECT w.id, w.user_id, u.name AS user_name, w.issue_id,
+	                 p.key || '-' || i.number AS issue_key, i.title AS issue_title,
+	                 w.minutes, w.started_at, w.comment
+		 FROM worklogs w
+		 JOIN issues i ON i.id = w.issue_id
+		 JOIN projects p ON p.id = i.project_id
+		 JOIN app_users u ON u.id = w.user_id
+		 WHERE i.project_id = $1`
+	args := []any{params.ProjectID}
+	if params.UserID != "" {
+		args = append(args, params.UserID)
+		query += fmt.Sprintf(" AND w.user_id = $%d", len(args))
+	}
+	if params.From != nil {
+		args = append(args, params.From.Format("2006-01-02"))
+		query += fmt.Sprintf(" AND w.started_at >= $%d::date AT TIME ZONE 'UTC'", len(args))
+	}
+	if params.To != nil {
+		args = append(args, params.To.AddDate(0, 0, 1).Format("2006-01-02"))
+		query += fmt.Sprintf(" AND w.started_at < $%d::date AT TIME ZONE 'UTC'", len(args))
+	}
+	query += " ORDER BY u.name, w.user_id, w.started_at, w.id"
+
+	entries := []TimesheetEntry{}
+	if err := db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("list timesheet entries: %w", err)
+	}
+	return entries, nil
+}
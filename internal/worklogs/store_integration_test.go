@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package worklogs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/issues"
+	"github.com/start-codex/tookly/internal/testpg"
+)
+
+func TestWorklogsAndTimesheet(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	alice := testpg.SeedUser(t, db)
+	bob := testpg.SeedUser(t, db)
+	wsID := testpg.SeedWorkspace(t, db)
+	projID := testpg.SeedProject(t, db, wsID, "WLG")
+	var typeID, statusID string
+	if err := db.GetContext(ctx, &typeID, `INSERT INTO issue_types (project_id, name, level) VALUES ($1, 'Task', 1) RETURNING id`, projID); err != nil {
+		t.Fatalf("insert issue_type: %v", err)
+	}
+	if err := db.GetContext(ctx, &statusID, `INSERT INTO statuses (project_id, name, category, position) VALUES ($1, 'To do', 'todo', 0) RETURNING id`, projID); err != nil {
+		t.Fatalf("insert status: %v", err)
+	}
+	minutes := func(n int) *int { return &n }
+	create := func(title, parentID string, estimate *int) issues.Issue {
+		t.Helper()
+		issue, err := issues.Create(ctx, db, issues.CreateParams{
+			ProjectID: projID, IssueTypeID: typeID, StatusID: statusID, ParentIssueID: parentID,
+			Title: title, ReporterID: alice, OriginalEstimate: estimate,
+		})
+		if err != nil {
+			t.Fatalf("create issue %s: %v", title, err)
+		}
+		return issue
+	}
+	parent := create("Parent", "", minutes(480))
+	if parent.RemainingEstimate == nil || *parent.RemainingEstimate != 480 {
+		t.Fatalf("remaining estimate = %v, want it to default to the original estimate", parent.RemainingEstimate)
+	}
+	child := create("Child", parent.ID, minutes(120))
+	grandchild := create("Grandchild", child.ID, nil)
+
+	day := func(d, h int) time.Time { return time.Date(2025, 6, d, h, 0, 0, 0, time.UTC) }
+	logWork := func(issueID, userID string, n int, startedAt time.Time) Worklog {
+		t.Helper()
+		w, err := Create(ctx, db, CreateParams{ProjectID: projID, IssueID: issueID, UserID: userID, Minutes: n, StartedAt: startedAt, Comment: "work"})
+		if err != nil {
+			t.Fatalf("create worklog: %v", err)
+		}
+		return w
+	}
+	first := logWork(parent.ID, alice, 60, day(2, 9))
+	logWork(child.ID, bob, 30, day(3, 9))
+	logWork(grandchild.ID, alice, 15, day(30, 23))
+	logWork(child.ID, alice, 45, day(1, 0).Add(-time.Minute))
+
+	got, err := issues.Get(ctx, db, projID, parent.ID)
+	if err != nil {
+		t.Fatalf("get parent: %v", err)
+	}
+	want := issues.TimeTracking{TimeSpent: 60, TotalOriginalEstimate: 600, TotalRemainingEstimate: 600, TotalTimeSpent: 150}
+	if got.TimeTracking != want {
+		t.Fatalf("parent time tracking = %+v, want %+v", got.TimeTracking, want)
+	}
+
+	// Archived sub-issues drop out of the roll-up.
+	if err := issues.Archive(ctx, db, projID, grandchild.ID, alice); err != nil {
+		t.Fatalf("archive grandchild: %v", err)
+	}
+	got, err = issues.Get(ctx, db, projID, parent.ID)
+	if err != nil {
+		t.Fatalf("get parent: %v", err)
+	}
+	if got.TimeTracking.TotalTimeSpent != 135 {
+		t.Fatalf("total time spent after archive = %d, want 135", got.TimeTracking.TotalTimeSpent)
+	}
+
+	// PUT without estimates keeps them; PATCH null clears one.
+	updated, err := issues.Update(ctx, db, issues.UpdateParams{IssueID: parent.ID, ProjectID: projID, ActorID: alice, Title: "Parent", Priority: "medium"})
+	if err != nil {
+		t.Fatalf("update parent: %v", err)
+	}
+	if updated.OriginalEstimate == nil || *updated.OriginalEstimate != 480 {
+		t.Fatalf("original estimate after PUT = %v, want 480", updated.OriginalEstimate)
+	}
+	patched, err := issues.Patch(ctx, db, issues.PatchParams{IssueID: parent.ID, ProjectID: projID, ActorID: alice, SetRemainingEstimate: true})
+	if err != nil {
+		t.Fatalf("patch parent: %v", err)
+	}
+	if patched.RemainingEstimate != nil || patched.TimeTracking.TotalRemainingEstimate != 120 {
+		t.Fatalf("after clearing remaining estimate: %v, %+v", patched.RemainingEstimate, patched.TimeTracking)
+	}
+
+	// Only the owner's entries change through Update; a worklog of another
+	// issue is not found.
+	edited, err := Update(ctx, db, UpdateParams{ProjectID: projID, IssueID: parent.ID, WorklogID: first.ID, Minutes: 90, StartedAt: first.StartedAt, Comment: "more"})
+	if err != nil {
+		t.Fatalf("update worklog: %v", err)
+	}
+	if edited.Minutes != 90 || edited.Comment != "more" || edited.UserName == "" {
+		t.Fatalf("updated worklog = %+v", edited)
+	}
+	if _, err := Update(ctx, db, UpdateParams{ProjectID: projID, IssueID: child.ID, WorklogID: first.ID, Minutes: 5, StartedAt: first.StartedAt}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("update via wrong issue: error = %v, want ErrNotFound", err)
+	}
+
+	from, to := day(1, 0), day(30, 0)
+	ts, err := GetTimesheet(ctx, db, TimesheetParams{ProjectID: projID, From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("GetTimesheet: %v", err)
+	}
+	// The entry just before June 1 is out of range; the one late on June 30
+	// is in, archived issue or not.
+	if ts.TotalMinutes != 135 || len(ts.Users) != 2 {
+		t.Fatalf("timesheet = %+v", ts)
+	}
+	ts, err = GetTimesheet(ctx, db, TimesheetParams{ProjectID: projID, UserID: bob})
+	if err != nil {
+		t.Fatalf("GetTimesheet for bob: %v", err)
+	}
+	if len(ts.Users) != 1 || ts.Users[0].UserID != bob || ts.Users[0].Entries[0].IssueKey != "WLG-2" {
+		t.Fatalf("bob's timesheet = %+v", ts)
+	}
+	var csv bytes.Buffer
+	if err := WriteCSV(&csv, ts); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[1], ",WLG-2,Child,30,0.50,") {
+		t.Fatalf("csv = %s", csv.String())
+	}
+
+	if err := Delete(ctx, db, projID, parent.ID, first.ID); err != nil {
+		t.Fatalf("delete worklog: %v", err)
+	}
+	if err := Delete(ctx, db, projID, parent.ID, first.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("delete twice: error = %v, want ErrNotFound", err)
+	}
+	list, err := List(ctx, db, projID, child.ID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || !list[0].StartedAt.Before(list[1].StartedAt) {
+		t.Fatalf("child worklogs = %+v", list)
+	}
+	if _, err := Create(ctx, db, CreateParams{ProjectID: projID, IssueID: grandchild.ID, UserID: alice, Minutes: 5, StartedAt: day(4, 9)}); !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("log work on archived issue: error = %v, want ErrIssueNotFound", err)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+// Package worklogs records the time people spend on issues and reports it
+// per user as a project timesheet.
+package worklogs
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// maxMinutes caps a single entry at one day.
+const maxMinutes = 24 * 60
+
+var (
+	ErrNotFound      = errors.New("worklog not found")
+	ErrIssueNotFound = errors.New("issue not found")
+	ErrInvalidRange  = errors.New("from must not be after to")
+)
+
+// Worklog is time spent by a user on an issue, starting at StartedAt.
+type Worklog struct {
+	ID        string    `db:"id"         json:"id"`
+	IssueID   string    `db:"issue_id"   json:"issue_id"`
+	UserID    string    `db:"user_id"    json:"user_id"`
+	UserName  string    `db:"user_name"  json:"user_name"`
+	Minutes   int       `db:"minutes"    json:"minutes"`
+	StartedAt time.Time `db:"started_at" json:"started_at"`
+	Comment   string    `db:"comment"    json:"comment"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type CreateParams struct {
+	ProjectID string
+	IssueID   string
+	UserID    string
+	Minutes   int
+	StartedAt time.Time
+	Comment   string
+}
+
+func (params CreateParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.IssueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if params.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	return validateEntry(params.Minutes, params.StartedAt)
+}
+
+// UpdateParams replaces the duration, start and comment of a worklog.
+type UpdateParams struct {
+	ProjectID string
+	IssueID   string
+	WorklogID string
+	Minutes   int
+	StartedAt time.Time
+	Comment   string
+}
+
+func (params UpdateParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.IssueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if params.WorklogID == "" {
+		return errors.New("worklog_id is required")
+	}
+	return validateEntry(params.Minutes, params.StartedAt)
+}
+
+func validateEntry(minutes int, startedAt time.Time) error {
+	if minutes <= 0 {
+		return errors.New("minutes must be > 0")
+	}
+	if minutes > maxMinutes {
+		return fmt.Errorf("minutes must be <= %d", maxMinutes)
+	}
+	if startedAt.IsZero() {
+		return errors.New("started_at is required")
+	}
+	return nil
+}
+
+// Create logs work on an active issue.
+func Create(ctx context.Context, db *sqlx.DB, params CreateParams) (Worklog, error) {
+	if db == nil {
+		return Worklog{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Worklog{}, err
+	}
+	return createWorklog(ctx, db, params)
+}
+
+func Get(ctx context.Context, db *sqlx.DB, projectID, issueID, worklogID string) (Worklog, error) {
+	if db == nil {
+		return Worklog{}, errors.New("db is required")
+	}
+	if projectID == "" {
+		return Worklog{}, errors.New("project_id is required")
+	}
+	if issueID == "" {
+		return Worklog{}, errors.New("issue_id is required")
+	}
+	if worklogID == "" {
+		return Worklog{}, errors.New("worklog_id is required")
+	}
+	return getWorklog(ctx, db, projectID, issueID, worklogID)
+}
+
+// List returns the worklogs of an issue in the order the work started.
+func List(ctx context.Context, db *sqlx.DB, projectID, issueID string) ([]Worklog, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if projectID == "" {
+		return nil, errors.New("project_id is required")
+	}
+	if issueID == "" {
+		return nil, errors.New("issue_id is required")
+	}
+	return listWorklogs(ctx, db, projectID, issueID)
+}
+
+func Update(ctx context.Context, db *sqlx.DB, params UpdateParams) (Worklog, error) {
+	if db == nil {
+		return Worklog{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Worklog{}, err
+	}
+	return updateWorklog(ctx, db, params)
+}
+
+func Delete(ctx context.Context, db *sqlx.DB, projectID, issueID, worklogID string) error {
+	if db == nil {
+		return errors.New("db is required")
+	}
+	if projectID == "" {
+		return errors.New("project_id is required")
+	}
+	if issueID == "" {
+		return errors.New("issue_id is required")
+	}
+	if worklogID == "" {
+		return errors.New("worklog_id is required")
+	}
+	return deleteWorklog(ctx, db, projectID, issueID, worklogID)
+}
+
+// TimesheetParams selects the worklogs of a project. From and To are
+// inclusive UTC dates; nil leaves that side of the range open. A non-empty
+// UserID limits the report to that user.
+type TimesheetParams struct {
+	ProjectID string
+	UserID    string
+	From      *time.Time
+	To        *time.Time
+}
+
+func (params TimesheetParams) Validate() error {
+	if params.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	if params.From != nil && params.To != nil && params.From.After(*params.To) {
+		return ErrInvalidRange
+	}
+	return nil
+}
+
+// Timesheet is the work logged in a project, grouped by user. Users are
+// ordered by name and their entries by start time.
+type Timesheet struct {
+	ProjectID    string          `json:"project_id"`
+	From         *string         `json:"from,omitempty"`
+	To           *string         `json:"to,omitempty"`
+	TotalMinutes int             `json:"total_minutes"`
+	Users        []TimesheetUser `json:"users"`
+}
+
+type TimesheetUser struct {
+	UserID       string           `json:"user_id"`
+	UserName     string           `json:"user_name"`
+	TotalMinutes int              `json:"total_minutes"`
+	Entries      []TimesheetEntry `json:"entries"`
+}
+
+// TimesheetEntry is one worklog in a timesheet. Worklogs on archived issues
+// are included; the time was still spent.
+type TimesheetEntry struct {
+	WorklogID  string    `db:"id"          json:"worklog_id"`
+	UserID     string    `db:"user_id"     json:"-"`
+	UserName   string    `db:"user_name"   json:"-"`
+	IssueID    string    `db:"issue_id"    json:"issue_id"`
+	IssueKey   string    `db:"issue_key"   json:"issue_key"`
+	IssueTitle string    `db:"issue_title" json:"issue_title"`
+	Minutes    int       `db:"minutes"     json:"minutes"`
+	StartedAt  time.Time `db:"started_at"  json:"started_at"`
+	Comment    string    `db:"comment"     json:"comment"`
+}
+
+func GetTimesheet(ctx context.Context, db *sqlx.DB, params TimesheetParams) (Timesheet, error) {
+	if db == nil {
+		return Timesheet{}, errors.New("db is required")
+	}
+	if err := params.Validate(); err != nil {
+		return Timesheet{}, err
+	}
+	entries, err := listTimesheetEntries(ctx, db, params)
+	if err != nil {
+		return Timesheet{}, err
+	}
+	ts := groupTimesheet(entries)
+	ts.ProjectID = params.ProjectID
+	ts.From = formatDate(params.From)
+	ts.To = formatDate(params.To)
+	return ts, nil
+}
+
+// groupTimesheet groups entries, which must be ordered by user, by user.
+func groupTimesheet(entries []TimesheetEntry) Timesheet {
+	ts := Timesheet{Users: []TimesheetUser{}}
+	for _, e := range entries {
+		if n := len(ts.Users); n == 0 || ts.Users[n-1].UserID != e.UserID {
+			ts.Users = append(ts.Users, TimesheetUser{UserID: e.UserID, UserName: e.UserName, Entries: []TimesheetEntry{}})
+		}
+		u := &ts.Users[len(ts.Users)-1]
+		u.Entries = append(u.Entries, e)
+		u.TotalMinutes += e.Minutes
+		ts.TotalMinutes += e.Minutes
+	}
+	return ts
+}
+
+func formatDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("2006-01-02")
+	return &s
+}
+
+// WriteCSV writes ts with one row per worklog. Cells that a spreadsheet
+// would read as a formula are prefixed with a single quote.
+func WriteCSV(w io.Writer, ts Timesheet) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"user", "date", "started_at", "issue", "title", "minutes", "hours", "comment"}); err != nil {
+		return err
+	}
+	for _, u := range ts.Users {
+		for _, e := range u.Entries {
+			started := e.StartedAt.UTC()
+			if err := cw.Write([]string{
+				csvCell(u.UserName),
+				started.Format("2006-01-02"),
+				started.Format(time.RFC3339),
+				csvCell(e.IssueKey),
+				csvCell(e.IssueTitle),
+				strconv.Itoa(e.Minutes),
+				strconv.FormatFloat(float64(e.Minutes)/60, 'f', 2, 64),
+				csvCell(e.Comment),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
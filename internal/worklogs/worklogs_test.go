@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Start Codex SAS. All rights reserved.
+// SPDX-License-Identifier: BUSL-1.1
+// Use of this software is governed by the Business Source License 1.1
+// included in the LICENSE file at the root of this repository.
+
+package worklogs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateWorklogParams_Validate(t *testing.T) {
+	valid := CreateParams{ProjectID: "p", IssueID: "i", UserID: "u", Minutes: 90, StartedAt: time.Now()}
+
+	tests := []struct {
+		name    string
+		params  CreateParams
+		wantErr bool
+	}{
+		{name: "valid", params: valid, wantErr: false},
+		{name: "valid full day", params: func() CreateParams { c := valid; c.Minutes = 24 * 60; return c }(), wantErr: false},
+		{name: "missing project_id", params: func() CreateParams { c := valid; c.ProjectID = ""; return c }(), wantErr: true},
+		{name: "missing issue_id", params: func() CreateParams { c := valid; c.IssueID = ""; return c }(), wantErr: true},
+		{name: "missing user_id", params: func() CreateParams { c := valid; c.UserID = ""; return c }(), wantErr: true},
+		{name: "zero minutes", params: func() CreateParams { c := valid; c.Minutes = 0; return c }(), wantErr: true},
+		{name: "negative minutes", params: func() CreateParams { c := valid; c.Minutes = -30; return c }(), wantErr: true},
+		{name: "over a day", params: func() CreateParams { c := valid; c.Minutes = 24*60 + 1; return c }(), wantErr: true},
+		{name: "missing started_at", params: func() CreateParams { c := valid; c.StartedAt = time.Time{}; return c }(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateWorklogParams_Validate(t *testing.T) {
+	valid := UpdateParams{ProjectID: "p", IssueID: "i", WorklogID: "w", Minutes: 15, StartedAt: time.Now()}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	missing := valid
+	missing.WorklogID = ""
+	if err := missing.Validate(); err == nil {
+		t.Fatal("Validate() without worklog_id: expected error")
+	}
+}
+
+func TestTimesheetParams_Validate(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	if err := (TimesheetParams{ProjectID: "p", From: &from, To: &to}).Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := (TimesheetParams{ProjectID: "p", From: &from, To: &from}).Validate(); err != nil {
+		t.Fatalf("Validate() single day error = %v", err)
+	}
+	if err := (TimesheetParams{ProjectID: "p", From: &to, To: &from}).Validate(); !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("Validate() reversed range error = %v, want ErrInvalidRange", err)
+	}
+	if err := (TimesheetParams{}).Validate(); err == nil {
+		t.Fatal("Validate() without project_id: expected error")
+	}
+}
+
+func TestGroupTimesheet(t *testing.T) {
+	ts := groupTimesheet([]TimesheetEntry{
+		{WorklogID: "w1", UserID: "u1", UserName: "Ada", Minutes: 30},
+		{WorklogID: "w2", UserID: "u1", UserName: "Ada", Minutes: 45},
+		{WorklogID: "w3", UserID: "u2", UserName: "Bob", Minutes: 60},
+	})
+	if ts.TotalMinutes != 135 || len(ts.Users) != 2 {
+		t.Fatalf("groupTimesheet() = %+v", ts)
+	}
+	if u := ts.Users[0]; u.UserID != "u1" || u.TotalMinutes != 75 || len(u.Entries) != 2 {
+		t.Fatalf("first user = %+v", u)
+	}
+	if u := ts.Users[1]; u.UserName != "Bob" || u.TotalMinutes != 60 {
+		t.Fatalf("second user = %+v", u)
+	}
+	if empty := groupTimesheet(nil); empty.Users == nil || empty.TotalMinutes != 0 {
+		t.Fatalf("groupTimesheet(nil) = %+v, want an empty users list", empty)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	started := time.Date(2025, 6, 2, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	ts := Timesheet{Users: []TimesheetUser{{
+		UserName: "Ada",
+		Entries: []TimesheetEntry{
+			{IssueKey: "ACME-7", IssueTitle: "Fix, then ship", Minutes: 90, StartedAt: started, Comment: "=HYPERLINK(\"x\")"},
+		},
+	}}}
+	var b strings.Builder
+	if err := WriteCSV(&b, ts); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	want := "user,date,started_at,issue,title,minutes,hours,comment\n" +
+		"Ada,2025-06-02,2025-06-02T07:30:00Z,ACME-7,\"Fix, then ship\",90,1.50,\"'=HYPERLINK(\"\"x\"\")\"\n"
+	if b.String() != want {
+		t.Fatalf("WriteCSV() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	for in, want := range map[string]string{
+		"":         "",
+		"plain":    "plain",
+		"=1+1":     "'=1+1",
+		"+31 6":    "'+31 6",
+		"-2":       "'-2",
+		"@SUM(A1)": "'@SUM(A1)",
+		"a=b":      "a=b",
+	} {
+		if got := csvCell(in); got != want {
+			t.Errorf("csvCell(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCreateWorklog_NilDB(t *testing.T) {
+	_, err := Create(context.Background(), nil, CreateParams{ProjectID: "p", IssueID: "i", UserID: "u", Minutes: 1, StartedAt: time.Now()})
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("Create() error = %v, want %q", err, "db is required")
+	}
+}
+
+func TestGetTimesheet_NilDB(t *testing.T) {
+	_, err := GetTimesheet(context.Background(), nil, TimesheetParams{ProjectID: "p"})
+	if err == nil || err.Error() != "db is required" {
+		t.Fatalf("GetTimesheet() error = %v, want %q", err, "db is required")
+	}
+}
@@ -12,7 +12,9 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/start-codex/tookly/internal/audit"
 	"github.com/start-codex/tookly/internal/pgutil"
+	"github.com/start-codex/tookly/internal/webhooks"
 )
 
 const selectCols = `id, name, slug, created_at, updated_at, archived_at`
@@ -110,38 +112,64 @@ func archiveWorkspace(ctx context.Context, db *sqlx.DB, id string) error {
 
 func addMember(ctx context.Context, db *sqlx.DB, params AddMemberParams) (Member, error) {
 	var member Member
-	err := db.QueryRowxContext(ctx,
-		`INSERT INTO workspace_members (workspace_id, user_id, role)
-		 VALUES ($1, $2, $3)
-		 ON CONFLICT (workspace_id, user_id)
-		 DO UPDATE SET role = excluded.role, archived_at = NULL
-		 RETURNING `+memberCols,
-		params.WorkspaceID, params.UserID, params.Role,
-	).StructScan(&member)
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit add workspace member", func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx,
+			`INSERT INTO workspace_members (workspace_id, user_id, role)
+			 VALUES ($1, $2, $3)
+			 ON CONFLICT (workspace_id, user_id) DO NOTHING
+			 RETURNING `+memberCols,
+			params.WorkspaceID, params.UserID, params.Role,
+		).StructScan(&member)
+		if err == nil {
+			return enqueueMemberWebhook(ctx, tx, webhooks.EventMemberAdded, member)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("add workspace member: %w", err)
+		}
+		// The user already has a membership row: update it in place without
+		// announcing a new member.
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE workspace_members
+			 SET role = $3, archived_at = NULL
+			 WHERE workspace_id = $1 AND user_id = $2
+			 RETURNING `+memberCols,
+			params.WorkspaceID, params.UserID, params.Role,
+		).StructScan(&member); err != nil {
+			return fmt.Errorf("update workspace member: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return Member{}, fmt.Errorf("add workspace member: %w", err)
+		return Member{}, err
 	}
 	return member, nil
 }
 
 func removeMember(ctx context.Context, db *sqlx.DB, workspaceID, userID string) error {
-	res, err := db.ExecContext(ctx,
-		`UPDATE workspace_members
-		 SET archived_at = NOW()
-		 WHERE workspace_id = $1 AND user_id = $2 AND archived_at IS NULL`,
-		workspaceID, userID,
-	)
-	if err != nil {
-		return fmt.Errorf("remove workspace member: %w", err)
-	}
-	n, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("remove workspace member rows affected: %w", err)
-	}
-	if n == 0 {
-		return ErrMemberNotFound
-	}
-	return nil
+	return pgutil.WithTx(ctx, db, nil, "begin tx", "commit remove workspace member", func(tx *sqlx.Tx) error {
+		var member Member
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE workspace_members
+			 SET archived_at = NOW()
+			 WHERE workspace_id = $1 AND user_id = $2 AND archived_at IS NULL
+			 RETURNING `+memberCols,
+			workspaceID, userID,
+		).StructScan(&member); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrMemberNotFound
+			}
+			return fmt.Errorf("remove workspace member: %w", err)
+		}
+		return enqueueMemberWebhook(ctx, tx, webhooks.EventMemberRemoved, member)
+	})
+}
+
+func enqueueMemberWebhook(ctx context.Context, tx *sqlx.Tx, eventType string, member Member) error {
+	return webhooks.Enqueue(ctx, tx, webhooks.Event{
+		WorkspaceID: member.WorkspaceID,
+		Type:        eventType,
+		Data:        webhooks.MemberData{WorkspaceID: member.WorkspaceID, UserID: member.UserID, Role: member.Role},
+	})
 }
 
 func listMembers(ctx context.Context, db *sqlx.DB, workspaceID string) ([]Member, error) {
@@ -161,18 +189,42 @@ func listMembers(ctx context.Context, db *sqlx.DB, workspaceID string) ([]Member
 
 func updateMemberRole(ctx context.Context, db *sqlx.DB, params UpdateMemberRoleParams) (Member, error) {
 	var member Member
-	err := db.QueryRowxContext(ctx,
-		`UPDATE workspace_members
-		 SET role = $1
-		 WHERE workspace_id = $2 AND user_id = $3 AND archived_at IS NULL
-		 RETURNING `+memberCols,
-		params.Role, params.WorkspaceID, params.UserID,
-	).StructScan(&member)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return Member{}, ErrMemberNotFound
+	err := pgutil.WithTx(ctx, db, nil, "begin tx", "commit update workspace member role", func(tx *sqlx.Tx) error {
+		var oldRole string
+		if err := tx.GetContext(ctx, &oldRole,
+			`SELECT role FROM workspace_members
+			 WHERE workspace_id = $1 AND user_id = $2 AND archived_at IS NULL
+			 FOR UPDATE`,
+			params.WorkspaceID, params.UserID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrMemberNotFound
+			}
+			return fmt.Errorf("lock workspace member: %w", err)
 		}
-		return Member{}, fmt.Errorf("update workspace member role: %w", err)
+		if err := tx.QueryRowxContext(ctx,
+			`UPDATE workspace_members
+			 SET role = $1
+			 WHERE workspace_id = $2 AND user_id = $3
+			 RETURNING `+memberCols,
+			params.Role, params.WorkspaceID, params.UserID,
+		).StructScan(&member); err != nil {
+			return fmt.Errorf("update workspace member role: %w", err)
+		}
+		if err := audit.Record(ctx, tx, audit.Change{
+			WorkspaceID: member.WorkspaceID,
+			Action:      audit.ActionWorkspaceMemberRoleChanged,
+			TargetType:  audit.TargetWorkspaceMember,
+			TargetID:    member.UserID,
+			Before:      map[string]string{"role": oldRole},
+			After:       map[string]string{"role": member.Role},
+		}); err != nil {
+			return err
+		}
+		return enqueueMemberWebhook(ctx, tx, webhooks.EventMemberRoleChanged, member)
+	})
+	if err != nil {
+		return Member{}, err
 	}
 	return member, nil
 }
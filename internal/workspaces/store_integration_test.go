@@ -12,7 +12,10 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/start-codex/tookly/internal/audit"
+	"github.com/start-codex/tookly/internal/authz"
 	"github.com/start-codex/tookly/internal/testpg"
+	"github.com/start-codex/tookly/internal/webhooks"
 )
 
 func TestCreateWorkspace(t *testing.T) {
@@ -376,3 +379,73 @@ func TestWorkspaceMembers(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateMemberRole_Audited(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+
+	adminID := testpg.SeedUser(t, db)
+	ctx := audit.WithRequest(authz.WithUserID(context.Background(), adminID), "203.0.113.7", "req-1")
+	ws, err := Create(ctx, db, CreateParams{Name: "WS", Slug: "ws-" + testpg.UniqueSuffix(t, db)})
+	if err != nil {
+		t.Fatalf("seed workspace: %v", err)
+	}
+	uID := testpg.SeedUser(t, db)
+	if _, err := AddMember(ctx, db, AddMemberParams{WorkspaceID: ws.ID, UserID: uID, Role: "member"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := UpdateMemberRole(ctx, db, UpdateMemberRoleParams{WorkspaceID: ws.ID, UserID: uID, Role: "admin"}); err != nil {
+		t.Fatalf("update role: %v", err)
+	}
+
+	entries, err := audit.List(context.Background(), db, audit.ListParams{WorkspaceID: ws.ID, Action: audit.ActionWorkspaceMemberRoleChanged})
+	if err != nil {
+		t.Fatalf("audit.List: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d audit entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.ActorID == nil || *e.ActorID != adminID || e.TargetID != uID || e.IP != "203.0.113.7" || e.RequestID != "req-1" {
+		t.Fatalf("entry = %+v", e)
+	}
+	if string(e.Before) != `{"role": "member"}` || string(e.After) != `{"role": "admin"}` {
+		t.Fatalf("before = %s, after = %s", e.Before, e.After)
+	}
+}
+
+func TestAddMember_WebhookOnlyForNewMembers(t *testing.T) {
+	db := testpg.Open(t)
+	testpg.EnsureMigrated(t, db)
+	ctx := context.Background()
+
+	ownerID := testpg.SeedUser(t, db)
+	ws, err := Create(ctx, db, CreateParams{Name: "WS", Slug: "ws-" + testpg.UniqueSuffix(t, db)})
+	if err != nil {
+		t.Fatalf("seed workspace: %v", err)
+	}
+	if _, err := webhooks.Create(ctx, db, webhooks.CreateParams{
+		WorkspaceID: ws.ID, URL: "https://hooks.example.com/hook", Events: []string{webhooks.EventMemberAdded}, Active: true, CreatedBy: ownerID,
+	}); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+
+	uID := testpg.SeedUser(t, db)
+	for _, role := range []string{"member", "admin", "admin"} {
+		if _, err := AddMember(ctx, db, AddMemberParams{WorkspaceID: ws.ID, UserID: uID, Role: role}); err != nil {
+			t.Fatalf("add as %s: %v", role, err)
+		}
+	}
+
+	var n int
+	if err := db.GetContext(ctx, &n,
+		`SELECT COUNT(*) FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		 WHERE w.workspace_id = $1 AND d.event_type = $2`,
+		ws.ID, webhooks.EventMemberAdded,
+	); err != nil {
+		t.Fatalf("count deliveries: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("member.added deliveries = %d, want 1", n)
+	}
+}
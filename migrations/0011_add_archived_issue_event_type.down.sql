@@ -0,0 +1,4 @@
+DELETE FROM issue_events WHERE event_type = 'archived';
+ALTER TABLE issue_events DROP CONSTRAINT IF EXISTS issue_events_event_type_check;
+ALTER TABLE issue_events ADD CONSTRAINT issue_events_event_type_check
+  CHECK (event_type IN ('created', 'updated', 'moved', 'commented'));
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_set_updated_at_comments ON comments;
+DROP TABLE IF EXISTS comment_revisions;
+DROP TABLE IF EXISTS comments;
@@ -0,0 +1,28 @@
+CREATE TABLE comments (
+    id                UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    issue_id          UUID        NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    parent_comment_id UUID        REFERENCES comments(id) ON DELETE CASCADE,
+    author_id         UUID        NOT NULL REFERENCES app_users(id),
+    body              TEXT        NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    edited_at         TIMESTAMPTZ,
+    deleted_at        TIMESTAMPTZ,
+    CHECK (parent_comment_id IS NULL OR parent_comment_id <> id)
+);
+
+CREATE TABLE comment_revisions (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id UUID        NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    body       TEXT        NOT NULL,
+    edited_by  UUID        NOT NULL REFERENCES app_users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_comments_issue_created_at ON comments (issue_id, created_at);
+CREATE INDEX idx_comments_parent ON comments (parent_comment_id) WHERE parent_comment_id IS NOT NULL;
+CREATE INDEX idx_comment_revisions_comment ON comment_revisions (comment_id, created_at DESC);
+
+CREATE TRIGGER trg_set_updated_at_comments
+BEFORE UPDATE ON comments
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_issues_sprint;
+ALTER TABLE issues DROP COLUMN IF EXISTS sprint_id;
+DROP TRIGGER IF EXISTS trg_set_updated_at_sprints ON sprints;
+DROP TABLE IF EXISTS sprints;
@@ -0,0 +1,27 @@
+CREATE TABLE sprints (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    board_id   UUID        NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    name       TEXT        NOT NULL,
+    goal       TEXT        NOT NULL DEFAULT '',
+    state      TEXT        NOT NULL DEFAULT 'planned' CHECK (state IN ('planned', 'active', 'closed')),
+    start_date DATE,
+    end_date   DATE,
+    started_at TIMESTAMPTZ,
+    closed_at  TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (board_id, name),
+    CHECK (end_date IS NULL OR start_date IS NULL OR end_date >= start_date)
+);
+
+-- At most one active sprint per board. Start also locks the board row, so this
+-- index is only a backstop.
+CREATE UNIQUE INDEX uq_sprints_active_per_board ON sprints (board_id) WHERE state = 'active';
+CREATE INDEX idx_sprints_board_state ON sprints (board_id, state);
+
+ALTER TABLE issues ADD COLUMN sprint_id UUID REFERENCES sprints(id) ON DELETE SET NULL;
+CREATE INDEX idx_issues_sprint ON issues (sprint_id) WHERE sprint_id IS NOT NULL;
+
+CREATE TRIGGER trg_set_updated_at_sprints
+BEFORE UPDATE ON sprints
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
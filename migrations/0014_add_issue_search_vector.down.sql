@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_issues_search_vector;
+ALTER TABLE issues DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,6 @@
+ALTER TABLE issues ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english'::regconfig, title), 'A') ||
+    setweight(to_tsvector('english'::regconfig, description), 'B')
+) STORED;
+
+CREATE INDEX idx_issues_search_vector ON issues USING GIN (search_vector);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_validate_issue_link ON issue_links;
+DROP FUNCTION IF EXISTS validate_issue_link();
+DROP TABLE IF EXISTS issue_links;
@@ -0,0 +1,79 @@
+-- Typed links between issues. Inverse directions (is blocked by, is
+-- duplicated by, is cloned by) are not stored; they are the same row read
+-- from the target side. relates_to is symmetric and stored once per pair.
+CREATE TABLE issue_links (
+    id              UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_issue_id UUID        NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    target_issue_id UUID        NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    link_type       TEXT        NOT NULL CHECK (link_type IN ('blocks', 'relates_to', 'duplicates', 'clones')),
+    created_by      UUID        NOT NULL REFERENCES app_users(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (source_issue_id, target_issue_id, link_type),
+    CHECK (source_issue_id <> target_issue_id)
+);
+
+CREATE INDEX idx_issue_links_target ON issue_links (target_issue_id, link_type);
+
+CREATE OR REPLACE FUNCTION validate_issue_link()
+RETURNS trigger AS $$
+DECLARE
+  v_source_workspace UUID;
+  v_target_workspace UUID;
+  v_cycle_found BOOLEAN;
+BEGIN
+  SELECT p.workspace_id INTO v_source_workspace
+  FROM issues i JOIN projects p ON p.id = i.project_id
+  WHERE i.id = NEW.source_issue_id;
+
+  SELECT p.workspace_id INTO v_target_workspace
+  FROM issues i JOIN projects p ON p.id = i.project_id
+  WHERE i.id = NEW.target_issue_id;
+
+  IF v_source_workspace IS DISTINCT FROM v_target_workspace THEN
+    RAISE EXCEPTION 'linked issues % and % must belong to the same workspace', NEW.source_issue_id, NEW.target_issue_id
+      USING ERRCODE = 'TK001';
+  END IF;
+
+  IF NEW.link_type = 'relates_to' AND EXISTS(
+    SELECT 1 FROM issue_links
+    WHERE source_issue_id = NEW.target_issue_id
+      AND target_issue_id = NEW.source_issue_id
+      AND link_type = 'relates_to'
+  ) THEN
+    RAISE EXCEPTION 'issues % and % are already related', NEW.source_issue_id, NEW.target_issue_id
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  IF NEW.link_type = 'blocks' THEN
+    -- Serialise blocking-link writes so two concurrent inserts cannot close a
+    -- cycle that neither sees on its own.
+    PERFORM pg_advisory_xact_lock(hashtext('issue_links.blocks'));
+
+    WITH RECURSIVE blocked AS (
+      SELECT l.target_issue_id AS id
+      FROM issue_links l
+      WHERE l.source_issue_id = NEW.target_issue_id
+        AND l.link_type = 'blocks'
+      UNION
+      SELECT l.target_issue_id
+      FROM issue_links l
+      JOIN blocked b ON l.source_issue_id = b.id
+      WHERE l.link_type = 'blocks'
+    )
+    SELECT EXISTS(
+      SELECT 1 FROM blocked WHERE id = NEW.source_issue_id
+    ) INTO v_cycle_found;
+
+    IF v_cycle_found THEN
+      RAISE EXCEPTION 'cycle detected in blocking links for issue %', NEW.source_issue_id
+        USING ERRCODE = 'TK002';
+    END IF;
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_validate_issue_link
+BEFORE INSERT OR UPDATE ON issue_links
+FOR EACH ROW EXECUTE FUNCTION validate_issue_link();
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS trg_set_updated_at_issue_custom_values ON issue_custom_values;
+DROP TRIGGER IF EXISTS trg_set_updated_at_custom_fields ON custom_fields;
+DROP TABLE IF EXISTS issue_custom_values;
+DROP TABLE IF EXISTS custom_field_issue_types;
+DROP TABLE IF EXISTS custom_fields;
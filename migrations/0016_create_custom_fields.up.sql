@@ -0,0 +1,41 @@
+CREATE TABLE custom_fields (
+    id          UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id  UUID        NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    key         TEXT        NOT NULL CHECK (key ~ '^[a-z][a-z0-9_]{0,62}$'),
+    name        TEXT        NOT NULL,
+    field_type  TEXT        NOT NULL CHECK (field_type IN ('text', 'number', 'date', 'single_select', 'multi_select', 'user', 'url')),
+    options     JSONB       NOT NULL DEFAULT '[]'::jsonb,
+    required    BOOLEAN     NOT NULL DEFAULT FALSE,
+    position    INT         NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    archived_at TIMESTAMPTZ
+);
+
+-- Keys are unique among active fields so an archived key can be reused.
+CREATE UNIQUE INDEX uq_custom_fields_project_key ON custom_fields (project_id, key) WHERE archived_at IS NULL;
+
+-- A field with no rows here applies to every issue type of its project.
+CREATE TABLE custom_field_issue_types (
+    custom_field_id UUID NOT NULL REFERENCES custom_fields(id) ON DELETE CASCADE,
+    issue_type_id   UUID NOT NULL REFERENCES issue_types(id) ON DELETE CASCADE,
+    PRIMARY KEY (custom_field_id, issue_type_id)
+);
+
+CREATE TABLE issue_custom_values (
+    issue_id        UUID        NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    custom_field_id UUID        NOT NULL REFERENCES custom_fields(id) ON DELETE CASCADE,
+    value           JSONB       NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issue_id, custom_field_id)
+);
+
+CREATE INDEX idx_issue_custom_values_field ON issue_custom_values (custom_field_id);
+
+CREATE TRIGGER trg_set_updated_at_custom_fields
+BEFORE UPDATE ON custom_fields
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER trg_set_updated_at_issue_custom_values
+BEFORE UPDATE ON issue_custom_values
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,24 @@
+-- A project's workflow is the set of allowed status transitions. Rows with a
+-- NULL issue_type_id form the project graph; rows for an issue type replace
+-- it for that type. A project without rows keeps free movement.
+CREATE TABLE status_transitions (
+    id               UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id       UUID        NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    issue_type_id    UUID        REFERENCES issue_types(id) ON DELETE CASCADE,
+    from_status_id   UUID        NOT NULL REFERENCES statuses(id) ON DELETE CASCADE,
+    to_status_id     UUID        NOT NULL REFERENCES statuses(id) ON DELETE CASCADE,
+    require_assignee BOOLEAN     NOT NULL DEFAULT FALSE,
+    admin_only       BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (from_status_id <> to_status_id)
+);
+
+CREATE UNIQUE INDEX uq_status_transitions_project
+    ON status_transitions (project_id, from_status_id, to_status_id)
+    WHERE issue_type_id IS NULL;
+
+CREATE UNIQUE INDEX uq_status_transitions_issue_type
+    ON status_transitions (project_id, issue_type_id, from_status_id, to_status_id)
+    WHERE issue_type_id IS NOT NULL;
+
+CREATE INDEX idx_status_transitions_from ON status_transitions (from_status_id);
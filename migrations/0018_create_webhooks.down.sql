@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_set_updated_at_webhooks ON webhooks;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,38 @@
+CREATE TABLE webhooks (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID        NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    url          TEXT        NOT NULL,
+    secret       TEXT        NOT NULL,
+    -- Event types to deliver; empty means every event.
+    events       TEXT[]      NOT NULL DEFAULT '{}',
+    active       BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_by   UUID        REFERENCES app_users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhooks_workspace ON webhooks (workspace_id) WHERE active;
+
+-- Outbox and delivery log: one row per event and subscribed webhook, written
+-- in the transaction that produced the event and drained by the worker.
+CREATE TABLE webhook_deliveries (
+    id              UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id      UUID        NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_type      TEXT        NOT NULL,
+    payload         JSONB       NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts        INT         NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_attempt_at TIMESTAMPTZ,
+    response_status INT,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
+
+CREATE TRIGGER trg_set_updated_at_webhooks
+BEFORE UPDATE ON webhooks
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,14 @@
+CREATE TABLE api_tokens (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID        NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+    name         TEXT        NOT NULL,
+    -- SHA-256 of the raw token; the raw token is only shown on creation.
+    token_hash   TEXT        NOT NULL UNIQUE,
+    scope        TEXT        NOT NULL CHECK (scope IN ('read', 'write')),
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at   TIMESTAMPTZ
+);
+
+CREATE INDEX idx_api_tokens_user ON api_tokens (user_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,31 @@
+-- Append-only record of administrative actions. There are deliberately no
+-- foreign keys so entries outlive the users, workspaces and objects they
+-- mention.
+CREATE TABLE audit_log (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- NULL for instance-level actions, only visible to instance admins.
+    workspace_id UUID,
+    actor_id     UUID,
+    action       TEXT        NOT NULL,
+    target_type  TEXT        NOT NULL,
+    target_id    TEXT        NOT NULL,
+    before       JSONB,
+    after        JSONB,
+    ip           TEXT        NOT NULL DEFAULT '',
+    request_id   TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_created ON audit_log (created_at DESC, id DESC);
+CREATE INDEX idx_audit_log_workspace ON audit_log (workspace_id, created_at DESC) WHERE workspace_id IS NOT NULL;
+CREATE INDEX idx_audit_log_target ON audit_log (target_type, target_id);
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
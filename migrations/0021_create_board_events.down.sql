@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_notify_board_event ON board_events;
+DROP FUNCTION IF EXISTS notify_board_event();
+DROP TABLE IF EXISTS board_events;
@@ -0,0 +1,25 @@
+-- Issue deltas streamed to open boards. The id is the SSE event ID clients
+-- send back in Last-Event-ID to resume; rows are pruned after a day.
+CREATE TABLE board_events (
+    id         BIGSERIAL   PRIMARY KEY,
+    project_id UUID        NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    issue_id   UUID        NOT NULL,
+    event_type TEXT        NOT NULL,
+    payload    JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_board_events_project ON board_events (project_id, id);
+CREATE INDEX idx_board_events_created ON board_events (created_at);
+
+-- Notifications are delivered on commit to every listening server replica.
+CREATE OR REPLACE FUNCTION notify_board_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('board_events', NEW.project_id::text || ':' || NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_notify_board_event
+AFTER INSERT ON board_events
+FOR EACH ROW EXECUTE FUNCTION notify_board_event();
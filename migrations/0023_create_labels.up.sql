@@ -0,0 +1,27 @@
+-- A label with a NULL project_id belongs to its workspace and can be used by
+-- every project in it.
+CREATE TABLE labels (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID        NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    project_id   UUID        REFERENCES projects(id) ON DELETE CASCADE,
+    name         TEXT        NOT NULL,
+    color        TEXT        NOT NULL CHECK (color ~ '^#[0-9a-f]{6}$'),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX uq_labels_project_name ON labels (project_id, lower(name)) WHERE project_id IS NOT NULL;
+CREATE UNIQUE INDEX uq_labels_workspace_name ON labels (workspace_id, lower(name)) WHERE project_id IS NULL;
+
+CREATE TRIGGER trg_set_updated_at_labels
+BEFORE UPDATE ON labels
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE issue_labels (
+    issue_id   UUID        NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    label_id   UUID        NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issue_id, label_id)
+);
+
+CREATE INDEX idx_issue_labels_label ON issue_labels (label_id);
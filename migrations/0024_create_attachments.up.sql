@@ -0,0 +1,14 @@
+-- Attachment bytes live in the configured storage backend under storage_key;
+-- this table only holds their metadata.
+CREATE TABLE attachments (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    issue_id     UUID        NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    uploader_id  UUID        NOT NULL REFERENCES app_users(id),
+    filename     TEXT        NOT NULL CHECK (filename <> ''),
+    content_type TEXT        NOT NULL,
+    size_bytes   BIGINT      NOT NULL CHECK (size_bytes >= 0),
+    storage_key  TEXT        NOT NULL UNIQUE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_attachments_issue_created_at ON attachments (issue_id, created_at);
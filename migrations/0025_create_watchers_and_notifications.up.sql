@@ -0,0 +1,32 @@
+-- Watchers receive a notification for every change to an issue made by
+-- someone else. Reporters, assignees and commenters are added automatically.
+CREATE TABLE issue_watchers (
+    issue_id   UUID        NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issue_id, user_id)
+);
+
+CREATE INDEX idx_issue_watchers_user ON issue_watchers (user_id);
+
+INSERT INTO issue_watchers (issue_id, user_id)
+SELECT id, reporter_id FROM issues
+UNION
+SELECT id, assignee_id FROM issues WHERE assignee_id IS NOT NULL
+UNION
+SELECT issue_id, author_id FROM comments
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE notifications (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+    issue_id   UUID        NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    actor_id   UUID        REFERENCES app_users(id) ON DELETE SET NULL,
+    event_type TEXT        NOT NULL,
+    data       JSONB       NOT NULL DEFAULT '{}',
+    read_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user_created_at ON notifications (user_id, created_at DESC);
+CREATE INDEX idx_notifications_user_unread ON notifications (user_id) WHERE read_at IS NULL;
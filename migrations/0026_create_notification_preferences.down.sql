@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notifications_email_pending;
+ALTER TABLE notifications DROP COLUMN IF EXISTS email_processed_at;
+DROP TABLE IF EXISTS notification_preferences;
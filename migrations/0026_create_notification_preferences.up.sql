@@ -0,0 +1,20 @@
+-- Email delivery of notifications, per user and notification type. Types
+-- without a row use the default delivery.
+CREATE TABLE notification_preferences (
+    user_id    UUID        NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+    event_type TEXT        NOT NULL,
+    delivery   TEXT        NOT NULL CHECK (delivery IN ('immediate', 'hourly', 'daily', 'off')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, event_type)
+);
+
+CREATE TRIGGER trg_set_updated_at_notification_preferences
+BEFORE UPDATE ON notification_preferences
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- email_processed_at is set once a notification has been emailed or skipped
+-- (read, turned off or too old). Existing notifications are not emailed.
+ALTER TABLE notifications ADD COLUMN email_processed_at TIMESTAMPTZ;
+UPDATE notifications SET email_processed_at = NOW();
+
+CREATE INDEX idx_notifications_email_pending ON notifications (user_id, created_at) WHERE email_processed_at IS NULL;
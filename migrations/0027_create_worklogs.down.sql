@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS worklogs;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS remaining_estimate_minutes,
+    DROP COLUMN IF EXISTS original_estimate_minutes;
@@ -0,0 +1,23 @@
+-- Estimates are kept in minutes. The remaining estimate is set by hand; it is
+-- not reduced when work is logged.
+ALTER TABLE issues
+    ADD COLUMN original_estimate_minutes  INTEGER CHECK (original_estimate_minutes >= 0),
+    ADD COLUMN remaining_estimate_minutes INTEGER CHECK (remaining_estimate_minutes >= 0);
+
+CREATE TABLE worklogs (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    issue_id   UUID        NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL REFERENCES app_users(id),
+    minutes    INTEGER     NOT NULL CHECK (minutes > 0),
+    started_at TIMESTAMPTZ NOT NULL,
+    comment    TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_worklogs_issue_started_at ON worklogs (issue_id, started_at);
+CREATE INDEX idx_worklogs_user_started_at ON worklogs (user_id, started_at);
+
+CREATE TRIGGER trg_set_updated_at_worklogs
+BEFORE UPDATE ON worklogs
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
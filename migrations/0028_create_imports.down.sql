@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS imports;
+
+DROP INDEX IF EXISTS uq_issues_project_external_ref;
+
+ALTER TABLE issues DROP COLUMN IF EXISTS external_ref;
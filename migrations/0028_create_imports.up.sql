@@ -0,0 +1,29 @@
+-- external_ref keeps the key an imported issue had in its previous tracker,
+-- so parents can be linked across imports and nothing is imported twice.
+ALTER TABLE issues ADD COLUMN external_ref TEXT;
+
+CREATE UNIQUE INDEX uq_issues_project_external_ref ON issues (project_id, external_ref)
+WHERE external_ref IS NOT NULL;
+
+-- An import job. The uploaded file is kept in source until the job has
+-- either been committed or failed; a previewed dry run keeps it so it can
+-- be committed later.
+CREATE TABLE imports (
+    id          UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id  UUID        NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    created_by  UUID        NOT NULL REFERENCES app_users(id),
+    format      TEXT        NOT NULL CHECK (format IN ('csv', 'jira')),
+    mapping     JSONB       NOT NULL DEFAULT '{}',
+    source      BYTEA,
+    dry_run     BOOLEAN     NOT NULL DEFAULT FALSE,
+    status      TEXT        NOT NULL DEFAULT 'queued'
+                CHECK (status IN ('queued', 'running', 'previewed', 'completed', 'failed')),
+    report      JSONB,
+    error       TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at  TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_imports_project_created_at ON imports (project_id, created_at DESC);
+CREATE INDEX idx_imports_pending ON imports (created_at) WHERE status IN ('queued', 'running');
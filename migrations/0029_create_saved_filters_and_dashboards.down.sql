@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS dashboard_widgets;
+DROP TABLE IF EXISTS dashboards;
+DROP TABLE IF EXISTS saved_filters;
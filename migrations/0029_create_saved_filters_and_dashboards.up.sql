@@ -0,0 +1,52 @@
+-- A saved issue filter. query holds issue list parameters as a URL query
+-- string; project_ids limits it to some projects of the workspace, all of
+-- them when empty. Shared filters are visible to every workspace member.
+CREATE TABLE saved_filters (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID        NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    owner_id     UUID        NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+    name         TEXT        NOT NULL,
+    query        TEXT        NOT NULL DEFAULT '',
+    project_ids  UUID[]      NOT NULL DEFAULT '{}',
+    shared       BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_saved_filters_workspace_owner ON saved_filters (workspace_id, owner_id);
+CREATE INDEX idx_saved_filters_workspace_shared ON saved_filters (workspace_id) WHERE shared;
+
+CREATE TRIGGER trg_set_updated_at_saved_filters
+BEFORE UPDATE ON saved_filters
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- A personal dashboard. Its widgets are replaced as a whole on update.
+CREATE TABLE dashboards (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID        NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    owner_id     UUID        NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+    name         TEXT        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_dashboards_workspace_owner ON dashboards (workspace_id, owner_id);
+
+CREATE TRIGGER trg_set_updated_at_dashboards
+BEFORE UPDATE ON dashboards
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Widgets run a saved filter; deleting the filter removes them.
+CREATE TABLE dashboard_widgets (
+    id           UUID    PRIMARY KEY DEFAULT gen_random_uuid(),
+    dashboard_id UUID    NOT NULL REFERENCES dashboards(id) ON DELETE CASCADE,
+    position     INTEGER NOT NULL CHECK (position >= 0),
+    type         TEXT    NOT NULL
+                 CHECK (type IN ('filter_results', 'status_category_counts', 'assignee_counts', 'due_this_week')),
+    title        TEXT    NOT NULL DEFAULT '',
+    filter_id    UUID    NOT NULL REFERENCES saved_filters(id) ON DELETE CASCADE,
+    issue_limit  INTEGER NOT NULL CHECK (issue_limit > 0),
+    UNIQUE (dashboard_id, position)
+);
+
+CREATE INDEX idx_dashboard_widgets_filter ON dashboard_widgets (filter_id);